@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FunctionRuntimeSpec defines how Functions using the runtime are run.
+// Optional fields which aren't set keep the runtime's built-in behavior.
+type FunctionRuntimeSpec struct {
+	// Specifies the image running the Function's sources. The Function Controller creates the FunctionRuntime
+	// of every built-in runtime with its default image. The image overridden in the Function takes precedence.
+	// The image must be allowed by the image policy of the Function Controller. When the policy requires signatures,
+	// the image must be signed with cosign, and the signature is verified for the image digest
+	// without checking its inclusion in the Rekor transparency log.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Specifies the absolute path of the directory to which the Function's sources are copied.
+	// The install and start commands are run in this directory. It must not contain any files of the image.
+	// +kubebuilder:validation:Pattern=`^/.+`
+	// +optional
+	WorkingDir string `json:"workingDir,omitempty"`
+
+	// Specifies the shell command installing the Function's dependencies instead of the built-in one.
+	// +optional
+	InstallCommand string `json:"installCommand,omitempty"`
+
+	// Specifies the shell command starting the server which calls the Function's handler on port `8080` instead of the built-in one.
+	// +optional
+	StartCommand string `json:"startCommand,omitempty"`
+
+	// Specifies the name of the file in which the inline source of the Function is stored instead of the built-in one, for example, `index.js`.
+	// +optional
+	HandlerFile string `json:"handlerFile,omitempty"`
+
+	// Specifies the name of the file in which the inline dependencies of the Function are stored instead of the built-in one.
+	// +optional
+	DependenciesFile string `json:"dependenciesFile,omitempty"`
+
+	// Specifies environment variables set for the Function's container. The Function's own environment variables take precedence.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Marks the runtime as deprecated. Functions using it are configured with a warning.
+	// +optional
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// Specifies the message added to the deprecation warning, for example, the runtime to migrate to.
+	// +optional
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories={all},shortName={fnrt}
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.image"
+// +kubebuilder:printcolumn:name="Deprecated",type="boolean",JSONPath=".spec.deprecated"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// FunctionRuntime is the Schema for the functionruntimes API.
+// Functions use the FunctionRuntime when their runtime is set to its name.
+// FunctionRuntimes of runtimes other than the built-in ones add new runtimes. Runtimes which aren't based on Node.js or Python
+// (their names don't start with `nodejs` or `python`) have no built-in behavior, so their FunctionRuntimes must set the working directory
+// and the start command, and also the handler and dependencies files when they're used by Functions with inline sources.
+type FunctionRuntime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec FunctionRuntimeSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// FunctionRuntimeList contains a list of FunctionRuntime.
+type FunctionRuntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []FunctionRuntime `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FunctionRuntime{}, &FunctionRuntimeList{})
+}
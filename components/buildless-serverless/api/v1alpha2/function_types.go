@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Runtime specifies the name of the Function's runtime.
@@ -42,18 +43,59 @@ const (
 	Python39 Runtime = "python39"
 )
 
+type Language string
+
+const (
+	JavaScript Language = "javascript"
+	TypeScript Language = "typescript"
+)
+
 // FunctionSpec defines the desired state of Function.
 type FunctionSpec struct {
-	// Specifies the runtime of the Function. The available values are `nodejs20` - deprecated, `nodejs22`, and `python312`.
-	// +kubebuilder:validation:Enum=nodejs20;nodejs22;python312;
+	// Specifies the runtime of the Function. The built-in runtimes are `nodejs20` - deprecated, `nodejs22`, and `python312`.
+	// The runtime is described by the FunctionRuntime with the same name, so other runtimes are available when their FunctionRuntimes are created.
 	Runtime Runtime `json:"runtime"`
 
-	// Specifies the runtime image used instead of the default one.
+	// Specifies the runtime image used instead of the default one. The image must be allowed by the image policy of the Function Controller.
+	// When the policy requires signatures, the image must be signed with cosign, and the signature is verified for the image digest
+	// without checking its inclusion in the Rekor transparency log.
 	// +optional
 	RuntimeImageOverride string `json:"runtimeImageOverride,omitempty"`
 
+	// Specifies the language of the Function's sources. The available values are `javascript` (default) and `typescript`.
+	// The `typescript` Function uses `handler.ts` as the entrypoint and is transpiled when the Function's Pod starts. It is supported only for Node.js runtimes.
+	// +kubebuilder:validation:Enum=javascript;typescript
+	// +optional
+	Language Language `json:"language,omitempty"`
+
+	// Specifies how the Function runs. The available values are `service` (default) and `job`.
+	// The `service` Function serves HTTP requests from the Deployment exposed by the Service.
+	// The `job` Function calls its handler once in the Job and completes.
+	// +kubebuilder:validation:Enum=service;job
+	// +kubebuilder:default:=service
+	// +optional
+	Workload WorkloadType `json:"workload,omitempty"`
+
+	// Configures the Job of the `job` Function.
+	// +optional
+	Job *JobSettings `json:"job,omitempty"`
+
+	// Specifies the backend serving the `service` Function. The available values are `deployment` and `knative`.
+	// The `deployment` backend runs the Function in the Deployment exposed by the Service.
+	// The `knative` backend runs the Function as the Knative Serving Service with request-based autoscaling and revisions.
+	// Defaults to the backend configured for the cluster.
+	// +kubebuilder:validation:Enum=deployment;knative
+	// +optional
+	Backend WorkloadBackend `json:"backend,omitempty"`
+
+	// Specifies if the Function's Pods join the service mesh configured for the cluster.
+	// The available values are `enabled` (default) and `disabled`. The `disabled` value opts the Function's Pods out of the sidecar injection.
+	// +kubebuilder:validation:Enum=enabled;disabled
+	// +optional
+	MeshInjection MeshInjection `json:"meshInjection,omitempty"`
+
 	// Contains the Function's source code configuration.
-	// +kubebuilder:validation:XValidation:message="Use GitRepository or Inline source",rule="has(self.gitRepository) && !has(self.inline) || !has(self.gitRepository) && has(self.inline)"
+	// +kubebuilder:validation:XValidation:message="Use exactly one of GitRepository, Inline, ConfigMap, OCI or Archive source",rule="[has(self.gitRepository), has(self.inline), has(self.configMap), has(self.oci), has(self.archive)].filter(x, x).size() == 1"
 	// +kubebuilder:validation:Required
 	Source Source `json:"source"`
 
@@ -73,8 +115,8 @@ type FunctionSpec struct {
 	// +optional
 	ResourceConfiguration *ResourceConfiguration `json:"resourceConfiguration,omitempty"`
 
-	// Deprecated:
-	// This setting will be removed. Serverless no longer automatically creates HPA.
+	// Configures scaling of the Function. Serverless no longer automatically creates HPA.
+	// When **Triggers** are set, the Function's Deployment is scaled by the KEDA ScaledObject.
 	// +optional
 	ScaleConfig *ScaleConfig `json:"scaleConfig,omitempty"`
 
@@ -86,6 +128,14 @@ type FunctionSpec struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Specifies the PodDisruptionBudget protecting the Function's Pods from voluntary disruptions, such as node drains.
+	// +optional
+	DisruptionBudget *DisruptionBudget `json:"disruptionBudget,omitempty"`
+
+	// Specifies how the Function's Deployment replaces Pods when the Function changes.
+	// +optional
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+
 	// Deprecated: Use **Labels** and **Annotations** to label and/or annotate Function's Pods.
 	// +optional
 	// +kubebuilder:validation:XValidation:message="Not supported: Use spec.labels and spec.annotations to label and/or annotate Function's Pods.",rule="!has(self.labels) && !has(self.annotations)"
@@ -94,6 +144,45 @@ type FunctionSpec struct {
 	// Specifies Secrets to mount into the Function's container filesystem.
 	SecretMounts []SecretMount `json:"secretMounts,omitempty"`
 
+	// Specifies the package registry configuration used to install the Function's dependencies.
+	// When not set, the cluster-wide package registry configuration is used.
+	// +optional
+	PackageRegistryConfig *PackageRegistryConfig `json:"packageRegistryConfig,omitempty"`
+
+	// Exposes the Function outside of the cluster. The Function Controller creates the APIRule
+	// when its CRD is installed, or the Gateway API HTTPRoute otherwise.
+	// +optional
+	Expose *Expose `json:"expose,omitempty"`
+
+	// Specifies schedules on which the Function is invoked. For every schedule, the Function Controller
+	// creates a CronJob that sends the HTTP request to the Function's Service.
+	// +kubebuilder:validation:XValidation:message="Schedule names must be unique",rule="self.all(x, self.exists_one(y, y.name == x.name))"
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Schedules []Schedule `json:"schedules,omitempty"`
+
+	// Specifies Eventing subscriptions delivering events to the Function. For every subscription,
+	// the Function Controller creates the Subscription targeting the Function's Service.
+	// +kubebuilder:validation:XValidation:message="Subscription names must be unique",rule="self.all(x, self.exists_one(y, y.name == x.name))"
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Subscriptions []Subscription `json:"subscriptions,omitempty"`
+
+	// Enables the asynchronous invocation of the Function. Requests accepted by the async endpoint
+	// of the Function Controller are queued and delivered to the Function's Service with retries.
+	// Requests to the Function with **Auth** must have a JWT accepted by **Auth.JWT**, so it can't be combined with **Auth.Namespaces** or **Auth.Principals**.
+	// +optional
+	Async *AsyncInvocation `json:"async,omitempty"`
+
+	// Restricts the network traffic of the Function's Pods with the NetworkPolicy.
+	// +optional
+	NetworkPolicy *NetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// Restricts calls to the Function with Istio. The Function Controller creates the RequestAuthentication
+	// and the AuthorizationPolicy scoped to the Function's Pods.
+	// +optional
+	Auth *Auth `json:"auth,omitempty"`
+
 	// Defines labels used in Deployment's PodTemplate and applied on the Function's runtime Pod.
 	// +optional
 	// +kubebuilder:validation:XValidation:message="Labels has key starting with serverless.kyma-project.io/ which is not allowed",rule="!(self.exists(e, e.startsWith('serverless.kyma-project.io/')))"
@@ -108,13 +197,25 @@ type FunctionSpec struct {
 }
 
 type Source struct {
-	// Defines the Function as git-sourced. Can't be used together with **Inline**.
+	// Defines the Function as git-sourced. Can't be used together with other sources.
 	// +optional
 	GitRepository *GitRepositorySource `json:"gitRepository,omitempty"`
 
-	// Defines the Function as the inline Function. Can't be used together with **GitRepository**.
+	// Defines the Function as the inline Function. Can't be used together with other sources.
 	// +optional
 	Inline *InlineSource `json:"inline,omitempty"`
+
+	// Defines the Function as sourced from a ConfigMap. Can't be used together with other sources.
+	// +optional
+	ConfigMap *ConfigMapSource `json:"configMap,omitempty"`
+
+	// Defines the Function as sourced from an OCI artifact. Can't be used together with other sources.
+	// +optional
+	OCI *OCISource `json:"oci,omitempty"`
+
+	// Defines the Function as sourced from a `tar.gz` archive served over HTTP(S). Can't be used together with other sources.
+	// +optional
+	Archive *ArchiveSource `json:"archive,omitempty"`
 }
 
 type InlineSource struct {
@@ -126,6 +227,71 @@ type InlineSource struct {
 	// Specifies the Function's dependencies.
 	//+optional
 	Dependencies string `json:"dependencies,omitempty"`
+
+	// Specifies additional source files written next to the handler, keyed by their relative path
+	// (for example, `utils/helpers.js`). The path can't point outside of the Function's sources directory
+	// and can't overwrite the files and directories provided by the runtime, such as the handler, dependencies,
+	// `server.mjs`, `server.py`, `openssl.cnf`, `lib/`, or `node_modules/`.
+	// +optional
+	Files map[string]string `json:"files,omitempty"`
+}
+
+type ConfigMapSource struct {
+	// Specifies the name of the ConfigMap with the Function's source files.
+	// Every key of the ConfigMap is written as a separate file to the Function's sources directory.
+	// This ConfigMap must be stored in the same Namespace as the Function CR.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+}
+
+type OCISource struct {
+	// Specifies the reference of the OCI artifact with the Function's source files,
+	// for example, an artifact created with `oras push`. The reference can point to a tag or a digest.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:message="Reference is required and cannot be empty",rule="self.trim().size() != 0"
+	Reference string `json:"reference"`
+
+	// Specifies the name of the `kubernetes.io/dockerconfigjson` Secret with credentials
+	// used to pull the artifact from a private registry.
+	// This Secret must be stored in the same Namespace as the Function CR.
+	// +optional
+	PullSecretName string `json:"pullSecretName,omitempty"`
+}
+
+type ArchiveSource struct {
+	// Specifies the HTTP(S) URL of the `tar.gz` archive with the Function's code and dependencies.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:message="URL must use http or https scheme",rule="self.startsWith('http://') || self.startsWith('https://')"
+	URL string `json:"url"`
+
+	// Specifies the expected hex-encoded SHA-256 checksum of the archive.
+	// When set, the Function Controller verifies every new revision of the archive before rolling it out
+	// and the archive is rejected if its checksum doesn't match.
+	// It's optional so that archives republished under the same URL are rolled out when their `ETag` or `Last-Modified` header changes.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-f0-9]{64}$`
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Specifies the relative path to the directory inside the archive that contains the Function's sources.
+	// +optional
+	BaseDir string `json:"baseDir,omitempty"`
+
+	// Specifies the basic authentication used to download the archive.
+	// +optional
+	Auth *ArchiveAuth `json:"auth,omitempty"`
+}
+
+// ArchiveAuth defines authentication used to download the archive
+type ArchiveAuth struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:message="SecretName is required and cannot be empty",rule="self.trim().size() != 0"
+
+	// Specifies the name of the `kubernetes.io/basic-auth` Secret with `username` and `password`
+	// used to download the archive.
+	// This Secret must be stored in the same Namespace as the Function CR.
+	SecretName string `json:"secretName"`
 }
 
 type GitRepositorySource struct {
@@ -210,12 +376,335 @@ type ResourceRequirements struct {
 
 type ScaleConfig struct {
 	// Defines the minimum number of Function's Pods to run at a time.
-	// +kubebuilder:validation:Minimum:=1
+	// `0` allows scaling the Function to zero when it's scaled by **Triggers** or served by Knative.
+	// +kubebuilder:validation:Minimum:=0
 	MinReplicas *int32 `json:"minReplicas"`
 
 	// Defines the maximum number of Function's Pods to run at a time.
 	// +kubebuilder:validation:Minimum:=1
 	MaxReplicas *int32 `json:"maxReplicas"`
+
+	// Specifies KEDA triggers scaling the Function's Deployment, for example, Kafka lag, Prometheus query, or cron.
+	// For every Function with triggers, the Function Controller creates a KEDA ScaledObject and leaves replicas to KEDA.
+	// +optional
+	Triggers []ScaleTrigger `json:"triggers,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Use exactly one of minAvailable or maxUnavailable",rule="has(self.minAvailable) != has(self.maxUnavailable)"
+type DisruptionBudget struct {
+	// Specifies the number or the percentage of the Function's Pods that must stay available during a disruption.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Specifies the number or the percentage of the Function's Pods that can be unavailable during a disruption.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type RollingUpdate struct {
+	// Specifies the number or the percentage of Pods that can be created above the desired number of Pods during the update.
+	// Defaults to `25%`.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// Specifies the number or the percentage of Pods that can be unavailable during the update.
+	// Defaults to `25%`.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type ScaleTrigger struct {
+	// Specifies the type of the KEDA scaler, for example, `kafka`, `prometheus`, or `cron`.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+
+	// Specifies the name of the trigger.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Specifies the configuration of the KEDA scaler.
+	// For configuration details, see the [KEDA documentation](https://keda.sh/docs/latest/scalers/).
+	Metadata map[string]string `json:"metadata"`
+
+	// Specifies the name of the KEDA TriggerAuthentication providing credentials to the scaler.
+	// This TriggerAuthentication must be stored in the same Namespace as the Function CR.
+	// +optional
+	AuthenticationRef string `json:"authenticationRef,omitempty"`
+}
+
+type PackageRegistryConfig struct {
+	// Specifies the name of the Secret with the `.npmrc` (Node.js) or `pip.conf` (Python) key.
+	// This Secret must be stored in the same Namespace as the Function CR.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+}
+
+// ExposeAuthMode is the enum of available authentication modes of the exposed Function
+// +kubebuilder:validation:Enum=noAuth;jwt
+type ExposeAuthMode string
+
+const (
+	ExposeAuthNoAuth ExposeAuthMode = "noAuth"
+	ExposeAuthJWT    ExposeAuthMode = "jwt"
+)
+
+// +kubebuilder:validation:XValidation:message="JWT is required when auth is jwt",rule="!has(self.auth) || self.auth != 'jwt' || has(self.jwt)"
+type Expose struct {
+	// Specifies the host under which the Function is exposed, for example, `my-function.example.com`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Host string `json:"host"`
+
+	// Specifies the path prefix under which the Function is exposed.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:default:="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Specifies the authentication mode. The available values are `noAuth` (default) and `jwt`.
+	// The `jwt` mode is supported only with the APIRule.
+	// +kubebuilder:default:=noAuth
+	// +optional
+	Auth ExposeAuthMode `json:"auth,omitempty"`
+
+	// Specifies the JWT issuer used when **Auth** is `jwt`.
+	// +optional
+	JWT *ExposeJWT `json:"jwt,omitempty"`
+}
+
+type ExposeJWT struct {
+	// Specifies the issuer of the accepted tokens.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Issuer string `json:"issuer"`
+
+	// Specifies the URL of the issuer's JSON Web Key Set.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	JWKSURI string `json:"jwksUri"`
+}
+
+type Schedule struct {
+	// Specifies the name of the schedule. The CronJob is named `{FUNCTION_NAME}-{SCHEDULE_NAME}`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=20
+	Name string `json:"name"`
+
+	// Specifies the cron expression of the schedule, for example, `*/15 * * * *`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Cron string `json:"cron"`
+
+	// Specifies the time zone of the cron expression, for example, `Europe/Warsaw`. Defaults to the time zone of the kube-controller-manager.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Specifies the body of the request sent to the Function. JSON payloads are sent with the `application/json` content type.
+	// +optional
+	Payload string `json:"payload,omitempty"`
+
+	// Specifies the CloudEvent type. When set, the request is sent as a binary-mode CloudEvent.
+	// +optional
+	CloudEventType string `json:"cloudEventType,omitempty"`
+}
+
+// SubscriptionTypeMatching is the enum of available matchings of the subscribed event types
+// +kubebuilder:validation:Enum=standard;exact
+type SubscriptionTypeMatching string
+
+const (
+	SubscriptionTypeMatchingStandard SubscriptionTypeMatching = "standard"
+	SubscriptionTypeMatchingExact    SubscriptionTypeMatching = "exact"
+)
+
+type Subscription struct {
+	// Specifies the name of the subscription. The Subscription is named `{FUNCTION_NAME}-{SUBSCRIPTION_NAME}`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Specifies the source of the subscribed events, for example, the name of the application sending them.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Specifies the subscribed event types, for example, `order.created.v1`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Types []string `json:"types"`
+
+	// Specifies how the event types are matched. The available values are `standard` (default) and `exact`.
+	// +kubebuilder:default:=standard
+	// +optional
+	TypeMatching SubscriptionTypeMatching `json:"typeMatching,omitempty"`
+
+	// Specifies additional configuration of the subscription, for example, `maxInFlightMessages`.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// WorkloadType is the enum of available kinds of workloads running the Function
+type WorkloadType string
+
+const (
+	WorkloadService WorkloadType = "service"
+	WorkloadJob     WorkloadType = "job"
+)
+
+// WorkloadBackend is the enum of available backends serving the `service` Function
+type WorkloadBackend string
+
+const (
+	WorkloadBackendDeployment WorkloadBackend = "deployment"
+	WorkloadBackendKnative    WorkloadBackend = "knative"
+)
+
+// MeshInjection is the enum of available modes of the Function's sidecar injection
+type MeshInjection string
+
+const (
+	MeshInjectionEnabled  MeshInjection = "enabled"
+	MeshInjectionDisabled MeshInjection = "disabled"
+)
+
+type JobSettings struct {
+	// Specifies how many times the failed Job's Pod is retried.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default:=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// Specifies how long the Job can run before it's terminated.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Specifies the identifier of the run. The Job runs again when the identifier is changed,
+	// and also when the Function's sources or configuration change.
+	// +optional
+	RunID string `json:"runId,omitempty"`
+}
+
+// AsyncInvocation configures the delivery of requests queued in the memory of the Function Controller.
+// The queue isn't persisted, so accepted requests are delivered at most once:
+// requests waiting for the delivery are lost when the Function Controller restarts.
+type AsyncInvocation struct {
+	// Specifies how many times the delivery of a failed request is retried.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=20
+	// +kubebuilder:default:=3
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// Specifies the delay before the first retry, for example, `2s`. The delay is doubled for every next retry.
+	// +kubebuilder:default:="1s"
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// Specifies how many requests are delivered to the Function concurrently.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +kubebuilder:default:=1
+	// +optional
+	Concurrency *int32 `json:"concurrency,omitempty"`
+
+	// Specifies where requests are forwarded when all retries are exhausted.
+	// When not set, such requests are dropped.
+	// +optional
+	DeadLetter *DeadLetter `json:"deadLetter,omitempty"`
+}
+
+type NetworkPolicy struct {
+	// Restricts the incoming traffic of the Function's Pods to the listed sources.
+	// When not set, the incoming traffic isn't restricted.
+	// +optional
+	Ingress *NetworkPolicyIngress `json:"ingress,omitempty"`
+
+	// Restricts the outgoing traffic of the Function's Pods to the listed destinations.
+	// DNS, the eventing publisher proxy, and the trace collector are always allowed.
+	// When not set, the outgoing traffic isn't restricted.
+	// +optional
+	Egress *NetworkPolicyEgress `json:"egress,omitempty"`
+}
+
+type NetworkPolicyIngress struct {
+	// Specifies sources allowed to call the Function.
+	// +optional
+	From []NetworkPolicySource `json:"from,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Use namespaceSelector, podSelector, or both",rule="has(self.namespaceSelector) || has(self.podSelector)"
+type NetworkPolicySource struct {
+	// Selects Namespaces of the allowed Pods. When not set, Pods are selected in the Function's Namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Selects the allowed Pods. When not set, all Pods in the selected Namespaces are allowed.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+type NetworkPolicyEgress struct {
+	// Specifies destinations the Function is allowed to call.
+	// +optional
+	To []NetworkPolicyDestination `json:"to,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Use cidrs, ports, or both",rule="has(self.cidrs) || has(self.ports)"
+type NetworkPolicyDestination struct {
+	// Specifies IP blocks of the destination, for example, `10.0.0.0/16`.
+	// When not set, all destinations are allowed on **Ports**, which allows calling services known only by their DNS names.
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	// Specifies ports of the destination. When not set, all ports are allowed.
+	// +optional
+	Ports []NetworkPolicyPort `json:"ports,omitempty"`
+}
+
+type NetworkPolicyPort struct {
+	// Specifies the port number.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Specifies the protocol. The value is either `TCP`, `UDP`, or `SCTP`. Defaults to `TCP`.
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Use jwt, principals, or namespaces",rule="has(self.jwt) || has(self.principals) || has(self.namespaces)"
+type Auth struct {
+	// Specifies the issuer of JWTs accepted by the Function. Calls with a valid token issued by it are allowed.
+	// +optional
+	JWT *ExposeJWT `json:"jwt,omitempty"`
+
+	// Specifies Istio principals of workloads allowed to call the Function, for example, `cluster.local/ns/default/sa/my-client`.
+	// +optional
+	Principals []string `json:"principals,omitempty"`
+
+	// Specifies Namespaces of workloads allowed to call the Function.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Exactly one of function or url must be set",rule="has(self.function) != has(self.url)"
+type DeadLetter struct {
+	// Specifies the name of the Function in the same Namespace receiving the dead-lettered requests.
+	// +optional
+	Function string `json:"function,omitempty"`
+
+	// Specifies the URL of the Service in the same Namespace receiving the dead-lettered requests,
+	// for example, `http://my-sink.default.svc.cluster.local/failed`. URLs outside of the cluster aren't allowed.
+	// +kubebuilder:validation:Pattern=`^https?://[a-z]([-a-z0-9]*[a-z0-9])?\.[a-z0-9]([-a-z0-9]*[a-z0-9])?\.svc(\.cluster\.local)?(:[0-9]+)?(/.*)?$`
+	// +optional
+	URL string `json:"url,omitempty"`
 }
 
 type SecretMount struct {
@@ -248,6 +737,12 @@ type FunctionStatus struct {
 	Runtime Runtime `json:"runtime,omitempty"`
 	// Specifies the image version used to build and run the Function's Pods.
 	RuntimeImage string `json:"runtimeImage,omitempty"`
+	// Specifies the digest the runtime image was resolved to. The Function's Pods run the image by this digest.
+	RuntimeImageDigest string `json:"runtimeImageDigest,omitempty"`
+	// Specifies when the runtime image was resolved to the digest. The image is resolved again after the interval configured in the Function Controller.
+	RuntimeImageDigestResolvedAt *metav1.Time `json:"runtimeImageDigestResolvedAt,omitempty"`
+	// Specifies the staged upgrade of the runtime image changed by the Function Controller's configuration.
+	RuntimeImageUpgrade *RuntimeImageUpgrade `json:"runtimeImageUpgrade,omitempty"`
 	// Specifies the total number of non-terminated Pods targeted by this Function.
 	Replicas int32 `json:"replicas,omitempty"`
 	// Specifies the Pod selector used to match Pods in the Function's Deployment.
@@ -266,10 +761,121 @@ type FunctionStatus struct {
 	Repository `json:",inline,omitempty"`
 	// Specifies the GitRepository status when the Function is sourced from a Git repository.
 	GitRepository *GitRepositoryStatus `json:"gitRepository,omitempty"`
+	// Specifies the ConfigMap status when the Function is sourced from a ConfigMap.
+	ConfigMap *ConfigMapSourceStatus `json:"configMap,omitempty"`
+	// Specifies the OCI artifact status when the Function is sourced from an OCI artifact.
+	OCI *OCISourceStatus `json:"oci,omitempty"`
+	// Specifies the archive status when the Function is sourced from an archive.
+	Archive *ArchiveSourceStatus `json:"archive,omitempty"`
 	// ContainerSecurityContext used by the Function's container
 	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
 	// PodSecurityContext used by the Function's Pod
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Specifies the public URL of the Function when it's exposed.
+	URL string `json:"url,omitempty"`
+	// Specifies the last runs of the Function's schedules.
+	Schedules []ScheduleStatus `json:"schedules,omitempty"`
+	// Specifies the state of the Job running the `job` Function.
+	Job *JobStatus `json:"job,omitempty"`
+	// Specifies the state of the PodDisruptionBudget protecting the Function's Pods.
+	DisruptionBudget *DisruptionBudgetStatus `json:"disruptionBudget,omitempty"`
+	// Specifies the rolling update parameters of the Function's Deployment.
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+	// Specifies the effective policy restricting calls to the Function.
+	Auth *AuthStatus `json:"auth,omitempty"`
+}
+
+// RuntimeImageUpgradeState is the state of the staged runtime image upgrade
+// +kubebuilder:validation:Enum=Pending;Paused;InProgress;Failed
+type RuntimeImageUpgradeState string
+
+const (
+	// RuntimeImageUpgradePending means the Function waits for the upgrade slot or the maintenance window
+	RuntimeImageUpgradePending RuntimeImageUpgradeState = "Pending"
+	// RuntimeImageUpgradePaused means the rollout is paused after too many failed upgrades
+	RuntimeImageUpgradePaused RuntimeImageUpgradeState = "Paused"
+	// RuntimeImageUpgradeInProgress means the Function's Deployment is rolled out with the new image
+	RuntimeImageUpgradeInProgress RuntimeImageUpgradeState = "InProgress"
+	// RuntimeImageUpgradeFailed means the Function's Deployment failed with the new image
+	RuntimeImageUpgradeFailed RuntimeImageUpgradeState = "Failed"
+)
+
+type RuntimeImageUpgrade struct {
+	// Specifies the runtime image the Function is upgraded to.
+	Image string `json:"image"`
+	// Specifies the digest of the runtime image the Function is upgraded to.
+	Digest string `json:"digest,omitempty"`
+	// Specifies the state of the upgrade.
+	State RuntimeImageUpgradeState `json:"state"`
+}
+
+type DisruptionBudgetStatus struct {
+	// Specifies the name of the PodDisruptionBudget.
+	Name string `json:"name"`
+	// Specifies the number of the Function's Pods that can be disrupted at the moment.
+	DisruptionsAllowed int32 `json:"disruptionsAllowed"`
+	// Specifies the number of healthy Pods of the Function.
+	CurrentHealthy int32 `json:"currentHealthy"`
+	// Specifies the minimum number of healthy Pods required by the PodDisruptionBudget.
+	DesiredHealthy int32 `json:"desiredHealthy"`
+}
+
+type AuthStatus struct {
+	// Specifies the name of the RequestAuthentication validating JWTs sent to the Function.
+	RequestAuthentication string `json:"requestAuthentication,omitempty"`
+	// Specifies the name of the AuthorizationPolicy allowing calls to the Function.
+	AuthorizationPolicy string `json:"authorizationPolicy"`
+	// Specifies request principals of the accepted JWTs, in the `{ISSUER}/{SUBJECT}` format.
+	RequestPrincipals []string `json:"requestPrincipals,omitempty"`
+	// Specifies Istio principals of workloads allowed to call the Function.
+	Principals []string `json:"principals,omitempty"`
+	// Specifies Namespaces of workloads allowed to call the Function,
+	// including the Namespaces from which the Function's schedules and subscriptions call it.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// JobPhase is the phase of the Job running the Function
+type JobPhase string
+
+const (
+	JobPhaseRunning   JobPhase = "Running"
+	JobPhaseSucceeded JobPhase = "Succeeded"
+	JobPhaseFailed    JobPhase = "Failed"
+)
+
+type JobStatus struct {
+	// Specifies the name of the Job running the Function.
+	Name string `json:"name"`
+	// Specifies the phase of the Job. The value is either `Running`, `Succeeded`, or `Failed`.
+	Phase JobPhase `json:"phase,omitempty"`
+	// Specifies the time the Job was started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Specifies the time the Job completed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Specifies the command printing logs of the Job.
+	Logs string `json:"logs,omitempty"`
+}
+
+// ScheduleRunOutcome is the outcome of the last scheduled invocation of the Function
+type ScheduleRunOutcome string
+
+const (
+	ScheduleRunOutcomeRunning   ScheduleRunOutcome = "Running"
+	ScheduleRunOutcomeSucceeded ScheduleRunOutcome = "Succeeded"
+	ScheduleRunOutcomeFailed    ScheduleRunOutcome = "Failed"
+)
+
+type ScheduleStatus struct {
+	// Specifies the name of the schedule.
+	Name string `json:"name"`
+	// Specifies the name of the CronJob running the schedule.
+	CronJobName string `json:"cronJobName"`
+	// Specifies the last time the Function was invoked on the schedule.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Specifies the last time the Function was successfully invoked on the schedule.
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Specifies the outcome of the last run. The value is either `Running`, `Succeeded`, or `Failed`.
+	LastRunOutcome ScheduleRunOutcome `json:"lastRunOutcome,omitempty"`
 }
 
 type GitRepositoryStatus struct {
@@ -278,11 +884,36 @@ type GitRepositoryStatus struct {
 	Commit     string `json:"commit,omitempty"`
 }
 
+type ConfigMapSourceStatus struct {
+	// Specifies the name of the ConfigMap used as the Function's source.
+	Name string `json:"name"`
+	// Specifies the hash of the ConfigMap's data used to run the Function.
+	Hash string `json:"hash,omitempty"`
+}
+
+type OCISourceStatus struct {
+	// Specifies the reference of the OCI artifact used as the Function's source.
+	Reference string `json:"reference"`
+	// Specifies the digest the reference was resolved to.
+	Digest string `json:"digest,omitempty"`
+}
+
+type ArchiveSourceStatus struct {
+	// Specifies the URL of the archive used as the Function's source.
+	URL string `json:"url"`
+	// Specifies the revision of the archive (`ETag` or `Last-Modified` header) used to run the Function.
+	Revision string `json:"revision,omitempty"`
+	// Specifies the SHA-256 checksum the revision of the archive was verified against.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
 type ConditionType string
 
 const (
 	ConditionRunning            ConditionType = "Running"
 	ConditionConfigurationReady ConditionType = "ConfigurationReady"
+	ConditionSubscriptionsReady ConditionType = "SubscriptionsReady"
+	ConditionScalingReady       ConditionType = "ScalingReady"
 )
 
 type ConditionReason string
@@ -291,6 +922,10 @@ const (
 	ConditionReasonInvalidFunctionSpec            ConditionReason = "InvalidFunctionSpec"
 	ConditionReasonFunctionSpecValidated          ConditionReason = "FunctionSpecValidated"
 	ConditionReasonFunctionSpecRuntimeFallback    ConditionReason = "FunctionSpecRuntimeFallback"
+	ConditionReasonRuntimeUpgraded                ConditionReason = "RuntimeUpgraded"
+	ConditionReasonRuntimeEndOfLife               ConditionReason = "RuntimeEndOfLife"
+	ConditionReasonRuntimeImageResolutionFailed   ConditionReason = "RuntimeImageResolutionFailed"
+	ConditionReasonRuntimeImageSignatureInvalid   ConditionReason = "RuntimeImageSignatureInvalid"
 	ConditionReasonSourceUpdated                  ConditionReason = "SourceUpdated"
 	ConditionReasonSourceUpdateFailed             ConditionReason = "SourceUpdateFailed"
 	ConditionReasonDeploymentCreated              ConditionReason = "DeploymentCreated"
@@ -304,7 +939,37 @@ const (
 	ConditionReasonServiceCreated                 ConditionReason = "ServiceCreated"
 	ConditionReasonServiceUpdated                 ConditionReason = "ServiceUpdated"
 	ConditionReasonServiceFailed                  ConditionReason = "ServiceFailed"
+	ConditionReasonPodDisruptionBudgetCreated     ConditionReason = "PodDisruptionBudgetCreated"
+	ConditionReasonPodDisruptionBudgetUpdated     ConditionReason = "PodDisruptionBudgetUpdated"
+	ConditionReasonPodDisruptionBudgetFailed      ConditionReason = "PodDisruptionBudgetFailed"
+	ConditionReasonNetworkPolicyCreated           ConditionReason = "NetworkPolicyCreated"
+	ConditionReasonNetworkPolicyUpdated           ConditionReason = "NetworkPolicyUpdated"
+	ConditionReasonNetworkPolicyFailed            ConditionReason = "NetworkPolicyFailed"
+	ConditionReasonAuthPolicyCreated              ConditionReason = "AuthPolicyCreated"
+	ConditionReasonAuthPolicyUpdated              ConditionReason = "AuthPolicyUpdated"
+	ConditionReasonAuthPolicyFailed               ConditionReason = "AuthPolicyFailed"
 	ConditionReasonMinReplicasNotAvailable        ConditionReason = "MinReplicasNotAvailable"
+	ConditionReasonCompilationFailed              ConditionReason = "CompilationFailed"
+	ConditionReasonPackageRegistryConfigInvalid   ConditionReason = "PackageRegistryConfigInvalid"
+	ConditionReasonExposeCreated                  ConditionReason = "ExposeCreated"
+	ConditionReasonExposeUpdated                  ConditionReason = "ExposeUpdated"
+	ConditionReasonExposeFailed                   ConditionReason = "ExposeFailed"
+	ConditionReasonScheduleFailed                 ConditionReason = "ScheduleFailed"
+	ConditionReasonSubscriptionsReady             ConditionReason = "SubscriptionsReady"
+	ConditionReasonSubscriptionsNotReady          ConditionReason = "SubscriptionsNotReady"
+	ConditionReasonSubscriptionFailed             ConditionReason = "SubscriptionFailed"
+	ConditionReasonJobCreated                     ConditionReason = "JobCreated"
+	ConditionReasonJobRunning                     ConditionReason = "JobRunning"
+	ConditionReasonJobSucceeded                   ConditionReason = "JobSucceeded"
+	ConditionReasonJobFailed                      ConditionReason = "JobFailed"
+	ConditionReasonKnativeServiceCreated          ConditionReason = "KnativeServiceCreated"
+	ConditionReasonKnativeServiceUpdated          ConditionReason = "KnativeServiceUpdated"
+	ConditionReasonKnativeServiceFailed           ConditionReason = "KnativeServiceFailed"
+	ConditionReasonKnativeServiceWaiting          ConditionReason = "KnativeServiceWaiting"
+	ConditionReasonKnativeServiceReady            ConditionReason = "KnativeServiceReady"
+	ConditionReasonScaledObjectReady              ConditionReason = "ScaledObjectReady"
+	ConditionReasonScaledObjectNotReady           ConditionReason = "ScaledObjectNotReady"
+	ConditionReasonScaledObjectFailed             ConditionReason = "ScaledObjectFailed"
 )
 
 // +kubebuilder:object:root=true
@@ -370,13 +1035,24 @@ func (s *FunctionStatus) Condition(c ConditionType) *metav1.Condition {
 }
 
 const (
-	FunctionNameLabel                    = "serverless.kyma-project.io/function-name"
-	FunctionManagedByLabel               = "serverless.kyma-project.io/managed-by"
-	FunctionControllerValue              = "function-controller"
-	FunctionUUIDLabel                    = "serverless.kyma-project.io/uuid"
-	FunctionResourceLabel                = "serverless.kyma-project.io/resource"
-	FunctionResourceLabelDeploymentValue = "deployment"
-	PodAppNameLabel                      = "app.kubernetes.io/name"
+	FunctionNameLabel                             = "serverless.kyma-project.io/function-name"
+	FunctionManagedByLabel                        = "serverless.kyma-project.io/managed-by"
+	FunctionControllerValue                       = "function-controller"
+	FunctionUUIDLabel                             = "serverless.kyma-project.io/uuid"
+	FunctionResourceLabel                         = "serverless.kyma-project.io/resource"
+	FunctionResourceLabelDeploymentValue          = "deployment"
+	FunctionResourceLabelInlineValue              = "inline-sources"
+	FunctionResourceLabelSBOMValue                = "sbom"
+	FunctionResourceLabelExposeValue              = "expose"
+	FunctionResourceLabelScheduleValue            = "schedule"
+	FunctionResourceLabelSubscriptionValue        = "subscription"
+	FunctionResourceLabelBatchValue               = "batch"
+	FunctionResourceLabelKnativeServiceValue      = "knative-service"
+	FunctionResourceLabelScaledObjectValue        = "scaled-object"
+	FunctionResourceLabelPodDisruptionBudgetValue = "pod-disruption-budget"
+	FunctionResourceLabelNetworkPolicyValue       = "network-policy"
+	FunctionResourceLabelAuthPolicyValue          = "auth-policy"
+	PodAppNameLabel                               = "app.kubernetes.io/name"
 )
 
 func (f *Function) InternalFunctionLabels() map[string]string {
@@ -433,6 +1109,54 @@ func (f *Function) HasInlineSources() bool {
 	return f.Spec.Source.Inline != nil
 }
 
+func (f *Function) HasConfigMapSources() bool {
+	return f.Spec.Source.ConfigMap != nil
+}
+
+func (f *Function) HasOCISources() bool {
+	return f.Spec.Source.OCI != nil
+}
+
+func (f *Function) HasOCIPullSecret() bool {
+	return f.Spec.Source.OCI != nil && f.Spec.Source.OCI.PullSecretName != ""
+}
+
+func (f *Function) HasArchiveSources() bool {
+	return f.Spec.Source.Archive != nil
+}
+
+func (f *Function) HasArchiveAuth() bool {
+	return f.Spec.Source.Archive != nil && f.Spec.Source.Archive.Auth != nil
+}
+
+func (f *Function) HasPackageRegistryConfig() bool {
+	return f.Spec.PackageRegistryConfig != nil
+}
+
+func (f *Function) HasScaleTriggers() bool {
+	return f.Spec.ScaleConfig != nil && len(f.Spec.ScaleConfig.Triggers) != 0
+}
+
+func (f *Function) IsJob() bool {
+	return f.Spec.Workload == WorkloadJob
+}
+
+func (f *Function) HasAsync() bool {
+	return f.Spec.Async != nil
+}
+
+func (f *Function) IsMeshInjectionDisabled() bool {
+	return f.Spec.MeshInjection == MeshInjectionDisabled
+}
+
+func (f *Function) HasExpose() bool {
+	return f.Spec.Expose != nil
+}
+
+func (f *Function) HasTypeScript() bool {
+	return f.HasNodejsRuntime() && f.Spec.Language == TypeScript
+}
+
 func (f *Function) HasPythonRuntime() bool {
 	return f.Spec.Runtime.IsRuntimePython()
 }
@@ -486,8 +1210,9 @@ func (runtime Runtime) IsRuntimeNodejs() bool {
 }
 
 // supportedRuntimeEquivalent maps given runtime to the supported one
+// runtimes which aren't built-in are described only by their FunctionRuntimes, so they aren't mapped
 func (runtime Runtime) SupportedRuntimeEquivalent() Runtime {
-	if runtime.IsRuntimeSupported() {
+	if runtime.IsRuntimeSupported() || !runtime.IsRuntimeKnown() {
 		return runtime
 	}
 	return runtime.latestRuntimeEquivalent()
@@ -32,6 +32,16 @@ func Test_XKubernetesValidations_Valid(t *testing.T) {
 	testCases := map[string]struct {
 		fn *serverlessv1alpha2.Function
 	}{
+		"runtime described by its FunctionRuntime": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "a"}},
+					Runtime: serverlessv1alpha2.Runtime("custom"),
+				},
+			},
+		},
 		"Profile set only for function": {
 			fn: &serverlessv1alpha2.Function{
 				ObjectMeta: fixMetadata,
@@ -677,7 +687,7 @@ func Test_XKubernetesValidations_Invalid(t *testing.T) {
 					},
 				},
 			},
-			expectedErrMsg: "Invalid value: Use GitRepository or Inline source",
+			expectedErrMsg: "Invalid value: Use exactly one of GitRepository, Inline, ConfigMap, OCI or Archive source",
 			fieldPath:      "spec.source",
 			expectedCause:  metav1.CauseTypeFieldValueInvalid,
 		},
@@ -689,7 +699,7 @@ func Test_XKubernetesValidations_Invalid(t *testing.T) {
 					Source:  serverlessv1alpha2.Source{},
 				},
 			},
-			expectedErrMsg: "Invalid value: Use GitRepository or Inline source",
+			expectedErrMsg: "Invalid value: Use exactly one of GitRepository, Inline, ConfigMap, OCI or Archive source",
 			fieldPath:      "spec.source",
 			expectedCause:  metav1.CauseTypeFieldValueInvalid,
 		},
@@ -856,19 +866,6 @@ func Test_XKubernetesValidations_InvalidMultipleCauses(t *testing.T) {
 		fieldPath      string
 		expectedCause  metav1.CauseType
 	}{
-		"disallowed runtime: custom": {
-			fn: &serverlessv1alpha2.Function{
-				ObjectMeta: fixMetadata,
-				Spec: serverlessv1alpha2.FunctionSpec{
-					Source: serverlessv1alpha2.Source{
-						Inline: &serverlessv1alpha2.InlineSource{Source: "a"}},
-					Runtime: serverlessv1alpha2.Runtime("custom"),
-				},
-			},
-			expectedCause:  metav1.CauseTypeFieldValueNotSupported,
-			fieldPath:      "spec.runtime",
-			expectedErrMsg: "Unsupported value: \"custom\": supported values: \"nodejs20\", \"nodejs22\", \"python312\"",
-		},
 		"Git source auth has incorrect Type": {
 			fn: &serverlessv1alpha2.Function{
 				ObjectMeta: fixMetadata,
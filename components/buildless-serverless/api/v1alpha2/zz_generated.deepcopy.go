@@ -24,232 +24,915 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchiveAuth) DeepCopyInto(out *ArchiveAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchiveAuth.
+func (in *ArchiveAuth) DeepCopy() *ArchiveAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchiveAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchiveSource) DeepCopyInto(out *ArchiveSource) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ArchiveAuth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchiveSource.
+func (in *ArchiveSource) DeepCopy() *ArchiveSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchiveSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArchiveSourceStatus) DeepCopyInto(out *ArchiveSourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArchiveSourceStatus.
+func (in *ArchiveSourceStatus) DeepCopy() *ArchiveSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArchiveSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AsyncInvocation) DeepCopyInto(out *AsyncInvocation) {
+	*out = *in
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeadLetter != nil {
+		in, out := &in.DeadLetter, &out.DeadLetter
+		*out = new(DeadLetter)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AsyncInvocation.
+func (in *AsyncInvocation) DeepCopy() *AsyncInvocation {
+	if in == nil {
+		return nil
+	}
+	out := new(AsyncInvocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Auth) DeepCopyInto(out *Auth) {
+	*out = *in
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(ExposeJWT)
+		**out = **in
+	}
+	if in.Principals != nil {
+		in, out := &in.Principals, &out.Principals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
+func (in *Auth) DeepCopy() *Auth {
+	if in == nil {
+		return nil
+	}
+	out := new(Auth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuthStatus) DeepCopyInto(out *AuthStatus) {
+	*out = *in
+	if in.RequestPrincipals != nil {
+		in, out := &in.RequestPrincipals, &out.RequestPrincipals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Principals != nil {
+		in, out := &in.Principals, &out.Principals
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthStatus.
+func (in *AuthStatus) DeepCopy() *AuthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AuthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapSource) DeepCopyInto(out *ConfigMapSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapSource.
+func (in *ConfigMapSource) DeepCopy() *ConfigMapSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapSourceStatus) DeepCopyInto(out *ConfigMapSourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapSourceStatus.
+func (in *ConfigMapSourceStatus) DeepCopy() *ConfigMapSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadLetter) DeepCopyInto(out *DeadLetter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadLetter.
+func (in *DeadLetter) DeepCopy() *DeadLetter {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadLetter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionBudget) DeepCopyInto(out *DisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionBudget.
+func (in *DisruptionBudget) DeepCopy() *DisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionBudgetStatus) DeepCopyInto(out *DisruptionBudgetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionBudgetStatus.
+func (in *DisruptionBudgetStatus) DeepCopy() *DisruptionBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Expose) DeepCopyInto(out *Expose) {
+	*out = *in
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(ExposeJWT)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Expose.
+func (in *Expose) DeepCopy() *Expose {
+	if in == nil {
+		return nil
+	}
+	out := new(Expose)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeJWT) DeepCopyInto(out *ExposeJWT) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeJWT.
+func (in *ExposeJWT) DeepCopy() *ExposeJWT {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeJWT)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Function.
+func (in *Function) DeepCopy() *Function {
+	if in == nil {
+		return nil
+	}
+	out := new(Function)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Function) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionList) DeepCopyInto(out *FunctionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Function, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionList.
+func (in *FunctionList) DeepCopy() *FunctionList {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FunctionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionRuntime) DeepCopyInto(out *FunctionRuntime) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionRuntime.
+func (in *FunctionRuntime) DeepCopy() *FunctionRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FunctionRuntime) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionRuntimeList) DeepCopyInto(out *FunctionRuntimeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FunctionRuntime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionRuntimeList.
+func (in *FunctionRuntimeList) DeepCopy() *FunctionRuntimeList {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionRuntimeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FunctionRuntimeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionRuntimeSpec) DeepCopyInto(out *FunctionRuntimeSpec) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionRuntimeSpec.
+func (in *FunctionRuntimeSpec) DeepCopy() *FunctionRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Source.DeepCopyInto(&out.Source)
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResourceConfiguration != nil {
+		in, out := &in.ResourceConfiguration, &out.ResourceConfiguration
+		*out = new(ResourceConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleConfig != nil {
+		in, out := &in.ScaleConfig, &out.ScaleConfig
+		*out = new(ScaleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DisruptionBudget != nil {
+		in, out := &in.DisruptionBudget, &out.DisruptionBudget
+		*out = new(DisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(Template)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretMounts != nil {
+		in, out := &in.SecretMounts, &out.SecretMounts
+		*out = make([]SecretMount, len(*in))
+		copy(*out, *in)
+	}
+	if in.PackageRegistryConfig != nil {
+		in, out := &in.PackageRegistryConfig, &out.PackageRegistryConfig
+		*out = new(PackageRegistryConfig)
+		**out = **in
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(Expose)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]Schedule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]Subscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Async != nil {
+		in, out := &in.Async, &out.Async
+		*out = new(AsyncInvocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(Auth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionSpec.
+func (in *FunctionSpec) DeepCopy() *FunctionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
+	*out = *in
+	if in.RuntimeImageDigestResolvedAt != nil {
+		in, out := &in.RuntimeImageDigestResolvedAt, &out.RuntimeImageDigestResolvedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RuntimeImageUpgrade != nil {
+		in, out := &in.RuntimeImageUpgrade, &out.RuntimeImageUpgrade
+		*out = new(RuntimeImageUpgrade)
+		**out = **in
+	}
+	if in.FunctionAnnotations != nil {
+		in, out := &in.FunctionAnnotations, &out.FunctionAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Repository = in.Repository
+	if in.GitRepository != nil {
+		in, out := &in.GitRepository, &out.GitRepository
+		*out = new(GitRepositoryStatus)
+		**out = **in
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapSourceStatus)
+		**out = **in
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCISourceStatus)
+		**out = **in
+	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(ArchiveSourceStatus)
+		**out = **in
+	}
+	if in.ContainerSecurityContext != nil {
+		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]ScheduleStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisruptionBudget != nil {
+		in, out := &in.DisruptionBudget, &out.DisruptionBudget
+		*out = new(DisruptionBudgetStatus)
+		**out = **in
+	}
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(AuthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
+func (in *FunctionStatus) DeepCopy() *FunctionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FunctionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositorySource) DeepCopyInto(out *GitRepositorySource) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(RepositoryAuth)
+		**out = **in
+	}
+	out.Repository = in.Repository
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositorySource.
+func (in *GitRepositorySource) DeepCopy() *GitRepositorySource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositorySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositoryStatus) DeepCopyInto(out *GitRepositoryStatus) {
+	*out = *in
+	out.Repository = in.Repository
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryStatus.
+func (in *GitRepositoryStatus) DeepCopy() *GitRepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineSource) DeepCopyInto(out *InlineSource) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineSource.
+func (in *InlineSource) DeepCopy() *InlineSource {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobSettings) DeepCopyInto(out *JobSettings) {
+	*out = *in
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobSettings.
+func (in *JobSettings) DeepCopy() *JobSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(JobSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobStatus) DeepCopyInto(out *JobStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
+func (in *JobStatus) DeepCopy() *JobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(NetworkPolicyIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(NetworkPolicyEgress)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Function.
-func (in *Function) DeepCopy() *Function {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicy.
+func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(Function)
+	out := new(NetworkPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Function) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyDestination) DeepCopyInto(out *NetworkPolicyDestination) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]NetworkPolicyPort, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyDestination.
+func (in *NetworkPolicyDestination) DeepCopy() *NetworkPolicyDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyDestination)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FunctionList) DeepCopyInto(out *FunctionList) {
+func (in *NetworkPolicyEgress) DeepCopyInto(out *NetworkPolicyEgress) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Function, len(*in))
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]NetworkPolicyDestination, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionList.
-func (in *FunctionList) DeepCopy() *FunctionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyEgress.
+func (in *NetworkPolicyEgress) DeepCopy() *NetworkPolicyEgress {
 	if in == nil {
 		return nil
 	}
-	out := new(FunctionList)
+	out := new(NetworkPolicyEgress)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FunctionList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
+func (in *NetworkPolicyIngress) DeepCopyInto(out *NetworkPolicyIngress) {
 	*out = *in
-	in.Source.DeepCopyInto(&out.Source)
-	if in.PodSecurityContext != nil {
-		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(v1.PodSecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ContainerSecurityContext != nil {
-		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(v1.SecurityContext)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]NetworkPolicySource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ResourceConfiguration != nil {
-		in, out := &in.ResourceConfiguration, &out.ResourceConfiguration
-		*out = new(ResourceConfiguration)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.ScaleConfig != nil {
-		in, out := &in.ScaleConfig, &out.ScaleConfig
-		*out = new(ScaleConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Template != nil {
-		in, out := &in.Template, &out.Template
-		*out = new(Template)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.SecretMounts != nil {
-		in, out := &in.SecretMounts, &out.SecretMounts
-		*out = make([]SecretMount, len(*in))
-		copy(*out, *in)
-	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionSpec.
-func (in *FunctionSpec) DeepCopy() *FunctionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyIngress.
+func (in *NetworkPolicyIngress) DeepCopy() *NetworkPolicyIngress {
 	if in == nil {
 		return nil
 	}
-	out := new(FunctionSpec)
+	out := new(NetworkPolicyIngress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
+func (in *NetworkPolicyPort) DeepCopyInto(out *NetworkPolicyPort) {
 	*out = *in
-	if in.FunctionAnnotations != nil {
-		in, out := &in.FunctionAnnotations, &out.FunctionAnnotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	out.Repository = in.Repository
-	if in.GitRepository != nil {
-		in, out := &in.GitRepository, &out.GitRepository
-		*out = new(GitRepositoryStatus)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyPort.
+func (in *NetworkPolicyPort) DeepCopy() *NetworkPolicyPort {
+	if in == nil {
+		return nil
 	}
-	if in.ContainerSecurityContext != nil {
-		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(v1.SecurityContext)
+	out := new(NetworkPolicyPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySource) DeepCopyInto(out *NetworkPolicySource) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.PodSecurityContext != nil {
-		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(v1.PodSecurityContext)
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
-func (in *FunctionStatus) DeepCopy() *FunctionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySource.
+func (in *NetworkPolicySource) DeepCopy() *NetworkPolicySource {
 	if in == nil {
 		return nil
 	}
-	out := new(FunctionStatus)
+	out := new(NetworkPolicySource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitRepositorySource) DeepCopyInto(out *GitRepositorySource) {
+func (in *OCISource) DeepCopyInto(out *OCISource) {
 	*out = *in
-	if in.Auth != nil {
-		in, out := &in.Auth, &out.Auth
-		*out = new(RepositoryAuth)
-		**out = **in
-	}
-	out.Repository = in.Repository
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositorySource.
-func (in *GitRepositorySource) DeepCopy() *GitRepositorySource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCISource.
+func (in *OCISource) DeepCopy() *OCISource {
 	if in == nil {
 		return nil
 	}
-	out := new(GitRepositorySource)
+	out := new(OCISource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitRepositoryStatus) DeepCopyInto(out *GitRepositoryStatus) {
+func (in *OCISourceStatus) DeepCopyInto(out *OCISourceStatus) {
 	*out = *in
-	out.Repository = in.Repository
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositoryStatus.
-func (in *GitRepositoryStatus) DeepCopy() *GitRepositoryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCISourceStatus.
+func (in *OCISourceStatus) DeepCopy() *OCISourceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GitRepositoryStatus)
+	out := new(OCISourceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InlineSource) DeepCopyInto(out *InlineSource) {
+func (in *PackageRegistryConfig) DeepCopyInto(out *PackageRegistryConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineSource.
-func (in *InlineSource) DeepCopy() *InlineSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRegistryConfig.
+func (in *PackageRegistryConfig) DeepCopy() *PackageRegistryConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(InlineSource)
+	out := new(PackageRegistryConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -329,6 +1012,46 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollingUpdate) DeepCopyInto(out *RollingUpdate) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollingUpdate.
+func (in *RollingUpdate) DeepCopy() *RollingUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(RollingUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeImageUpgrade) DeepCopyInto(out *RuntimeImageUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeImageUpgrade.
+func (in *RuntimeImageUpgrade) DeepCopy() *RuntimeImageUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeImageUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaleConfig) DeepCopyInto(out *ScaleConfig) {
 	*out = *in
@@ -342,6 +1065,13 @@ func (in *ScaleConfig) DeepCopyInto(out *ScaleConfig) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]ScaleTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleConfig.
@@ -354,6 +1084,66 @@ func (in *ScaleConfig) DeepCopy() *ScaleConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleTrigger) DeepCopyInto(out *ScaleTrigger) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleTrigger.
+func (in *ScaleTrigger) DeepCopy() *ScaleTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Schedule) DeepCopyInto(out *Schedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schedule.
+func (in *Schedule) DeepCopy() *Schedule {
+	if in == nil {
+		return nil
+	}
+	out := new(Schedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretMount) DeepCopyInto(out *SecretMount) {
 	*out = *in
@@ -380,8 +1170,23 @@ func (in *Source) DeepCopyInto(out *Source) {
 	if in.Inline != nil {
 		in, out := &in.Inline, &out.Inline
 		*out = new(InlineSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapSource)
+		**out = **in
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCISource)
 		**out = **in
 	}
+	if in.Archive != nil {
+		in, out := &in.Archive, &out.Archive
+		*out = new(ArchiveSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Source.
@@ -394,6 +1199,33 @@ func (in *Source) DeepCopy() *Source {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subscription) DeepCopyInto(out *Subscription) {
+	*out = *in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
+func (in *Subscription) DeepCopy() *Subscription {
+	if in == nil {
+		return nil
+	}
+	out := new(Subscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Template) DeepCopyInto(out *Template) {
 	*out = *in
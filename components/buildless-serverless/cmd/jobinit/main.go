@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
 	"github.com/vrischmann/envconfig"
 
 	"github.com/go-git/go-git/v5"
@@ -18,17 +23,34 @@ import (
 	crypto_ssh "golang.org/x/crypto/ssh"
 )
 
-const envPrefix = "APP"
+const (
+	envPrefix = "APP"
+
+	sourceTypeGit     = "git"
+	sourceTypeOCI     = "oci"
+	sourceTypeArchive = "archive"
+
+	ociPullTimeout         = 5 * time.Minute
+	archiveDownloadTimeout = 5 * time.Minute
+)
 
 type initConfig struct {
-	RepositoryURL       string
-	RepositoryReference string
-	RepositoryCommit    string
+	SourceType          string `envconfig:"default=git"`
 	DestinationPath     string
+	RepositoryURL       string                                `envconfig:"optional"`
+	RepositoryReference string                                `envconfig:"optional"`
+	RepositoryCommit    string                                `envconfig:"optional"`
 	RepositoryAuthType  serverlessv1alpha2.RepositoryAuthType `envconfig:"optional"`
 	RepositoryUsername  string                                `envconfig:"optional"`
 	RepositoryPassword  string                                `envconfig:"optional"`
 	RepositoryKey       string                                `envconfig:"optional"`
+	OCIReference        string                                `envconfig:"optional"`
+	OCIDockerConfigPath string                                `envconfig:"optional"`
+	ArchiveURL          string                                `envconfig:"optional"`
+	ArchiveSHA256       string                                `envconfig:"optional"`
+	ArchiveRevision     string                                `envconfig:"optional"`
+	ArchiveUsername     string                                `envconfig:"optional"`
+	ArchivePassword     string                                `envconfig:"optional"`
 }
 
 func main() {
@@ -39,6 +61,19 @@ func main() {
 		log.Fatalf("while reading env variables: %s", err.Error())
 	}
 
+	switch cfg.SourceType {
+	case sourceTypeGit:
+		fetchGitRepository(cfg)
+	case sourceTypeOCI:
+		fetchOCIArtifact(cfg)
+	case sourceTypeArchive:
+		fetchArchive(cfg)
+	default:
+		log.Fatalf("unknown source type: %s", cfg.SourceType)
+	}
+}
+
+func fetchGitRepository(cfg initConfig) {
 	auth, err := chooseAuth(cfg)
 	failOnErr(err, "unable to choose auth")
 
@@ -55,6 +90,60 @@ func main() {
 	log.Printf("Cloned repository: %s, from commit: %s, to path: %s", cfg.RepositoryURL, cfg.RepositoryCommit, cfg.DestinationPath)
 }
 
+func fetchOCIArtifact(cfg initConfig) {
+	var creds *oci.Credentials
+	if cfg.OCIDockerConfigPath != "" {
+		var err error
+		creds, err = oci.LoadCredentials(cfg.OCIDockerConfigPath)
+		failOnErr(err, "unable to load registry credentials")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ociPullTimeout)
+	defer cancel()
+
+	log.Printf("Pull artifact: %s...\n", cfg.OCIReference)
+	files, err := oci.FetchFiles(ctx, cfg.OCIReference, creds)
+	failOnErr(err, "while pulling artifact")
+
+	err = writeFiles(cfg.DestinationPath, files)
+	failOnErr(err, "while writing artifact files")
+
+	log.Printf("Pulled artifact: %s, files: %d, to path: %s", cfg.OCIReference, len(files), cfg.DestinationPath)
+}
+
+func fetchArchive(cfg initConfig) {
+	var auth *archive.Auth
+	if cfg.ArchiveUsername != "" {
+		auth = archive.NewBasicAuth(cfg.ArchiveUsername, cfg.ArchivePassword)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), archiveDownloadTimeout)
+	defer cancel()
+
+	log.Printf("Download archive: %s, revision: %s...\n", cfg.ArchiveURL, cfg.ArchiveRevision)
+	data, err := archive.Download(ctx, cfg.ArchiveURL, auth, cfg.ArchiveSHA256)
+	failOnErr(err, "while downloading archive")
+
+	err = archive.Unpack(data, cfg.DestinationPath)
+	failOnErr(err, "while unpacking archive")
+
+	log.Printf("Downloaded archive: %s, to path: %s", cfg.ArchiveURL, cfg.DestinationPath)
+}
+
+func writeFiles(destination string, files []oci.File) error {
+	for _, file := range files {
+		// paths are validated to be local by the oci package
+		filePath := filepath.Join(destination, file.Path)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filePath, file.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func clone(c initConfig, auth transport.AuthMethod) error {
 	r, err := git.PlainClone(c.DestinationPath, false, &git.CloneOptions{
 		URL:           c.RepositoryURL,
@@ -32,11 +32,16 @@ import (
 	"github.com/go-logr/zapr"
 	logconfig "github.com/kyma-project/manager-toolkit/logging/config"
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/async"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/catalog"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
 	serverlessmetrics "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
 	orphaned_resources "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/orphaned-resources"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/upgrade"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/logging"
 	"github.com/vrischmann/envconfig"
@@ -136,6 +141,8 @@ func main() {
 					&serverlessv1alpha2.Function{},
 					&corev1.Secret{},
 					&corev1.ConfigMap{},
+					&corev1.Pod{},
+					&corev1.ServiceAccount{},
 				},
 			},
 		},
@@ -147,6 +154,11 @@ func main() {
 	}
 
 	serverlessmetrics.Register()
+	serverlessmetrics.RegisterDeprecatedRuntimes(mgr.GetClient(), cfg.RuntimeLifecycle)
+	rollout := upgrade.NewRollout(cfg.RuntimeUpgrade)
+	serverlessmetrics.RegisterRuntimeImageUpgrade(rollout)
+	async.RegisterMetrics()
+	asyncQueue := async.NewQueue(ctx, logWithCtx.Named("async"), cfg.Async)
 
 	healthHandler, healthEventsCh, healthResponseCh := controller.NewHealthChecker(cfg.Healthz.LivenessTimeout, logWithCtx.Named("healthz"))
 	if err := mgr.AddHealthzCheck("healthz", healthHandler.Checker); err != nil {
@@ -159,13 +171,17 @@ func main() {
 	}
 
 	fnCtrl, err := (&controller.FunctionReconciler{
-		Client:        mgr.GetClient(),
-		Scheme:        mgr.GetScheme(),
-		Log:           logWithCtx,
-		Config:        cfg,
-		EventRecorder: mgr.GetEventRecorderFor(serverlessv1alpha2.FunctionControllerValue),
-		GitChecker:    git.NewAsyncLatestCommitChecker(ctx, logWithCtx),
-		HealthCh:      healthResponseCh,
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Log:            logWithCtx,
+		Config:         cfg,
+		EventRecorder:  mgr.GetEventRecorderFor(serverlessv1alpha2.FunctionControllerValue),
+		GitChecker:     git.NewAsyncLatestCommitChecker(ctx, logWithCtx),
+		ArchiveChecker: archive.NewAsyncLatestRevisionChecker(ctx, logWithCtx),
+		Rollout:        rollout,
+		SignatureCache: oci.NewSignatureCache(),
+		AsyncQueue:     asyncQueue,
+		HealthCh:       healthResponseCh,
 	}).SetupWithManager(mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Function")
@@ -187,6 +203,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// pending Functions are requeued when the rollout lets them upgrade
+	err = fnCtrl.Watch(source.Channel(rollout.Events(), &handler.EnqueueRequestForObject{}))
+	if err != nil {
+		setupLog.Error(err, "unable to watch runtime image upgrade events channel")
+		os.Exit(1)
+	}
+
 	// disable default log to prevent http server from logging returned status codes
 	log.SetOutput(io.Discard)
 
@@ -198,6 +221,30 @@ func main() {
 		}
 	}()
 
+	// callers are found by IP with the field selector served by the API server, pods aren't cached
+	if err := mgr.Add(async.NewServer(logWithCtx.Named("async"), mgr.GetClient(), asyncQueue, async.NewTokenVerifier(), cfg.Async.Port)); err != nil {
+		setupLog.Error(err, "unable to set up async HTTP server")
+		os.Exit(1)
+	}
+
+	if rollout.IsStaged() {
+		rolloutStore, err := upgrade.NewStore(mgr.GetClient(), rollout, cfg.RuntimeUpgrade.StateConfigMap, logWithCtx.Named("upgrade"))
+		if err != nil {
+			setupLog.Error(err, "unable to create runtime image upgrade store")
+			os.Exit(1)
+		}
+		if err := mgr.Add(rolloutStore); err != nil {
+			setupLog.Error(err, "unable to set up runtime image upgrade store")
+			os.Exit(1)
+		}
+	}
+
+	// built-in runtimes are seeded into the catalog so their images are configured with FunctionRuntimes
+	if err := mgr.Add(catalog.NewSeeder(mgr.GetClient(), cfg.Images, logWithCtx.Named("catalog"))); err != nil {
+		setupLog.Error(err, "unable to set up FunctionRuntime catalog")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
@@ -0,0 +1,347 @@
+package async
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/pkg/errors"
+)
+
+const (
+	// jwksCacheTTL limits how long keys are used before the JWKS is fetched again to pick up rotated keys
+	jwksCacheTTL = 5 * time.Minute
+	// jwksRefreshInterval limits fetching the JWKS for tokens signed with unknown keys
+	jwksRefreshInterval = 30 * time.Second
+	// jwksFetchTimeout limits fetching the JWKS, the caller waits for it before the request is queued
+	jwksFetchTimeout = 10 * time.Second
+	// maxJWKSSize limits the JWKS kept in memory
+	maxJWKSSize = 1 << 20
+	// clockSkew tolerates clocks of the issuer and the cluster being slightly out of sync
+	clockSkew = time.Minute
+)
+
+// ErrInvalidToken is returned when the JWT of the request isn't accepted by the function's spec.auth.jwt
+var ErrInvalidToken = errors.New("invalid token")
+
+// ErrJWKSUnavailable is returned when the keys of the issuer can't be fetched to verify the JWT
+var ErrJWKSUnavailable = errors.New("JWKS unavailable")
+
+// TokenVerifier validates JWTs of async requests the same way the function's RequestAuthentication does on the delivery,
+// so requests which can't be delivered aren't queued
+type TokenVerifier struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	keySets map[string]*jwks
+}
+
+type jwks struct {
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func NewTokenVerifier() *TokenVerifier {
+	return &TokenVerifier{
+		client:  &http.Client{Timeout: jwksFetchTimeout},
+		now:     time.Now,
+		keySets: map[string]*jwks{},
+	}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Issuer    string       `json:"iss"`
+	ExpiresAt *json.Number `json:"exp"`
+	NotBefore *json.Number `json:"nbf"`
+}
+
+// Verify checks the bearer token from the Authorization header against the issuer and the JWKS of the function's spec.auth.jwt
+// ErrJWKSUnavailable is returned when the keys can't be fetched, other errors wrap ErrInvalidToken
+func (v *TokenVerifier) Verify(ctx context.Context, jwt *serverlessv1alpha2.ExposeJWT, authorization string) error {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || token == "" {
+		return errors.Wrap(ErrInvalidToken, "missing bearer token")
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.Wrap(ErrInvalidToken, "malformed token")
+	}
+
+	header := jwtHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return errors.Wrap(ErrInvalidToken, "malformed token header")
+	}
+	claims := jwtClaims{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return errors.Wrap(ErrInvalidToken, "malformed token claims")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.Wrap(ErrInvalidToken, "malformed token signature")
+	}
+
+	if claims.Issuer != jwt.Issuer {
+		return errors.Wrapf(ErrInvalidToken, "issuer %q isn't accepted", claims.Issuer)
+	}
+	if err := v.verifyTime(claims); err != nil {
+		return err
+	}
+
+	key, err := v.key(ctx, jwt.JWKSURI, header.Kid)
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return errors.Wrap(ErrInvalidToken, err.Error())
+	}
+	return nil
+}
+
+func (v *TokenVerifier) verifyTime(claims jwtClaims) error {
+	now := v.now()
+	if claims.ExpiresAt != nil {
+		exp, err := claims.ExpiresAt.Int64()
+		if err != nil {
+			return errors.Wrap(ErrInvalidToken, "malformed exp claim")
+		}
+		if now.After(time.Unix(exp, 0).Add(clockSkew)) {
+			return errors.Wrap(ErrInvalidToken, "token is expired")
+		}
+	}
+	if claims.NotBefore != nil {
+		nbf, err := claims.NotBefore.Int64()
+		if err != nil {
+			return errors.Wrap(ErrInvalidToken, "malformed nbf claim")
+		}
+		if now.Add(clockSkew).Before(time.Unix(nbf, 0)) {
+			return errors.Wrap(ErrInvalidToken, "token isn't valid yet")
+		}
+	}
+	return nil
+}
+
+// key finds the key signing the token in the cached JWKS
+// the JWKS is fetched again when the key isn't found, so keys rotated by the issuer are used without waiting for the cache to expire,
+// but not more often than every jwksRefreshInterval to not flood the issuer with tokens signed with unknown keys
+func (v *TokenVerifier) key(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	cached, ok := v.keySets[jwksURI]
+	if ok && v.now().Sub(cached.fetchedAt) < jwksCacheTTL {
+		key, found := findKey(cached.keys, kid)
+		if found {
+			return key, nil
+		}
+		if v.now().Sub(cached.fetchedAt) < jwksRefreshInterval {
+			return nil, errors.Wrapf(ErrInvalidToken, "key %q not found in JWKS", kid)
+		}
+	}
+
+	keys, err := v.fetchKeys(ctx, jwksURI)
+	if err != nil {
+		return nil, errors.Wrap(ErrJWKSUnavailable, err.Error())
+	}
+	v.keySets[jwksURI] = &jwks{keys: keys, fetchedAt: v.now()}
+
+	key, found := findKey(keys, kid)
+	if !found {
+		return nil, errors.Wrapf(ErrInvalidToken, "key %q not found in JWKS", kid)
+	}
+	return key, nil
+}
+
+// findKey returns the key with the given ID, tokens without the ID are accepted when the JWKS has a single key
+func findKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (v *TokenVerifier) fetchKeys(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create JWKS request")
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch JWKS")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	set := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&set); err != nil {
+		return nil, errors.Wrap(err, "failed to decode JWKS")
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// keys of unsupported types are skipped, tokens signed with them are rejected as signed with unknown keys
+		if key, err := parseKey(jwk); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func parseKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RSA modulus")
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid RSA exponent")
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		curve, err := ellipticCurve(jwk.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid EC x coordinate")
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid EC y coordinate")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC point isn't on the curve")
+		}
+		return key, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func ellipticCurve(crv string) (elliptic.Curve, error) {
+	switch crv {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, errors.Errorf("unsupported curve %q", crv)
+	}
+}
+
+// verifySignature checks the signature with the algorithms supported by Istio's RequestAuthentication
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	hash, err := signatureHash(alg)
+	if err != nil {
+		return err
+	}
+	hasher := hash.New()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.Errorf("key doesn't match algorithm %s", alg)
+		}
+		if alg[:2] == "PS" {
+			return rsa.VerifyPSS(rsaKey, hash, digest, signature, nil)
+		}
+		return rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+	default:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve.Params().Name != ecdsaCurves[alg] {
+			return errors.Errorf("key doesn't match algorithm %s", alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	}
+}
+
+// ecdsaCurves maps ECDSA algorithms to curves of their keys
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+func signatureHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	default:
+		return 0, errors.Errorf("unsupported algorithm %q", alg)
+	}
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
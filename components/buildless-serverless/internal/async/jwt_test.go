@@ -0,0 +1,245 @@
+package async
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://issuer.example.com"
+
+// testTokenIssuer serves the JWKS with its keys and signs tokens like the identity provider accepted by functions
+type testTokenIssuer struct {
+	jwksURI   string
+	rsaKey    *rsa.PrivateKey
+	ecKey     *ecdsa.PrivateKey
+	fetches   atomic.Int32
+	available atomic.Bool
+}
+
+func newTestTokenIssuer(t *testing.T) *testTokenIssuer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer := &testTokenIssuer{rsaKey: rsaKey, ecKey: ecKey}
+	issuer.available.Store(true)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.fetches.Add(1)
+		if !issuer.available.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": "rsa-key",
+					"use": "sig",
+					"n":   encodeSegment(rsaKey.N.Bytes()),
+					"e":   encodeSegment(big.NewInt(int64(rsaKey.E)).Bytes()),
+				},
+				{
+					"kty": "EC",
+					"kid": "ec-key",
+					"crv": "P-256",
+					"x":   encodeSegment(ecKey.X.FillBytes(make([]byte, 32))),
+					"y":   encodeSegment(ecKey.Y.FillBytes(make([]byte, 32))),
+				},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	issuer.jwksURI = server.URL
+	return issuer
+}
+
+func (i *testTokenIssuer) jwt() *serverlessv1alpha2.ExposeJWT {
+	return &serverlessv1alpha2.ExposeJWT{Issuer: testIssuer, JWKSURI: i.jwksURI}
+}
+
+// token signs the claims with the RSA key and returns them as the Authorization header
+func (i *testTokenIssuer) token(t *testing.T, claims map[string]interface{}) string {
+	return "Bearer " + i.sign(t, "RS256", "rsa-key", claims)
+}
+
+func (i *testTokenIssuer) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + encodeSegment(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss": testIssuer,
+		"sub": "client",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	t.Run("accept token signed with RSA key", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims()))
+
+		require.NoError(t, err)
+	})
+	t.Run("accept token signed with EC key", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), "Bearer "+issuer.sign(t, "ES256", "ec-key", validClaims()))
+
+		require.NoError(t, err)
+	})
+	t.Run("reject token from other issuer", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		claims := validClaims()
+		claims["iss"] = "https://other-issuer.example.com"
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), issuer.token(t, claims))
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.ErrorContains(t, err, `issuer "https://other-issuer.example.com" isn't accepted`)
+	})
+	t.Run("reject expired token", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), issuer.token(t, claims))
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.ErrorContains(t, err, "token is expired")
+	})
+	t.Run("reject token which isn't valid yet", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		claims := validClaims()
+		claims["nbf"] = time.Now().Add(time.Hour).Unix()
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), issuer.token(t, claims))
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("reject token with invalid signature", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		token := issuer.token(t, validClaims())
+		claims := validClaims()
+		claims["sub"] = "admin"
+		forged := issuer.token(t, claims)
+		// the claims of the forged token with the signature of the issued one
+		token = forged[:strings.LastIndex(forged, ".")] + token[strings.LastIndex(token, "."):]
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), token)
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("reject token signed with unknown key", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), "Bearer "+issuer.sign(t, "RS256", "rotated-key", validClaims()))
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.ErrorContains(t, err, `key "rotated-key" not found in JWKS`)
+	})
+	t.Run("reject token signed with key of other algorithm", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), "Bearer "+issuer.sign(t, "ES256", "rsa-key", validClaims()))
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("reject unsigned token", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		token := issuer.sign(t, "none", "rsa-key", validClaims())
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), "Bearer "+token)
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("reject request without bearer token", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), "")
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.Zero(t, issuer.fetches.Load())
+	})
+	t.Run("return error when JWKS is unavailable", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		issuer.available.Store(false)
+
+		err := NewTokenVerifier().Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims()))
+
+		require.ErrorIs(t, err, ErrJWKSUnavailable)
+		require.NotErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("cache JWKS", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		verifier := NewTokenVerifier()
+
+		require.NoError(t, verifier.Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims())))
+		require.NoError(t, verifier.Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims())))
+
+		require.Equal(t, int32(1), issuer.fetches.Load())
+	})
+	t.Run("fetch JWKS again when cache expires", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		verifier := NewTokenVerifier()
+		now := time.Now()
+		verifier.now = func() time.Time { return now }
+		require.NoError(t, verifier.Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims())))
+
+		now = now.Add(jwksCacheTTL)
+		err := verifier.Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims()))
+
+		require.NoError(t, err)
+		require.Equal(t, int32(2), issuer.fetches.Load())
+	})
+	t.Run("don't fetch JWKS again for unknown keys right after it was fetched", func(t *testing.T) {
+		issuer := newTestTokenIssuer(t)
+		verifier := NewTokenVerifier()
+		require.NoError(t, verifier.Verify(context.Background(), issuer.jwt(), issuer.token(t, validClaims())))
+
+		err := verifier.Verify(context.Background(), issuer.jwt(), "Bearer "+issuer.sign(t, "RS256", "rotated-key", validClaims()))
+
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.Equal(t, int32(1), issuer.fetches.Load())
+	})
+}
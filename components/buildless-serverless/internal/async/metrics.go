@@ -0,0 +1,54 @@
+package async
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	MessagesQueuedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "serverless_function_async_messages_queued_total",
+			Help: "Total number of asynchronous requests accepted for delivery to the function",
+		},
+		[]string{"namespace", "function"},
+	)
+	MessagesDeliveredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "serverless_function_async_messages_delivered_total",
+			Help: "Total number of asynchronous requests successfully delivered to the function",
+		},
+		[]string{"namespace", "function"},
+	)
+	MessagesRetriedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "serverless_function_async_messages_retried_total",
+			Help: "Total number of delivery retries of asynchronous requests",
+		},
+		[]string{"namespace", "function"},
+	)
+	MessagesDeadLetteredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "serverless_function_async_messages_dead_lettered_total",
+			Help: "Total number of asynchronous requests forwarded to the dead letter after all retries were exhausted",
+		},
+		[]string{"namespace", "function"},
+	)
+	MessagesDroppedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "serverless_function_async_messages_dropped_total",
+			Help: "Total number of asynchronous requests which were neither delivered nor dead-lettered",
+		},
+		[]string{"namespace", "function"},
+	)
+)
+
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(
+		MessagesQueuedTotal,
+		MessagesDeliveredTotal,
+		MessagesRetriedTotal,
+		MessagesDeadLetteredTotal,
+		MessagesDroppedTotal,
+	)
+}
@@ -0,0 +1,279 @@
+package async
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	defaultMaxRetries  = 3
+	defaultBackoff     = time.Second
+	defaultConcurrency = 1
+
+	DeadLetterFunctionHeader = "X-Serverless-Dead-Letter-Function"
+	DeadLetterReasonHeader   = "X-Serverless-Dead-Letter-Reason"
+	DeadLetterAttemptsHeader = "X-Serverless-Dead-Letter-Attempts"
+)
+
+var ErrQueueFull = errors.New("queue is full")
+
+// Message is the request waiting for the delivery to the function
+type Message struct {
+	Path   string
+	Header http.Header
+	Body   []byte
+
+	function types.NamespacedName
+	policy   deliveryPolicy
+}
+
+// deliveryPolicy is taken from the function's spec when the message is queued,
+// so the message is delivered the way the function was configured when it was accepted
+type deliveryPolicy struct {
+	maxRetries    int
+	backoff       time.Duration
+	deadLetterURL string
+}
+
+// Queue keeps an in-memory queue for every function invoked asynchronously
+// and delivers queued messages to functions' Services with the concurrency configured in their specs
+// queues aren't persisted, so messages waiting for the delivery are lost when the controller restarts (at-most-once delivery)
+type Queue struct {
+	ctx    context.Context
+	log    *zap.SugaredLogger
+	config config.AsyncConfig
+	client *http.Client
+
+	// serviceURL is replaced in tests to deliver messages to the test server
+	serviceURL func(namespace, name string) string
+
+	mu     sync.Mutex
+	queues map[types.NamespacedName]*functionQueue
+}
+
+type functionQueue struct {
+	messages chan *Message
+	// workers are stopped by closing their channels when the concurrency is decreased
+	workers []chan struct{}
+	// ctx is cancelled when the function is forgotten to stop its deliveries in progress
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewQueue(ctx context.Context, log *zap.SugaredLogger, config config.AsyncConfig) *Queue {
+	return &Queue{
+		ctx:        ctx,
+		log:        log,
+		config:     config,
+		client:     &http.Client{Timeout: config.DeliveryTimeout},
+		serviceURL: resources.ServiceURL,
+		queues:     map[types.NamespacedName]*functionQueue{},
+	}
+}
+
+// Enqueue adds the message to the function's queue and adjusts the number of its workers to the function's concurrency
+// ErrQueueFull is returned when the function has too many messages waiting for the delivery
+func (q *Queue) Enqueue(f *serverlessv1alpha2.Function, msg *Message) error {
+	msg.function = types.NamespacedName{Namespace: f.GetNamespace(), Name: f.GetName()}
+	msg.policy = q.deliveryPolicy(f)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	fq, ok := q.queues[msg.function]
+	if !ok {
+		ctx, cancel := context.WithCancel(q.ctx)
+		fq = &functionQueue{
+			messages: make(chan *Message, q.config.QueueSize),
+			ctx:      ctx,
+			cancel:   cancel,
+		}
+		q.queues[msg.function] = fq
+	}
+	q.resize(fq, concurrency(f))
+
+	select {
+	case fq.messages <- msg:
+		MessagesQueuedTotal.WithLabelValues(msg.function.Namespace, msg.function.Name).Inc()
+		return nil
+	default:
+		MessagesDroppedTotal.WithLabelValues(msg.function.Namespace, msg.function.Name).Inc()
+		return ErrQueueFull
+	}
+}
+
+// Forget stops workers of the deleted function or the function which doesn't enable async invocation anymore
+// and drops its messages waiting for the delivery
+func (q *Queue) Forget(function types.NamespacedName) {
+	if q == nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	fq, ok := q.queues[function]
+	if !ok {
+		return
+	}
+	delete(q.queues, function)
+	fq.cancel()
+	q.resize(fq, 0)
+	for {
+		select {
+		case <-fq.messages:
+			MessagesDroppedTotal.WithLabelValues(function.Namespace, function.Name).Inc()
+		default:
+			return
+		}
+	}
+}
+
+func (q *Queue) deliveryPolicy(f *serverlessv1alpha2.Function) deliveryPolicy {
+	policy := deliveryPolicy{
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+	}
+	async := f.Spec.Async
+	if async == nil {
+		return policy
+	}
+	if async.MaxRetries != nil {
+		policy.maxRetries = int(*async.MaxRetries)
+	}
+	if async.Backoff != nil && async.Backoff.Duration > 0 {
+		policy.backoff = async.Backoff.Duration
+	}
+	if async.DeadLetter != nil {
+		switch {
+		case async.DeadLetter.Function != "":
+			policy.deadLetterURL = q.serviceURL(f.GetNamespace(), async.DeadLetter.Function)
+		// the URL is validated with the function, it's checked again to never send messages outside of the function's namespace
+		case resources.IsNamespaceServiceURL(async.DeadLetter.URL, f.GetNamespace()):
+			policy.deadLetterURL = async.DeadLetter.URL
+		}
+	}
+	return policy
+}
+
+func concurrency(f *serverlessv1alpha2.Function) int {
+	if f.Spec.Async == nil || f.Spec.Async.Concurrency == nil || *f.Spec.Async.Concurrency < 1 {
+		return defaultConcurrency
+	}
+	return int(*f.Spec.Async.Concurrency)
+}
+
+// resize starts or stops workers of the function's queue, it must be called with the queue's lock held
+func (q *Queue) resize(fq *functionQueue, workers int) {
+	for len(fq.workers) < workers {
+		stop := make(chan struct{})
+		fq.workers = append(fq.workers, stop)
+		go q.work(fq.ctx, fq.messages, stop)
+	}
+	for len(fq.workers) > workers {
+		last := len(fq.workers) - 1
+		close(fq.workers[last])
+		fq.workers = fq.workers[:last]
+	}
+}
+
+func (q *Queue) work(ctx context.Context, messages <-chan *Message, stop <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case msg := <-messages:
+			q.deliver(ctx, msg)
+		}
+	}
+}
+
+// deliver sends the message to the function, failed deliveries are retried with the exponential backoff
+// and the message is forwarded to the dead letter when all retries are exhausted
+func (q *Queue) deliver(ctx context.Context, msg *Message) {
+	namespace, name := msg.function.Namespace, msg.function.Name
+	url := q.serviceURL(namespace, name) + msg.Path
+	backoff := msg.policy.backoff
+
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		err = q.send(ctx, url, msg.Header, msg.Body)
+		if err == nil {
+			MessagesDeliveredTotal.WithLabelValues(namespace, name).Inc()
+			return
+		}
+		if attempts > msg.policy.maxRetries {
+			break
+		}
+
+		q.log.Debugf("delivery of async request to function '%s/%s' failed, retrying in %s: %s", namespace, name, backoff, err)
+		MessagesRetriedTotal.WithLabelValues(namespace, name).Inc()
+		select {
+		case <-ctx.Done():
+			MessagesDroppedTotal.WithLabelValues(namespace, name).Inc()
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, q.config.MaxBackoff)
+	}
+
+	q.log.Infof("delivery of async request to function '%s/%s' failed after %d attempts: %s", namespace, name, attempts, err)
+	if msg.policy.deadLetterURL == "" {
+		MessagesDroppedTotal.WithLabelValues(namespace, name).Inc()
+		return
+	}
+
+	header := msg.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(DeadLetterFunctionHeader, msg.function.String())
+	header.Set(DeadLetterReasonHeader, err.Error())
+	header.Set(DeadLetterAttemptsHeader, strconv.Itoa(attempts))
+	if err := q.send(ctx, msg.policy.deadLetterURL, header, msg.Body); err != nil {
+		q.log.Errorf("failed to forward async request of function '%s/%s' to dead letter: %s", namespace, name, err)
+		MessagesDroppedTotal.WithLabelValues(namespace, name).Inc()
+		return
+	}
+	MessagesDeadLetteredTotal.WithLabelValues(namespace, name).Inc()
+}
+
+func (q *Queue) send(ctx context.Context, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package async
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+type receivedRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+// testSink records requests and responds with statuses returned by respond
+type testSink struct {
+	mu       sync.Mutex
+	requests []receivedRequest
+	respond  func(path string) int
+}
+
+func (s *testSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, receivedRequest{path: r.URL.Path, header: r.Header, body: string(body)})
+	s.mu.Unlock()
+	w.WriteHeader(s.respond(r.URL.Path))
+}
+
+func (s *testSink) received() []receivedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedRequest{}, s.requests...)
+}
+
+func newTestQueue(t *testing.T, sink *testSink) *Queue {
+	server := httptest.NewServer(sink)
+	t.Cleanup(server.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	q := NewQueue(ctx, zap.NewNop().Sugar(), config.AsyncConfig{
+		QueueSize:       2,
+		DeliveryTimeout: time.Second,
+		MaxBackoff:      10 * time.Millisecond,
+	})
+	q.serviceURL = func(_, name string) string {
+		return server.URL + "/" + name
+	}
+	return q
+}
+
+func asyncFunction(name string, async *serverlessv1alpha2.AsyncInvocation) *serverlessv1alpha2.Function {
+	return &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+		},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Async: async,
+		},
+	}
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("deliver message to the function", func(t *testing.T) {
+		sink := &testSink{respond: func(string) int { return http.StatusOK }}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("deliver-fn", &serverlessv1alpha2.AsyncInvocation{})
+
+		err := q.Enqueue(f, &Message{
+			Path:   "/orders",
+			Header: http.Header{"Content-Type": []string{"application/json"}},
+			Body:   []byte(`{"id": 1}`),
+		})
+
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(MessagesDeliveredTotal.WithLabelValues("test-namespace", "deliver-fn")) == 1
+		}, time.Second, 10*time.Millisecond)
+		received := sink.received()
+		require.Len(t, received, 1)
+		require.Equal(t, "/deliver-fn/orders", received[0].path)
+		require.Equal(t, "application/json", received[0].header.Get("Content-Type"))
+		require.Equal(t, `{"id": 1}`, received[0].body)
+		require.Equal(t, float64(1), testutil.ToFloat64(MessagesQueuedTotal.WithLabelValues("test-namespace", "deliver-fn")))
+	})
+	t.Run("retry failed delivery", func(t *testing.T) {
+		attempts := 0
+		sink := &testSink{respond: func(string) int {
+			attempts++
+			if attempts < 3 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		}}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("retry-fn", &serverlessv1alpha2.AsyncInvocation{
+			MaxRetries: ptr.To[int32](3),
+			Backoff:    &metav1.Duration{Duration: time.Millisecond},
+		})
+
+		err := q.Enqueue(f, &Message{Body: []byte("hello")})
+
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(MessagesDeliveredTotal.WithLabelValues("test-namespace", "retry-fn")) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Len(t, sink.received(), 3)
+		require.Equal(t, float64(2), testutil.ToFloat64(MessagesRetriedTotal.WithLabelValues("test-namespace", "retry-fn")))
+	})
+	t.Run("forward message to dead letter function when retries are exhausted", func(t *testing.T) {
+		sink := &testSink{respond: func(path string) int {
+			if path == "/dead-letter-fn" {
+				return http.StatusOK
+			}
+			return http.StatusInternalServerError
+		}}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("failing-fn", &serverlessv1alpha2.AsyncInvocation{
+			MaxRetries: ptr.To[int32](1),
+			Backoff:    &metav1.Duration{Duration: time.Millisecond},
+			DeadLetter: &serverlessv1alpha2.DeadLetter{Function: "dead-letter-fn"},
+		})
+
+		err := q.Enqueue(f, &Message{Body: []byte("hello")})
+
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(MessagesDeadLetteredTotal.WithLabelValues("test-namespace", "failing-fn")) == 1
+		}, time.Second, 10*time.Millisecond)
+		received := sink.received()
+		require.Len(t, received, 3)
+		deadLettered := received[2]
+		require.Equal(t, "/dead-letter-fn", deadLettered.path)
+		require.Equal(t, "hello", deadLettered.body)
+		require.Equal(t, "test-namespace/failing-fn", deadLettered.header.Get(DeadLetterFunctionHeader))
+		require.Equal(t, "unexpected status code 500", deadLettered.header.Get(DeadLetterReasonHeader))
+		require.Equal(t, "2", deadLettered.header.Get(DeadLetterAttemptsHeader))
+	})
+	t.Run("drop message without dead letter when retries are exhausted", func(t *testing.T) {
+		sink := &testSink{respond: func(string) int { return http.StatusInternalServerError }}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("dropping-fn", &serverlessv1alpha2.AsyncInvocation{
+			MaxRetries: ptr.To[int32](0),
+		})
+
+		err := q.Enqueue(f, &Message{Body: []byte("hello")})
+
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(MessagesDroppedTotal.WithLabelValues("test-namespace", "dropping-fn")) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Len(t, sink.received(), 1)
+	})
+	t.Run("drop message with dead letter url outside of the function's namespace", func(t *testing.T) {
+		sink := &testSink{respond: func(string) int { return http.StatusInternalServerError }}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("foreign-dead-letter-fn", &serverlessv1alpha2.AsyncInvocation{
+			MaxRetries: ptr.To[int32](0),
+			DeadLetter: &serverlessv1alpha2.DeadLetter{URL: "http://169.254.169.254/latest/meta-data"},
+		})
+
+		err := q.Enqueue(f, &Message{Body: []byte("hello")})
+
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(MessagesDroppedTotal.WithLabelValues("test-namespace", "foreign-dead-letter-fn")) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Len(t, sink.received(), 1)
+	})
+	t.Run("reject message when queue is full", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		sink := &testSink{respond: func(string) int {
+			<-block
+			return http.StatusOK
+		}}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("busy-fn", &serverlessv1alpha2.AsyncInvocation{})
+
+		// the first message is taken by the worker, the next two fill the queue
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		require.Eventually(t, func() bool {
+			return len(sink.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		err := q.Enqueue(f, &Message{})
+
+		require.ErrorIs(t, err, ErrQueueFull)
+	})
+	t.Run("adjust workers to the function's concurrency", func(t *testing.T) {
+		q := newTestQueue(t, &testSink{respond: func(string) int { return http.StatusOK }})
+		f := asyncFunction("concurrent-fn", &serverlessv1alpha2.AsyncInvocation{Concurrency: ptr.To[int32](3)})
+
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		require.Len(t, q.queues[types.NamespacedName{Namespace: "test-namespace", Name: "concurrent-fn"}].workers, 3)
+
+		f.Spec.Async.Concurrency = ptr.To[int32](1)
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		require.Len(t, q.queues[types.NamespacedName{Namespace: "test-namespace", Name: "concurrent-fn"}].workers, 1)
+	})
+}
+
+func TestQueue_Forget(t *testing.T) {
+	t.Run("stop workers and drop messages of forgotten function", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		sink := &testSink{respond: func(string) int {
+			<-block
+			return http.StatusOK
+		}}
+		q := newTestQueue(t, sink)
+		f := asyncFunction("deleted-fn", &serverlessv1alpha2.AsyncInvocation{})
+		function := types.NamespacedName{Namespace: "test-namespace", Name: "deleted-fn"}
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		require.Eventually(t, func() bool {
+			return len(sink.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(t, q.Enqueue(f, &Message{}))
+		require.NoError(t, q.Enqueue(f, &Message{}))
+
+		q.Forget(function)
+
+		require.NotContains(t, q.queues, function)
+		// the delivery in progress is cancelled with the queued messages
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(MessagesDroppedTotal.WithLabelValues("test-namespace", "deleted-fn")) == 3
+		}, time.Second, 10*time.Millisecond)
+		require.Len(t, sink.received(), 1)
+	})
+	t.Run("ignore unknown function", func(t *testing.T) {
+		q := newTestQueue(t, &testSink{})
+
+		q.Forget(types.NamespacedName{Namespace: "test-namespace", Name: "unknown-fn"})
+
+		require.Empty(t, q.queues)
+	})
+	t.Run("ignore nil queue", func(t *testing.T) {
+		var q *Queue
+
+		require.NotPanics(t, func() {
+			q.Forget(types.NamespacedName{Namespace: "test-namespace", Name: "unknown-fn"})
+		})
+	})
+}
@@ -0,0 +1,262 @@
+package async
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxBodySize limits requests kept in memory until they are delivered
+	maxBodySize = 1 << 20
+
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 30 * time.Second
+	writeTimeout      = 30 * time.Second
+	idleTimeout       = 120 * time.Second
+	shutdownTimeout   = 10 * time.Second
+
+	// PodIPIndex indexes Pods by their IP to find the workload calling the server
+	PodIPIndex = "status.podIP"
+)
+
+// forwardedHeaders are set by proxies and the mesh, they can't be trusted when they're sent by the caller
+var forwardedHeaders = []string{
+	"Forwarded",
+	"X-Forwarded-",
+	"X-Envoy-",
+	"X-Real-Ip",
+	"X-Serverless-Dead-Letter-",
+}
+
+// hopByHopHeaders describe the connection to the server and aren't passed to the function
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+type Server struct {
+	bindAddr string
+	mux      *mux.Router
+	k8s      client.Client
+	log      *zap.SugaredLogger
+	queue    *Queue
+	tokens   *TokenVerifier
+}
+
+func NewServer(log *zap.SugaredLogger, k8s client.Client, queue *Queue, tokens *TokenVerifier, bindAddr string) *Server {
+	server := &Server{
+		bindAddr: bindAddr,
+		mux:      mux.NewRouter(),
+		k8s:      k8s,
+		log:      log,
+		queue:    queue,
+		tokens:   tokens,
+	}
+
+	server.mux.HandleFunc("/async/{namespace}/{name}", server.handleAsyncRequest).Methods(http.MethodPost)
+	server.mux.PathPrefix("/async/{namespace}/{name}/").HandlerFunc(server.handleAsyncRequest).Methods(http.MethodPost)
+
+	return server
+}
+
+// Start serves requests until the context is cancelled, it implements the manager's Runnable
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.bindAddr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	s.log.Info("shutting down async HTTP server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return errors.Wrap(err, "failed to shut down async HTTP server")
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection accepts requests only in the leading replica, which reconciles functions and forgets their queues
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleAsyncRequest queues the request for the delivery to the function and responds immediately with 202 Accepted
+// the path after the function's name is passed to the function
+func (s *Server) handleAsyncRequest(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ns, name := vars["namespace"], vars["name"]
+	s.log.Debugf("handling async request for function '%s/%s'", ns, name)
+
+	function := serverlessv1alpha2.Function{}
+	err := s.k8s.Get(r.Context(), client.ObjectKey{Namespace: ns, Name: name}, &function)
+	if k8serrors.IsNotFound(err) {
+		s.writeErrorResponse(w, http.StatusNotFound, errors.Errorf("function '%s/%s' not found", ns, name))
+		return
+	}
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "failed to get function '%s/%s'", ns, name))
+		return
+	}
+	if !function.HasAsync() {
+		s.writeErrorResponse(w, http.StatusBadRequest, errors.Errorf("function '%s/%s' doesn't enable async invocation", ns, name))
+		return
+	}
+
+	caller, err := s.callerPod(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusForbidden, errors.Wrap(err, "failed to identify caller"))
+		return
+	}
+	if !isCallerAllowed(&function, caller) {
+		s.writeErrorResponse(w, http.StatusForbidden, errors.Errorf("caller '%s/%s' isn't allowed to call function '%s/%s'",
+			caller.GetNamespace(), caller.GetName(), ns, name))
+		return
+	}
+	if function.Spec.Auth != nil {
+		err := s.tokens.Verify(r.Context(), function.Spec.Auth.JWT, r.Header.Get("Authorization"))
+		if errors.Is(err, ErrJWKSUnavailable) {
+			s.writeErrorResponse(w, http.StatusServiceUnavailable, errors.Wrapf(err, "failed to verify token for function '%s/%s'", ns, name))
+			return
+		}
+		if err != nil {
+			s.writeErrorResponse(w, http.StatusUnauthorized, errors.Wrapf(err, "token rejected by function '%s/%s'", ns, name))
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusRequestEntityTooLarge, errors.Wrap(err, "failed to read request body"))
+		return
+	}
+
+	msg := &Message{
+		Path:   strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/async/%s/%s", ns, name)),
+		Header: forwardableHeader(r.Header),
+		Body:   body,
+	}
+	if err := s.queue.Enqueue(&function, msg); err != nil {
+		s.writeErrorResponse(w, http.StatusTooManyRequests, errors.Wrapf(err, "failed to queue request for function '%s/%s'", ns, name))
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// callerPod finds the Pod sending the request by its IP
+// Pods in the host network share the node's IP and can't be told apart
+func (s *Server) callerPod(r *http.Request) (*corev1.Pod, error) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid remote address '%s'", r.RemoteAddr)
+	}
+
+	pods := &corev1.PodList{}
+	if err := s.k8s.List(r.Context(), pods, client.MatchingFields{PodIPIndex: ip}); err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	var caller *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.HostNetwork || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if caller != nil {
+			return nil, errors.Errorf("more than one pod with IP '%s'", ip)
+		}
+		caller = pod
+	}
+	if caller == nil {
+		return nil, errors.Errorf("no pod with IP '%s'", ip)
+	}
+	return caller, nil
+}
+
+// isCallerAllowed applies the function's auth to the caller, so the server doesn't bypass the function's AuthorizationPolicy
+// callers from the function's namespace are allowed when the function doesn't restrict calls
+// the Function Controller isn't in the mesh, so the function's AuthorizationPolicy accepts its deliveries only by the JWT,
+// requests to functions restricting calls are queued only when the function accepts JWTs and the token is verified by the server
+func isCallerAllowed(f *serverlessv1alpha2.Function, caller *corev1.Pod) bool {
+	if f.Spec.Auth == nil {
+		return caller.GetNamespace() == f.GetNamespace()
+	}
+
+	// the AuthorizationPolicy isn't created yet when the status is empty
+	auth := f.Status.Auth
+	return f.Spec.Auth.JWT != nil && auth != nil && len(auth.RequestPrincipals) != 0
+}
+
+// forwardableHeader copies headers passed to the function without hop-by-hop headers and headers set by proxies
+// the Authorization header is passed to let the function's RequestAuthentication validate the JWT on the delivery
+func forwardableHeader(header http.Header) http.Header {
+	forwarded := header.Clone()
+	for key := range forwarded {
+		if slices.Contains(hopByHopHeaders, key) || slices.ContainsFunc(forwardedHeaders, func(prefix string) bool {
+			return strings.HasPrefix(key, prefix)
+		}) {
+			forwarded.Del(key)
+		}
+	}
+	return forwarded
+}
+
+func (s *Server) writeErrorResponse(w http.ResponseWriter, status int, respErr error) {
+	buf := bytes.NewBuffer([]byte{})
+	err := json.NewEncoder(buf).Encode(types.ErrorResponse{Error: respErr.Error()})
+	if err != nil {
+		s.log.Errorf("failed to encode error response: %v", err)
+		status = http.StatusInternalServerError
+		buf = bytes.NewBufferString(`{"error":"internal server error"}`)
+	}
+
+	s.log.Debugf("writing error response with status: %d", status)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprint(w, buf.String())
+}
@@ -0,0 +1,252 @@
+package async
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestServer_handleAsyncRequest(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	authFunction := asyncFunction("auth-fn", &serverlessv1alpha2.AsyncInvocation{})
+	authFunction.Spec.Auth = &serverlessv1alpha2.Auth{Namespaces: []string{"allowed-namespace"}}
+	authFunction.Status.Auth = &serverlessv1alpha2.AuthStatus{
+		AuthorizationPolicy: "auth-fn",
+		Namespaces:          []string{"allowed-namespace"},
+		Principals:          []string{"cluster.local/ns/other-namespace/sa/allowed-sa"},
+	}
+	issuer := newTestTokenIssuer(t)
+	jwtFunction := asyncFunction("jwt-fn", &serverlessv1alpha2.AsyncInvocation{})
+	jwtFunction.Spec.Auth = &serverlessv1alpha2.Auth{JWT: issuer.jwt()}
+	jwtFunction.Status.Auth = &serverlessv1alpha2.AuthStatus{
+		AuthorizationPolicy: "jwt-fn",
+		RequestPrincipals:   []string{testIssuer + "/*"},
+	}
+
+	newServer := func(t *testing.T, sink *testSink) *Server {
+		k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			asyncFunction("async-fn", &serverlessv1alpha2.AsyncInvocation{}),
+			asyncFunction("sync-fn", nil),
+			authFunction,
+			jwtFunction,
+			callerPod("test-namespace", "caller", "default", "10.0.0.1"),
+			callerPod("other-namespace", "foreign-caller", "default", "10.0.0.2"),
+			callerPod("allowed-namespace", "allowed-caller", "default", "10.0.0.3"),
+			callerPod("other-namespace", "principal-caller", "allowed-sa", "10.0.0.4"),
+		).WithIndex(&corev1.Pod{}, PodIPIndex, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Status.PodIP}
+		}).Build()
+		return NewServer(zap.NewNop().Sugar(), k8s, newTestQueue(t, sink), NewTokenVerifier(), ":0")
+	}
+	newRequest := func(method, path, remoteIP string) *http.Request {
+		req := httptest.NewRequest(method, path, strings.NewReader("hello"))
+		req.RemoteAddr = remoteIP + ":41234"
+		return req
+	}
+
+	t.Run("accept request for async function", func(t *testing.T) {
+		sink := &testSink{respond: func(string) int { return http.StatusOK }}
+		s := newServer(t, sink)
+		req := newRequest(http.MethodPost, "/async/test-namespace/async-fn/orders", "10.0.0.1")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		require.Eventually(t, func() bool {
+			received := sink.received()
+			return len(received) == 1 && received[0].path == "/async-fn/orders" && received[0].body == "hello"
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("drop headers set by proxies", func(t *testing.T) {
+		sink := &testSink{respond: func(string) int { return http.StatusOK }}
+		s := newServer(t, sink)
+		req := newRequest(http.MethodPost, "/async/test-namespace/async-fn", "10.0.0.1")
+		req.Header.Set("X-Forwarded-For", "10.0.0.3")
+		req.Header.Set("X-Forwarded-Client-Cert", "By=spiffe://cluster.local/ns/allowed-namespace/sa/default")
+		req.Header.Set(DeadLetterFunctionHeader, "test-namespace/other-fn")
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set("X-Custom", "value")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		require.Eventually(t, func() bool {
+			return len(sink.received()) == 1
+		}, time.Second, 10*time.Millisecond)
+		header := sink.received()[0].header
+		require.Empty(t, header.Get("X-Forwarded-Client-Cert"))
+		require.Empty(t, header.Get(DeadLetterFunctionHeader))
+		require.Equal(t, "Bearer token", header.Get("Authorization"))
+		require.Equal(t, "value", header.Get("X-Custom"))
+	})
+	t.Run("reject request from other namespace", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/async-fn", "10.0.0.2")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.JSONEq(t, `{"error":"caller 'other-namespace/foreign-caller' isn't allowed to call function 'test-namespace/async-fn'"}`, w.Body.String())
+	})
+	t.Run("reject request from unknown caller", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/async-fn", "10.0.0.99")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.JSONEq(t, `{"error":"failed to identify caller: no pod with IP '10.0.0.99'"}`, w.Body.String())
+	})
+	// the Function Controller isn't in the mesh, so the function's AuthorizationPolicy would deny the delivery
+	t.Run("reject request from namespace allowed by function's auth", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/auth-fn", "10.0.0.3")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("reject request from principal allowed by function's auth", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/auth-fn", "10.0.0.4")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("reject request from function's namespace not allowed by function's auth", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/auth-fn", "10.0.0.1")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("accept request with valid token for function accepting jwt", func(t *testing.T) {
+		sink := &testSink{respond: func(string) int { return http.StatusOK }}
+		s := newServer(t, sink)
+		req := newRequest(http.MethodPost, "/async/test-namespace/jwt-fn", "10.0.0.2")
+		token := issuer.token(t, validClaims())
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusAccepted, w.Code)
+		require.Eventually(t, func() bool {
+			received := sink.received()
+			return len(received) == 1 && received[0].header.Get("Authorization") == token
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("reject request with invalid token for function accepting jwt", func(t *testing.T) {
+		sink := &testSink{}
+		s := newServer(t, sink)
+		req := newRequest(http.MethodPost, "/async/test-namespace/jwt-fn", "10.0.0.2")
+		req.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.JSONEq(t, `{"error":"token rejected by function 'test-namespace/jwt-fn': malformed token: invalid token"}`, w.Body.String())
+		require.Empty(t, sink.received())
+	})
+	t.Run("reject request with expired token for function accepting jwt", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/jwt-fn", "10.0.0.2")
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		req.Header.Set("Authorization", issuer.token(t, claims))
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("reject request without token for function accepting jwt", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/jwt-fn", "10.0.0.1")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("reject request when JWKS of function accepting jwt is unavailable", func(t *testing.T) {
+		issuer.available.Store(false)
+		defer issuer.available.Store(true)
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/jwt-fn", "10.0.0.2")
+		req.Header.Set("Authorization", issuer.token(t, validClaims()))
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+	t.Run("reject request for function without async invocation", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/sync-fn", "10.0.0.1")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.JSONEq(t, `{"error":"function 'test-namespace/sync-fn' doesn't enable async invocation"}`, w.Body.String())
+	})
+	t.Run("reject request for missing function", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodPost, "/async/test-namespace/missing-fn", "10.0.0.1")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+	t.Run("reject request with other method than POST", func(t *testing.T) {
+		s := newServer(t, &testSink{})
+		req := newRequest(http.MethodGet, "/async/test-namespace/async-fn", "10.0.0.1")
+		w := httptest.NewRecorder()
+
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
+
+func callerPod(namespace, name, serviceAccount, ip string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccount,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: ip,
+		},
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,14 +19,25 @@ type FunctionConfig struct {
 	LeaderElectionID                string `yaml:"leaderElectionID"`
 	SecretMutatingWebhookPort       int    `yaml:"secretMutatingWebhookPort"`
 	Healthz                         healthzConfig
-	Images                          ImagesConfig   `yaml:"images"`
-	RequeueDuration                 time.Duration  `yaml:"requeueDuration"`
-	FunctionReadyRequeueDuration    time.Duration  `yaml:"functionReadyRequeueDuration"`
-	PackageRegistryConfigSecretName string         `yaml:"packageRegistryConfigSecretName"`
-	FunctionTraceCollectorEndpoint  string         `yaml:"functionTraceCollectorEndpoint"`
-	FunctionPublisherProxyAddress   string         `yaml:"functionPublisherProxyAddress"`
-	ResourceConfig                  ResourceConfig `yaml:"resourcesConfiguration"`
-	InternalEndpointPort            string         `yaml:"internalEndpointPort"`
+	Images                          ImagesConfig     `yaml:"images"`
+	RequeueDuration                 time.Duration    `yaml:"requeueDuration"`
+	FunctionReadyRequeueDuration    time.Duration    `yaml:"functionReadyRequeueDuration"`
+	PackageRegistryConfigSecretName string           `yaml:"packageRegistryConfigSecretName"`
+	FunctionTraceCollectorEndpoint  string           `yaml:"functionTraceCollectorEndpoint"`
+	FunctionPublisherProxyAddress   string           `yaml:"functionPublisherProxyAddress"`
+	ResourceConfig                  ResourceConfig   `yaml:"resourcesConfiguration"`
+	InternalEndpointPort            string           `yaml:"internalEndpointPort"`
+	InlineSourcesMaxSize            Quantity         `yaml:"inlineSourcesMaxSize"`
+	DependencyPolicy                DependencyPolicy `yaml:"dependencyPolicy"`
+	SBOMConfigMapEnabled            bool             `yaml:"sbomConfigMapEnabled"`
+	Expose                          ExposeConfig     `yaml:"expose"`
+	Async                           AsyncConfig      `yaml:"async"`
+	WorkloadBackend                 string           `yaml:"workloadBackend"`
+	MeshMode                        MeshMode         `yaml:"meshMode"`
+	RuntimeLifecycle                RuntimeLifecycle `yaml:"runtimeLifecycle"`
+	RuntimeUpgrade                  RuntimeUpgrade   `yaml:"runtimeUpgrade"`
+	ImageDigests                    ImageDigests     `yaml:"imageDigests"`
+	ImagePolicy                     ImagePolicy      `yaml:"imagePolicy"`
 }
 type healthzConfig struct {
 	Port            string        `yaml:"healthzPort"`
@@ -47,14 +59,241 @@ func defaultFunctionConfig() FunctionConfig {
 		PackageRegistryConfigSecretName: "serverless-package-registry-config",
 		FunctionPublisherProxyAddress:   "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
 		InternalEndpointPort:            ":12137",
+		InlineSourcesMaxSize:            Quantity{Quantity: resource.MustParse("512Ki")},
+		Expose: ExposeConfig{
+			Gateway: "kyma-system/kyma-gateway",
+		},
+		Async: AsyncConfig{
+			Port:            ":8095",
+			QueueSize:       100,
+			DeliveryTimeout: time.Minute,
+			MaxBackoff:      time.Minute * 5,
+		},
+		WorkloadBackend: "deployment",
+		MeshMode:        MeshModeIstio,
+		RuntimeLifecycle: RuntimeLifecycle{
+			Policy: RuntimePolicyWarn,
+		},
+		RuntimeUpgrade: RuntimeUpgrade{
+			StateConfigMap: "kyma-system/serverless-runtime-upgrade-state",
+		},
+		ImageDigests: ImageDigests{
+			ResolveInterval: time.Hour * 24,
+		},
 	}
 }
 
 type ImagesConfig struct {
-	NodeJs20    string `yaml:"nodejs20"`
-	NodeJs22    string `yaml:"nodejs22"`
-	Python312   string `yaml:"python312"`
-	RepoFetcher string `yaml:"repoFetcher"`
+	NodeJs20        string `yaml:"nodejs20"`
+	NodeJs22        string `yaml:"nodejs22"`
+	Python312       string `yaml:"python312"`
+	RepoFetcher     string `yaml:"repoFetcher"`
+	ScheduleInvoker string `yaml:"scheduleInvoker"`
+}
+
+// MeshMode selects the service mesh the Functions' Pods are prepared for
+type MeshMode string
+
+const (
+	MeshModeIstio   MeshMode = "istio"
+	MeshModeLinkerd MeshMode = "linkerd"
+	MeshModeNone    MeshMode = "none"
+)
+
+// RuntimePolicy decides what happens with Functions using runtimes after their end of life
+type RuntimePolicy string
+
+const (
+	// RuntimePolicyWarn keeps Functions running their runtime and warns about it in their status
+	RuntimePolicyWarn RuntimePolicy = "warn"
+	// RuntimePolicyAutoUpgrade runs Functions with the replacement runtime starting from the upgrade window
+	RuntimePolicyAutoUpgrade RuntimePolicy = "autoUpgrade"
+	// RuntimePolicyBlock stops configuring Functions until they are migrated to another runtime
+	RuntimePolicyBlock RuntimePolicy = "block"
+)
+
+// RuntimeLifecycle configures deprecation of the built-in runtimes
+type RuntimeLifecycle struct {
+	// Policy is applied to Functions using runtimes after their end of life
+	Policy RuntimePolicy `yaml:"policy"`
+	// UpgradeWindow limits when Functions are upgraded with the autoUpgrade policy
+	UpgradeWindow MaintenanceWindow `yaml:"upgradeWindow"`
+	// Deprecations lists the deprecated runtimes
+	Deprecations []RuntimeDeprecation `yaml:"deprecations"`
+}
+
+// Deprecation returns the deprecation of the runtime or nil if the runtime isn't deprecated
+func (l RuntimeLifecycle) Deprecation(runtime string) *RuntimeDeprecation {
+	for i := range l.Deprecations {
+		if l.Deprecations[i].Runtime == runtime {
+			return &l.Deprecations[i]
+		}
+	}
+	return nil
+}
+
+type RuntimeDeprecation struct {
+	// Runtime is the name of the deprecated runtime
+	Runtime string `yaml:"runtime"`
+	// EndOfLife is the date (YYYY-MM-DD) the policy is applied from, the runtime is only deprecated when it's not set
+	EndOfLife Date `yaml:"endOfLife"`
+	// ReplacedBy is the runtime Functions should migrate to and are upgraded to with the autoUpgrade policy
+	ReplacedBy string `yaml:"replacedBy"`
+	// Message is added to warnings about the deprecated runtime
+	Message string `yaml:"message"`
+}
+
+// MaintenanceWindow is the recurring time range in which Functions can be changed by the controller
+type MaintenanceWindow struct {
+	// Days lists days of the week (sun, mon, ...) the window opens on, empty value means every day
+	Days []string `yaml:"days"`
+	// Start is the time of the day (HH:MM, UTC) the window opens at
+	Start string `yaml:"start"`
+	// Duration is how long the window is open, zero value means the window is always open
+	Duration time.Duration `yaml:"duration"`
+}
+
+// IsOpen checks if the window is open at the given time
+func (w MaintenanceWindow) IsOpen(now time.Time) bool {
+	if w.Duration <= 0 {
+		return true
+	}
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return false
+	}
+	now = now.UTC()
+	// the window opened today or the day before could still be open
+	for _, daysAgo := range []int{0, 1} {
+		day := now.AddDate(0, 0, -daysAgo)
+		if !w.opensOn(day.Weekday()) {
+			continue
+		}
+		opensAt := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if !now.Before(opensAt) && now.Before(opensAt.Add(w.Duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w MaintenanceWindow) opensOn(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if strings.EqualFold(day, weekday.String()[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuntimeUpgrade configures the staged rollout of runtime images changed in the images configuration
+type RuntimeUpgrade struct {
+	// MaxConcurrent limits the number of Functions upgraded at the same time, zero value means no limit
+	MaxConcurrent int `yaml:"maxConcurrent"`
+	// MaxFailures pauses the rollout when the number of failed upgrades reaches it, zero value means the rollout is never paused
+	MaxFailures int `yaml:"maxFailures"`
+	// Window is the maintenance window in which Functions are upgraded
+	Window MaintenanceWindow `yaml:"window"`
+	// NamespaceWindows overrides the maintenance window for Functions in the given namespaces
+	NamespaceWindows map[string]MaintenanceWindow `yaml:"namespaceWindows"`
+	// StateConfigMap is the ConfigMap in the `namespace/name` format the rollout's state is persisted in, so it survives restarts of the controller
+	StateConfigMap string `yaml:"stateConfigMap"`
+}
+
+// IsStaged checks if runtime images are rolled out in stages instead of upgrading all Functions at once
+func (u RuntimeUpgrade) IsStaged() bool {
+	return u.MaxConcurrent > 0 || u.Window.Duration > 0 || len(u.NamespaceWindows) > 0
+}
+
+// WindowFor returns the maintenance window of Functions in the namespace
+func (u RuntimeUpgrade) WindowFor(namespace string) MaintenanceWindow {
+	if window, ok := u.NamespaceWindows[namespace]; ok {
+		return window
+	}
+	return u.Window
+}
+
+// ImageDigests configures resolving runtime images to digests the Functions are deployed with
+type ImageDigests struct {
+	// Enabled turns on resolving runtime images, Functions are deployed with image tags when it's disabled (default)
+	Enabled bool `yaml:"enabled"`
+	// ResolveInterval is how long the resolved digest is reused before the image is resolved again, so moved tags are picked up
+	// zero value means the image is resolved again only when it changes
+	ResolveInterval time.Duration `yaml:"resolveInterval"`
+	// PullSecret is the `kubernetes.io/dockerconfigjson` Secret in the `namespace/name` format with credentials to the runtime images' registries
+	// credentials from the image pull secrets of the Function's ServiceAccount take precedence
+	PullSecret string `yaml:"pullSecret"`
+	// PlainHTTPRegistries lists registries called without TLS, for example, `localhost:5000` of the local test registry
+	PlainHTTPRegistries []string `yaml:"plainHTTPRegistries"`
+}
+
+// ImagePolicy restricts runtime images the Functions run with
+// empty policy allows all images
+type ImagePolicy struct {
+	// AllowedImages lists glob patterns of images (`registry/repository`, without tag or digest) which can be set in the Function's runtimeImageOverride
+	// the trailing `/**` matches all repositories under the given path, for example, `europe-docker.pkg.dev/kyma-project/**`
+	AllowedImages []string `yaml:"allowedImages"`
+	// PublicKeys lists PEM encoded cosign public keys, runtime images have to be signed with one of them
+	// signatures are verified for digests, so setting them resolves runtime images to digests even if ImageDigests are disabled
+	// the transparency log (Rekor) inclusion of signatures isn't checked
+	PublicKeys []string `yaml:"publicKeys"`
+}
+
+// RequiresSignature checks if runtime images have to be signed
+func (p ImagePolicy) RequiresSignature() bool {
+	return len(p.PublicKeys) != 0
+}
+
+// PinsRuntimeImages checks if the Functions run with runtime images pinned to digests,
+// it's required by ImageDigests or by the ImagePolicy verifying signatures of the digests
+// both resolve images with the pull secret, plain HTTP registries and the resolve interval from ImageDigests
+func (c FunctionConfig) PinsRuntimeImages() bool {
+	return c.ImageDigests.Enabled || c.ImagePolicy.RequiresSignature()
+}
+
+// ExposeConfig configures APIRules and HTTPRoutes of the exposed Functions
+type ExposeConfig struct {
+	// Gateway is the gateway in the `namespace/name` format the exposed Functions are attached to
+	Gateway string `yaml:"gateway"`
+}
+
+// AsyncConfig configures the queue delivering asynchronous invocations of Functions
+type AsyncConfig struct {
+	// Port is the address the async endpoint listens on
+	Port string `yaml:"port"`
+	// QueueSize limits the number of requests waiting for delivery to a single Function
+	QueueSize int `yaml:"queueSize"`
+	// DeliveryTimeout limits the time of a single delivery attempt
+	DeliveryTimeout time.Duration `yaml:"deliveryTimeout"`
+	// MaxBackoff limits the delay between retries
+	MaxBackoff time.Duration `yaml:"maxBackoff"`
+}
+
+// DependencyPolicy restricts packages installed as Function's dependencies
+// empty policy allows all packages from all registries
+type DependencyPolicy struct {
+	// AllowedRegistries lists hosts (glob patterns) packages can be installed from
+	AllowedRegistries []string `yaml:"allowedRegistries"`
+	// Allowed lists packages which can be used as Function's direct dependencies
+	Allowed []PackageRule `yaml:"allowed"`
+	// Denied lists packages which can't be installed, also as transitive dependencies
+	Denied []PackageRule `yaml:"denied"`
+}
+
+type PackageRule struct {
+	// Ecosystem limits the rule to npm or pypi packages, empty value matches both
+	Ecosystem string `yaml:"ecosystem"`
+	// Name is a glob pattern of the package name
+	Name string `yaml:"name"`
+	// Versions is a semver constraint, empty value matches all versions
+	Versions string `yaml:"versions"`
+}
+
+func (p DependencyPolicy) IsEmpty() bool {
+	return len(p.AllowedRegistries) == 0 && len(p.Allowed) == 0 && len(p.Denied) == 0
 }
 
 type ResourceConfig struct {
@@ -106,6 +345,25 @@ func (q *Quantity) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// Date is the calendar day (YYYY-MM-DD) in UTC
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	date := ""
+	err := unmarshal(&date)
+	if err != nil {
+		return errors.Wrap(err, "while unmarshalling date")
+	}
+	out, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return errors.Wrap(err, "while parsing date")
+	}
+	d.Time = out
+	return nil
+}
+
 func LoadFunctionConfig(path string) (FunctionConfig, error) {
 	cfg := defaultFunctionConfig()
 
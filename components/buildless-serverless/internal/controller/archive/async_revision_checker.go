@@ -0,0 +1,111 @@
+package archive
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//go:generate mockery --name=AsyncLatestRevisionChecker --output=automock --outpkg=automock --case=underscore
+type AsyncLatestRevisionChecker interface {
+	PlaceOrder(string, string, *Auth)
+	CollectOrder(string) *OrderResult
+}
+
+type asyncLatestRevisionChecker struct {
+	ctx               context.Context
+	cache             sync.Map
+	log               *zap.SugaredLogger
+	cacheElemLifetime time.Duration
+
+	// implemented to allow easier testing
+	getLatestRevision func(url string, auth *Auth) (string, error)
+}
+
+type OrderResult struct {
+	Revision  string
+	Error     error
+	timestamp time.Time
+}
+
+func NewAsyncLatestRevisionChecker(ctx context.Context, log *zap.SugaredLogger) AsyncLatestRevisionChecker {
+	checker := &asyncLatestRevisionChecker{
+		ctx:               ctx,
+		log:               log,
+		getLatestRevision: GetLatestRevision,
+		cacheElemLifetime: 2 * time.Minute,
+	}
+
+	// start periodic cache cleanup
+	checker.clearCacheEvery(time.Hour * 24)
+
+	return checker
+}
+
+// PlaceOrder orders asynchronous archive latest revision check
+// when the check is complete, the result can be accessed using the orderID
+func (c *asyncLatestRevisionChecker) PlaceOrder(orderID, url string, auth *Auth) {
+	_, exists := c.cache.Load(orderID)
+	if exists {
+		// already ordered
+		return
+	}
+
+	// store a nil value to indicate that the order is in progress
+	c.cache.Store(orderID, nil)
+
+	go func() {
+		c.log.Debugf("starting async latest revision check for %s", url)
+		revision, err := c.getLatestRevision(url, auth)
+
+		c.log.Debugf("finished async latest revision check for %s with revision %s", url, revision)
+		c.cache.Store(orderID, &OrderResult{
+			Revision:  revision,
+			Error:     err,
+			timestamp: time.Now(),
+		})
+	}()
+}
+
+// CollectOrder collects the result of the latest revision check for the given orderID
+// if the result is found or the order is still in progress, nil is returned
+// if order is older than 2 minutes, it is removed from the cache but latest order is returned
+func (c *asyncLatestRevisionChecker) CollectOrder(orderID string) *OrderResult {
+	result := c.load(orderID)
+	if result != nil && time.Since(result.timestamp) > c.cacheElemLifetime {
+		// remove old result from cache if is older than 2 minutes
+		c.cache.Delete(orderID)
+	}
+
+	return result
+}
+
+func (c *asyncLatestRevisionChecker) load(orderID string) *OrderResult {
+	value, exists := c.cache.Load(orderID)
+	if !exists {
+		return nil
+	}
+
+	result, ok := value.(*OrderResult)
+	if !ok {
+		return nil
+	}
+
+	return result
+}
+
+func (c *asyncLatestRevisionChecker) clearCacheEvery(duration time.Duration) {
+	go func() {
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(duration):
+				c.log.Debug("clearing async latest revision checker cache")
+				c.cache.Clear()
+			}
+		}
+	}()
+}
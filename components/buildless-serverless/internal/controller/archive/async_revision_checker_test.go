@@ -0,0 +1,62 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_AsyncLatestRevisionChecker(t *testing.T) {
+	t.Run("order latest revision check and cleanup cache entry", func(t *testing.T) {
+		id := "order-id"
+		checker := asyncLatestRevisionChecker{
+			ctx:               context.Background(),
+			log:               zap.NewNop().Sugar(),
+			cacheElemLifetime: 0,
+			getLatestRevision: func(url string, auth *Auth) (string, error) {
+				return "test-revision", nil
+			},
+		}
+
+		result := checker.CollectOrder(id)
+		require.Nil(t, result)
+
+		checker.PlaceOrder(id, "test-url", nil)
+
+		require.Eventually(t, func() bool {
+			return checker.load(id) != nil
+		}, time.Second, time.Millisecond)
+
+		result = checker.CollectOrder(id)
+		require.NotNil(t, result, "revision check should be ordered and finished")
+		require.Equal(t, "test-revision", result.Revision)
+		require.NoError(t, result.Error)
+
+		result = checker.CollectOrder(id)
+		require.Nil(t, result, "cache entry should be removed")
+	})
+	t.Run("return error from revision check", func(t *testing.T) {
+		id := "order-id"
+		checker := asyncLatestRevisionChecker{
+			ctx:               context.Background(),
+			log:               zap.NewNop().Sugar(),
+			cacheElemLifetime: time.Minute,
+			getLatestRevision: func(url string, auth *Auth) (string, error) {
+				return "", errors.New("test-error")
+			},
+		}
+
+		checker.PlaceOrder(id, "test-url", nil)
+
+		require.Eventually(t, func() bool {
+			return checker.CollectOrder(id) != nil
+		}, time.Second, time.Millisecond)
+
+		result := checker.CollectOrder(id)
+		require.ErrorContains(t, result.Error, "test-error")
+	})
+}
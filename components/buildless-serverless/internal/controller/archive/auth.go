@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	usernameEnvVarName = "APP_ARCHIVE_USERNAME"
+	passwordEnvVarName = "APP_ARCHIVE_PASSWORD"
+)
+
+// Auth holds basic auth credentials used to download the archive
+type Auth struct {
+	secretName string
+	username   string
+	password   string
+}
+
+// NewAuth reads credentials from the `kubernetes.io/basic-auth` Secret referenced by the function
+func NewAuth(ctx context.Context, c client.Client, f *serverlessv1alpha2.Function) (*Auth, error) {
+	secretName := f.Spec.Source.Archive.Auth.SecretName
+	s := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: f.GetNamespace(), Name: secretName}, s)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("failed to get secret: %s", err.Error()))
+	}
+	username, usernameFound := s.Data[corev1.BasicAuthUsernameKey]
+	password, passwordFound := s.Data[corev1.BasicAuthPasswordKey]
+	if !usernameFound || !passwordFound {
+		return nil, errors.New(fmt.Sprintf("missing '%s' or '%s'", corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey))
+	}
+	return &Auth{
+		secretName: secretName,
+		username:   string(username),
+		password:   string(password),
+	}, nil
+}
+
+// NewBasicAuth creates credentials from plain values
+func NewBasicAuth(username, password string) *Auth {
+	return &Auth{
+		username: username,
+		password: password,
+	}
+}
+
+// GetAuthEnvs returns envs with credentials read from the Secret by the init container
+func (a *Auth) GetAuthEnvs() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		a.secretEnv(usernameEnvVarName, corev1.BasicAuthUsernameKey),
+		a.secretEnv(passwordEnvVarName, corev1.BasicAuthPasswordKey),
+	}
+}
+
+func (a *Auth) secretEnv(name, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: a.secretName,
+				},
+				Key: key,
+			},
+		},
+	}
+}
+
+func (a *Auth) apply(req *http.Request) {
+	if a == nil {
+		return
+	}
+	req.SetBasicAuth(a.username, a.password)
+}
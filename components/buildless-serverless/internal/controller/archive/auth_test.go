@@ -0,0 +1,85 @@
+package archive
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewAuth(t *testing.T) {
+	f := &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-function", Namespace: "default"},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Source: serverlessv1alpha2.Source{
+				Archive: &serverlessv1alpha2.ArchiveSource{
+					URL:  "https://artifacts.local/function.tar.gz",
+					Auth: &serverlessv1alpha2.ArchiveAuth{SecretName: "archive-credentials"},
+				},
+			},
+		},
+	}
+
+	t.Run("read credentials from secret", func(t *testing.T) {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "archive-credentials", Namespace: "default"},
+			Type:       corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("pass"),
+			},
+		}
+		c := fake.NewClientBuilder().WithObjects(s).Build()
+
+		auth, err := NewAuth(context.Background(), c, f)
+
+		require.NoError(t, err)
+		require.Equal(t, &Auth{secretName: "archive-credentials", username: "user", password: "pass"}, auth)
+		require.Equal(t, []corev1.EnvVar{
+			{
+				Name: "APP_ARCHIVE_USERNAME",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "archive-credentials"},
+						Key:                  "username",
+					},
+				},
+			},
+			{
+				Name: "APP_ARCHIVE_PASSWORD",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "archive-credentials"},
+						Key:                  "password",
+					},
+				},
+			},
+		}, auth.GetAuthEnvs())
+	})
+	t.Run("fail when secret is missing password", func(t *testing.T) {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "archive-credentials", Namespace: "default"},
+			Data: map[string][]byte{
+				"username": []byte("user"),
+			},
+		}
+		c := fake.NewClientBuilder().WithObjects(s).Build()
+
+		auth, err := NewAuth(context.Background(), c, f)
+
+		require.ErrorContains(t, err, "missing 'username' or 'password'")
+		require.Nil(t, auth)
+	})
+	t.Run("fail when secret does not exist", func(t *testing.T) {
+		c := fake.NewClientBuilder().Build()
+
+		auth, err := NewAuth(context.Background(), c, f)
+
+		require.ErrorContains(t, err, "failed to get secret")
+		require.Nil(t, auth)
+	})
+}
@@ -0,0 +1,107 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const maxArchiveSize = 64 * 1024 * 1024
+
+// Download fetches the archive and verifies its checksum when expectedSHA256 is not empty
+func Download(ctx context.Context, url string, auth *Auth, expectedSHA256 string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating request")
+	}
+	auth.apply(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("unexpected status: %s", resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading archive")
+	}
+	if len(data) > maxArchiveSize {
+		return nil, errors.New(fmt.Sprintf("archive is bigger than %d bytes", maxArchiveSize))
+	}
+
+	if expectedSHA256 != "" {
+		sum := sha256.Sum256(data)
+		if actual := hex.EncodeToString(sum[:]); actual != expectedSHA256 {
+			return nil, errors.New(fmt.Sprintf("checksum mismatch: expected %s, got %s", expectedSHA256, actual))
+		}
+	}
+	return data, nil
+}
+
+// Unpack extracts regular files and directories from the `tar.gz` archive to the destination
+func Unpack(data []byte, destination string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "while opening gzip stream")
+	}
+	defer gz.Close()
+
+	var totalSize int64
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "while reading tar stream")
+		}
+		if !filepath.IsLocal(hdr.Name) {
+			return errors.New(fmt.Sprintf("file %s is not a local path", hdr.Name))
+		}
+		target := filepath.Join(destination, hdr.Name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			totalSize += hdr.Size
+			if totalSize > maxArchiveSize {
+				return errors.New(fmt.Sprintf("unpacked content is bigger than %d bytes", maxArchiveSize))
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Clean(target), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, r)
+	return err
+}
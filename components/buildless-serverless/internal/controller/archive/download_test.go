@@ -0,0 +1,99 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	data := tarGzip(t, map[string]string{"handler.js": "module.exports = {}"})
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	t.Run("download and verify checksum", func(t *testing.T) {
+		got, err := Download(context.Background(), server.URL, nil, checksum)
+
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+	t.Run("download without checksum", func(t *testing.T) {
+		got, err := Download(context.Background(), server.URL, nil, "")
+
+		require.NoError(t, err)
+		require.Equal(t, data, got)
+	})
+	t.Run("fail on checksum mismatch", func(t *testing.T) {
+		wrong := "0000000000000000000000000000000000000000000000000000000000000000"
+
+		got, err := Download(context.Background(), server.URL, nil, wrong)
+
+		require.ErrorContains(t, err, "checksum mismatch: expected "+wrong+", got "+checksum)
+		require.Nil(t, got)
+	})
+}
+
+func TestUnpack(t *testing.T) {
+	t.Run("unpack files", func(t *testing.T) {
+		dir := t.TempDir()
+		data := tarGzip(t, map[string]string{
+			"handler.js":     "module.exports = {}",
+			"lib/helpers.js": "exports.a = 1",
+		})
+
+		err := Unpack(data, dir)
+
+		require.NoError(t, err)
+		handler, err := os.ReadFile(filepath.Join(dir, "handler.js"))
+		require.NoError(t, err)
+		require.Equal(t, "module.exports = {}", string(handler))
+		helpers, err := os.ReadFile(filepath.Join(dir, "lib", "helpers.js"))
+		require.NoError(t, err)
+		require.Equal(t, "exports.a = 1", string(helpers))
+	})
+	t.Run("fail on path outside of destination", func(t *testing.T) {
+		data := tarGzip(t, map[string]string{"../handler.js": "module.exports = {}"})
+
+		err := Unpack(data, t.TempDir())
+
+		require.ErrorContains(t, err, "file ../handler.js is not a local path")
+	})
+	t.Run("fail on invalid archive", func(t *testing.T) {
+		err := Unpack([]byte("not an archive"), t.TempDir())
+
+		require.ErrorContains(t, err, "while opening gzip stream")
+	})
+}
+
+func tarGzip(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,40 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 30 * time.Second
+
+// GetLatestRevision returns the revision of the archive based on the `ETag` or `Last-Modified` header
+// empty revision is returned when the server returns none of them
+func GetLatestRevision(url string, auth *Auth) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "while creating request")
+	}
+	auth.apply(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(fmt.Sprintf("unexpected status: %s", resp.Status))
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return etag, nil
+	}
+	return resp.Header.Get("Last-Modified"), nil
+}
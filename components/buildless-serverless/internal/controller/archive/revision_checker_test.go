@@ -0,0 +1,63 @@
+package archive
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLatestRevision(t *testing.T) {
+	t.Run("return etag", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodHead, r.Method)
+			w.Header().Set("ETag", `"abc"`)
+			w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 10:00:00 GMT")
+		}))
+		defer server.Close()
+
+		revision, err := GetLatestRevision(server.URL, nil)
+
+		require.NoError(t, err)
+		require.Equal(t, `"abc"`, revision)
+	})
+	t.Run("return last modified when etag is missing", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 10:00:00 GMT")
+		}))
+		defer server.Close()
+
+		revision, err := GetLatestRevision(server.URL, nil)
+
+		require.NoError(t, err)
+		require.Equal(t, "Mon, 19 Oct 2026 10:00:00 GMT", revision)
+	})
+	t.Run("use basic auth", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("ETag", `"abc"`)
+		}))
+		defer server.Close()
+
+		revision, err := GetLatestRevision(server.URL, NewBasicAuth("user", "pass"))
+
+		require.NoError(t, err)
+		require.Equal(t, `"abc"`, revision)
+	})
+	t.Run("fail on unexpected status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		revision, err := GetLatestRevision(server.URL, nil)
+
+		require.ErrorContains(t, err, "unexpected status: 404 Not Found")
+		require.Empty(t, revision)
+	})
+}
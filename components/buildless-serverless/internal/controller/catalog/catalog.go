@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"context"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultFunctionRuntimes returns FunctionRuntimes of the runtimes with images from the Function Controller's configuration
+// runtimes without the configured image are skipped
+func DefaultFunctionRuntimes(images config.ImagesConfig) []serverlessv1alpha2.FunctionRuntime {
+	defaultImages := []struct {
+		runtime serverlessv1alpha2.Runtime
+		image   string
+	}{
+		{serverlessv1alpha2.NodeJs20, images.NodeJs20},
+		{serverlessv1alpha2.NodeJs22, images.NodeJs22},
+		{serverlessv1alpha2.Python312, images.Python312},
+	}
+
+	functionRuntimes := []serverlessv1alpha2.FunctionRuntime{}
+	for _, d := range defaultImages {
+		if d.image == "" {
+			continue
+		}
+		functionRuntimes = append(functionRuntimes, serverlessv1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: string(d.runtime),
+				Labels: map[string]string{
+					serverlessv1alpha2.FunctionManagedByLabel: serverlessv1alpha2.FunctionControllerValue,
+				},
+			},
+			Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+				Image: d.image,
+			},
+		})
+	}
+	return functionRuntimes
+}
+
+// Get returns the FunctionRuntime describing the runtime
+// nil is returned when it's missing, for example, when the FunctionRuntime CRD isn't installed yet
+func Get(ctx context.Context, reader client.Reader, runtime serverlessv1alpha2.Runtime) (*serverlessv1alpha2.FunctionRuntime, error) {
+	functionRuntime := &serverlessv1alpha2.FunctionRuntime{}
+	err := reader.Get(ctx, client.ObjectKey{Name: string(runtime)}, functionRuntime)
+	if k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "while fetching FunctionRuntime %s", runtime)
+	}
+	return functionRuntime, nil
+}
+
+// Seeder creates FunctionRuntimes of the runtimes missing in the catalog
+// and keeps images of the FunctionRuntimes managed by the Function Controller up to date with its configuration
+// FunctionRuntimes without the managed-by label are customized by platform teams and aren't changed
+type Seeder struct {
+	client client.Client
+	images config.ImagesConfig
+	log    *zap.SugaredLogger
+}
+
+func NewSeeder(client client.Client, images config.ImagesConfig, log *zap.SugaredLogger) *Seeder {
+	return &Seeder{
+		client: client,
+		images: images,
+		log:    log,
+	}
+}
+
+// Start seeds the catalog once, it implements the manager's Runnable
+// failures are only logged, because Functions fall back to images from the configuration when their FunctionRuntime is missing
+func (s *Seeder) Start(ctx context.Context) error {
+	for _, functionRuntime := range DefaultFunctionRuntimes(s.images) {
+		if err := s.seed(ctx, &functionRuntime); err != nil {
+			s.log.Error(err, "unable to seed FunctionRuntime", "FunctionRuntime", functionRuntime.GetName())
+		}
+	}
+	return nil
+}
+
+// NeedLeaderElection seeds the catalog only in the leading replica
+func (s *Seeder) NeedLeaderElection() bool {
+	return true
+}
+
+func (s *Seeder) seed(ctx context.Context, defaultRuntime *serverlessv1alpha2.FunctionRuntime) error {
+	current, err := Get(ctx, s.client, serverlessv1alpha2.Runtime(defaultRuntime.GetName()))
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		s.log.Info("creating FunctionRuntime", "FunctionRuntime", defaultRuntime.GetName())
+		return errors.Wrap(client.IgnoreAlreadyExists(s.client.Create(ctx, defaultRuntime)), "while creating FunctionRuntime")
+	}
+
+	if current.GetLabels()[serverlessv1alpha2.FunctionManagedByLabel] != serverlessv1alpha2.FunctionControllerValue ||
+		current.Spec.Image == defaultRuntime.Spec.Image {
+		return nil
+	}
+	s.log.Info("updating image of FunctionRuntime", "FunctionRuntime", current.GetName(), "image", defaultRuntime.Spec.Image)
+	current.Spec.Image = defaultRuntime.Spec.Image
+	return errors.Wrap(s.client.Update(ctx, current), "while updating FunctionRuntime")
+}
@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDefaultFunctionRuntimes(t *testing.T) {
+	t.Run("return function runtimes of runtimes with configured images", func(t *testing.T) {
+		images := config.ImagesConfig{
+			NodeJs22:  "test-image-nodejs22",
+			Python312: "test-image-python312",
+		}
+
+		r := DefaultFunctionRuntimes(images)
+
+		require.Len(t, r, 2)
+		require.Equal(t, "nodejs22", r[0].GetName())
+		require.Equal(t, "test-image-nodejs22", r[0].Spec.Image)
+		require.Equal(t, "python312", r[1].GetName())
+		require.Equal(t, "test-image-python312", r[1].Spec.Image)
+		for _, functionRuntime := range r {
+			require.Equal(t, serverlessv1alpha2.FunctionControllerValue, functionRuntime.GetLabels()[serverlessv1alpha2.FunctionManagedByLabel])
+		}
+	})
+}
+
+func TestGet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+
+	t.Run("return function runtime", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&serverlessv1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodejs22"},
+			Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "test-image-nodejs22"},
+		}).Build()
+
+		r, err := Get(context.Background(), k8sClient, serverlessv1alpha2.NodeJs22)
+
+		require.NoError(t, err)
+		require.NotNil(t, r)
+		require.Equal(t, "test-image-nodejs22", r.Spec.Image)
+	})
+	t.Run("return nil when function runtime is missing", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		r, err := Get(context.Background(), k8sClient, serverlessv1alpha2.NodeJs22)
+
+		require.NoError(t, err)
+		require.Nil(t, r)
+	})
+}
+
+func TestSeeder_Start(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	images := config.ImagesConfig{
+		NodeJs20:  "test-image-nodejs20:v2",
+		NodeJs22:  "test-image-nodejs22:v2",
+		Python312: "test-image-python312:v2",
+	}
+
+	t.Run("create missing function runtimes and update managed ones", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nodejs22",
+					Labels: map[string]string{
+						serverlessv1alpha2.FunctionManagedByLabel: serverlessv1alpha2.FunctionControllerValue,
+					},
+				},
+				Spec: serverlessv1alpha2.FunctionRuntimeSpec{Image: "test-image-nodejs22:v1"},
+			},
+			&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "python312"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "custom-python312:v1"},
+			},
+		).Build()
+		s := NewSeeder(k8sClient, images, zap.NewNop().Sugar())
+
+		err := s.Start(context.Background())
+
+		require.NoError(t, err)
+		requireImage(t, k8sClient, "nodejs20", "test-image-nodejs20:v2")
+		requireImage(t, k8sClient, "nodejs22", "test-image-nodejs22:v2")
+		// function runtime customized by the platform team is kept
+		requireImage(t, k8sClient, "python312", "custom-python312:v1")
+	})
+}
+
+func requireImage(t *testing.T, k8sClient client.Client, name, image string) {
+	functionRuntime := &serverlessv1alpha2.FunctionRuntime{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: name}, functionRuntime))
+	require.Equal(t, image, functionRuntime.Spec.Image)
+}
@@ -0,0 +1,138 @@
+package deprecation
+
+import (
+	"fmt"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+)
+
+// Action is what the controller does with the Function using the deprecated runtime
+type Action string
+
+const (
+	ActionNone    Action = ""
+	ActionWarn    Action = "warn"
+	ActionUpgrade Action = "upgrade"
+	ActionBlock   Action = "block"
+)
+
+// Result describes the lifecycle of the Function's runtime
+type Result struct {
+	// Runtime is the runtime from the Function's spec
+	Runtime serverlessv1alpha2.Runtime
+	// RunWith is the runtime the Function runs with, it differs from Runtime when the Function is upgraded
+	RunWith serverlessv1alpha2.Runtime
+	// EndOfLife is the end of life of the deprecated runtime, nil when it's not known
+	EndOfLife *time.Time
+	// ReplacedBy is the runtime the Function should migrate to
+	ReplacedBy serverlessv1alpha2.Runtime
+	Action     Action
+	// Message explains the action to users
+	Message string
+	// upgradePending is set when the Function waits for the upgrade window
+	upgradePending bool
+}
+
+// IsDeprecated checks if the Function uses the deprecated runtime
+func (r Result) IsDeprecated() bool {
+	return r.Action != ActionNone
+}
+
+// IsLegacy checks if the Function uses the runtime which isn't supported anymore and can't run at all
+func (r Result) IsLegacy() bool {
+	return r.Runtime.IsRuntimeKnown() && !r.Runtime.IsRuntimeSupported()
+}
+
+// Check applies the runtime lifecycle configuration to the Function at the given time
+// Functions upgraded before (with the replacement runtime in their status) stay upgraded also outside the upgrade window
+func Check(f *serverlessv1alpha2.Function, c config.RuntimeLifecycle, now time.Time) Result {
+	runtime := f.Spec.Runtime
+	result := Result{
+		Runtime: runtime,
+		RunWith: runtime,
+	}
+
+	deprecation := c.Deprecation(string(runtime))
+	if deprecation == nil && !result.IsLegacy() {
+		return result
+	}
+	if deprecation != nil {
+		result.ReplacedBy = serverlessv1alpha2.Runtime(deprecation.ReplacedBy)
+		if !deprecation.EndOfLife.IsZero() {
+			result.EndOfLife = &deprecation.EndOfLife.Time
+		}
+	}
+
+	if result.IsLegacy() {
+		// legacy runtimes have no images, so they can only be upgraded or blocked
+		// they are blocked only after the end of life configured for them, until then they fall back to their supported equivalent
+		if result.ReplacedBy == "" {
+			result.ReplacedBy = runtime.SupportedRuntimeEquivalent()
+		}
+		if c.Policy == config.RuntimePolicyBlock && result.isEndOfLife(now) {
+			return result.withAction(ActionBlock, deprecation)
+		}
+		result.RunWith = result.ReplacedBy
+		return result.withAction(ActionUpgrade, deprecation)
+	}
+
+	if !result.isEndOfLife(now) {
+		return result.withAction(ActionWarn, deprecation)
+	}
+
+	switch c.Policy {
+	case config.RuntimePolicyBlock:
+		return result.withAction(ActionBlock, deprecation)
+	case config.RuntimePolicyAutoUpgrade:
+		if result.ReplacedBy == "" {
+			return result.withAction(ActionWarn, deprecation)
+		}
+		if f.Status.Runtime == result.ReplacedBy || c.UpgradeWindow.IsOpen(now) {
+			result.RunWith = result.ReplacedBy
+			return result.withAction(ActionUpgrade, deprecation)
+		}
+		result.upgradePending = true
+		return result.withAction(ActionWarn, deprecation)
+	default:
+		return result.withAction(ActionWarn, deprecation)
+	}
+}
+
+// isEndOfLife checks if the end of life of the runtime is configured and has passed
+func (r Result) isEndOfLife(now time.Time) bool {
+	return r.EndOfLife != nil && !now.Before(*r.EndOfLife)
+}
+
+func (r Result) withAction(action Action, deprecation *config.RuntimeDeprecation) Result {
+	r.Action = action
+	r.Message = r.message(deprecation)
+	return r
+}
+
+func (r Result) message(deprecation *config.RuntimeDeprecation) string {
+	msg := fmt.Sprintf("runtime %s is deprecated", r.Runtime)
+	if r.EndOfLife != nil {
+		msg = fmt.Sprintf("%s (end of life on %s)", msg, r.EndOfLife.Format(time.DateOnly))
+	}
+	switch {
+	case r.upgradePending:
+		msg = fmt.Sprintf("%s, the function will be upgraded to %s in the next upgrade window", msg, r.ReplacedBy)
+	case r.Action == ActionUpgrade:
+		msg = fmt.Sprintf("%s, upgraded to %s", msg, r.RunWith)
+	case r.Action == ActionBlock:
+		msg = fmt.Sprintf("%s, migrate the function to another runtime", msg)
+		if r.ReplacedBy != "" {
+			msg = fmt.Sprintf("%s, for example, %s", msg, r.ReplacedBy)
+		}
+	default:
+		if r.ReplacedBy != "" {
+			msg = fmt.Sprintf("%s, migrate the function to %s", msg, r.ReplacedBy)
+		}
+	}
+	if deprecation != nil && deprecation.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, deprecation.Message)
+	}
+	return msg
+}
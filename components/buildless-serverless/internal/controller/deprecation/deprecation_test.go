@@ -0,0 +1,187 @@
+package deprecation
+
+import (
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	endOfLife := time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+	beforeEndOfLife := endOfLife.Add(-24 * time.Hour)
+	afterEndOfLife := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC) // monday
+	nodejs20Deprecation := config.RuntimeDeprecation{
+		Runtime:    "nodejs20",
+		EndOfLife:  config.Date{Time: endOfLife},
+		ReplacedBy: "nodejs22",
+	}
+
+	tests := []struct {
+		name          string
+		function      serverlessv1alpha2.Function
+		lifecycle     config.RuntimeLifecycle
+		now           time.Time
+		wantAction    Action
+		wantRunWith   serverlessv1alpha2.Runtime
+		wantMessage   string
+		wantEndOfLife *time.Time
+	}{
+		{
+			name:        "runtime is not deprecated",
+			function:    functionWithRuntime(serverlessv1alpha2.NodeJs22),
+			lifecycle:   config.RuntimeLifecycle{Policy: config.RuntimePolicyBlock, Deprecations: []config.RuntimeDeprecation{nodejs20Deprecation}},
+			now:         afterEndOfLife,
+			wantAction:  ActionNone,
+			wantRunWith: serverlessv1alpha2.NodeJs22,
+		},
+		{
+			name:     "runtime without end of life is only deprecated",
+			function: functionWithRuntime(serverlessv1alpha2.NodeJs20),
+			lifecycle: config.RuntimeLifecycle{Policy: config.RuntimePolicyBlock, Deprecations: []config.RuntimeDeprecation{
+				{Runtime: "nodejs20", ReplacedBy: "nodejs22", Message: "see the migration guide"},
+			}},
+			now:         afterEndOfLife,
+			wantAction:  ActionWarn,
+			wantRunWith: serverlessv1alpha2.NodeJs20,
+			wantMessage: "runtime nodejs20 is deprecated, migrate the function to nodejs22: see the migration guide",
+		},
+		{
+			name:          "policy is not applied before end of life",
+			function:      functionWithRuntime(serverlessv1alpha2.NodeJs20),
+			lifecycle:     config.RuntimeLifecycle{Policy: config.RuntimePolicyBlock, Deprecations: []config.RuntimeDeprecation{nodejs20Deprecation}},
+			now:           beforeEndOfLife,
+			wantAction:    ActionWarn,
+			wantRunWith:   serverlessv1alpha2.NodeJs20,
+			wantMessage:   "runtime nodejs20 is deprecated (end of life on 2026-04-30), migrate the function to nodejs22",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name:          "warn policy after end of life",
+			function:      functionWithRuntime(serverlessv1alpha2.NodeJs20),
+			lifecycle:     config.RuntimeLifecycle{Policy: config.RuntimePolicyWarn, Deprecations: []config.RuntimeDeprecation{nodejs20Deprecation}},
+			now:           afterEndOfLife,
+			wantAction:    ActionWarn,
+			wantRunWith:   serverlessv1alpha2.NodeJs20,
+			wantMessage:   "runtime nodejs20 is deprecated (end of life on 2026-04-30), migrate the function to nodejs22",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name:          "block policy after end of life",
+			function:      functionWithRuntime(serverlessv1alpha2.NodeJs20),
+			lifecycle:     config.RuntimeLifecycle{Policy: config.RuntimePolicyBlock, Deprecations: []config.RuntimeDeprecation{nodejs20Deprecation}},
+			now:           afterEndOfLife,
+			wantAction:    ActionBlock,
+			wantRunWith:   serverlessv1alpha2.NodeJs20,
+			wantMessage:   "runtime nodejs20 is deprecated (end of life on 2026-04-30), migrate the function to another runtime, for example, nodejs22",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name:     "autoUpgrade policy in the upgrade window",
+			function: functionWithRuntime(serverlessv1alpha2.NodeJs20),
+			lifecycle: config.RuntimeLifecycle{
+				Policy:        config.RuntimePolicyAutoUpgrade,
+				UpgradeWindow: config.MaintenanceWindow{Days: []string{"mon"}, Start: "10:00", Duration: 4 * time.Hour},
+				Deprecations:  []config.RuntimeDeprecation{nodejs20Deprecation},
+			},
+			now:           afterEndOfLife,
+			wantAction:    ActionUpgrade,
+			wantRunWith:   serverlessv1alpha2.NodeJs22,
+			wantMessage:   "runtime nodejs20 is deprecated (end of life on 2026-04-30), upgraded to nodejs22",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name:     "autoUpgrade policy outside the upgrade window",
+			function: functionWithRuntime(serverlessv1alpha2.NodeJs20),
+			lifecycle: config.RuntimeLifecycle{
+				Policy:        config.RuntimePolicyAutoUpgrade,
+				UpgradeWindow: config.MaintenanceWindow{Days: []string{"sat", "sun"}, Start: "10:00", Duration: 4 * time.Hour},
+				Deprecations:  []config.RuntimeDeprecation{nodejs20Deprecation},
+			},
+			now:           afterEndOfLife,
+			wantAction:    ActionWarn,
+			wantRunWith:   serverlessv1alpha2.NodeJs20,
+			wantMessage:   "runtime nodejs20 is deprecated (end of life on 2026-04-30), the function will be upgraded to nodejs22 in the next upgrade window",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name: "autoUpgrade policy keeps upgraded function outside the upgrade window",
+			function: serverlessv1alpha2.Function{
+				Spec:   serverlessv1alpha2.FunctionSpec{Runtime: serverlessv1alpha2.NodeJs20},
+				Status: serverlessv1alpha2.FunctionStatus{Runtime: serverlessv1alpha2.NodeJs22},
+			},
+			lifecycle: config.RuntimeLifecycle{
+				Policy:        config.RuntimePolicyAutoUpgrade,
+				UpgradeWindow: config.MaintenanceWindow{Days: []string{"sat", "sun"}, Start: "10:00", Duration: 4 * time.Hour},
+				Deprecations:  []config.RuntimeDeprecation{nodejs20Deprecation},
+			},
+			now:           afterEndOfLife,
+			wantAction:    ActionUpgrade,
+			wantRunWith:   serverlessv1alpha2.NodeJs22,
+			wantMessage:   "runtime nodejs20 is deprecated (end of life on 2026-04-30), upgraded to nodejs22",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name:        "legacy runtime is upgraded to its supported equivalent",
+			function:    functionWithRuntime(serverlessv1alpha2.NodeJs18),
+			lifecycle:   config.RuntimeLifecycle{Policy: config.RuntimePolicyWarn},
+			now:         afterEndOfLife,
+			wantAction:  ActionUpgrade,
+			wantRunWith: serverlessv1alpha2.NodeJs20,
+			wantMessage: "runtime nodejs18 is deprecated, upgraded to nodejs20",
+		},
+		{
+			name:        "legacy runtime without end of life is upgraded with block policy",
+			function:    functionWithRuntime(serverlessv1alpha2.NodeJs18),
+			lifecycle:   config.RuntimeLifecycle{Policy: config.RuntimePolicyBlock},
+			now:         afterEndOfLife,
+			wantAction:  ActionUpgrade,
+			wantRunWith: serverlessv1alpha2.NodeJs20,
+			wantMessage: "runtime nodejs18 is deprecated, upgraded to nodejs20",
+		},
+		{
+			name:     "legacy runtime before its end of life is upgraded with block policy",
+			function: functionWithRuntime(serverlessv1alpha2.NodeJs18),
+			lifecycle: config.RuntimeLifecycle{
+				Policy:       config.RuntimePolicyBlock,
+				Deprecations: []config.RuntimeDeprecation{{Runtime: "nodejs18", EndOfLife: config.Date{Time: endOfLife}}},
+			},
+			now:           beforeEndOfLife,
+			wantAction:    ActionUpgrade,
+			wantRunWith:   serverlessv1alpha2.NodeJs20,
+			wantMessage:   "runtime nodejs18 is deprecated (end of life on 2026-04-30), upgraded to nodejs20",
+			wantEndOfLife: &endOfLife,
+		},
+		{
+			name:     "legacy runtime after its end of life is blocked with block policy",
+			function: functionWithRuntime(serverlessv1alpha2.NodeJs18),
+			lifecycle: config.RuntimeLifecycle{
+				Policy:       config.RuntimePolicyBlock,
+				Deprecations: []config.RuntimeDeprecation{{Runtime: "nodejs18", EndOfLife: config.Date{Time: endOfLife}}},
+			},
+			now:           afterEndOfLife,
+			wantAction:    ActionBlock,
+			wantRunWith:   serverlessv1alpha2.NodeJs18,
+			wantMessage:   "runtime nodejs18 is deprecated (end of life on 2026-04-30), migrate the function to another runtime, for example, nodejs20",
+			wantEndOfLife: &endOfLife,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Check(&tt.function, tt.lifecycle, tt.now)
+
+			require.Equal(t, tt.wantAction, result.Action)
+			require.Equal(t, tt.wantRunWith, result.RunWith)
+			require.Equal(t, tt.wantMessage, result.Message)
+			require.Equal(t, tt.wantEndOfLife, result.EndOfLife)
+		})
+	}
+}
+
+func functionWithRuntime(runtime serverlessv1alpha2.Runtime) serverlessv1alpha2.Function {
+	return serverlessv1alpha2.Function{
+		Spec: serverlessv1alpha2.FunctionSpec{Runtime: runtime},
+	}
+}
@@ -0,0 +1,114 @@
+package deprecation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FunctionReport describes the Function using the deprecated runtime
+type FunctionReport struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Runtime    string `json:"runtime"`
+	RunWith    string `json:"runWith"`
+	EndOfLife  string `json:"endOfLife,omitempty"`
+	ReplacedBy string `json:"replacedBy,omitempty"`
+	Action     Action `json:"action"`
+	Message    string `json:"message"`
+}
+
+// Report lists all Functions using runtimes deprecated in the Function Controller's configuration or in their FunctionRuntimes
+// the configuration takes precedence, as it can upgrade or block Functions
+func Report(ctx context.Context, reader client.Reader, c config.RuntimeLifecycle, now time.Time) ([]FunctionReport, error) {
+	functions := &serverlessv1alpha2.FunctionList{}
+	if err := reader.List(ctx, functions); err != nil {
+		return nil, errors.Wrap(err, "while listing functions")
+	}
+
+	deprecatedRuntimes, err := deprecatedCatalogRuntimes(ctx, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []FunctionReport{}
+	for i := range functions.Items {
+		f := &functions.Items[i]
+		result := Check(f, c, now)
+		if !result.IsDeprecated() {
+			if functionRuntime, ok := deprecatedRuntimes[string(f.Spec.Runtime)]; ok {
+				reports = append(reports, catalogRuntimeReport(f, functionRuntime))
+			}
+			continue
+		}
+		report := FunctionReport{
+			Namespace:  f.GetNamespace(),
+			Name:       f.GetName(),
+			Runtime:    string(result.Runtime),
+			RunWith:    string(result.RunWith),
+			ReplacedBy: string(result.ReplacedBy),
+			Action:     result.Action,
+			Message:    result.Message,
+		}
+		if result.EndOfLife != nil {
+			report.EndOfLife = result.EndOfLife.Format(time.DateOnly)
+		}
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Namespace != reports[j].Namespace {
+			return reports[i].Namespace < reports[j].Namespace
+		}
+		return reports[i].Name < reports[j].Name
+	})
+	return reports, nil
+}
+
+// deprecatedCatalogRuntimes returns deprecated FunctionRuntimes by their names
+func deprecatedCatalogRuntimes(ctx context.Context, reader client.Reader) (map[string]*serverlessv1alpha2.FunctionRuntime, error) {
+	functionRuntimes := &serverlessv1alpha2.FunctionRuntimeList{}
+	err := reader.List(ctx, functionRuntimes)
+	if meta.IsNoMatchError(err) {
+		return map[string]*serverlessv1alpha2.FunctionRuntime{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing function runtimes")
+	}
+
+	result := map[string]*serverlessv1alpha2.FunctionRuntime{}
+	for i := range functionRuntimes.Items {
+		functionRuntime := &functionRuntimes.Items[i]
+		if functionRuntime.Spec.Deprecated {
+			result[functionRuntime.GetName()] = functionRuntime
+		}
+	}
+	return result, nil
+}
+
+func catalogRuntimeReport(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) FunctionReport {
+	return FunctionReport{
+		Namespace: f.GetNamespace(),
+		Name:      f.GetName(),
+		Runtime:   string(f.Spec.Runtime),
+		RunWith:   string(f.Spec.Runtime),
+		Action:    ActionWarn,
+		Message:   CatalogRuntimeMessage(functionRuntime),
+	}
+}
+
+// CatalogRuntimeMessage explains to users that the runtime is deprecated in its FunctionRuntime
+func CatalogRuntimeMessage(functionRuntime *serverlessv1alpha2.FunctionRuntime) string {
+	msg := fmt.Sprintf("runtime %s is deprecated", functionRuntime.GetName())
+	if functionRuntime.Spec.DeprecationMessage != "" {
+		msg = fmt.Sprintf("%s: %s", msg, functionRuntime.Spec.DeprecationMessage)
+	}
+	return msg
+}
@@ -0,0 +1,92 @@
+package deprecation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReport(t *testing.T) {
+	t.Run("should list functions using deprecated runtimes", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			reportFunction("test-ns-2", "test-nodejs20", serverlessv1alpha2.NodeJs20),
+			reportFunction("test-ns-1", "test-nodejs22", serverlessv1alpha2.NodeJs22),
+			reportFunction("test-ns-1", "test-python312", serverlessv1alpha2.Python312),
+			&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "nodejs20"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Deprecated: true, DeprecationMessage: "migrate the function to nodejs22"},
+			},
+			&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "nodejs22"},
+			},
+			&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "python312"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Deprecated: true, DeprecationMessage: "migrate the function to nodejs22"},
+			},
+		).Build()
+		lifecycle := config.RuntimeLifecycle{
+			Policy: config.RuntimePolicyBlock,
+			Deprecations: []config.RuntimeDeprecation{{
+				Runtime:    "nodejs20",
+				EndOfLife:  config.Date{Time: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+				ReplacedBy: "nodejs22",
+			}},
+		}
+
+		// Act
+		reports, err := Report(context.Background(), k8sClient, lifecycle, time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC))
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, []FunctionReport{
+			{
+				Namespace: "test-ns-1",
+				Name:      "test-python312",
+				Runtime:   "python312",
+				RunWith:   "python312",
+				Action:    ActionWarn,
+				Message:   "runtime python312 is deprecated: migrate the function to nodejs22",
+			},
+			{
+				Namespace:  "test-ns-2",
+				Name:       "test-nodejs20",
+				Runtime:    "nodejs20",
+				RunWith:    "nodejs20",
+				EndOfLife:  "2026-04-30",
+				ReplacedBy: "nodejs22",
+				Action:     ActionBlock,
+				Message:    "runtime nodejs20 is deprecated (end of life on 2026-04-30), migrate the function to another runtime, for example, nodejs22",
+			},
+		}, reports)
+	})
+	t.Run("should return empty list without functions", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		// Act
+		reports, err := Report(context.Background(), k8sClient, config.RuntimeLifecycle{}, time.Now())
+
+		// Assert
+		require.NoError(t, err)
+		require.Empty(t, reports)
+	})
+}
+
+func reportFunction(namespace, name string, functionRuntime serverlessv1alpha2.Runtime) *serverlessv1alpha2.Function {
+	return &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       serverlessv1alpha2.FunctionSpec{Runtime: functionRuntime},
+	}
+}
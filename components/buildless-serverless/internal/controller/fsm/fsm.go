@@ -10,11 +10,18 @@ import (
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/deprecation"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
 	serverlessmetrics "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/upgrade"
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -24,12 +31,25 @@ import (
 type StateFn func(context.Context, *StateMachine) (StateFn, *ctrl.Result, error)
 
 type SystemState struct {
-	Function          serverlessv1alpha2.Function
-	statusSnapshot    serverlessv1alpha2.FunctionStatus
-	BuiltDeployment   *resources.Deployment
-	ClusterDeployment *appsv1.Deployment
-	Commit            string
-	GitAuth           *git.GitAuth
+	Function                serverlessv1alpha2.Function
+	FunctionRuntime         *serverlessv1alpha2.FunctionRuntime
+	RuntimeCheck            deprecation.Result
+	statusSnapshot          serverlessv1alpha2.FunctionStatus
+	BuiltDeployment         *resources.Deployment
+	ClusterDeployment       *appsv1.Deployment
+	ClusterJob              *batchv1.Job
+	ClusterDisruptionBudget *policyv1.PodDisruptionBudget
+	Commit                  string
+	GitAuth                 *git.GitAuth
+	SourceHash              string
+	OCIDigest               string
+	ArchiveRevision         string
+	ArchiveAuth             *archive.Auth
+	InlineSourcesConfigMap  string
+	// RuntimeImageDigestResolvedAt is when the digest the runtime image is pinned to was resolved
+	RuntimeImageDigestResolvedAt *metav1.Time
+	// RuntimeImageSignatureErr is why the signature of the runtime image isn't verified, the workload isn't changed until it's fixed
+	RuntimeImageSignatureErr error
 }
 
 func (s *SystemState) saveStatusSnapshot() {
@@ -48,7 +68,10 @@ type StateMachine struct {
 	FunctionConfig config.FunctionConfig
 	Scheme         *apimachineryruntime.Scheme
 	GitChecker     git.AsyncLatestCommitChecker
+	ArchiveChecker archive.AsyncLatestRevisionChecker
 	EventRecorder  record.EventRecorder
+	Rollout        *upgrade.Rollout
+	SignatureCache *oci.SignatureCache
 }
 
 func (m *StateMachine) stateFnName() string {
@@ -100,7 +123,7 @@ type StateMachineReconciler interface {
 	Reconcile(ctx context.Context) (ctrl.Result, error)
 }
 
-func New(client client.Client, functionConfig config.FunctionConfig, instance *serverlessv1alpha2.Function, startState StateFn, recorder record.EventRecorder, gitChecker git.AsyncLatestCommitChecker, scheme *apimachineryruntime.Scheme, log *zap.SugaredLogger) StateMachineReconciler {
+func New(client client.Client, functionConfig config.FunctionConfig, instance *serverlessv1alpha2.Function, startState StateFn, recorder record.EventRecorder, gitChecker git.AsyncLatestCommitChecker, archiveChecker archive.AsyncLatestRevisionChecker, rollout *upgrade.Rollout, signatureCache *oci.SignatureCache, scheme *apimachineryruntime.Scheme, log *zap.SugaredLogger) StateMachineReconciler {
 	sm := StateMachine{
 		nextFn: startState,
 		State: SystemState{
@@ -111,7 +134,10 @@ func New(client client.Client, functionConfig config.FunctionConfig, instance *s
 		Client:         client,
 		Scheme:         scheme,
 		GitChecker:     gitChecker,
+		ArchiveChecker: archiveChecker,
 		EventRecorder:  recorder,
+		Rollout:        rollout,
+		SignatureCache: signatureCache,
 	}
 	sm.State.saveStatusSnapshot()
 	return &sm
@@ -22,14 +22,22 @@ import (
 	"time"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/async"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/state"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/upgrade"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -37,34 +45,58 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-const healthCheckTimeout = time.Second
+const (
+	healthCheckTimeout = time.Second
+
+	// configMapSourceIndex indexes Functions by the name of their source ConfigMap
+	configMapSourceIndex = "spec.source.configMap.name"
+)
 
 // FunctionReconciler reconciles a Function object
 type FunctionReconciler struct {
 	client.Client
-	Scheme        *runtime.Scheme
-	Log           *zap.SugaredLogger
-	Config        config.FunctionConfig
-	EventRecorder record.EventRecorder
-	GitChecker    git.AsyncLatestCommitChecker
-	HealthCh      chan bool
+	Scheme         *runtime.Scheme
+	Log            *zap.SugaredLogger
+	Config         config.FunctionConfig
+	EventRecorder  record.EventRecorder
+	GitChecker     git.AsyncLatestCommitChecker
+	ArchiveChecker archive.AsyncLatestRevisionChecker
+	Rollout        *upgrade.Rollout
+	SignatureCache *oci.SignatureCache
+	AsyncQueue     *async.Queue
+	HealthCh       chan bool
+
+	// functionCache serves indexed Functions, the client reads them from the API server
+	functionCache client.Reader
 }
 
 // +kubebuilder:rbac:groups=serverless.kyma-project.io,resources=functions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=serverless.kyma-project.io,resources=functions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=serverless.kyma-project.io,resources=functionruntimes,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=apps,resources=deployments/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // TODO: This is temporary, it is necessary to delete orphaned resources
-// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=list;delete
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=list;delete
-// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=list;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apirules,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=security.istio.io,resources=requestauthentications;authorizationpolicies,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -79,24 +111,46 @@ func (fr *FunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	var instance serverlessv1alpha2.Function
 	if err := fr.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if k8serrors.IsNotFound(err) {
+			// deleted function doesn't wait for the runtime image upgrade anymore
+			fr.Rollout.Forget(req.String())
+			fr.AsyncQueue.Forget(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	if !instance.DeletionTimestamp.IsZero() {
+		fr.AsyncQueue.Forget(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
+	if !instance.HasAsync() {
+		fr.AsyncQueue.Forget(req.NamespacedName)
+	}
 
-	sm := fsm.New(fr.Client, fr.Config, &instance, state.StartState(), fr.EventRecorder, fr.GitChecker, fr.Scheme, log)
+	sm := fsm.New(fr.Client, fr.Config, &instance, state.StartState(), fr.EventRecorder, fr.GitChecker, fr.ArchiveChecker, fr.Rollout, fr.SignatureCache, fr.Scheme, log)
 	return sm.Reconcile(ctx)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (fr *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) (controller.Controller, error) {
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &serverlessv1alpha2.Function{}, configMapSourceIndex, configMapSourceName)
+	if err != nil {
+		return nil, err
+	}
+	fr.functionCache = mgr.GetCache()
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("function-controller").
 		For(&serverlessv1alpha2.Function{}).
 		WithEventFilter(buildPredicates()).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&batchv1.Job{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Watches(&serverlessv1alpha2.FunctionRuntime{}, handler.EnqueueRequestsFromMapFunc(fr.functionsUsingRuntime)).
+		// only metadata of ConfigMaps is cached, their content is read by the Function's reconciliation
+		WatchesMetadata(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(fr.functionsUsingConfigMap)).
 		Named("function").
 		WithOptions(controller.Options{
 			RateLimiter: workqueue.NewTypedMaxOfRateLimiter[reconcile.Request](
@@ -112,6 +166,54 @@ func (fr *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) (controller.Con
 		Build(fr)
 }
 
+// functionsUsingRuntime maps the FunctionRuntime to requests of all Functions using it, including Functions upgraded to its runtime,
+// so changes in the runtime catalog are rolled out to the Functions
+func (fr *FunctionReconciler) functionsUsingRuntime(ctx context.Context, obj client.Object) []reconcile.Request {
+	var functions serverlessv1alpha2.FunctionList
+	if err := fr.List(ctx, &functions); err != nil {
+		fr.Log.Error(err, "unable to list Functions using FunctionRuntime", "FunctionRuntime", obj.GetName())
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, f := range functions.Items {
+		if string(f.Spec.Runtime.SupportedRuntimeEquivalent()) != obj.GetName() && string(f.Status.Runtime) != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&f),
+		})
+	}
+	return requests
+}
+
+// functionsUsingConfigMap maps the ConfigMap to requests of Functions using it as their source,
+// so changes of the sources are rolled out without waiting for the periodic reconciliation
+func (fr *FunctionReconciler) functionsUsingConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	var functions serverlessv1alpha2.FunctionList
+	err := fr.functionCache.List(ctx, &functions,
+		client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{configMapSourceIndex: obj.GetName()})
+	if err != nil {
+		fr.Log.Error(err, "unable to list Functions using ConfigMap", "ConfigMap", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, f := range functions.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&f),
+		})
+	}
+	return requests
+}
+
+func configMapSourceName(obj client.Object) []string {
+	f, ok := obj.(*serverlessv1alpha2.Function)
+	if !ok || !f.HasConfigMapSources() {
+		return nil
+	}
+	return []string{f.Spec.Source.ConfigMap.Name}
+}
+
 func (fr *FunctionReconciler) sendHealthCheck() {
 	fr.Log.Debug("health check request received")
 
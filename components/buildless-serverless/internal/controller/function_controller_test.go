@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestFunctionReconciler_functionsUsingConfigMap(t *testing.T) {
+	t.Run("map configmap to functions using it as source", func(t *testing.T) {
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithIndex(&serverlessv1alpha2.Function{}, configMapSourceIndex, configMapSourceName).
+			WithObjects(
+				fixConfigMapFunction("test-ns", "first-function", "function-sources"),
+				fixConfigMapFunction("test-ns", "second-function", "other-sources"),
+				fixConfigMapFunction("other-ns", "third-function", "function-sources"),
+				&serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{Name: "inline-function", Namespace: "test-ns"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Source: serverlessv1alpha2.Source{
+							Inline: &serverlessv1alpha2.InlineSource{Source: "source"}}}},
+			).Build()
+		fr := &FunctionReconciler{
+			Log:           zap.NewNop().Sugar(),
+			functionCache: k8sClient,
+		}
+
+		requests := fr.functionsUsingConfigMap(context.Background(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "function-sources", Namespace: "test-ns"},
+		})
+
+		require.Equal(t, []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Namespace: "test-ns", Name: "first-function"}},
+		}, requests)
+	})
+}
+
+func fixConfigMapFunction(namespace, name, configMapName string) *serverlessv1alpha2.Function {
+	return &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Source: serverlessv1alpha2.Source{
+				ConfigMap: &serverlessv1alpha2.ConfigMapSource{Name: configMapName}}},
+	}
+}
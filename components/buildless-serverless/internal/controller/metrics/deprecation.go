@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/deprecation"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const deprecationReportTimeout = 10 * time.Second
+
+var deprecatedRuntimeFunctionsDesc = prometheus.NewDesc(
+	"serverless_function_deprecated_runtime",
+	"Number of functions using deprecated runtimes (computed on every scrape)",
+	[]string{"runtime", "replaced_by", "end_of_life", "action"},
+	nil,
+)
+
+// deprecatedRuntimesCollector counts Functions using deprecated runtimes
+type deprecatedRuntimesCollector struct {
+	reader    client.Reader
+	lifecycle config.RuntimeLifecycle
+}
+
+type deprecatedRuntimeKey struct {
+	runtime    string
+	replacedBy string
+	endOfLife  string
+	action     string
+}
+
+func RegisterDeprecatedRuntimes(reader client.Reader, lifecycle config.RuntimeLifecycle) {
+	metrics.Registry.MustRegister(&deprecatedRuntimesCollector{
+		reader:    reader,
+		lifecycle: lifecycle,
+	})
+}
+
+func (c *deprecatedRuntimesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- deprecatedRuntimeFunctionsDesc
+}
+
+func (c *deprecatedRuntimesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), deprecationReportTimeout)
+	defer cancel()
+
+	reports, err := deprecation.Report(ctx, c.reader, c.lifecycle, time.Now())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(deprecatedRuntimeFunctionsDesc, err)
+		return
+	}
+
+	counts := map[deprecatedRuntimeKey]int{}
+	for _, report := range reports {
+		counts[deprecatedRuntimeKey{
+			runtime:    report.Runtime,
+			replacedBy: report.ReplacedBy,
+			endOfLife:  report.EndOfLife,
+			action:     string(report.Action),
+		}]++
+	}
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(deprecatedRuntimeFunctionsDesc, prometheus.GaugeValue, float64(count),
+			key.runtime, key.replacedBy, key.endOfLife, key.action)
+	}
+}
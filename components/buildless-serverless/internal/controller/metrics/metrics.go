@@ -64,10 +64,18 @@ func runtimeName(f serverlessv1alpha2.Function) string {
 }
 
 func sourceType(f serverlessv1alpha2.Function) string {
-	if f.HasGitSources() {
+	switch {
+	case f.HasGitSources():
 		return "git"
+	case f.HasConfigMapSources():
+		return "configmap"
+	case f.HasOCISources():
+		return "oci"
+	case f.HasArchiveSources():
+		return "archive"
+	default:
+		return "inline"
 	}
-	return "inline"
 }
 
 func PublishFunctionsTotal(f serverlessv1alpha2.Function) {
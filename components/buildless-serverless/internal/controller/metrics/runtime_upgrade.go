@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/upgrade"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	runtimeImageUpgradeFunctionsDesc = prometheus.NewDesc(
+		"serverless_runtime_image_upgrade_functions",
+		"Number of functions in the staged runtime image upgrade by the upgrade state",
+		[]string{"state"},
+		nil,
+	)
+	runtimeImageUpgradePausedDesc = prometheus.NewDesc(
+		"serverless_runtime_image_upgrade_paused",
+		"Whether the staged runtime image upgrade is paused after failed upgrades (1) or not (0)",
+		nil,
+		nil,
+	)
+)
+
+// runtimeImageUpgradeCollector publishes the progress of the staged runtime image upgrade
+type runtimeImageUpgradeCollector struct {
+	rollout *upgrade.Rollout
+}
+
+func RegisterRuntimeImageUpgrade(rollout *upgrade.Rollout) {
+	metrics.Registry.MustRegister(&runtimeImageUpgradeCollector{
+		rollout: rollout,
+	})
+}
+
+func (c *runtimeImageUpgradeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- runtimeImageUpgradeFunctionsDesc
+	ch <- runtimeImageUpgradePausedDesc
+}
+
+func (c *runtimeImageUpgradeCollector) Collect(ch chan<- prometheus.Metric) {
+	progress := c.rollout.Progress()
+
+	for state, count := range map[string]int{
+		"pending":    progress.Pending,
+		"inProgress": progress.InProgress,
+		"failed":     progress.Failed,
+	} {
+		ch <- prometheus.MustNewConstMetric(runtimeImageUpgradeFunctionsDesc, prometheus.GaugeValue, float64(count), state)
+	}
+
+	paused := 0.0
+	if progress.Paused {
+		paused = 1
+	}
+	ch <- prometheus.MustNewConstMetric(runtimeImageUpgradePausedDesc, prometheus.GaugeValue, paused)
+}
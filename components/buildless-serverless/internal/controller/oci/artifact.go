@@ -0,0 +1,198 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+const (
+	// annotation set by `oras push` on layers created from directories
+	annotationUnpack = "io.deis.oras.content.unpack"
+
+	maxManifestSize = 4 * 1024 * 1024
+	maxArtifactSize = 64 * 1024 * 1024
+)
+
+// File is a single file stored in the OCI artifact
+type File struct {
+	Path string
+	Data []byte
+}
+
+// ParseReference parses and validates the OCI artifact reference
+func ParseReference(reference string) (registry.Reference, error) {
+	ref, err := registry.ParseReference(reference)
+	if err != nil {
+		return registry.Reference{}, err
+	}
+	if ref.Reference == "" {
+		return registry.Reference{}, errors.New(fmt.Sprintf("reference %s has no tag or digest", reference))
+	}
+	return ref, nil
+}
+
+// ResolveDigest returns digest of the manifest the reference points to
+func ResolveDigest(ctx context.Context, reference string, creds *Credentials) (string, error) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return "", errors.Wrap(err, "while parsing reference")
+	}
+	if d, err := ref.Digest(); err == nil {
+		// reference is already pinned
+		return d.String(), nil
+	}
+
+	repo, err := newRepository(ref, creds)
+	if err != nil {
+		return "", err
+	}
+	desc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return "", errors.Wrapf(err, "while resolving %s", reference)
+	}
+	return desc.Digest.String(), nil
+}
+
+// PinnedReference returns reference pointing to the given digest
+func PinnedReference(reference, digest string) (string, error) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return "", errors.Wrap(err, "while parsing reference")
+	}
+	return fmt.Sprintf("%s/%s@%s", ref.Registry, ref.Repository, digest), nil
+}
+
+// FetchFiles downloads all titled layers of the artifact
+// layers created by `oras push` from directories are unpacked
+func FetchFiles(ctx context.Context, reference string, creds *Credentials) ([]File, error) {
+	ref, err := ParseReference(reference)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing reference")
+	}
+	repo, err := newRepository(ref, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := fetchManifest(ctx, repo, ref.Reference, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []File{}
+	var totalSize int64
+	for _, layer := range manifest.Layers {
+		title := layer.Annotations[ocispec.AnnotationTitle]
+		if title == "" {
+			continue
+		}
+		totalSize += layer.Size
+		if totalSize > maxArtifactSize {
+			return nil, errors.New(fmt.Sprintf("artifact %s is bigger than %d bytes", reference, maxArtifactSize))
+		}
+
+		data, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while fetching layer %s", title)
+		}
+
+		if layer.Annotations[annotationUnpack] == "true" {
+			unpacked, err := untarGzip(data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "while unpacking layer %s", title)
+			}
+			files = append(files, unpacked...)
+			continue
+		}
+
+		if !filepath.IsLocal(title) {
+			return nil, errors.New(fmt.Sprintf("layer title %s is not a local path", title))
+		}
+		files = append(files, File{Path: filepath.Clean(title), Data: data})
+	}
+	return files, nil
+}
+
+// fetchManifest downloads the image manifest the tag or digest points to, name is the full reference used in errors
+func fetchManifest(ctx context.Context, repo *remote.Repository, reference, name string) (ocispec.Manifest, error) {
+	manifestDesc, manifestReader, err := repo.FetchReference(ctx, reference)
+	if err != nil {
+		return ocispec.Manifest{}, errors.Wrapf(err, "while fetching manifest of %s", name)
+	}
+	defer manifestReader.Close()
+	if manifestDesc.Size > maxManifestSize {
+		return ocispec.Manifest{}, errors.New(fmt.Sprintf("manifest of %s is too big", name))
+	}
+	manifestData, err := content.ReadAll(manifestReader, manifestDesc)
+	if err != nil {
+		return ocispec.Manifest{}, errors.Wrapf(err, "while reading manifest of %s", name)
+	}
+	manifest := ocispec.Manifest{}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return ocispec.Manifest{}, errors.Wrapf(err, "while parsing manifest of %s", name)
+	}
+	return manifest, nil
+}
+
+func newRepository(ref registry.Reference, creds *Credentials) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", ref.Registry, ref.Repository))
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating repository client")
+	}
+	repo.Client = &auth.Client{
+		Client:     retry.DefaultClient,
+		Cache:      auth.NewCache(),
+		Credential: creds.credentialFunc(),
+	}
+	return repo, nil
+}
+
+func untarGzip(data []byte) ([]File, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := []File{}
+	var totalSize int64
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !filepath.IsLocal(hdr.Name) {
+			return nil, errors.New(fmt.Sprintf("file %s is not a local path", hdr.Name))
+		}
+		totalSize += hdr.Size
+		if totalSize > maxArtifactSize {
+			return nil, errors.New(fmt.Sprintf("unpacked content is bigger than %d bytes", maxArtifactSize))
+		}
+		fileData, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Clean(hdr.Name), Data: fileData})
+	}
+}
@@ -0,0 +1,86 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Run("parse tagged reference", func(t *testing.T) {
+		ref, err := ParseReference("ghcr.io/user/function:v1")
+
+		require.NoError(t, err)
+		require.Equal(t, "ghcr.io", ref.Registry)
+		require.Equal(t, "user/function", ref.Repository)
+		require.Equal(t, "v1", ref.Reference)
+	})
+	t.Run("fail on reference without tag", func(t *testing.T) {
+		_, err := ParseReference("ghcr.io/user/function")
+
+		require.ErrorContains(t, err, "reference ghcr.io/user/function has no tag or digest")
+	})
+	t.Run("fail on invalid reference", func(t *testing.T) {
+		_, err := ParseReference("not a reference")
+
+		require.Error(t, err)
+	})
+}
+
+func TestPinnedReference(t *testing.T) {
+	digest := "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+
+	ref, err := PinnedReference("ghcr.io/user/function:v1", digest)
+
+	require.NoError(t, err)
+	require.Equal(t, "ghcr.io/user/function@"+digest, ref)
+}
+
+func Test_untarGzip(t *testing.T) {
+	t.Run("unpack regular files", func(t *testing.T) {
+		data := tarGzip(t, map[string]string{
+			"handler.js":     "module.exports = {}",
+			"lib/helpers.js": "exports.a = 1",
+		})
+
+		files, err := untarGzip(data)
+
+		require.NoError(t, err)
+		require.ElementsMatch(t, []File{
+			{Path: "handler.js", Data: []byte("module.exports = {}")},
+			{Path: "lib/helpers.js", Data: []byte("exports.a = 1")},
+		}, files)
+	})
+	t.Run("fail on path outside of destination", func(t *testing.T) {
+		data := tarGzip(t, map[string]string{
+			"../handler.js": "module.exports = {}",
+		})
+
+		files, err := untarGzip(data)
+
+		require.ErrorContains(t, err, "file ../handler.js is not a local path")
+		require.Nil(t, files)
+	})
+}
+
+func tarGzip(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
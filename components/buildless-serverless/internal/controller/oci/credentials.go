@@ -0,0 +1,126 @@
+package oci
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Credentials holds registry credentials read from a docker config json
+type Credentials struct {
+	auths map[string]auth.Credential
+}
+
+type dockerConfig struct {
+	Auths map[string]dockerConfigAuth `json:"auths"`
+}
+
+type dockerConfigAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// NewCredentials reads credentials from the `kubernetes.io/dockerconfigjson` Secret
+func NewCredentials(ctx context.Context, c client.Client, namespace, secretName string) (*Credentials, error) {
+	s := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pull secret")
+	}
+	data, ok := s.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("pull secret %s is missing '%s'", secretName, corev1.DockerConfigJsonKey))
+	}
+	return ParseCredentials(data)
+}
+
+// LoadCredentials reads credentials from the docker config json file
+func LoadCredentials(path string) (*Credentials, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read docker config")
+	}
+	return ParseCredentials(data)
+}
+
+// ParseCredentials parses the docker config json content
+func ParseCredentials(data []byte) (*Credentials, error) {
+	cfg := dockerConfig{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse docker config")
+	}
+
+	c := &Credentials{auths: map[string]auth.Credential{}}
+	for host, a := range cfg.Auths {
+		cred := auth.Credential{
+			Username: a.Username,
+			Password: a.Password,
+		}
+		if a.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode auth for registry %s", host)
+			}
+			username, password, found := strings.Cut(string(decoded), ":")
+			if !found {
+				return nil, errors.New(fmt.Sprintf("invalid auth format for registry %s", host))
+			}
+			cred.Username, cred.Password = username, password
+		}
+		c.auths[normalizeHost(host)] = cred
+	}
+	return c, nil
+}
+
+// MergeCredentials combines credentials, the first ones take precedence for the same registry
+func MergeCredentials(all ...*Credentials) *Credentials {
+	result := &Credentials{auths: map[string]auth.Credential{}}
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i] == nil {
+			continue
+		}
+		for host, cred := range all[i].auths {
+			result.auths[host] = cred
+		}
+	}
+	return result
+}
+
+func (c *Credentials) credentialFunc() auth.CredentialFunc {
+	return func(_ context.Context, hostport string) (auth.Credential, error) {
+		if c == nil {
+			return auth.EmptyCredential, nil
+		}
+		if cred, ok := c.auths[normalizeHost(hostport)]; ok {
+			return cred, nil
+		}
+		// docker config keeps Docker Hub credentials under its index address
+		if hostport == dockerHubHost {
+			for _, host := range []string{"index.docker.io", dockerHubRegistry} {
+				if cred, ok := c.auths[host]; ok {
+					return cred, nil
+				}
+			}
+		}
+		return auth.EmptyCredential, nil
+	}
+}
+
+// normalizeHost strips the scheme and path from docker config keys like `https://index.docker.io/v1/`
+func normalizeHost(host string) string {
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
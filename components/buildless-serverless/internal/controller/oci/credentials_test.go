@@ -0,0 +1,119 @@
+package oci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseCredentials(t *testing.T) {
+	t.Run("parse username and password", func(t *testing.T) {
+		c, err := ParseCredentials([]byte(`{"auths":{"ghcr.io":{"username":"user","password":"pass"}}}`))
+
+		require.NoError(t, err)
+		require.Equal(t, auth.Credential{Username: "user", Password: "pass"}, c.auths["ghcr.io"])
+	})
+	t.Run("parse encoded auth", func(t *testing.T) {
+		// dXNlcjpwYXNz = user:pass
+		c, err := ParseCredentials([]byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpwYXNz"}}}`))
+
+		require.NoError(t, err)
+		require.Equal(t, auth.Credential{Username: "user", Password: "pass"}, c.auths["index.docker.io"])
+	})
+	t.Run("fail on invalid auth format", func(t *testing.T) {
+		// dXNlcg== = user
+		c, err := ParseCredentials([]byte(`{"auths":{"ghcr.io":{"auth":"dXNlcg=="}}}`))
+
+		require.ErrorContains(t, err, "invalid auth format for registry ghcr.io")
+		require.Nil(t, c)
+	})
+	t.Run("fail on invalid json", func(t *testing.T) {
+		c, err := ParseCredentials([]byte(`{`))
+
+		require.ErrorContains(t, err, "failed to parse docker config")
+		require.Nil(t, c)
+	})
+}
+
+func TestNewCredentials(t *testing.T) {
+	t.Run("read credentials from secret", func(t *testing.T) {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "default"},
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"username":"user","password":"pass"}}}`),
+			},
+		}
+		c := fake.NewClientBuilder().WithObjects(s).Build()
+
+		creds, err := NewCredentials(context.Background(), c, "default", "registry-credentials")
+
+		require.NoError(t, err)
+		cred, err := creds.credentialFunc()(context.Background(), "ghcr.io")
+		require.NoError(t, err)
+		require.Equal(t, auth.Credential{Username: "user", Password: "pass"}, cred)
+	})
+	t.Run("fail when secret has no docker config", func(t *testing.T) {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "default"},
+		}
+		c := fake.NewClientBuilder().WithObjects(s).Build()
+
+		creds, err := NewCredentials(context.Background(), c, "default", "registry-credentials")
+
+		require.ErrorContains(t, err, "pull secret registry-credentials is missing '.dockerconfigjson'")
+		require.Nil(t, creds)
+	})
+}
+
+func TestCredentials_credentialFunc(t *testing.T) {
+	t.Run("return empty credential for unknown host", func(t *testing.T) {
+		c := &Credentials{auths: map[string]auth.Credential{}}
+
+		cred, err := c.credentialFunc()(context.Background(), "ghcr.io")
+
+		require.NoError(t, err)
+		require.Equal(t, auth.EmptyCredential, cred)
+	})
+	t.Run("return empty credential for nil credentials", func(t *testing.T) {
+		var c *Credentials
+
+		cred, err := c.credentialFunc()(context.Background(), "ghcr.io")
+
+		require.NoError(t, err)
+		require.Equal(t, auth.EmptyCredential, cred)
+	})
+}
+
+func TestMergeCredentials(t *testing.T) {
+	t.Run("first credentials take precedence", func(t *testing.T) {
+		first := &Credentials{auths: map[string]auth.Credential{
+			"ghcr.io": {Username: "first", Password: "first-password"},
+		}}
+		second := &Credentials{auths: map[string]auth.Credential{
+			"ghcr.io":               {Username: "second", Password: "second-password"},
+			"europe-docker.pkg.dev": {Username: "second", Password: "second-password"},
+		}}
+
+		c := MergeCredentials(first, nil, second)
+
+		require.Equal(t, map[string]auth.Credential{
+			"ghcr.io":               {Username: "first", Password: "first-password"},
+			"europe-docker.pkg.dev": {Username: "second", Password: "second-password"},
+		}, c.auths)
+	})
+	t.Run("use Docker Hub index credentials for its registry", func(t *testing.T) {
+		c := MergeCredentials(&Credentials{auths: map[string]auth.Credential{
+			"index.docker.io": {Username: "user", Password: "password"},
+		}})
+
+		cred, err := c.credentialFunc()(context.Background(), dockerHubHost)
+
+		require.NoError(t, err)
+		require.Equal(t, auth.Credential{Username: "user", Password: "password"}, cred)
+	})
+}
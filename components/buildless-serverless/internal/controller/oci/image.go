@@ -0,0 +1,75 @@
+package oci
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2/registry"
+)
+
+const (
+	dockerHubRegistry = "docker.io"
+	// dockerHubHost is the address Docker Hub's registry API is called at
+	dockerHubHost = "registry-1.docker.io"
+)
+
+// ParseImage parses the container image reference
+// images without the registry are pulled from Docker Hub like container runtimes do
+func ParseImage(image string) (registry.Reference, error) {
+	ref, err := registry.ParseReference(normalizeImage(image))
+	if err != nil {
+		return registry.Reference{}, err
+	}
+	if ref.Reference == "" {
+		ref.Reference = "latest"
+	}
+	return ref, nil
+}
+
+// ResolveImageDigest returns digest of the manifest the container image points to
+// registries listed in plainHTTPRegistries, like local test registries, are called without TLS
+func ResolveImageDigest(ctx context.Context, image string, creds *Credentials, plainHTTPRegistries []string) (string, error) {
+	ref, err := ParseImage(image)
+	if err != nil {
+		return "", errors.Wrap(err, "while parsing image")
+	}
+	if d, err := ref.Digest(); err == nil {
+		// image is already pinned
+		return d.String(), nil
+	}
+
+	repo, err := newRepository(ref, creds)
+	if err != nil {
+		return "", err
+	}
+	repo.PlainHTTP = slices.Contains(plainHTTPRegistries, ref.Registry)
+	desc, err := repo.Resolve(ctx, ref.Reference)
+	if err != nil {
+		return "", errors.Wrapf(err, "while resolving %s", image)
+	}
+	return desc.Digest.String(), nil
+}
+
+// PinnedImage returns the container image pointing to the given digest
+func PinnedImage(image, digest string) (string, error) {
+	ref, err := ParseImage(image)
+	if err != nil {
+		return "", errors.Wrap(err, "while parsing image")
+	}
+	return fmt.Sprintf("%s/%s@%s", ref.Registry, ref.Repository, digest), nil
+}
+
+// normalizeImage adds the Docker Hub registry and the `library` repository to short image names, for example, `nginx:1.27`
+func normalizeImage(image string) string {
+	domain, remainder, found := strings.Cut(image, "/")
+	if !found {
+		return fmt.Sprintf("%s/library/%s", dockerHubRegistry, image)
+	}
+	if !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		return fmt.Sprintf("%s/%s/%s", dockerHubRegistry, domain, remainder)
+	}
+	return image
+}
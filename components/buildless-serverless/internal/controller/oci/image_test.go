@@ -0,0 +1,149 @@
+package oci
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+const testImageDigest = "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		image          string
+		wantRegistry   string
+		wantRepository string
+		wantReference  string
+	}{
+		{
+			name:           "image with registry and tag",
+			image:          "europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs22:main",
+			wantRegistry:   "europe-docker.pkg.dev",
+			wantRepository: "kyma-project/prod/function-runtime-nodejs22",
+			wantReference:  "main",
+		},
+		{
+			name:           "official Docker Hub image",
+			image:          "nginx:1.27",
+			wantRegistry:   "docker.io",
+			wantRepository: "library/nginx",
+			wantReference:  "1.27",
+		},
+		{
+			name:           "Docker Hub image without tag",
+			image:          "user/runtime",
+			wantRegistry:   "docker.io",
+			wantRepository: "user/runtime",
+			wantReference:  "latest",
+		},
+		{
+			name:           "local registry image",
+			image:          "localhost:5000/runtime:v1",
+			wantRegistry:   "localhost:5000",
+			wantRepository: "runtime",
+			wantReference:  "v1",
+		},
+		{
+			name:           "pinned image",
+			image:          "ghcr.io/user/runtime@" + testImageDigest,
+			wantRegistry:   "ghcr.io",
+			wantRepository: "user/runtime",
+			wantReference:  testImageDigest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseImage(tt.image)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRegistry, ref.Registry)
+			require.Equal(t, tt.wantRepository, ref.Repository)
+			require.Equal(t, tt.wantReference, ref.Reference)
+		})
+	}
+	t.Run("fail on invalid image", func(t *testing.T) {
+		_, err := ParseImage("not an image")
+
+		require.Error(t, err)
+	})
+}
+
+func TestPinnedImage(t *testing.T) {
+	t.Run("replace tag with digest", func(t *testing.T) {
+		image, err := PinnedImage("ghcr.io/user/runtime:v1", testImageDigest)
+
+		require.NoError(t, err)
+		require.Equal(t, "ghcr.io/user/runtime@"+testImageDigest, image)
+	})
+	t.Run("pin Docker Hub image", func(t *testing.T) {
+		image, err := PinnedImage("nginx:1.27", testImageDigest)
+
+		require.NoError(t, err)
+		require.Equal(t, "docker.io/library/nginx@"+testImageDigest, image)
+	})
+}
+
+func TestResolveImageDigest(t *testing.T) {
+	t.Run("resolve tag to digest", func(t *testing.T) {
+		registryHost := startTestRegistry(t, "")
+
+		digest, err := ResolveImageDigest(context.Background(), registryHost+"/user/runtime:v1", nil, []string{registryHost})
+
+		require.NoError(t, err)
+		require.Equal(t, testImageDigest, digest)
+	})
+	t.Run("resolve tag with credentials", func(t *testing.T) {
+		registryHost := startTestRegistry(t, "user:password")
+		creds := &Credentials{auths: map[string]auth.Credential{
+			registryHost: {Username: "user", Password: "password"},
+		}}
+
+		digest, err := ResolveImageDigest(context.Background(), registryHost+"/user/runtime:v1", creds, []string{registryHost})
+
+		require.NoError(t, err)
+		require.Equal(t, testImageDigest, digest)
+	})
+	t.Run("fail without credentials", func(t *testing.T) {
+		registryHost := startTestRegistry(t, "user:password")
+
+		_, err := ResolveImageDigest(context.Background(), registryHost+"/user/runtime:v1", nil, []string{registryHost})
+
+		require.ErrorContains(t, err, "while resolving "+registryHost+"/user/runtime:v1")
+	})
+	t.Run("return digest of pinned image", func(t *testing.T) {
+		digest, err := ResolveImageDigest(context.Background(), "ghcr.io/user/runtime@"+testImageDigest, nil, nil)
+
+		require.NoError(t, err)
+		require.Equal(t, testImageDigest, digest)
+	})
+}
+
+// startTestRegistry serves the manifest of the `user/runtime:v1` image, basic auth is required when userPassword is set
+func startTestRegistry(t *testing.T, userPassword string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userPassword != "" {
+			username, password, ok := r.BasicAuth()
+			if !ok || username+":"+password != userPassword {
+				w.Header().Set("Www-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		if r.URL.Path != "/v2/user/runtime/manifests/v1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.oci.image.index.v1+json")
+		w.Header().Set("Docker-Content-Digest", testImageDigest)
+		w.Header().Set("Content-Length", "256")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
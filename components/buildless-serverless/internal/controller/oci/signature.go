@@ -0,0 +1,128 @@
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+const (
+	// media type of the payload signed by `cosign sign`
+	cosignPayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// annotation keeping the base64 encoded signature of the payload
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	maxSignaturePayloadSize = 1024 * 1024
+)
+
+// cosignPayload is the simple signing payload of the cosign signature
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// ParsePublicKeys parses PEM encoded public keys images are signed with
+func ParsePublicKeys(keys []string) ([]crypto.PublicKey, error) {
+	result := []crypto.PublicKey{}
+	for i, key := range keys {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil {
+			return nil, errors.New(fmt.Sprintf("public key %d is not PEM encoded", i))
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while parsing public key %d", i)
+		}
+		result = append(result, publicKey)
+	}
+	return result, nil
+}
+
+// VerifyImageSignature checks if the image manifest with the given digest is signed by cosign with one of the public keys
+// signatures are read from the `sha256-<digest>.sig` tag cosign stores them at, the transparency log isn't checked
+func VerifyImageSignature(ctx context.Context, image, digest string, creds *Credentials, plainHTTPRegistries []string, keys []crypto.PublicKey) error {
+	ref, err := ParseImage(image)
+	if err != nil {
+		return errors.Wrap(err, "while parsing image")
+	}
+	repo, err := newRepository(ref, creds)
+	if err != nil {
+		return err
+	}
+	repo.PlainHTTP = slices.Contains(plainHTTPRegistries, ref.Registry)
+
+	pinnedImage := fmt.Sprintf("%s/%s@%s", ref.Registry, ref.Repository, digest)
+	signatureTag := strings.Replace(digest, ":", "-", 1) + ".sig"
+	manifest, err := fetchManifest(ctx, repo, signatureTag, fmt.Sprintf("%s/%s:%s", ref.Registry, ref.Repository, signatureTag))
+	if errors.Is(err, errdef.ErrNotFound) {
+		return errors.New(fmt.Sprintf("image %s is not signed", pinnedImage))
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, layer := range manifest.Layers {
+		encodedSignature := layer.Annotations[cosignSignatureAnnotation]
+		if layer.MediaType != cosignPayloadMediaType || encodedSignature == "" || layer.Size > maxSignaturePayloadSize {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+		if err != nil {
+			continue
+		}
+		payload, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return errors.Wrapf(err, "while fetching signature of %s", pinnedImage)
+		}
+		if !verifySignature(keys, payload, signature) {
+			continue
+		}
+		p := cosignPayload{}
+		if err := json.Unmarshal(payload, &p); err != nil {
+			continue
+		}
+		// the signature is valid only for the image it was created for
+		if p.Critical.Image.DockerManifestDigest == digest {
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("image %s has no signature matching the public keys", pinnedImage))
+}
+
+func verifySignature(keys []crypto.PublicKey, payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+	for _, key := range keys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, hash[:], signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package oci
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// SignatureCache remembers image digests with verified signatures, so the registry isn't called on every reconciliation
+// the digest identifies the immutable manifest, so its verification result doesn't change until the public keys change,
+// which requires restarting the Function Controller
+// failed verifications aren't cached, so signatures added later are picked up
+type SignatureCache struct {
+	mu       sync.Mutex
+	verified sets.Set[string]
+}
+
+func NewSignatureCache() *SignatureCache {
+	return &SignatureCache{
+		verified: sets.New[string](),
+	}
+}
+
+// IsVerified checks if the signature of the image manifest with the given digest was verified before
+func (c *SignatureCache) IsVerified(image, digest string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.verified.Has(signatureCacheKey(image, digest))
+}
+
+// Verified remembers the image manifest with the given digest as verified
+func (c *SignatureCache) Verified(image, digest string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.verified.Insert(signatureCacheKey(image, digest))
+}
+
+// signatureCacheKey identifies the manifest by its repository and digest, the tag doesn't matter
+func signatureCacheKey(image, digest string) string {
+	ref, err := ParseImage(image)
+	if err != nil {
+		return fmt.Sprintf("%s@%s", image, digest)
+	}
+	return fmt.Sprintf("%s/%s@%s", ref.Registry, ref.Repository, digest)
+}
@@ -0,0 +1,29 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureCache(t *testing.T) {
+	const testDigest = "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+
+	t.Run("remember verified digest of the repository", func(t *testing.T) {
+		cache := NewSignatureCache()
+
+		cache.Verified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", testDigest)
+
+		require.True(t, cache.IsVerified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:moved", testDigest))
+		require.False(t, cache.IsVerified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs20:main", testDigest))
+		require.False(t, cache.IsVerified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main",
+			"sha256:1f5b0a9c8e3d7b2a4c6e8f0a1b3c5d7e9f1a3b5c7d9e1f3a5b7c9d1e3f5a7b9c"))
+	})
+	t.Run("nil cache doesn't remember digests", func(t *testing.T) {
+		var cache *SignatureCache
+
+		cache.Verified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", testDigest)
+
+		require.False(t, cache.IsVerified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", testDigest))
+	})
+}
@@ -0,0 +1,145 @@
+package oci
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublicKeys(t *testing.T) {
+	t.Run("parse PEM encoded keys", func(t *testing.T) {
+		key := generateTestKey(t)
+
+		keys, err := ParsePublicKeys([]string{encodeTestPublicKey(t, key)})
+
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		require.True(t, key.PublicKey.Equal(keys[0]))
+	})
+	t.Run("fail on key which isn't PEM encoded", func(t *testing.T) {
+		_, err := ParsePublicKeys([]string{"not a key"})
+
+		require.ErrorContains(t, err, "public key 0 is not PEM encoded")
+	})
+}
+
+func TestVerifyImageSignature(t *testing.T) {
+	key := generateTestKey(t)
+
+	t.Run("accept image signed with the key", func(t *testing.T) {
+		registryHost := startTestSignatureRegistry(t, signTestPayload(t, key, testImageDigest))
+
+		err := VerifyImageSignature(context.Background(), registryHost+"/user/runtime:v1", testImageDigest, nil, []string{registryHost}, []crypto.PublicKey{&key.PublicKey})
+
+		require.NoError(t, err)
+	})
+	t.Run("reject image signed with another key", func(t *testing.T) {
+		registryHost := startTestSignatureRegistry(t, signTestPayload(t, generateTestKey(t), testImageDigest))
+
+		err := VerifyImageSignature(context.Background(), registryHost+"/user/runtime:v1", testImageDigest, nil, []string{registryHost}, []crypto.PublicKey{&key.PublicKey})
+
+		require.ErrorContains(t, err, "has no signature matching the public keys")
+	})
+	t.Run("reject signature created for another image", func(t *testing.T) {
+		otherDigest := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		registryHost := startTestSignatureRegistry(t, signTestPayload(t, key, otherDigest))
+
+		err := VerifyImageSignature(context.Background(), registryHost+"/user/runtime:v1", testImageDigest, nil, []string{registryHost}, []crypto.PublicKey{&key.PublicKey})
+
+		require.ErrorContains(t, err, "has no signature matching the public keys")
+	})
+	t.Run("reject image without signature", func(t *testing.T) {
+		registryHost := startTestSignatureRegistry(t, nil)
+
+		err := VerifyImageSignature(context.Background(), registryHost+"/user/runtime:v1", testImageDigest, nil, []string{registryHost}, []crypto.PublicKey{&key.PublicKey})
+
+		require.ErrorContains(t, err, fmt.Sprintf("image %s/user/runtime@%s is not signed", registryHost, testImageDigest))
+	})
+}
+
+// testSignature is the cosign payload with its signature
+type testSignature struct {
+	payload   []byte
+	signature string
+}
+
+func generateTestKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func encodeTestPublicKey(t *testing.T, key *ecdsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func signTestPayload(t *testing.T, key *ecdsa.PrivateKey, imageDigest string) *testSignature {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry/user/runtime"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, imageDigest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+	return &testSignature{
+		payload:   payload,
+		signature: base64.StdEncoding.EncodeToString(signature),
+	}
+}
+
+// startTestSignatureRegistry serves the cosign signature of the `user/runtime` image, the image isn't signed when signature is nil
+func startTestSignatureRegistry(t *testing.T, signature *testSignature) string {
+	manifestPath := "/v2/user/runtime/manifests/" + strings.Replace(testImageDigest, ":", "-", 1) + ".sig"
+	var manifest, payload []byte
+	var payloadDigest digest.Digest
+	if signature != nil {
+		payload = signature.payload
+		payloadDigest = digest.FromBytes(payload)
+		var err error
+		manifest, err = json.Marshal(ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config: ocispec.Descriptor{
+				MediaType: "application/vnd.oci.image.config.v1+json",
+				Digest:    digest.FromString("{}"),
+				Size:      2,
+			},
+			Layers: []ocispec.Descriptor{{
+				MediaType:   cosignPayloadMediaType,
+				Digest:      payloadDigest,
+				Size:        int64(len(payload)),
+				Annotations: map[string]string{cosignSignatureAnnotation: signature.signature},
+			}},
+		})
+		require.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case signature != nil && r.URL.Path == manifestPath:
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+			w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+			_, _ = w.Write(manifest)
+		case signature != nil && r.URL.Path == "/v2/user/runtime/blobs/"+payloadDigest.String():
+			_, _ = w.Write(payload)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
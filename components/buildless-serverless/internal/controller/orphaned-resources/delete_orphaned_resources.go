@@ -50,6 +50,9 @@ func DeleteOrphanedResources(ctx context.Context, m manager.Manager) error {
 
 	// delete orphaned jobs
 	for _, job := range jobs.Items {
+		if isFunctionWorkloadJob(&job) {
+			continue
+		}
 		err := deleteOrphanedResource(ctx, m.GetClient(), &job)
 		if err != nil && !errors.IsNotFound(err) {
 			collectedErrors = append(collectedErrors, fmt.Sprintf("failed to delete orphaned-resources %s/%s: %s", job.Namespace, job.Name, err))
@@ -138,6 +141,15 @@ func DeleteOrphanedResources(ctx context.Context, m manager.Manager) error {
 	return nil
 }
 
+// isFunctionWorkloadJob returns true for Jobs run by scheduled or batch functions, they are owned by functions and aren't orphaned
+func isFunctionWorkloadJob(job *batchv1.Job) bool {
+	switch job.GetLabels()["serverless.kyma-project.io/resource"] {
+	case "schedule", "batch":
+		return true
+	}
+	return false
+}
+
 func listServiceAccountsByName(ctx context.Context, m client.Reader, resourceList *corev1.ServiceAccountList, name string) error {
 	return m.List(ctx, resourceList, &client.ListOptions{
 		FieldSelector: fields.OneTermEqualSelector("metadata.name", name),
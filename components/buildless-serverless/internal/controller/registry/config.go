@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	NpmrcKey   = ".npmrc"
+	PipConfKey = "pip.conf"
+)
+
+var (
+	iniSectionRegex = regexp.MustCompile(`^\[[^\[\]]+\]$`)
+	iniOptionRegex  = regexp.MustCompile(`^[A-Za-z0-9_.-]+\s*[=:]`)
+	npmrcEntryRegex = regexp.MustCompile(`^[^=\s][^=]*=`)
+)
+
+// SecretName returns the name of the Secret with the package registry configuration used by the function
+// the function's own Secret takes precedence over the cluster-wide one
+func SecretName(f *serverlessv1alpha2.Function, c *config.FunctionConfig) string {
+	if f.HasPackageRegistryConfig() {
+		return f.Spec.PackageRegistryConfig.SecretName
+	}
+	return c.PackageRegistryConfigSecretName
+}
+
+// ValidateSecret checks the package registry configuration Secret referenced by the function
+func ValidateSecret(ctx context.Context, c client.Client, f *serverlessv1alpha2.Function) error {
+	secretName := f.Spec.PackageRegistryConfig.SecretName
+	s := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: f.GetNamespace(), Name: secretName}, s)
+	if err != nil {
+		return errors.New(fmt.Sprintf("failed to get secret: %s", err.Error()))
+	}
+
+	if f.HasNodejsRuntime() {
+		return validateKey(s, NpmrcKey, validateNpmrc)
+	}
+	if f.HasPythonRuntime() {
+		return validateKey(s, PipConfKey, validatePipConf)
+	}
+	return nil
+}
+
+func validateKey(s *corev1.Secret, key string, validate func(string) error) error {
+	data, found := s.Data[key]
+	if !found {
+		return errors.New(fmt.Sprintf("missing '%s'", key))
+	}
+	if err := validate(string(data)); err != nil {
+		return errors.Wrapf(err, "invalid '%s'", key)
+	}
+	return nil
+}
+
+// validateNpmrc checks that every entry of the .npmrc file is in the key=value format
+func validateNpmrc(content string) error {
+	return validateLines(content, func(line string, _ bool) error {
+		if !npmrcEntryRegex.MatchString(line) {
+			return errors.New("entry must be in the key=value format")
+		}
+		return nil
+	})
+}
+
+// validatePipConf checks that the pip.conf file is in the INI format with options placed in sections
+func validatePipConf(content string) error {
+	inSection := false
+	return validateLines(content, func(line string, indented bool) error {
+		switch {
+		case iniSectionRegex.MatchString(line):
+			inSection = true
+		case !inSection:
+			return errors.New("option must be placed in a section")
+		case indented:
+			// continuation of the multi-line value
+		case !iniOptionRegex.MatchString(line):
+			return errors.New("option must be in the key = value format")
+		}
+		return nil
+	})
+}
+
+// validateLines calls validate for every line which is not empty or comment
+func validateLines(content string, validate func(line string, indented bool) error) error {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if err := validate(line, raw[0] == ' ' || raw[0] == '\t'); err != nil {
+			return errors.Wrapf(err, "line %d", lineNumber)
+		}
+	}
+	return scanner.Err()
+}
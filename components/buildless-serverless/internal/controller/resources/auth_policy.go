@@ -0,0 +1,125 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	RequestAuthenticationGVK = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1", Kind: "RequestAuthentication"}
+	AuthorizationPolicyGVK   = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1", Kind: "AuthorizationPolicy"}
+)
+
+// AuthPolicyLabels returns labels of the Istio policies restricting calls to the function
+func AuthPolicyLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.FunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelAuthPolicyValue,
+	})
+}
+
+// AuthStatus returns the effective policy restricting calls to the function
+// schedules call the function from its namespace and subscriptions from the eventing namespace,
+// so these namespaces are always allowed to keep them working
+func AuthStatus(f *serverlessv1alpha2.Function, c *config.FunctionConfig) *serverlessv1alpha2.AuthStatus {
+	auth := f.Spec.Auth
+	status := &serverlessv1alpha2.AuthStatus{
+		AuthorizationPolicy: f.GetName(),
+		Principals:          append([]string(nil), auth.Principals...),
+	}
+	if auth.JWT != nil {
+		status.RequestAuthentication = f.GetName()
+		status.RequestPrincipals = []string{fmt.Sprintf("%s/*", auth.JWT.Issuer)}
+	}
+
+	namespaces := map[string]bool{}
+	for _, namespace := range auth.Namespaces {
+		namespaces[namespace] = true
+	}
+	if len(f.Spec.Schedules) != 0 {
+		namespaces[f.GetNamespace()] = true
+	}
+	if len(f.Spec.Subscriptions) != 0 {
+		if namespace, _, ok := endpointNamespaceAndPort(c.FunctionPublisherProxyAddress, f.GetNamespace()); ok && namespace != "" {
+			namespaces[namespace] = true
+		}
+	}
+	for namespace := range namespaces {
+		status.Namespaces = append(status.Namespaces, namespace)
+	}
+	sort.Strings(status.Namespaces)
+	return status
+}
+
+// NewRequestAuthentication builds the Istio RequestAuthentication validating JWTs sent to the function's Pods
+func NewRequestAuthentication(f *serverlessv1alpha2.Function) *unstructured.Unstructured {
+	jwt := f.Spec.Auth.JWT
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(RequestAuthenticationGVK)
+	u.SetName(f.GetName())
+	u.SetNamespace(f.GetNamespace())
+	u.SetLabels(AuthPolicyLabels(f))
+	u.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": stringMapToInterface(f.SelectorLabels()),
+		},
+		"jwtRules": []interface{}{
+			map[string]interface{}{
+				"issuer":  jwt.Issuer,
+				"jwksUri": jwt.JWKSURI,
+			},
+		},
+	}
+	return u
+}
+
+// NewAuthorizationPolicy builds the Istio AuthorizationPolicy allowing only the sources from the given status to call the function's Pods
+func NewAuthorizationPolicy(f *serverlessv1alpha2.Function, status *serverlessv1alpha2.AuthStatus) *unstructured.Unstructured {
+	// rules are ORed, so the call is allowed when it matches any of the sources
+	rules := []interface{}{}
+	if len(status.RequestPrincipals) != 0 {
+		rules = append(rules, authorizationRule("requestPrincipals", status.RequestPrincipals))
+	}
+	if len(status.Principals) != 0 {
+		rules = append(rules, authorizationRule("principals", status.Principals))
+	}
+	if len(status.Namespaces) != 0 {
+		rules = append(rules, authorizationRule("namespaces", status.Namespaces))
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(AuthorizationPolicyGVK)
+	u.SetName(status.AuthorizationPolicy)
+	u.SetNamespace(f.GetNamespace())
+	u.SetLabels(AuthPolicyLabels(f))
+	u.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": stringMapToInterface(f.SelectorLabels()),
+		},
+		"action": "ALLOW",
+		"rules":  rules,
+	}
+	return u
+}
+
+func authorizationRule(sourceField string, values []string) map[string]interface{} {
+	items := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		items = append(items, value)
+	}
+	return map[string]interface{}{
+		"from": []interface{}{
+			map[string]interface{}{
+				"source": map[string]interface{}{
+					sourceField: items,
+				},
+			},
+		},
+	}
+}
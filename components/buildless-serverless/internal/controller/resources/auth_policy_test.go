@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthStatus(t *testing.T) {
+	c := &config.FunctionConfig{
+		FunctionPublisherProxyAddress: "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
+	}
+
+	t.Run("return sources from function's auth", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Auth = &serverlessv1alpha2.Auth{
+			JWT: &serverlessv1alpha2.ExposeJWT{
+				Issuer:  "https://issuer.example.com",
+				JWKSURI: "https://issuer.example.com/jwks",
+			},
+			Principals: []string{"cluster.local/ns/default/sa/client"},
+			Namespaces: []string{"default"},
+		}
+
+		r := AuthStatus(f, c)
+
+		require.Equal(t, &serverlessv1alpha2.AuthStatus{
+			RequestAuthentication: "test-function-name",
+			AuthorizationPolicy:   "test-function-name",
+			RequestPrincipals:     []string{"https://issuer.example.com/*"},
+			Principals:            []string{"cluster.local/ns/default/sa/client"},
+			Namespaces:            []string{"default"},
+		}, r)
+	})
+	t.Run("allow namespaces calling function's schedules and subscriptions", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Auth = &serverlessv1alpha2.Auth{
+			Namespaces: []string{"default", "kyma-system"},
+		}
+		f.Spec.Schedules = []serverlessv1alpha2.Schedule{{Name: "nightly", Cron: "0 0 * * *"}}
+		f.Spec.Subscriptions = []serverlessv1alpha2.Subscription{{Name: "orders"}}
+
+		r := AuthStatus(f, c)
+
+		require.Empty(t, r.RequestAuthentication)
+		require.Nil(t, r.RequestPrincipals)
+		require.Equal(t, []string{"default", "kyma-system", "test-function-namespace"}, r.Namespaces)
+	})
+}
+
+func TestNewRequestAuthentication(t *testing.T) {
+	t.Run("create proper RequestAuthentication", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Auth = &serverlessv1alpha2.Auth{
+			JWT: &serverlessv1alpha2.ExposeJWT{
+				Issuer:  "https://issuer.example.com",
+				JWKSURI: "https://issuer.example.com/jwks",
+			},
+		}
+
+		r := NewRequestAuthentication(f)
+
+		require.Equal(t, RequestAuthenticationGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "auth-policy",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, r.GetLabels())
+		require.Equal(t, map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": stringMapToInterface(f.SelectorLabels()),
+			},
+			"jwtRules": []interface{}{
+				map[string]interface{}{
+					"issuer":  "https://issuer.example.com",
+					"jwksUri": "https://issuer.example.com/jwks",
+				},
+			},
+		}, r.Object["spec"])
+	})
+}
+
+func TestNewAuthorizationPolicy(t *testing.T) {
+	t.Run("create proper AuthorizationPolicy", func(t *testing.T) {
+		f := minimalFunction()
+		status := &serverlessv1alpha2.AuthStatus{
+			AuthorizationPolicy: "test-function-name",
+			RequestPrincipals:   []string{"https://issuer.example.com/*"},
+			Principals:          []string{"cluster.local/ns/default/sa/client"},
+			Namespaces:          []string{"default"},
+		}
+
+		r := NewAuthorizationPolicy(f, status)
+
+		require.Equal(t, AuthorizationPolicyGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, "auth-policy", r.GetLabels()["serverless.kyma-project.io/resource"])
+		require.Equal(t, map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": stringMapToInterface(f.SelectorLabels()),
+			},
+			"action": "ALLOW",
+			"rules": []interface{}{
+				map[string]interface{}{"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+					"requestPrincipals": []interface{}{"https://issuer.example.com/*"},
+				}}}},
+				map[string]interface{}{"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+					"principals": []interface{}{"cluster.local/ns/default/sa/client"},
+				}}}},
+				map[string]interface{}{"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{
+					"namespaces": []interface{}{"default"},
+				}}}},
+			},
+		}, r.Object["spec"])
+	})
+}
@@ -0,0 +1,187 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/sbom"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	inlineSourceKey       = "source"
+	inlineDependenciesKey = "dependencies"
+	inlineHashLength      = 10
+	// "file-" and 16 hex digits of the path hash
+	inlineFileKeyLength = 21
+
+	SBOMKey = "bom.cdx.json"
+)
+
+// NewInlineSourcesConfigMap builds immutable ConfigMap with inline sources of the function
+// the name contains hash of the content, so every change of sources results in a new ConfigMap
+func NewInlineSourcesConfigMap(f *serverlessv1alpha2.Function) *corev1.ConfigMap {
+	inline := f.Spec.Source.Inline
+	data := map[string]string{
+		inlineSourceKey: inline.Source,
+	}
+	if inline.Dependencies != "" {
+		data[inlineDependenciesKey] = inline.Dependencies
+	}
+	for _, filePath := range inlineFilePaths(f) {
+		data[inlineFileKey(filePath)] = inline.Files[filePath]
+	}
+
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: f.GetNamespace(),
+			Labels:    InlineSourcesConfigMapLabels(f),
+		},
+		Immutable: ptr.To(true),
+		Data:      data,
+	}
+	cm.Name = fmt.Sprintf("%s-inline-%s", f.GetName(), ConfigMapHash(cm)[:inlineHashLength])
+	return cm
+}
+
+// InlineSourcesConfigMapLabels returns labels used to find all inline sources ConfigMaps of the function
+func InlineSourcesConfigMapLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.InternalFunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelInlineValue,
+	})
+}
+
+// NewSBOMConfigMap builds ConfigMap with the bill of materials of the function
+func NewSBOMConfigMap(f *serverlessv1alpha2.Function, doc *sbom.Document) (*corev1.ConfigMap, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-sbom", f.GetName()),
+			Namespace: f.GetNamespace(),
+			Labels: labels.Merge(f.InternalFunctionLabels(), map[string]string{
+				serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelSBOMValue,
+			}),
+		},
+		Data: map[string]string{
+			SBOMKey: string(data),
+		},
+	}, nil
+}
+
+// ConfigMapHash calculates hash of the ConfigMap content independent of keys order
+func ConfigMapHash(cm *corev1.ConfigMap) string {
+	keys := make([]string, 0, len(cm.Data)+len(cm.BinaryData))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	for key := range cm.BinaryData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		if value, ok := cm.Data[key]; ok {
+			h.Write([]byte(value))
+		} else {
+			h.Write(cm.BinaryData[key])
+		}
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// InlineSourcesHash calculates hash of the inline sources ConfigMap and the paths its keys are mounted at,
+// so renamed files are rolled out even if their content doesn't change
+func InlineSourcesHash(cm *corev1.ConfigMap, f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) string {
+	h := sha256.New()
+	h.Write([]byte(ConfigMapHash(cm)))
+	h.Write([]byte{0})
+	for _, item := range inlineSourcesItems(f, functionRuntime) {
+		h.Write([]byte(item.Key))
+		h.Write([]byte{0})
+		h.Write([]byte(item.Path))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// inlineSourcesItems maps ConfigMap keys to the files layout expected by the runtime
+// keys can't contain '/' so nested files are placed in their directories this way
+func inlineSourcesItems(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) []corev1.KeyToPath {
+	handlerName, dependenciesName := InlineFileNames(f, functionRuntime)
+	items := []corev1.KeyToPath{
+		{
+			Key:  inlineSourceKey,
+			Path: handlerName,
+		},
+	}
+	if f.Spec.Source.Inline.Dependencies != "" {
+		items = append(items, corev1.KeyToPath{
+			Key:  inlineDependenciesKey,
+			Path: dependenciesName,
+		})
+	}
+	for _, filePath := range inlineFilePaths(f) {
+		items = append(items, corev1.KeyToPath{
+			Key:  inlineFileKey(filePath),
+			Path: filePath,
+		})
+	}
+	return items
+}
+
+// InlineFileNames returns names of the handler and dependencies files the runtime expects in the sources directory
+// names set in the FunctionRuntime take precedence over the built-in ones
+func InlineFileNames(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) (handlerName, dependenciesName string) {
+	switch {
+	case f.HasTypeScript():
+		handlerName, dependenciesName = "handler.ts", "package.json"
+	case f.HasNodejsRuntime():
+		handlerName, dependenciesName = "handler.js", "package.json"
+	case f.HasPythonRuntime():
+		handlerName, dependenciesName = "handler.py", "requirements.txt"
+	}
+	if functionRuntime != nil && functionRuntime.Spec.HandlerFile != "" {
+		handlerName = functionRuntime.Spec.HandlerFile
+	}
+	if functionRuntime != nil && functionRuntime.Spec.DependenciesFile != "" {
+		dependenciesName = functionRuntime.Spec.DependenciesFile
+	}
+	return handlerName, dependenciesName
+}
+
+// inlineFilePaths returns paths of additional inline files in stable order
+func inlineFilePaths(f *serverlessv1alpha2.Function) []string {
+	paths := make([]string, 0, len(f.Spec.Source.Inline.Files))
+	for filePath := range f.Spec.Source.Inline.Files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// inlineFileKey derives the ConfigMap key from the file path, so keys of other files don't change when files are added or removed
+// paths can't be used as keys directly because keys can't contain '/'
+func inlineFileKey(filePath string) string {
+	return fmt.Sprintf("file-%x", sha256.Sum256([]byte(filePath)))[:inlineFileKeyLength]
+}
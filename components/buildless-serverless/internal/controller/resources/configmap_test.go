@@ -0,0 +1,156 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestNewInlineSourcesConfigMap(t *testing.T) {
+	t.Run("build immutable configmap with inline sources", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Source.Inline.Dependencies = "test-dependencies"
+		f.Spec.Source.Inline.Files = map[string]string{
+			"utils.py":       "utils-source",
+			"lib/helpers.py": "helpers-source",
+		}
+
+		cm := NewInlineSourcesConfigMap(f)
+
+		require.Regexp(t, "^test-function-name-inline-[0-9a-f]{10}$", cm.GetName())
+		require.Equal(t, "test-function-namespace", cm.GetNamespace())
+		require.Equal(t, map[string]string{
+			serverlessv1alpha2.FunctionNameLabel:      "test-function-name",
+			serverlessv1alpha2.FunctionManagedByLabel: serverlessv1alpha2.FunctionControllerValue,
+			serverlessv1alpha2.FunctionUUIDLabel:      "test-uid",
+			serverlessv1alpha2.FunctionResourceLabel:  serverlessv1alpha2.FunctionResourceLabelInlineValue,
+		}, cm.GetLabels())
+		require.True(t, *cm.Immutable)
+		require.Equal(t, map[string]string{
+			"source":                        "test-function-source",
+			"dependencies":                  "test-dependencies",
+			inlineFileKey("lib/helpers.py"): "helpers-source",
+			inlineFileKey("utils.py"):       "utils-source",
+		}, cm.Data)
+	})
+	t.Run("skip empty dependencies", func(t *testing.T) {
+		cm := NewInlineSourcesConfigMap(minimalFunction())
+
+		require.Equal(t, map[string]string{
+			"source": "test-function-source",
+		}, cm.Data)
+	})
+	t.Run("name changes with sources", func(t *testing.T) {
+		f := minimalFunction()
+		before := NewInlineSourcesConfigMap(f)
+		f.Spec.Source.Inline.Source = "changed-function-source"
+
+		after := NewInlineSourcesConfigMap(f)
+
+		require.NotEqual(t, before.GetName(), after.GetName())
+	})
+	t.Run("name does not depend on files order", func(t *testing.T) {
+		a := minimalFunction()
+		a.Spec.Source.Inline.Files = map[string]string{"a.py": "1", "b.py": "2"}
+		b := minimalFunction()
+		b.Spec.Source.Inline.Files = map[string]string{"b.py": "2", "a.py": "1"}
+
+		require.Equal(t, NewInlineSourcesConfigMap(a).GetName(), NewInlineSourcesConfigMap(b).GetName())
+	})
+}
+
+func Test_inlineFileKey(t *testing.T) {
+	t.Run("derive valid key from the path", func(t *testing.T) {
+		key := inlineFileKey("lib/sub/helpers.js")
+
+		require.Regexp(t, "^file-[0-9a-f]{16}$", key)
+		require.Empty(t, validation.IsConfigMapKey(key))
+	})
+	t.Run("keep keys of other files when files are added or removed", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Source.Inline.Files = map[string]string{"b.py": "b-source", "c.py": "c-source"}
+		before := NewInlineSourcesConfigMap(f)
+		f.Spec.Source.Inline.Files = map[string]string{"a.py": "a-source", "b.py": "b-source"}
+
+		after := NewInlineSourcesConfigMap(f)
+
+		key := inlineFileKey("b.py")
+		require.Equal(t, "b-source", before.Data[key])
+		require.Equal(t, "b-source", after.Data[key])
+		require.Len(t, after.Data, 3)
+	})
+}
+
+func Test_inlineSourcesItems(t *testing.T) {
+	t.Run("map keys to nodejs files", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Runtime = serverlessv1alpha2.NodeJs22
+		f.Spec.Source.Inline.Dependencies = "{}"
+		f.Spec.Source.Inline.Files = map[string]string{"lib/sub/helpers.js": "helpers-source"}
+
+		items := inlineSourcesItems(f, nil)
+
+		require.Equal(t, []corev1.KeyToPath{
+			{Key: "source", Path: "handler.js"},
+			{Key: "dependencies", Path: "package.json"},
+			{Key: inlineFileKey("lib/sub/helpers.js"), Path: "lib/sub/helpers.js"},
+		}, items)
+	})
+	t.Run("map handler to typescript file", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Runtime = serverlessv1alpha2.NodeJs22
+		f.Spec.Language = serverlessv1alpha2.TypeScript
+
+		items := inlineSourcesItems(f, nil)
+
+		require.Equal(t, []corev1.KeyToPath{
+			{Key: "source", Path: "handler.ts"},
+		}, items)
+	})
+}
+
+func TestInlineSourcesHash(t *testing.T) {
+	t.Run("hash changes when file is renamed", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Source.Inline.Files = map[string]string{"helper.js": "helper-source"}
+		before := NewInlineSourcesConfigMap(f)
+		beforeHash := InlineSourcesHash(before, f, nil)
+		f.Spec.Source.Inline.Files = map[string]string{"util.js": "helper-source"}
+
+		after := NewInlineSourcesConfigMap(f)
+
+		require.NotEqual(t, before.GetName(), after.GetName())
+		require.NotEqual(t, beforeHash, InlineSourcesHash(after, f, nil))
+	})
+	t.Run("hash changes when handler file of the FunctionRuntime changes", func(t *testing.T) {
+		f := minimalFunction()
+		cm := NewInlineSourcesConfigMap(f)
+		functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+			Spec: serverlessv1alpha2.FunctionRuntimeSpec{HandlerFile: "main.py"},
+		}
+
+		require.Equal(t, InlineSourcesHash(cm, f, nil), InlineSourcesHash(cm, f, nil))
+		require.NotEqual(t, InlineSourcesHash(cm, f, nil), InlineSourcesHash(cm, f, functionRuntime))
+	})
+}
+
+func TestConfigMapHash(t *testing.T) {
+	t.Run("hash does not depend on keys order", func(t *testing.T) {
+		a := &corev1.ConfigMap{Data: map[string]string{"a": "1", "b": "2"}}
+		b := &corev1.ConfigMap{Data: map[string]string{"b": "2", "a": "1"}}
+		require.Equal(t, ConfigMapHash(a), ConfigMapHash(b))
+	})
+	t.Run("hash depends on content", func(t *testing.T) {
+		a := &corev1.ConfigMap{Data: map[string]string{"a": "1"}}
+		b := &corev1.ConfigMap{Data: map[string]string{"a": "2"}}
+		require.NotEqual(t, ConfigMapHash(a), ConfigMapHash(b))
+	})
+	t.Run("hash includes binary data", func(t *testing.T) {
+		a := &corev1.ConfigMap{Data: map[string]string{"a": "1"}}
+		b := &corev1.ConfigMap{Data: map[string]string{"a": "1"}, BinaryData: map[string][]byte{"b": {0x1}}}
+		require.NotEqual(t, ConfigMapHash(a), ConfigMapHash(b))
+	})
+}
@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	scheduleInvokerContainerName = "invoker"
+
+	// scheduleInvokerScript sends the request to the function, all values are passed as envs to avoid escaping them
+	scheduleInvokerScript = `set -eu
+if [ -n "${CE_TYPE}" ]; then
+  set -- -H "ce-specversion: 1.0" -H "ce-type: ${CE_TYPE}" -H "ce-source: ${CE_SOURCE}" -H "ce-id: $(cat /proc/sys/kernel/random/uuid)"
+fi
+exec curl -sS --fail-with-body --max-time 60 -X POST -H "Content-Type: ${CONTENT_TYPE}" "$@" --data-raw "${PAYLOAD}" "${FUNCTION_URL}"
+`
+)
+
+// ScheduleCronJobName returns the name of the CronJob invoking the function on the schedule
+func ScheduleCronJobName(f *serverlessv1alpha2.Function, s serverlessv1alpha2.Schedule) string {
+	return fmt.Sprintf("%s-%s", f.GetName(), s.Name)
+}
+
+// ScheduleCronJobLabels returns labels used to find all schedule CronJobs of the function
+func ScheduleCronJobLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.InternalFunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelScheduleValue,
+	})
+}
+
+// NewScheduleCronJob builds the CronJob sending the schedule's payload to the function's Service
+func NewScheduleCronJob(f *serverlessv1alpha2.Function, s serverlessv1alpha2.Schedule, c *config.FunctionConfig) *batchv1.CronJob {
+	cronJobLabels := ScheduleCronJobLabels(f)
+
+	var timeZone *string
+	if s.TimeZone != "" {
+		timeZone = ptr.To(s.TimeZone)
+	}
+
+	return &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CronJob",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScheduleCronJobName(f, s),
+			Namespace: f.GetNamespace(),
+			Labels:    cronJobLabels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   s.Cron,
+			TimeZone:                   timeZone,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](1),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: cronJobLabels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](2),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels:      cronJobLabels,
+							Annotations: meshJobAnnotations(f, c),
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers: []corev1.Container{
+								{
+									Name:    scheduleInvokerContainerName,
+									Image:   c.Images.ScheduleInvoker,
+									Command: []string{"/bin/sh", "-c", scheduleInvokerScript},
+									Env:     scheduleInvokerEnvs(f, s),
+									Resources: corev1.ResourceRequirements{
+										Requests: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("10m"),
+											corev1.ResourceMemory: resource.MustParse("16Mi"),
+										},
+										Limits: corev1.ResourceList{
+											corev1.ResourceCPU:    resource.MustParse("100m"),
+											corev1.ResourceMemory: resource.MustParse("64Mi"),
+										},
+									},
+									SecurityContext: &corev1.SecurityContext{
+										Privileged: ptr.To(false),
+										Capabilities: &corev1.Capabilities{
+											Drop: []corev1.Capability{"ALL"},
+										},
+										ReadOnlyRootFilesystem:   ptr.To(true),
+										AllowPrivilegeEscalation: ptr.To(false),
+										RunAsNonRoot:             ptr.To(true),
+									},
+								},
+							},
+							SecurityContext: &corev1.PodSecurityContext{
+								RunAsUser:  ptr.To[int64](1000),
+								RunAsGroup: ptr.To[int64](1000),
+								SeccompProfile: &corev1.SeccompProfile{
+									Type: corev1.SeccompProfileTypeRuntimeDefault,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func scheduleInvokerEnvs(f *serverlessv1alpha2.Function, s serverlessv1alpha2.Schedule) []corev1.EnvVar {
+	contentType := "text/plain"
+	if s.Payload == "" || json.Valid([]byte(s.Payload)) {
+		contentType = "application/json"
+	}
+	return []corev1.EnvVar{
+		{Name: "FUNCTION_URL", Value: functionServiceURL(f)},
+		{Name: "PAYLOAD", Value: s.Payload},
+		{Name: "CONTENT_TYPE", Value: contentType},
+		{Name: "CE_TYPE", Value: s.CloudEventType},
+		{Name: "CE_SOURCE", Value: fmt.Sprintf("/%s/%s/schedules/%s", f.GetNamespace(), f.GetName(), s.Name)},
+	}
+}
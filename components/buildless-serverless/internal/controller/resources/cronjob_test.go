@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewScheduleCronJob(t *testing.T) {
+	f := &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function-name",
+			Namespace: "test-function-namespace",
+			UID:       "test-uid",
+		},
+	}
+
+	c := &config.FunctionConfig{
+		Images: config.ImagesConfig{ScheduleInvoker: "curlimages/curl:8.11.1"},
+	}
+
+	t.Run("create proper CronJob", func(t *testing.T) {
+		s := serverlessv1alpha2.Schedule{
+			Name:     "cleanup",
+			Cron:     "*/15 * * * *",
+			TimeZone: "Europe/Warsaw",
+			Payload:  `{"olderThan": "24h"}`,
+		}
+
+		r := NewScheduleCronJob(f, s, c)
+
+		require.Equal(t, "test-function-name-cleanup", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		expectedLabels := map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "schedule",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}
+		require.Equal(t, expectedLabels, r.GetLabels())
+		require.Equal(t, expectedLabels, r.Spec.JobTemplate.Spec.Template.GetLabels())
+		require.Equal(t, "*/15 * * * *", r.Spec.Schedule)
+		require.Equal(t, ptr.To("Europe/Warsaw"), r.Spec.TimeZone)
+		require.Equal(t, batchv1.ForbidConcurrent, r.Spec.ConcurrencyPolicy)
+		podSpec := r.Spec.JobTemplate.Spec.Template.Spec
+		require.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+		require.Len(t, podSpec.Containers, 1)
+		require.Equal(t, "curlimages/curl:8.11.1", podSpec.Containers[0].Image)
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "FUNCTION_URL", Value: "http://test-function-name.test-function-namespace.svc.cluster.local"},
+			{Name: "PAYLOAD", Value: `{"olderThan": "24h"}`},
+			{Name: "CONTENT_TYPE", Value: "application/json"},
+			{Name: "CE_TYPE", Value: ""},
+			{Name: "CE_SOURCE", Value: "/test-function-namespace/test-function-name/schedules/cleanup"},
+		}, podSpec.Containers[0].Env)
+	})
+	t.Run("send text payload as CloudEvent", func(t *testing.T) {
+		s := serverlessv1alpha2.Schedule{
+			Name:           "report",
+			Cron:           "@daily",
+			Payload:        "daily report",
+			CloudEventType: "sap.kyma.custom.report.v1",
+		}
+
+		r := NewScheduleCronJob(f, s, c)
+
+		require.Nil(t, r.Spec.TimeZone)
+		env := r.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env
+		require.Contains(t, env, corev1.EnvVar{Name: "CONTENT_TYPE", Value: "text/plain"})
+		require.Contains(t, env, corev1.EnvVar{Name: "CE_TYPE", Value: "sap.kyma.custom.report.v1"})
+	})
+	t.Run("prepare invoker's Pod for configured mesh", func(t *testing.T) {
+		s := serverlessv1alpha2.Schedule{Name: "report", Cron: "@daily"}
+
+		istio := NewScheduleCronJob(f, s, c)
+		linkerd := NewScheduleCronJob(f, s, &config.FunctionConfig{MeshMode: config.MeshModeLinkerd})
+		none := NewScheduleCronJob(f, s, &config.FunctionConfig{MeshMode: config.MeshModeNone})
+
+		require.Equal(t, map[string]string{
+			"sidecar.istio.io/nativeSidecar": "true",
+		}, istio.Spec.JobTemplate.Spec.Template.GetAnnotations())
+		require.Equal(t, map[string]string{
+			"config.alpha.linkerd.io/proxy-enable-native-sidecar": "true",
+		}, linkerd.Spec.JobTemplate.Spec.Template.GetAnnotations())
+		require.Empty(t, none.Spec.JobTemplate.Spec.Template.GetAnnotations())
+	})
+}
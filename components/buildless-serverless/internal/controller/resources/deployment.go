@@ -7,20 +7,38 @@ import (
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/registry"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
 const DefaultDeploymentReplicas int32 = 1
+
+// defaultRollingUpdateValue is the Kubernetes default of rolling update's maxSurge and maxUnavailable
+var defaultRollingUpdateValue = intstr.FromString("25%")
+
+const SourceHashAnnotationKey = "serverless.kyma-project.io/source-hash"
+
 const (
-	istioConfigLabelKey                      = "proxy.istio.io/config"
-	istioEnableHoldUntilProxyStartLabelValue = "{ \"holdApplicationUntilProxyStarts\": true }"
-	istioNativeSidecarLabelKey               = "sidecar.istio.io/nativeSidecar"
+	configMapSourcesVolumeName = "configmap-sources"
+	configMapSourcesMountPath  = "/configmap-sources"
+	ociArtifactVolumeName      = "oci-artifact"
+	ociArtifactMountPath       = "/oci-artifact"
+	ociPullSecretVolumeName    = "oci-pull-secret"
+	ociPullSecretMountPath     = "/oci-pull-secret"
+	archiveVolumeName          = "archive"
+	archiveMountPath           = "/archive"
+	inlineSourcesVolumeName    = "inline-sources"
+	inlineSourcesMountPath     = "/inline-sources"
+	packageRegistryVolumeName  = "package-registry-config"
 )
 
 type deployOptions func(*Deployment)
@@ -55,10 +73,10 @@ func DeployAppendSelectorLabels(labels map[string]string) deployOptions {
 	}
 }
 
-// DeployUseGeneralEnvs - use general envs function for the deployment
-func DeployUseGeneralEnvs() deployOptions {
+// DeployUseGeneralEnvs - use general envs function for the deployment, including envs of the FunctionRuntime (if any)
+func DeployUseGeneralEnvs(functionRuntime *serverlessv1alpha2.FunctionRuntime) deployOptions {
 	return func(d *Deployment) {
-		d.podEnvs = generalEnvs(d.function, d.functionConfig)
+		d.podEnvs = generalEnvs(d.function, d.functionConfig, functionRuntime)
 	}
 }
 
@@ -76,19 +94,86 @@ func DeploySetCmd(cmd []string) deployOptions {
 	}
 }
 
+// DeploySetRuntime - run the function with the given built-in runtime, for example, when it's upgraded from the deprecated one
+func DeploySetRuntime(runtime serverlessv1alpha2.Runtime) deployOptions {
+	return func(d *Deployment) {
+		if !runtime.IsRuntimeKnown() || d.function.Spec.RuntimeImageOverride != "" {
+			return
+		}
+		d.podImage = imageForRuntime(runtime, d.functionConfig)
+	}
+}
+
+// DeploySetFunctionRuntime - run the function as described by the FunctionRuntime of its runtime, fields it doesn't set keep the built-in behavior
+// it has to be applied after DeploySetRuntime and before options overriding the image, the command or envs
+func DeploySetFunctionRuntime(functionRuntime *serverlessv1alpha2.FunctionRuntime) deployOptions {
+	return func(d *Deployment) {
+		if functionRuntime == nil {
+			return
+		}
+		d.functionRuntime = functionRuntime
+		if d.function.Spec.RuntimeImageOverride == "" {
+			d.podImage = functionRuntime.Spec.Image
+		}
+		d.podEnvs = append(generalEnvs(d.function, d.functionConfig, functionRuntime), sourceEnvs(d.function)...)
+		d.podCmd = []string{
+			"sh",
+			"-c",
+			runtimeCommand(d.function, functionRuntime),
+		}
+	}
+}
+
+// DeploySetSourceHash - set the hash of the function sources to rollout pods when sources change
+func DeploySetSourceHash(hash string) deployOptions {
+	return func(d *Deployment) {
+		d.sourceHash = hash
+	}
+}
+
+// DeploySetOCIDigest - set the digest of the OCI artifact pulled by the init container
+func DeploySetOCIDigest(digest string) deployOptions {
+	return func(d *Deployment) {
+		d.ociDigest = digest
+	}
+}
+
+// DeploySetArchive - set the revision of the archive and credentials used by the init container
+func DeploySetArchive(revision string, auth *archive.Auth) deployOptions {
+	return func(d *Deployment) {
+		d.archiveRevision = revision
+		d.archiveAuth = auth
+	}
+}
+
+// DeploySetInlineSourcesConfigMap - set the name of the ConfigMap with inline sources mounted into the pod
+func DeploySetInlineSourcesConfigMap(name string) deployOptions {
+	return func(d *Deployment) {
+		d.inlineSourcesConfigMap = name
+	}
+}
+
 type Deployment struct {
 	*appsv1.Deployment
 	functionConfig           *config.FunctionConfig
 	function                 *serverlessv1alpha2.Function
+	functionRuntime          *serverlessv1alpha2.FunctionRuntime
 	clusterDeployment        *appsv1.Deployment
 	commit                   string
 	gitAuth                  *git.GitAuth
+	sourceHash               string
+	ociDigest                string
+	archiveRevision          string
+	archiveAuth              *archive.Auth
+	inlineSourcesConfigMap   string
 	functionLabels           map[string]string
 	selectorLabels           map[string]string
 	podLabels                map[string]string
 	deployName               string
 	deployGeneratedName      string
 	podImage                 string
+	podImageDigest           string
+	pinnedPodImage           string
 	podEnvs                  []corev1.EnvVar
 	podCmd                   []string
 	podSecurityContext       *corev1.PodSecurityContext
@@ -108,13 +193,13 @@ func NewDeployment(f *serverlessv1alpha2.Function, c *config.FunctionConfig, clu
 		deployName:               "",
 		deployGeneratedName:      fmt.Sprintf("%s-", f.Name),
 		podImage:                 runtimeImage(f, c),
-		podEnvs:                  append(generalEnvs(f, c), sourceEnvs(f)...),
+		podEnvs:                  append(generalEnvs(f, c, nil), sourceEnvs(f)...),
 		podSecurityContext:       podSecurityContext(f),
 		containerSecurityContext: containerSecurityContext(f),
 		podCmd: []string{
 			"sh",
 			"-c",
-			runtimeCommand(f),
+			runtimeCommand(f, nil),
 		},
 	}
 
@@ -157,6 +242,7 @@ func (d *Deployment) construct() *appsv1.Deployment {
 				Spec: d.podSpec(),
 			},
 			Replicas: d.replicas(),
+			Strategy: d.strategy(),
 		},
 	}
 	return deployment
@@ -170,8 +256,36 @@ func (d *Deployment) ContainerSecurityContext() *corev1.SecurityContext {
 	return d.Spec.Template.Spec.Containers[0].SecurityContext
 }
 
+// RuntimeImage returns the runtime image as configured, without the digest it's pinned to
 func (d *Deployment) RuntimeImage() string {
-	return d.Spec.Template.Spec.Containers[0].Image
+	return d.podImage
+}
+
+func (d *Deployment) RuntimeImageDigest() string {
+	return d.podImageDigest
+}
+
+// HoldRuntimeImage keeps the runtime image the Function runs with, pinned to its digest (if any),
+// for example, until its staged upgrade starts
+func (d *Deployment) HoldRuntimeImage(image, pinnedImage, digest string) {
+	d.podImage = image
+	d.podImageDigest = digest
+	d.pinnedPodImage = pinnedImage
+	d.Deployment = d.construct()
+}
+
+// PinRuntimeImage runs the runtime image by the digest it was resolved to
+func (d *Deployment) PinRuntimeImage(pinnedImage, digest string) {
+	d.pinnedPodImage = pinnedImage
+	d.podImageDigest = digest
+	d.Deployment = d.construct()
+}
+
+func (d *Deployment) containerImage() string {
+	if d.pinnedPodImage != "" {
+		return d.pinnedPodImage
+	}
+	return d.podImage
 }
 
 func (d *Deployment) podAnnotations() map[string]string {
@@ -184,15 +298,22 @@ func (d *Deployment) podAnnotations() map[string]string {
 	// for example in case when someone use `kubectl rollout restart` on it
 	// before merge we need to remove annotations that are not present in the current function to allow removing them
 	result = labels.Merge(d.currentAnnotationsWithoutPreviousFunctionAnnotations(), result)
-	result = labels.Merge(d.annotationsRequiredByIstio(), result)
+	result = labels.Merge(result, meshPodAnnotations(d.function, d.functionConfig))
+	if d.sourceHash == "" {
+		// remove hash left by sources used previously
+		delete(result, SourceHashAnnotationKey)
+	}
 
 	return result
 }
 
 func (d *Deployment) defaultAnnotations() map[string]string {
-	return map[string]string{
-		istioConfigLabelKey: istioEnableHoldUntilProxyStartLabelValue,
+	result := map[string]string{}
+	if d.sourceHash != "" {
+		// changing hash triggers rollout when sources are changed in place (e.g. in ConfigMap)
+		result[SourceHashAnnotationKey] = d.sourceHash
 	}
+	return result
 }
 
 func (d *Deployment) currentAnnotationsWithoutPreviousFunctionAnnotations() map[string]string {
@@ -200,6 +321,10 @@ func (d *Deployment) currentAnnotationsWithoutPreviousFunctionAnnotations() map[
 	currentAnnotations := d.currentAnnotations()
 	result := make(map[string]string)
 	for key := range currentAnnotations {
+		// annotations of the mesh are set again for the current mesh mode
+		if isMeshAnnotation(key) {
+			continue
+		}
 		if _, ok := previousFunctionAnnotations[key]; !ok {
 			result[key] = currentAnnotations[key]
 		}
@@ -207,14 +332,6 @@ func (d *Deployment) currentAnnotationsWithoutPreviousFunctionAnnotations() map[
 	return result
 }
 
-// allow istio to inject native sidecar (istio-proxy as init container)
-// this is required for init container of git sourced functions to fetch source from git repository
-func (d *Deployment) annotationsRequiredByIstio() map[string]string {
-	result := make(map[string]string)
-	result[istioNativeSidecarLabelKey] = "true"
-	return result
-}
-
 func (d *Deployment) currentAnnotations() map[string]string {
 	if d.clusterDeployment == nil {
 		return map[string]string{}
@@ -228,12 +345,12 @@ func (d *Deployment) podSpec() corev1.PodSpec {
 
 	return corev1.PodSpec{
 		Volumes:        append(d.volumes(), secretVolumes...),
-		InitContainers: d.initContainerForGitRepository(),
+		InitContainers: d.initContainers(),
 		Containers: []corev1.Container{
 			{
 				Name:         "function",
-				Image:        d.podImage,
-				WorkingDir:   workingSourcesDir(d.function),
+				Image:        d.containerImage(),
+				WorkingDir:   d.workingDir(),
 				Command:      d.podCmd,
 				Resources:    d.resourceConfiguration(),
 				Env:          d.podEnvs,
@@ -286,51 +403,142 @@ func (d *Deployment) podSpec() corev1.PodSpec {
 	}
 }
 
-func (d *Deployment) initContainerForGitRepository() []corev1.Container {
-	if !d.function.HasGitSources() {
-		return []corev1.Container{}
+func (d *Deployment) initContainers() []corev1.Container {
+	if d.function.HasGitSources() {
+		return d.initContainerForGitRepository()
+	}
+	if d.function.HasOCISources() {
+		return d.initContainerForOCIArtifact()
 	}
+	if d.function.HasArchiveSources() {
+		return d.initContainerForArchive()
+	}
+	return []corev1.Container{}
+}
 
+func (d *Deployment) initContainerForArchive() []corev1.Container {
 	return []corev1.Container{
-		{
-			Name:       "init",
-			Image:      d.functionConfig.Images.RepoFetcher,
-			WorkingDir: workingSourcesDir(d.function),
-			Command: []string{
-				"sh",
-				"-c",
-				d.initContainerCommand(),
-			},
-			Env: d.initContainerEnvs(),
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse("50m"),
-					corev1.ResourceMemory: resource.MustParse("64Mi"),
-				},
-				Limits: corev1.ResourceList{
-					corev1.ResourceCPU:    resource.MustParse("200m"),
-					corev1.ResourceMemory: resource.MustParse("512Mi"),
+		d.sourcesFetcherContainer(
+			d.archiveInitContainerCommand(),
+			d.archiveInitContainerEnvs(),
+			[]corev1.VolumeMount{
+				{
+					Name:      archiveVolumeName,
+					ReadOnly:  false,
+					MountPath: archiveMountPath,
 				},
 			},
-			VolumeMounts: []corev1.VolumeMount{
+		),
+	}
+}
+
+func (d *Deployment) initContainerForGitRepository() []corev1.Container {
+	return []corev1.Container{
+		d.sourcesFetcherContainer(
+			d.initContainerCommand(),
+			d.initContainerEnvs(),
+			[]corev1.VolumeMount{
 				{
 					Name:      "git-repository",
 					ReadOnly:  false,
 					MountPath: "/git-repository",
 				},
 			},
-			SecurityContext: &corev1.SecurityContext{
-				Privileged: ptr.To(false),
-				Capabilities: &corev1.Capabilities{
-					Drop: []corev1.Capability{
-						"ALL",
-					},
+		),
+	}
+}
+
+func (d *Deployment) initContainerForOCIArtifact() []corev1.Container {
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      ociArtifactVolumeName,
+			ReadOnly:  false,
+			MountPath: ociArtifactMountPath,
+		},
+	}
+	if d.function.HasOCIPullSecret() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      ociPullSecretVolumeName,
+			ReadOnly:  true,
+			MountPath: ociPullSecretMountPath,
+		})
+	}
+
+	return []corev1.Container{
+		d.sourcesFetcherContainer(
+			fmt.Sprintf("rm -rf %s/*\n/app/gitcloner", ociArtifactMountPath),
+			d.ociInitContainerEnvs(),
+			volumeMounts,
+		),
+	}
+}
+
+// sourcesFetcherContainer builds the init container fetching function sources to the shared volume
+func (d *Deployment) sourcesFetcherContainer(command string, envs []corev1.EnvVar, volumeMounts []corev1.VolumeMount) corev1.Container {
+	return corev1.Container{
+		Name:       "init",
+		Image:      d.functionConfig.Images.RepoFetcher,
+		WorkingDir: d.workingDir(),
+		Command: []string{
+			"sh",
+			"-c",
+			command,
+		},
+		Env: envs,
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("50m"),
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("200m"),
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		},
+		VolumeMounts: volumeMounts,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{
+					"ALL",
 				},
-				ProcMount:              ptr.To(corev1.DefaultProcMount),
-				ReadOnlyRootFilesystem: ptr.To(true),
 			},
+			ProcMount:              ptr.To(corev1.DefaultProcMount),
+			ReadOnlyRootFilesystem: ptr.To(true),
+		},
+	}
+}
+
+func (d *Deployment) ociInitContainerEnvs() []corev1.EnvVar {
+	reference := d.function.Spec.Source.OCI.Reference
+	if d.ociDigest != "" {
+		// pull exactly the artifact resolved by the controller
+		if pinned, err := oci.PinnedReference(reference, d.ociDigest); err == nil {
+			reference = pinned
+		}
+	}
+
+	envs := []corev1.EnvVar{
+		{
+			Name:  "APP_SOURCE_TYPE",
+			Value: "oci",
+		},
+		{
+			Name:  "APP_OCI_REFERENCE",
+			Value: reference,
+		},
+		{
+			Name:  "APP_DESTINATION_PATH",
+			Value: path.Join(ociArtifactMountPath, "src"),
 		},
 	}
+	if d.function.HasOCIPullSecret() {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "APP_OCI_DOCKER_CONFIG_PATH",
+			Value: path.Join(ociPullSecretMountPath, corev1.DockerConfigJsonKey),
+		})
+	}
+	return envs
 }
 
 func (d *Deployment) initContainerEnvs() []corev1.EnvVar {
@@ -359,6 +567,47 @@ func (d *Deployment) initContainerEnvs() []corev1.EnvVar {
 	return envs
 }
 
+func (d *Deployment) archiveInitContainerEnvs() []corev1.EnvVar {
+	archiveSource := d.function.Spec.Source.Archive
+	envs := []corev1.EnvVar{
+		{
+			Name:  "APP_SOURCE_TYPE",
+			Value: "archive",
+		},
+		{
+			Name:  "APP_ARCHIVE_URL",
+			Value: archiveSource.URL,
+		},
+		{
+			Name:  "APP_ARCHIVE_SHA256",
+			Value: archiveSource.SHA256,
+		},
+		{
+			// changing revision triggers rollout when new archive is published under the same url
+			Name:  "APP_ARCHIVE_REVISION",
+			Value: d.archiveRevision,
+		},
+		{
+			Name:  "APP_DESTINATION_PATH",
+			Value: path.Join(archiveMountPath, "repo"),
+		},
+	}
+	if d.archiveAuth != nil {
+		envs = append(envs, d.archiveAuth.GetAuthEnvs()...)
+	}
+	return envs
+}
+
+func (d *Deployment) archiveInitContainerCommand() string {
+	var arr []string
+	arr = append(arr, fmt.Sprintf("rm -rf %s/*", archiveMountPath))
+	arr = append(arr, "/app/gitcloner")
+	arr = append(arr,
+		fmt.Sprintf("mkdir %[1]s/src;cp -r '%[1]s/repo/%[2]s'/* %[1]s/src;",
+			archiveMountPath, strings.Trim(d.function.Spec.Source.Archive.BaseDir, "/ ")))
+	return strings.Join(arr, "\n")
+}
+
 func (d *Deployment) initContainerCommand() string {
 	gitRepo := d.function.Spec.Source.GitRepository
 	var arr []string
@@ -371,6 +620,16 @@ func (d *Deployment) initContainerCommand() string {
 }
 
 func (d *Deployment) replicas() *int32 {
+	// replicas of the function scaled by KEDA triggers are left to the ScaledObject
+	if d.function.HasScaleTriggers() {
+		if d.clusterDeployment != nil && d.clusterDeployment.Spec.Replicas != nil {
+			return d.clusterDeployment.Spec.Replicas
+		}
+		if d.function.Spec.ScaleConfig.MinReplicas != nil {
+			return d.function.Spec.ScaleConfig.MinReplicas
+		}
+	}
+
 	replicas := d.function.Spec.Replicas
 	if replicas != nil {
 		return replicas
@@ -379,6 +638,27 @@ func (d *Deployment) replicas() *int32 {
 	return &defaultValue
 }
 
+// strategy sets rolling update parameters explicitly, so the Deployment defaulted by Kubernetes matches the built one
+func (d *Deployment) strategy() appsv1.DeploymentStrategy {
+	maxSurge := defaultRollingUpdateValue
+	maxUnavailable := defaultRollingUpdateValue
+	if rollingUpdate := d.function.Spec.RollingUpdate; rollingUpdate != nil {
+		if rollingUpdate.MaxSurge != nil {
+			maxSurge = *rollingUpdate.MaxSurge
+		}
+		if rollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *rollingUpdate.MaxUnavailable
+		}
+	}
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+}
+
 func (d *Deployment) volumes() []corev1.Volume {
 	volumes := []corev1.Volume{
 		{
@@ -389,11 +669,12 @@ func (d *Deployment) volumes() []corev1.Volume {
 			},
 		},
 		{
-			Name: "package-registry-config",
+			Name: packageRegistryVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: d.functionConfig.PackageRegistryConfigSecretName,
-					Optional:   ptr.To(true),
+					SecretName: registry.SecretName(d.function, d.functionConfig),
+					// the cluster-wide config is optional, the function's own Secret is validated before
+					Optional: ptr.To(!d.function.HasPackageRegistryConfig()),
 				},
 			},
 		},
@@ -412,6 +693,57 @@ func (d *Deployment) volumes() []corev1.Volume {
 			},
 		})
 	}
+	if d.function.HasConfigMapSources() {
+		volumes = append(volumes, corev1.Volume{
+			Name: configMapSourcesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: d.function.Spec.Source.ConfigMap.Name,
+					},
+				},
+			},
+		})
+	}
+	if d.function.HasOCISources() {
+		volumes = append(volumes, corev1.Volume{
+			Name: ociArtifactVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+	if d.function.HasArchiveSources() {
+		volumes = append(volumes, corev1.Volume{
+			Name: archiveVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+	if d.inlineSourcesConfigMap != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: inlineSourcesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: d.inlineSourcesConfigMap,
+					},
+					Items: inlineSourcesItems(d.function, d.functionRuntime),
+				},
+			},
+		})
+	}
+	if d.function.HasOCIPullSecret() {
+		volumes = append(volumes, corev1.Volume{
+			Name: ociPullSecretVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: d.function.Spec.Source.OCI.PullSecretName,
+				},
+			},
+		})
+	}
 	if d.function.HasPythonRuntime() {
 		volumes = append(volumes, corev1.Volume{
 			// required by pip to save deps to .local dir
@@ -428,7 +760,7 @@ func (d *Deployment) volumeMounts() []corev1.VolumeMount {
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      "sources",
-			MountPath: workingSourcesDir(d.function),
+			MountPath: d.workingDir(),
 		},
 		{
 			Name:      "tmp",
@@ -442,10 +774,36 @@ func (d *Deployment) volumeMounts() []corev1.VolumeMount {
 			MountPath: "/git-repository",
 		})
 	}
+	if d.function.HasConfigMapSources() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      configMapSourcesVolumeName,
+			ReadOnly:  true,
+			MountPath: configMapSourcesMountPath,
+		})
+	}
+	if d.function.HasOCISources() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      ociArtifactVolumeName,
+			MountPath: ociArtifactMountPath,
+		})
+	}
+	if d.function.HasArchiveSources() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      archiveVolumeName,
+			MountPath: archiveMountPath,
+		})
+	}
+	if d.inlineSourcesConfigMap != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      inlineSourcesVolumeName,
+			ReadOnly:  true,
+			MountPath: inlineSourcesMountPath,
+		})
+	}
 	if d.function.HasNodejsRuntime() {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      "package-registry-config",
-			MountPath: path.Join(workingSourcesDir(d.function), "package-registry-config/.npmrc"),
+			Name:      packageRegistryVolumeName,
+			MountPath: path.Join(d.workingDir(), "package-registry-config/.npmrc"),
 			SubPath:   ".npmrc",
 		})
 	}
@@ -456,8 +814,8 @@ func (d *Deployment) volumeMounts() []corev1.VolumeMount {
 				MountPath: "/.local",
 			},
 			corev1.VolumeMount{
-				Name:      "package-registry-config",
-				MountPath: path.Join(workingSourcesDir(d.function), "package-registry-config/pip.conf"),
+				Name:      packageRegistryVolumeName,
+				MountPath: path.Join(d.workingDir(), "package-registry-config/pip.conf"),
 				SubPath:   "pip.conf",
 			})
 	}
@@ -534,13 +892,27 @@ func getSliceOrDefault[T any](field []T, defaultValue []T) []T {
 	return field
 }
 
+// PackageRegistryConfigSecretName returns name of the package registry config Secret mounted into the deployment pods
+func PackageRegistryConfigSecretName(d *appsv1.Deployment) string {
+	for _, volume := range d.Spec.Template.Spec.Volumes {
+		if volume.Name == packageRegistryVolumeName && volume.Secret != nil {
+			return volume.Secret.SecretName
+		}
+	}
+	return ""
+}
+
 func runtimeImage(f *serverlessv1alpha2.Function, c *config.FunctionConfig) string {
 	runtimeOverride := f.Spec.RuntimeImageOverride
 	if runtimeOverride != "" {
 		return runtimeOverride
 	}
 
-	switch f.Spec.Runtime.SupportedRuntimeEquivalent() {
+	return imageForRuntime(f.Spec.Runtime.SupportedRuntimeEquivalent(), c)
+}
+
+func imageForRuntime(runtime serverlessv1alpha2.Runtime, c *config.FunctionConfig) string {
+	switch runtime {
 	case serverlessv1alpha2.NodeJs20:
 		return c.Images.NodeJs20
 	case serverlessv1alpha2.NodeJs22:
@@ -552,6 +924,14 @@ func runtimeImage(f *serverlessv1alpha2.Function, c *config.FunctionConfig) stri
 	}
 }
 
+// workingDir returns the directory with the function's sources in the runtime container
+func (d *Deployment) workingDir() string {
+	if d.functionRuntime != nil && d.functionRuntime.Spec.WorkingDir != "" {
+		return d.functionRuntime.Spec.WorkingDir
+	}
+	return workingSourcesDir(d.function)
+}
+
 func workingSourcesDir(f *serverlessv1alpha2.Function) string {
 	if f.HasNodejsRuntime() {
 		return "/usr/src/app/function"
@@ -561,62 +941,123 @@ func workingSourcesDir(f *serverlessv1alpha2.Function) string {
 	return ""
 }
 
-func runtimeCommand(f *serverlessv1alpha2.Function) string {
+// DefaultTypeScriptConfig is used to transpile TypeScript functions which don't provide their own tsconfig.json
+const DefaultTypeScriptConfig = `{
+  "compilerOptions": {
+    "target": "es2022",
+    "module": "commonjs",
+    "esModuleInterop": true,
+    "skipLibCheck": true,
+    "strict": true
+  },
+  "exclude": ["node_modules"]
+}`
+
+// TypeScriptCompilationFailedMessage prefixes the termination message of pods with TypeScript errors
+const TypeScriptCompilationFailedMessage = "TypeScript compilation failed"
+
+// runtimeCommand runs the install and start commands set in the FunctionRuntime instead of the built-in ones
+func runtimeCommand(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) string {
 	var result []string
 	result = append(result, runtimeCommandSources(f))
-	result = append(result, runtimeCommandInstall(f))
-	result = append(result, runtimeCommandStart(f))
+	result = append(result, functionRuntimeInstallCommand(f, functionRuntime))
+	if f.HasTypeScript() {
+		result = append(result, runtimeCommandTranspile())
+	}
+	if functionRuntime != nil && functionRuntime.Spec.StartCommand != "" {
+		result = append(result, functionRuntime.Spec.StartCommand)
+	} else {
+		result = append(result, runtimeCommandStart(f))
+	}
 
 	return strings.Join(result, "\n")
 }
 
-func runtimeCommandSources(f *serverlessv1alpha2.Function) string {
-	spec := &f.Spec
-	if spec.Source.GitRepository != nil {
-		return runtimeCommandGitSources(f)
+func functionRuntimeInstallCommand(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) string {
+	if functionRuntime != nil && functionRuntime.Spec.InstallCommand != "" {
+		return functionRuntime.Spec.InstallCommand
 	}
-	return runtimeCommandInlineSources(f)
+	return runtimeCommandInstall(f)
 }
 
-func runtimeCommandGitSources(f *serverlessv1alpha2.Function) string {
-	var result []string
-	if f.HasNodejsRuntime() {
-		result = append(result, `echo "{}" > package.json;`)
+func runtimeCommandSources(f *serverlessv1alpha2.Function) string {
+	switch {
+	case f.HasGitSources():
+		return runtimeCommandCopySources(f, `cp -r /git-repository/src/* .;`)
+	case f.HasConfigMapSources():
+		// keys are mounted as symlinks, so they have to be dereferenced
+		return runtimeCommandCopySources(f, fmt.Sprintf(`cp -rL %s/* .;`, configMapSourcesMountPath))
+	case f.HasInlineSources():
+		return runtimeCommandCopySources(f, fmt.Sprintf(`cp -rL %s/* .;`, inlineSourcesMountPath))
+	case f.HasOCISources():
+		return runtimeCommandCopySources(f, fmt.Sprintf(`cp -r %s/src/* .;`, ociArtifactMountPath))
+	case f.HasArchiveSources():
+		return runtimeCommandCopySources(f, fmt.Sprintf(`cp -r %s/src/* .;`, archiveMountPath))
+	default:
+		return ""
 	}
-	result = append(result, `cp -r /git-repository/src/* .;`)
-	return strings.Join(result, "\n")
 }
 
-func runtimeCommandInlineSources(f *serverlessv1alpha2.Function) string {
+func runtimeCommandCopySources(f *serverlessv1alpha2.Function, copyCommand string) string {
 	var result []string
-	spec := &f.Spec
-	dependencies := spec.Source.Inline.Dependencies
-
-	handlerName, dependenciesName := "", ""
 	if f.HasNodejsRuntime() {
-		handlerName, dependenciesName = "handler.js", "package.json"
 		result = append(result, `echo "{}" > package.json;`)
-	} else if f.HasPythonRuntime() {
-		handlerName, dependenciesName = "handler.py", "requirements.txt"
-	}
-
-	result = append(result, fmt.Sprintf(`echo "${FUNC_HANDLER_SOURCE}" > %s;`, handlerName))
-	if dependencies != "" {
-		result = append(result, fmt.Sprintf(`echo "${FUNC_HANDLER_DEPENDENCIES}" > %s;`, dependenciesName))
 	}
+	result = append(result, copyCommand)
 	return strings.Join(result, "\n")
 }
 
+// runtimeCommandInstall installs dependencies preferring lockfiles for reproducible installs
+// python dependencies are taken from poetry.lock, pyproject.toml or requirements.txt (in this order)
+// poetry shipped in the runtime image exports poetry.lock and reads [tool.poetry] dependencies of pyproject.toml
 func runtimeCommandInstall(f *serverlessv1alpha2.Function) string {
 	if f.HasNodejsRuntime() {
-		return `npm install --prefer-offline --no-audit --progress=false;`
+		return `if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi`
 	} else if f.HasPythonRuntime() {
 		return `export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";`
 	}
 	return ""
 }
 
+// runtimeCommandTranspile transpiles TypeScript sources next to them (handler.ts to handler.js)
+// the compiler from the function's dependencies takes precedence over the one shipped in the runtime image
+// compilation errors are written to the termination log to report them in the function status
+func runtimeCommandTranspile() string {
+	return fmt.Sprintf(`[ -f tsconfig.json ] || echo '%s' > tsconfig.json;
+TSC=node_modules/.bin/tsc;
+[ -x "${TSC}" ] || TSC=tsc;
+if ! "${TSC}" --project tsconfig.json > /tmp/tsc.log 2>&1; then
+  cat /tmp/tsc.log;
+  { echo "%s:"; head -c 3072 /tmp/tsc.log; } > /dev/termination-log;
+  exit 1;
+fi`, DefaultTypeScriptConfig, TypeScriptCompilationFailedMessage)
+}
+
 func runtimeCommandStart(f *serverlessv1alpha2.Function) string {
 	if f.HasNodejsRuntime() {
 		return `cd ..;
@@ -633,7 +1074,8 @@ fi`
 	return ""
 }
 
-func generalEnvs(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []corev1.EnvVar {
+// generalEnvs returns envs of the function's container, envs of the runtime from the catalog (if any) go before the function's own ones
+func generalEnvs(f *serverlessv1alpha2.Function, c *config.FunctionConfig, functionRuntime *serverlessv1alpha2.FunctionRuntime) []corev1.EnvVar {
 	spec := &f.Spec
 	envs := []corev1.EnvVar{
 		{
@@ -674,25 +1116,15 @@ func generalEnvs(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []cor
 			},
 		}...)
 	}
+	if functionRuntime != nil {
+		envs = append(envs, functionRuntime.Spec.Env...)
+	}
 	envs = append(envs, spec.Env...) //TODO: this order is critical, should we provide option for users to override envs?
 	return envs
 }
 
 func sourceEnvs(f *serverlessv1alpha2.Function) []corev1.EnvVar {
-	spec := &f.Spec
 	envs := []corev1.EnvVar{}
-	if f.HasInlineSources() {
-		envs = append(envs, []corev1.EnvVar{
-			{
-				Name:  "FUNC_HANDLER_SOURCE",
-				Value: spec.Source.Inline.Source,
-			},
-			{
-				Name:  "FUNC_HANDLER_DEPENDENCIES",
-				Value: spec.Source.Inline.Dependencies,
-			},
-		}...)
-	}
 	if f.HasNodejsRuntime() {
 		envs = append(envs, []corev1.EnvVar{
 			{
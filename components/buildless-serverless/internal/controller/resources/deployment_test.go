@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"slices"
 	"testing"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
@@ -11,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
@@ -30,30 +32,139 @@ func TestNewDeployment(t *testing.T) {
 	})
 }
 
+func TestDeploySetFunctionRuntime(t *testing.T) {
+	functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "python312",
+		},
+		Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+			Image:            "test-image-custom-python312",
+			WorkingDir:       "/app/function",
+			InstallCommand:   "pip install --target=/app/function/.local -r deps.txt",
+			StartCommand:     "python ../server.py",
+			HandlerFile:      "main.py",
+			DependenciesFile: "deps.txt",
+			Env:              []corev1.EnvVar{{Name: "PYTHONOPTIMIZE", Value: "1"}},
+		},
+	}
+	t.Run("run function as described by its function runtime", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Source.Inline.Dependencies = "requests==2.32.3"
+		f.Spec.Env = []corev1.EnvVar{{Name: "PYTHONOPTIMIZE", Value: "2"}}
+
+		r := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "",
+			DeploySetFunctionRuntime(functionRuntime),
+			DeploySetInlineSourcesConfigMap("test-function-name-inline"))
+
+		container := r.Spec.Template.Spec.Containers[0]
+		require.Equal(t, "test-image-custom-python312", container.Image)
+		require.Equal(t, "/app/function", container.WorkingDir)
+		require.Equal(t, []string{"sh", "-c", "cp -rL /inline-sources/* .;\npip install --target=/app/function/.local -r deps.txt\npython ../server.py"}, container.Command)
+		// envs of the function runtime go before the function's own ones
+		require.Subset(t, container.Env, []corev1.EnvVar{
+			{Name: "PYTHONOPTIMIZE", Value: "1"},
+			{Name: "PYTHONOPTIMIZE", Value: "2"},
+		})
+		require.Less(t,
+			slices.Index(container.Env, corev1.EnvVar{Name: "PYTHONOPTIMIZE", Value: "1"}),
+			slices.Index(container.Env, corev1.EnvVar{Name: "PYTHONOPTIMIZE", Value: "2"}))
+		require.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "sources", MountPath: "/app/function"})
+		for _, volume := range r.Spec.Template.Spec.Volumes {
+			if volume.Name == inlineSourcesVolumeName {
+				require.Equal(t, []corev1.KeyToPath{
+					{Key: "source", Path: "main.py"},
+					{Key: "dependencies", Path: "deps.txt"},
+				}, volume.ConfigMap.Items)
+			}
+		}
+	})
+	t.Run("keep built-in behavior for fields which aren't set in function runtime", func(t *testing.T) {
+		f := minimalFunction()
+
+		r := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "",
+			DeploySetFunctionRuntime(&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "python312"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "test-image-custom-python312"},
+			}))
+
+		container := r.Spec.Template.Spec.Containers[0]
+		require.Equal(t, "test-image-custom-python312", container.Image)
+		require.Equal(t, "/kubeless", container.WorkingDir)
+		require.Equal(t, []string{"sh", "-c", runtimeCommand(f, nil)}, container.Command)
+	})
+	t.Run("runtime image override wins over image of function runtime", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.RuntimeImageOverride = "test-image-override"
+
+		r := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "",
+			DeploySetFunctionRuntime(functionRuntime))
+
+		require.Equal(t, "test-image-override", r.Spec.Template.Spec.Containers[0].Image)
+	})
+	t.Run("run function with runtime which isn't built-in as described by its function runtime", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Runtime = "go123"
+		f.Spec.Source.Inline.Dependencies = "module example.com/function"
+
+		r := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "",
+			DeploySetRuntime(f.Spec.Runtime),
+			DeploySetFunctionRuntime(&serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "go123"},
+				Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+					Image:            "test-image-go123",
+					WorkingDir:       "/app/function",
+					StartCommand:     "go run ../server",
+					HandlerFile:      "handler.go",
+					DependenciesFile: "go.mod",
+				},
+			}),
+			DeploySetInlineSourcesConfigMap("test-function-name-inline"))
+
+		container := r.Spec.Template.Spec.Containers[0]
+		require.Equal(t, "test-image-go123", container.Image)
+		require.Equal(t, "/app/function", container.WorkingDir)
+		require.Equal(t, []string{"sh", "-c", "cp -rL /inline-sources/* .;\n\ngo run ../server"}, container.Command)
+		for _, volume := range r.Spec.Template.Spec.Volumes {
+			if volume.Name == inlineSourcesVolumeName {
+				require.Equal(t, []corev1.KeyToPath{
+					{Key: "source", Path: "handler.go"},
+					{Key: "dependencies", Path: "go.mod"},
+				}, volume.ConfigMap.Items)
+			}
+		}
+	})
+	t.Run("keep built-in runtime when function runtime isn't set", func(t *testing.T) {
+		f := minimalFunction()
+
+		r := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "",
+			DeploySetFunctionRuntime(nil))
+
+		require.Equal(t, "test-image-python312", r.Spec.Template.Spec.Containers[0].Image)
+		require.Equal(t, "/kubeless", r.Spec.Template.Spec.Containers[0].WorkingDir)
+	})
+}
+
 func TestDeployment_RuntimeImage(t *testing.T) {
-	t.Run("return image from deployment", func(t *testing.T) {
+	t.Run("return configured runtime image", func(t *testing.T) {
 		d := &Deployment{
-			Deployment: &appsv1.Deployment{
-				Spec: appsv1.DeploymentSpec{
-					Template: corev1.PodTemplateSpec{
-						Spec: corev1.PodSpec{
-							Containers: []corev1.Container{
-								{
-									Image: "test-runtime-image",
-								},
-							},
-						},
-					},
-				},
-			},
-			functionConfig: nil,
-			function:       nil,
+			podImage: "test-runtime-image",
 		}
 
 		r := d.RuntimeImage()
 
 		require.Equal(t, "test-runtime-image", r)
 	})
+	t.Run("return runtime image without digest it's pinned to", func(t *testing.T) {
+		d := minimalDeployment()
+		d.podImage = "registry.io/test-runtime-image:v1"
+		digest := "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+
+		d.PinRuntimeImage("registry.io/test-runtime-image@"+digest, digest)
+
+		require.Equal(t, "registry.io/test-runtime-image:v1", d.RuntimeImage())
+		require.Equal(t, digest, d.RuntimeImageDigest())
+		require.Equal(t, "registry.io/test-runtime-image@"+digest, d.Spec.Template.Spec.Containers[0].Image)
+	})
 }
 
 func TestDeployment_construct(t *testing.T) {
@@ -156,6 +267,67 @@ func TestDeployment_construct(t *testing.T) {
 			"sidecar.istio.io/nativeSidecar": "true",
 		}, r.Spec.Template.ObjectMeta.Annotations)
 	})
+	t.Run("prepare pod for linkerd mesh", func(t *testing.T) {
+		c := minimalFunctionConfig()
+		c.MeshMode = config.MeshModeLinkerd
+		d := NewDeployment(minimalFunction(), c, nil, "", nil, "")
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Equal(t, map[string]string{
+			"config.alpha.linkerd.io/proxy-enable-native-sidecar": "true",
+			"config.linkerd.io/proxy-await":                       "enabled",
+		}, r.Spec.Template.ObjectMeta.Annotations)
+	})
+	t.Run("skip mesh annotations when mesh is disabled", func(t *testing.T) {
+		c := minimalFunctionConfig()
+		c.MeshMode = config.MeshModeNone
+		d := NewDeployment(minimalFunction(), c, nil, "", nil, "")
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Empty(t, r.Spec.Template.ObjectMeta.Annotations)
+	})
+	t.Run("opt function out of sidecar injection", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.MeshInjection = serverlessv1alpha2.MeshInjectionDisabled
+		d := minimalDeploymentForFunction(f)
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Equal(t, map[string]string{
+			"sidecar.istio.io/inject": "false",
+		}, r.Spec.Template.ObjectMeta.Annotations)
+	})
+	t.Run("remove annotations of previous mesh mode", func(t *testing.T) {
+		c := minimalFunctionConfig()
+		c.MeshMode = config.MeshModeLinkerd
+		d := NewDeployment(minimalFunction(), c, &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							"proxy.istio.io/config":          "{ \"holdApplicationUntilProxyStarts\": true }",
+							"sidecar.istio.io/nativeSidecar": "true",
+							"thompson":                       "exciting",
+						},
+					},
+				},
+			},
+		}, "", nil, "")
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Equal(t, map[string]string{
+			"config.alpha.linkerd.io/proxy-enable-native-sidecar": "true",
+			"config.linkerd.io/proxy-await":                       "enabled",
+			"thompson":                                            "exciting",
+		}, r.Spec.Template.ObjectMeta.Annotations)
+	})
 	t.Run("use fixed container name", func(t *testing.T) {
 		d := minimalDeployment()
 
@@ -192,9 +364,29 @@ func TestDeployment_construct(t *testing.T) {
 			[]string{
 				"sh",
 				"-c",
-				`echo "${FUNC_HANDLER_SOURCE}" > handler.py;
+				`cp -rL /inline-sources/* .;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -233,7 +425,7 @@ fi`,
 		require.NotNil(t, r)
 		require.Equal(t, *rc.Function.Resources, r.Spec.Template.Spec.Containers[0].Resources)
 	})
-	t.Run("use container env based on function", func(t *testing.T) {
+	t.Run("don't pass inline sources in container env", func(t *testing.T) {
 		f := minimalFunction()
 		f.Spec.Source.Inline.Source = "special-function-source"
 		d := minimalDeploymentForFunction(f)
@@ -241,11 +433,47 @@ fi`,
 		r := d.construct()
 
 		require.NotNil(t, r)
+		for _, env := range r.Spec.Template.Spec.Containers[0].Env {
+			require.NotEqual(t, "special-function-source", env.Value)
+		}
+	})
+	t.Run("mount inline sources ConfigMap", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Source.Inline.Dependencies = "test-dependencies"
+		f.Spec.Source.Inline.Files = map[string]string{
+			"utils.py":       "utils-source",
+			"lib/helpers.py": "helpers-source",
+		}
+		d := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "",
+			DeploySetInlineSourcesConfigMap("test-function-name-inline-0123456789"))
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Contains(t,
+			r.Spec.Template.Spec.Volumes,
+			corev1.Volume{
+				Name: "inline-sources",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "test-function-name-inline-0123456789",
+						},
+						Items: []corev1.KeyToPath{
+							{Key: "source", Path: "handler.py"},
+							{Key: "dependencies", Path: "requirements.txt"},
+							{Key: inlineFileKey("lib/helpers.py"), Path: "lib/helpers.py"},
+							{Key: inlineFileKey("utils.py"), Path: "utils.py"},
+						},
+					},
+				},
+			})
 		require.Contains(t,
-			r.Spec.Template.Spec.Containers[0].Env,
-			corev1.EnvVar{
-				Name:  "FUNC_HANDLER_SOURCE",
-				Value: "special-function-source",
+			r.Spec.Template.Spec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "inline-sources",
+				ReadOnly:  true,
+				MountPath: "/inline-sources",
 			})
 	})
 	t.Run("use container volume mounts based on function", func(t *testing.T) {
@@ -358,6 +586,105 @@ mkdir /git-repository/src;cp -r '/git-repository/repo/recursing-mcnulty'/* /git-
 mkdir /git-repository/src;cp -r '/git-repository/repo/git functions/nodejs12'/* /git-repository/src;`}
 		require.Equal(t, expectedCommand, c.Command)
 	})
+	t.Run("doesn't create init container for configmap function", func(t *testing.T) {
+		d := minimalDeployment()
+		d.function.Spec.Source = serverlessv1alpha2.Source{
+			ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+				Name: "function-sources"}}
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Empty(t, r.Spec.Template.Spec.InitContainers)
+		require.Contains(t, r.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "configmap-sources",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "function-sources"}}}})
+	})
+	t.Run("create init container for oci function pulling resolved digest", func(t *testing.T) {
+		d := minimalDeployment()
+		d.ociDigest = "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+		d.function.Spec.Source = serverlessv1alpha2.Source{
+			OCI: &serverlessv1alpha2.OCISource{
+				Reference:      "ghcr.io/user/function:v1",
+				PullSecretName: "registry-credentials"}}
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		c := r.Spec.Template.Spec.InitContainers[0]
+		expectedCommand := []string{"sh", "-c",
+			`rm -rf /oci-artifact/*
+/app/gitcloner`}
+		require.Equal(t, expectedCommand, c.Command)
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "APP_SOURCE_TYPE", Value: "oci"},
+			{Name: "APP_OCI_REFERENCE", Value: "ghcr.io/user/function@sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"},
+			{Name: "APP_DESTINATION_PATH", Value: "/oci-artifact/src"},
+			{Name: "APP_OCI_DOCKER_CONFIG_PATH", Value: "/oci-pull-secret/.dockerconfigjson"},
+		}, c.Env)
+		require.Equal(t, []corev1.VolumeMount{
+			{Name: "oci-artifact", MountPath: "/oci-artifact"},
+			{Name: "oci-pull-secret", ReadOnly: true, MountPath: "/oci-pull-secret"},
+		}, c.VolumeMounts)
+	})
+	t.Run("create init container for archive function with data based on function", func(t *testing.T) {
+		d := minimalDeployment()
+		d.archiveRevision = `"test-etag"`
+		d.function.Spec.Source = serverlessv1alpha2.Source{
+			Archive: &serverlessv1alpha2.ArchiveSource{
+				URL:     "https://artifacts.local/function.tar.gz",
+				SHA256:  "9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0",
+				BaseDir: "/functions/hello/"}}
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		c := r.Spec.Template.Spec.InitContainers[0]
+		expectedCommand := []string{"sh", "-c",
+			`rm -rf /archive/*
+/app/gitcloner
+mkdir /archive/src;cp -r '/archive/repo/functions/hello'/* /archive/src;`}
+		require.Equal(t, expectedCommand, c.Command)
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "APP_SOURCE_TYPE", Value: "archive"},
+			{Name: "APP_ARCHIVE_URL", Value: "https://artifacts.local/function.tar.gz"},
+			{Name: "APP_ARCHIVE_SHA256", Value: "9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"},
+			{Name: "APP_ARCHIVE_REVISION", Value: `"test-etag"`},
+			{Name: "APP_DESTINATION_PATH", Value: "/archive/repo"},
+		}, c.Env)
+		require.Contains(t, r.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name: "archive",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+	})
+	t.Run("set source hash annotation", func(t *testing.T) {
+		d := minimalDeployment()
+		d.sourceHash = "test-hash"
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Equal(t, "test-hash", r.Spec.Template.ObjectMeta.Annotations["serverless.kyma-project.io/source-hash"])
+	})
+	t.Run("remove source hash annotation left by previous sources", func(t *testing.T) {
+		d := minimalDeployment()
+		d.clusterDeployment = &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							"serverless.kyma-project.io/source-hash": "old-hash"}}}}}
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.NotContains(t, r.Spec.Template.ObjectMeta.Annotations, "serverless.kyma-project.io/source-hash")
+	})
 }
 
 func TestDeployment_replicas(t *testing.T) {
@@ -385,6 +712,78 @@ func TestDeployment_replicas(t *testing.T) {
 
 		assert.Equal(t, int32(1), *r)
 	})
+	t.Run("keep replicas set by KEDA for function with triggers", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Replicas: ptr.To[int32](17),
+					ScaleConfig: &serverlessv1alpha2.ScaleConfig{
+						MinReplicas: ptr.To[int32](0),
+						MaxReplicas: ptr.To[int32](5),
+						Triggers:    []serverlessv1alpha2.ScaleTrigger{{Type: "cron"}},
+					},
+				},
+			},
+			clusterDeployment: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](4)},
+			},
+		}
+
+		r := d.replicas()
+
+		assert.Equal(t, int32(4), *r)
+	})
+	t.Run("start function with triggers from minReplicas", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Replicas: ptr.To[int32](17),
+					ScaleConfig: &serverlessv1alpha2.ScaleConfig{
+						MinReplicas: ptr.To[int32](0),
+						MaxReplicas: ptr.To[int32](5),
+						Triggers:    []serverlessv1alpha2.ScaleTrigger{{Type: "cron"}},
+					},
+				},
+			},
+		}
+
+		r := d.replicas()
+
+		assert.Equal(t, int32(0), *r)
+	})
+}
+
+func TestDeployment_strategy(t *testing.T) {
+	t.Run("get default rolling update parameters", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{},
+			},
+		}
+
+		r := d.strategy()
+
+		assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, r.Type)
+		assert.Equal(t, ptr.To(intstr.FromString("25%")), r.RollingUpdate.MaxSurge)
+		assert.Equal(t, ptr.To(intstr.FromString("25%")), r.RollingUpdate.MaxUnavailable)
+	})
+	t.Run("get rolling update parameters from function", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					RollingUpdate: &serverlessv1alpha2.RollingUpdate{
+						MaxSurge:       ptr.To(intstr.FromInt32(1)),
+						MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+					},
+				},
+			},
+		}
+
+		r := d.strategy()
+
+		assert.Equal(t, ptr.To(intstr.FromInt32(1)), r.RollingUpdate.MaxSurge)
+		assert.Equal(t, ptr.To(intstr.FromInt32(0)), r.RollingUpdate.MaxUnavailable)
+	})
 }
 
 func TestDeployment_workingSourcesDir(t *testing.T) {
@@ -949,6 +1348,60 @@ func TestDeployment_volumes(t *testing.T) {
 	}
 }
 
+func TestDeployment_packageRegistryConfigVolume(t *testing.T) {
+	c := &config.FunctionConfig{
+		PackageRegistryConfigSecretName: "serverless-package-registry-config",
+	}
+	tests := []struct {
+		name           string
+		registryConfig *serverlessv1alpha2.PackageRegistryConfig
+		want           corev1.Volume
+	}{
+		{
+			name:           "mount optional cluster-wide package registry config",
+			registryConfig: nil,
+			want: corev1.Volume{
+				Name: "package-registry-config",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "serverless-package-registry-config",
+						Optional:   ptr.To[bool](true),
+					},
+				},
+			},
+		},
+		{
+			name:           "mount package registry config of the function",
+			registryConfig: &serverlessv1alpha2.PackageRegistryConfig{SecretName: "team-registry-config"},
+			want: corev1.Volume{
+				Name: "package-registry-config",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "team-registry-config",
+						Optional:   ptr.To[bool](false),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime:               serverlessv1alpha2.NodeJs22,
+					Source:                serverlessv1alpha2.Source{Inline: &serverlessv1alpha2.InlineSource{Source: "x"}},
+					PackageRegistryConfig: tt.registryConfig,
+				},
+			}
+
+			d := NewDeployment(f, c, nil, "", nil, "")
+
+			require.Contains(t, d.Deployment.Spec.Template.Spec.Volumes, tt.want)
+			require.Equal(t, tt.want.Secret.SecretName, PackageRegistryConfigSecretName(d.Deployment))
+		})
+	}
+}
+
 func TestDeployment_deploymentSecretVolumes(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -1063,14 +1516,6 @@ func TestDeployment_envs(t *testing.T) {
 					Name:  "SERVICE_NAMESPACE",
 					Value: "function-namespace",
 				},
-				{
-					Name:  "FUNC_HANDLER_SOURCE",
-					Value: "function-source",
-				},
-				{
-					Name:  "FUNC_HANDLER_DEPENDENCIES",
-					Value: "function-dependencies",
-				},
 				{
 					Name:  "HANDLER_PATH",
 					Value: "./function/handler.js",
@@ -1115,14 +1560,6 @@ func TestDeployment_envs(t *testing.T) {
 					Name:  "SERVICE_NAMESPACE",
 					Value: "function-namespace",
 				},
-				{
-					Name:  "FUNC_HANDLER_SOURCE",
-					Value: "function-source",
-				},
-				{
-					Name:  "FUNC_HANDLER_DEPENDENCIES",
-					Value: "function-dependencies",
-				},
 				{
 					Name:  "HANDLER_PATH",
 					Value: "./function/handler.js",
@@ -1214,18 +1651,10 @@ func TestDeployment_envs(t *testing.T) {
 					Name:  "SERVICE_NAMESPACE",
 					Value: "function-namespace",
 				},
-				{
-					Name:  "FUNC_HANDLER_SOURCE",
-					Value: "function-source-py",
-				},
 				{
 					Name:  "FUNCTION_PATH",
 					Value: "/kubeless",
 				},
-				{
-					Name:  "FUNC_HANDLER_DEPENDENCIES",
-					Value: "function-dependencies-py",
-				},
 				{
 					Name:  "TRACE_COLLECTOR_ENDPOINT",
 					Value: "test-trace-collector-endpoint",
@@ -1279,9 +1708,29 @@ func TestDeployment_runtimeCommand(t *testing.T) {
 					},
 				},
 			},
-			want: `echo "${FUNC_HANDLER_SOURCE}" > handler.py;
+			want: `cp -rL /inline-sources/* .;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -1303,10 +1752,29 @@ fi`,
 					},
 				},
 			},
-			want: `echo "${FUNC_HANDLER_SOURCE}" > handler.py;
-echo "${FUNC_HANDLER_DEPENDENCIES}" > requirements.txt;
+			want: `cp -rL /inline-sources/* .;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -1333,7 +1801,27 @@ fi`,
 			},
 			want: `cp -r /git-repository/src/* .;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -1355,8 +1843,12 @@ fi`,
 				},
 			},
 			want: `echo "{}" > package.json;
-echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-npm install --prefer-offline --no-audit --progress=false;
+cp -rL /inline-sources/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
 cd ..;
 npm start;`,
 		},
@@ -1374,9 +1866,12 @@ npm start;`,
 				},
 			},
 			want: `echo "{}" > package.json;
-echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-echo "${FUNC_HANDLER_DEPENDENCIES}" > package.json;
-npm install --prefer-offline --no-audit --progress=false;
+cp -rL /inline-sources/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
 cd ..;
 npm start;`,
 		},
@@ -1398,7 +1893,11 @@ npm start;`,
 			},
 			want: `echo "{}" > package.json;
 cp -r /git-repository/src/* .;
-npm install --prefer-offline --no-audit --progress=false;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
 cd ..;
 npm start;`,
 		},
@@ -1415,8 +1914,12 @@ npm start;`,
 				},
 			},
 			want: `echo "{}" > package.json;
-echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-npm install --prefer-offline --no-audit --progress=false;
+cp -rL /inline-sources/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
 cd ..;
 npm start;`,
 		},
@@ -1434,9 +1937,12 @@ npm start;`,
 				},
 			},
 			want: `echo "{}" > package.json;
-echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-echo "${FUNC_HANDLER_DEPENDENCIES}" > package.json;
-npm install --prefer-offline --no-audit --progress=false;
+cp -rL /inline-sources/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
 cd ..;
 npm start;`,
 		},
@@ -1458,14 +1964,189 @@ npm start;`,
 			},
 			want: `echo "{}" > package.json;
 cp -r /git-repository/src/* .;
-npm install --prefer-offline --no-audit --progress=false;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
+cd ..;
+npm start;`,
+		},
+		{
+			name: "build runtime command for configmap nodejs22",
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs22,
+					Source: serverlessv1alpha2.Source{
+						ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+							Name: "function-sources",
+						},
+					},
+				},
+			},
+			want: `echo "{}" > package.json;
+cp -rL /configmap-sources/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
+cd ..;
+npm start;`,
+		},
+		{
+			name: "build runtime command for inline typescript nodejs22",
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime:  serverlessv1alpha2.NodeJs22,
+					Language: serverlessv1alpha2.TypeScript,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{
+							Source: "function-source",
+						},
+					},
+				},
+			},
+			want: `echo "{}" > package.json;
+cp -rL /inline-sources/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
+[ -f tsconfig.json ] || echo '{
+  "compilerOptions": {
+    "target": "es2022",
+    "module": "commonjs",
+    "esModuleInterop": true,
+    "skipLibCheck": true,
+    "strict": true
+  },
+  "exclude": ["node_modules"]
+}' > tsconfig.json;
+TSC=node_modules/.bin/tsc;
+[ -x "${TSC}" ] || TSC=tsc;
+if ! "${TSC}" --project tsconfig.json > /tmp/tsc.log 2>&1; then
+  cat /tmp/tsc.log;
+  { echo "TypeScript compilation failed:"; head -c 3072 /tmp/tsc.log; } > /dev/termination-log;
+  exit 1;
+fi
+cd ..;
+npm start;`,
+		},
+		{
+			name: "ignore typescript language for python312",
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime:  serverlessv1alpha2.Python312,
+					Language: serverlessv1alpha2.TypeScript,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{
+							Source: "function-source",
+						},
+					},
+				},
+			},
+			want: `cp -rL /inline-sources/* .;
+export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";
+cd ..;
+if [ -f "./kubeless.py" ]; then
+  # old file location support
+  python kubeless.py;
+else
+  python server.py;
+fi`,
+		},
+		{
+			name: "build runtime command for archive python312",
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Archive: &serverlessv1alpha2.ArchiveSource{
+							URL: "https://artifacts.local/function.tar.gz",
+						},
+					},
+				},
+			},
+			want: `cp -r /archive/src/* .;
+export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
+REQUIREMENTS=requirements.txt
+if [ -f poetry.lock ]; then
+  PYTHONPATH= POETRY_CACHE_DIR=/tmp/poetry-cache /opt/poetry/bin/poetry export --format=requirements.txt --output=/tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+elif [ -f pyproject.toml ]; then
+  PYTHONPATH= /opt/poetry/bin/python -c '
+import tomllib
+pyproject = tomllib.load(open("pyproject.toml", "rb"))
+requirements = pyproject.get("project", {}).get("dependencies", [])
+if "poetry" in pyproject.get("tool", {}):
+    from pathlib import Path
+    from poetry.core.factory import Factory
+    requirements = [d.to_pep_508() for d in Factory().create_poetry(Path(".")).package.requires]
+print("\n".join(requirements))' > /tmp/requirements.txt;
+  REQUIREMENTS=/tmp/requirements.txt
+fi
+REQUIRE_HASHES=""
+if grep -q -- '--hash=' "${REQUIREMENTS}" 2>/dev/null; then
+  REQUIRE_HASHES="--require-hashes"
+fi
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir ${REQUIRE_HASHES} -r "${REQUIREMENTS}";
+cd ..;
+if [ -f "./kubeless.py" ]; then
+  # old file location support
+  python kubeless.py;
+else
+  python server.py;
+fi`,
+		},
+		{
+			name: "build runtime command for oci nodejs22",
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs22,
+					Source: serverlessv1alpha2.Source{
+						OCI: &serverlessv1alpha2.OCISource{
+							Reference: "ghcr.io/user/function:v1",
+						},
+					},
+				},
+			},
+			want: `echo "{}" > package.json;
+cp -r /oci-artifact/src/* .;
+if [ -f package-lock.json ]; then
+  npm ci --prefer-offline --no-audit --progress=false;
+else
+  npm install --prefer-offline --no-audit --progress=false;
+fi
 cd ..;
 npm start;`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := runtimeCommand(tt.function)
+			r := runtimeCommand(tt.function, nil)
 
 			assert.Equal(t, tt.want, r)
 		})
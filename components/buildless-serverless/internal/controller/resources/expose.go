@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	APIRuleGVK   = schema.GroupVersionKind{Group: "gateway.kyma-project.io", Version: "v2", Kind: "APIRule"}
+)
+
+var apiRuleMethods = []interface{}{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// NewHTTPRoute builds the Gateway API HTTPRoute routing the function's host and path to its Service
+func NewHTTPRoute(f *serverlessv1alpha2.Function, gateway string) *unstructured.Unstructured {
+	gatewayNamespace, gatewayName := splitGateway(f, gateway)
+	route := newExposeObject(f, HTTPRouteGVK)
+	route.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{
+			map[string]interface{}{
+				"name":      gatewayName,
+				"namespace": gatewayNamespace,
+			},
+		},
+		"hostnames": []interface{}{f.Spec.Expose.Host},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": exposePath(f),
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": f.GetName(),
+						"port": int64(80),
+					},
+				},
+			},
+		},
+	}
+	return route
+}
+
+// NewAPIRule builds the Kyma APIRule exposing the function's Service on its host and path
+func NewAPIRule(f *serverlessv1alpha2.Function, gateway string) *unstructured.Unstructured {
+	rule := map[string]interface{}{
+		"path":    strings.TrimSuffix(exposePath(f), "/") + "/{**}",
+		"methods": apiRuleMethods,
+	}
+	if f.Spec.Expose.Auth == serverlessv1alpha2.ExposeAuthJWT && f.Spec.Expose.JWT != nil {
+		rule["jwt"] = map[string]interface{}{
+			"authentications": []interface{}{
+				map[string]interface{}{
+					"issuer":  f.Spec.Expose.JWT.Issuer,
+					"jwksUri": f.Spec.Expose.JWT.JWKSURI,
+				},
+			},
+		}
+	} else {
+		rule["noAuth"] = true
+	}
+
+	apiRule := newExposeObject(f, APIRuleGVK)
+	apiRule.Object["spec"] = map[string]interface{}{
+		"hosts":   []interface{}{f.Spec.Expose.Host},
+		"gateway": gateway,
+		"service": map[string]interface{}{
+			"name":      f.GetName(),
+			"namespace": f.GetNamespace(),
+			"port":      int64(80),
+		},
+		"rules": []interface{}{rule},
+	}
+	return apiRule
+}
+
+// ExposeURL returns the public URL of the exposed function
+func ExposeURL(f *serverlessv1alpha2.Function) string {
+	return fmt.Sprintf("https://%s%s", f.Spec.Expose.Host, exposePath(f))
+}
+
+func newExposeObject(f *serverlessv1alpha2.Function, gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(f.GetName())
+	u.SetNamespace(f.GetNamespace())
+	u.SetLabels(labels.Merge(f.FunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelExposeValue,
+	}))
+	return u
+}
+
+func exposePath(f *serverlessv1alpha2.Function) string {
+	if f.Spec.Expose.Path == "" {
+		return "/"
+	}
+	return f.Spec.Expose.Path
+}
+
+// splitGateway returns namespace and name of the gateway, the gateway without namespace is looked up in the function's namespace
+func splitGateway(f *serverlessv1alpha2.Function, gateway string) (string, string) {
+	namespace, name, found := strings.Cut(gateway, "/")
+	if !found {
+		return f.GetNamespace(), gateway
+	}
+	return namespace, name
+}
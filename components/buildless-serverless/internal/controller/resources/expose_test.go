@@ -0,0 +1,138 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func exposedFunction(expose *serverlessv1alpha2.Expose) *serverlessv1alpha2.Function {
+	return &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function-name",
+			Namespace: "test-function-namespace",
+			UID:       "test-uid",
+		},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Expose: expose,
+		},
+	}
+}
+
+func TestNewHTTPRoute(t *testing.T) {
+	t.Run("create proper HTTPRoute", func(t *testing.T) {
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com", Path: "/api"})
+
+		r := NewHTTPRoute(f, "kyma-system/kyma-gateway")
+
+		require.Equal(t, HTTPRouteGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "expose",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, r.GetLabels())
+		require.Equal(t, map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": "kyma-gateway", "namespace": "kyma-system"},
+			},
+			"hostnames": []interface{}{"fn.example.com"},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{
+							"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"},
+						},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "test-function-name", "port": int64(80)},
+					},
+				},
+			},
+		}, r.Object["spec"])
+	})
+	t.Run("use gateway from function namespace", func(t *testing.T) {
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+
+		r := NewHTTPRoute(f, "my-gateway")
+
+		spec := r.Object["spec"].(map[string]interface{})
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"name": "my-gateway", "namespace": "test-function-namespace"},
+		}, spec["parentRefs"])
+	})
+}
+
+func TestNewAPIRule(t *testing.T) {
+	t.Run("create APIRule without auth", func(t *testing.T) {
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+
+		r := NewAPIRule(f, "kyma-system/kyma-gateway")
+
+		require.Equal(t, APIRuleGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]interface{}{
+			"hosts":   []interface{}{"fn.example.com"},
+			"gateway": "kyma-system/kyma-gateway",
+			"service": map[string]interface{}{
+				"name":      "test-function-name",
+				"namespace": "test-function-namespace",
+				"port":      int64(80),
+			},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"path":    "/{**}",
+					"methods": apiRuleMethods,
+					"noAuth":  true,
+				},
+			},
+		}, r.Object["spec"])
+	})
+	t.Run("create APIRule with jwt auth", func(t *testing.T) {
+		f := exposedFunction(&serverlessv1alpha2.Expose{
+			Host: "fn.example.com",
+			Path: "/api/",
+			Auth: serverlessv1alpha2.ExposeAuthJWT,
+			JWT: &serverlessv1alpha2.ExposeJWT{
+				Issuer:  "https://issuer.example.com",
+				JWKSURI: "https://issuer.example.com/keys",
+			},
+		})
+
+		r := NewAPIRule(f, "kyma-system/kyma-gateway")
+
+		spec := r.Object["spec"].(map[string]interface{})
+		require.Equal(t, []interface{}{
+			map[string]interface{}{
+				"path":    "/api/{**}",
+				"methods": apiRuleMethods,
+				"jwt": map[string]interface{}{
+					"authentications": []interface{}{
+						map[string]interface{}{
+							"issuer":  "https://issuer.example.com",
+							"jwksUri": "https://issuer.example.com/keys",
+						},
+					},
+				},
+			},
+		}, spec["rules"])
+	})
+}
+
+func TestExposeURL(t *testing.T) {
+	t.Run("use default path", func(t *testing.T) {
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+
+		require.Equal(t, "https://fn.example.com/", ExposeURL(f))
+	})
+	t.Run("use function path", func(t *testing.T) {
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com", Path: "/api"})
+
+		require.Equal(t, "https://fn.example.com/api", ExposeURL(f))
+	})
+}
@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// jobNameHashLength is the length of the pod template hash added to the function's name
+	jobNameHashLength = 10
+	// jobNameMaxLength keeps the name valid as the value of the job-name label set on the Job's pods
+	jobNameMaxLength = 63
+)
+
+// JobLabels returns labels used to find all Jobs running the function
+func JobLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.InternalFunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelBatchValue,
+	})
+}
+
+// JobCommand returns the command of the function's container which prepares sources the same way as the Deployment does
+// and calls the function's handler once instead of starting the server
+func JobCommand(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) []string {
+	var result []string
+	result = append(result, runtimeCommandSources(f))
+	result = append(result, functionRuntimeInstallCommand(f, functionRuntime))
+	if f.HasTypeScript() {
+		result = append(result, runtimeCommandTranspile())
+	}
+	result = append(result, jobCommandRun(f, functionRuntime))
+
+	return []string{"sh", "-c", strings.Join(result, "\n")}
+}
+
+// jobCommandRun calls the handler with an empty event, the container fails when the handler throws
+func jobCommandRun(f *serverlessv1alpha2.Function, functionRuntime *serverlessv1alpha2.FunctionRuntime) string {
+	handlerName, _ := InlineFileNames(f, functionRuntime)
+	// the TypeScript handler is transpiled next to its source
+	module := strings.TrimSuffix(handlerName, path.Ext(handlerName))
+	if f.HasNodejsRuntime() {
+		return fmt.Sprintf(`node -e 'Promise.resolve().then(() => require("./%s.js").main({ data: {}, extensions: {} }, {})).then(() => process.exit(0), (err) => { console.error(err); process.exit(1); });'`, module)
+	} else if f.HasPythonRuntime() {
+		return fmt.Sprintf(`python -c 'import %s; %s.main({"data": {}, "extensions": {}}, {})'`, module, module)
+	}
+	return ""
+}
+
+// NewJob builds the Job running the pod built for the function's Deployment to completion
+// the Job is named after the hash of its pod template, so the function runs again when its sources or configuration change
+func NewJob(f *serverlessv1alpha2.Function, d *Deployment) *batchv1.Job {
+	template := d.Spec.Template.DeepCopy()
+	template.Labels = labels.Merge(template.Labels, JobLabels(f))
+	template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	for i := range template.Spec.Containers {
+		container := &template.Spec.Containers[i]
+		container.Ports = nil
+		container.StartupProbe = nil
+		container.ReadinessProbe = nil
+		container.LivenessProbe = nil
+	}
+
+	var backoffLimit *int32
+	var activeDeadlineSeconds *int64
+	runID := ""
+	if f.Spec.Job != nil {
+		backoffLimit = f.Spec.Job.BackoffLimit
+		activeDeadlineSeconds = f.Spec.Job.ActiveDeadlineSeconds
+		runID = f.Spec.Job.RunID
+	}
+	if backoffLimit == nil {
+		backoffLimit = ptr.To[int32](0)
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName(f, template, runID),
+			Namespace: f.GetNamespace(),
+			Labels:    labels.Merge(f.FunctionLabels(), JobLabels(f)),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          backoffLimit,
+			ActiveDeadlineSeconds: activeDeadlineSeconds,
+			Template:              *template,
+		},
+	}
+}
+
+// JobLogsCommand returns the command printing logs of the function's container run by the Job
+func JobLogsCommand(job *batchv1.Job) string {
+	return fmt.Sprintf("kubectl logs -n %s job/%s -c function", job.GetNamespace(), job.GetName())
+}
+
+func jobName(f *serverlessv1alpha2.Function, template *corev1.PodTemplateSpec, runID string) string {
+	// marshalling of the pod template can't fail as it contains only serializable fields
+	data, _ := json.Marshal(template)
+	sum := sha256.Sum256(append(data, []byte(runID)...))
+	hash := hex.EncodeToString(sum[:])[:jobNameHashLength]
+
+	name := f.GetName()
+	if maxLength := jobNameMaxLength - jobNameHashLength - 1; len(name) > maxLength {
+		name = strings.TrimSuffix(name[:maxLength], "-")
+	}
+	return fmt.Sprintf("%s-%s", name, hash)
+}
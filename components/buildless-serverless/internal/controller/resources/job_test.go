@@ -0,0 +1,112 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewJob(t *testing.T) {
+	t.Run("create proper Job", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Workload = serverlessv1alpha2.WorkloadJob
+		f.Spec.Job = &serverlessv1alpha2.JobSettings{
+			ActiveDeadlineSeconds: ptr.To[int64](600),
+		}
+		d := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "", DeploySetCmd(JobCommand(f, nil)))
+
+		j := NewJob(f, d)
+
+		require.True(t, strings.HasPrefix(j.GetName(), "test-function-name-"))
+		require.Len(t, j.GetName(), len("test-function-name-")+jobNameHashLength)
+		require.Equal(t, "test-function-namespace", j.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "batch",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, j.GetLabels())
+		require.Equal(t, "batch", j.Spec.Template.GetLabels()[serverlessv1alpha2.FunctionResourceLabel])
+		require.Equal(t, ptr.To[int32](0), j.Spec.BackoffLimit)
+		require.Equal(t, ptr.To[int64](600), j.Spec.ActiveDeadlineSeconds)
+		podSpec := j.Spec.Template.Spec
+		require.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+		require.Len(t, podSpec.Containers, 1)
+		require.Empty(t, podSpec.Containers[0].Ports)
+		require.Nil(t, podSpec.Containers[0].StartupProbe)
+		require.Nil(t, podSpec.Containers[0].ReadinessProbe)
+		require.Nil(t, podSpec.Containers[0].LivenessProbe)
+		require.Contains(t, podSpec.Containers[0].Command[2], "handler.main(")
+	})
+	t.Run("keep the name when nothing changes", func(t *testing.T) {
+		f := minimalFunction()
+		d := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "")
+
+		require.Equal(t, NewJob(f, d).GetName(), NewJob(f, d).GetName())
+	})
+	t.Run("change the name when sources change", func(t *testing.T) {
+		f := minimalFunction()
+		before := NewJob(f, NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "", DeploySetSourceHash("test-hash")))
+
+		after := NewJob(f, NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "", DeploySetSourceHash("changed-test-hash")))
+
+		require.NotEqual(t, before.GetName(), after.GetName())
+	})
+	t.Run("change the name when run id changes", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Job = &serverlessv1alpha2.JobSettings{RunID: "1"}
+		d := NewDeployment(f, minimalFunctionConfig(), nil, "", nil, "")
+		before := NewJob(f, d)
+
+		f.Spec.Job.RunID = "2"
+		after := NewJob(f, d)
+
+		require.NotEqual(t, before.GetName(), after.GetName())
+	})
+	t.Run("truncate long function name", func(t *testing.T) {
+		f := minimalFunction()
+		f.Name = strings.Repeat("a", 63)
+
+		j := NewJob(f, NewDeployment(f, minimalFunctionConfig(), nil, "", nil, ""))
+
+		require.Len(t, j.GetName(), jobNameMaxLength)
+	})
+}
+
+func TestJobCommand(t *testing.T) {
+	t.Run("call nodejs handler", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Runtime = serverlessv1alpha2.NodeJs22
+
+		cmd := JobCommand(f, nil)
+
+		require.Equal(t, "sh", cmd[0])
+		require.Contains(t, cmd[2], `require("./handler.js").main(`)
+	})
+	t.Run("call python handler", func(t *testing.T) {
+		cmd := JobCommand(minimalFunction(), nil)
+
+		require.Contains(t, cmd[2], "import handler; handler.main(")
+	})
+	t.Run("use install command and handler file of function runtime", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Runtime = serverlessv1alpha2.NodeJs22
+		functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+			Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+				InstallCommand: "npm ci --omit=dev",
+				StartCommand:   "node server.js",
+				HandlerFile:    "index.js",
+			},
+		}
+
+		cmd := JobCommand(f, functionRuntime)
+
+		require.Contains(t, cmd[2], "npm ci --omit=dev")
+		require.Contains(t, cmd[2], `require("./index.js").main(`)
+		require.NotContains(t, cmd[2], "node server.js")
+	})
+}
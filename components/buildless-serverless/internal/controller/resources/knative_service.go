@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var KnativeServiceGVK = schema.GroupVersionKind{Group: "serving.knative.dev", Version: "v1", Kind: "Service"}
+
+const (
+	knativeMinScaleAnnotation = "autoscaling.knative.dev/min-scale"
+	knativeMaxScaleAnnotation = "autoscaling.knative.dev/max-scale"
+)
+
+// KnativeServiceLabels returns labels of the Knative Service serving the function
+func KnativeServiceLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.FunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelKnativeServiceValue,
+	})
+}
+
+// NewKnativeService builds the Knative Service running the pod built for the function's Deployment
+// the Service is named after the function, so the function is reachable at the same address as with the Deployment backend
+func NewKnativeService(f *serverlessv1alpha2.Function, d *Deployment) (*unstructured.Unstructured, error) {
+	template := d.Spec.Template.DeepCopy()
+	for i := range template.Spec.Containers {
+		// Knative routes requests to the revision when it's ready, so the startup probe isn't needed
+		template.Spec.Containers[i].StartupProbe = nil
+	}
+	template.Annotations = labels.Merge(template.Annotations, knativeScaleAnnotations(f))
+
+	podSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&template.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(KnativeServiceGVK)
+	u.SetName(f.GetName())
+	u.SetNamespace(f.GetNamespace())
+	u.SetLabels(KnativeServiceLabels(f))
+	u.Object["spec"] = map[string]interface{}{
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      stringMapToInterface(template.Labels),
+				"annotations": stringMapToInterface(template.Annotations),
+			},
+			"spec": podSpec,
+		},
+	}
+	return u, nil
+}
+
+// knativeScaleAnnotations keeps the number of pods between scaleConfig's bounds
+// without scaleConfig the function is scaled up with requests and never below its replicas
+func knativeScaleAnnotations(f *serverlessv1alpha2.Function) map[string]string {
+	if f.Spec.ScaleConfig != nil {
+		result := map[string]string{}
+		if f.Spec.ScaleConfig.MinReplicas != nil {
+			result[knativeMinScaleAnnotation] = fmt.Sprint(*f.Spec.ScaleConfig.MinReplicas)
+		}
+		if f.Spec.ScaleConfig.MaxReplicas != nil {
+			result[knativeMaxScaleAnnotation] = fmt.Sprint(*f.Spec.ScaleConfig.MaxReplicas)
+		}
+		return result
+	}
+
+	replicas := DefaultDeploymentReplicas
+	if f.Spec.Replicas != nil {
+		replicas = *f.Spec.Replicas
+	}
+	return map[string]string{
+		knativeMinScaleAnnotation: fmt.Sprint(replicas),
+	}
+}
+
+// KnativeServiceReadyStatus returns the status and the message of the Knative Service's Ready condition
+// the status is unknown until Knative reconciles the last change of the Service
+func KnativeServiceReadyStatus(u *unstructured.Unstructured) (metav1.ConditionStatus, string) {
+	observedGeneration, _, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if observedGeneration != u.GetGeneration() {
+		return metav1.ConditionUnknown, ""
+	}
+	return readyConditionStatus(u)
+}
+
+// readyConditionStatus returns the status and the message of the Ready condition reported in the resource's status
+func readyConditionStatus(u *unstructured.Unstructured) (metav1.ConditionStatus, string) {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		message, _ := condition["message"].(string)
+		return metav1.ConditionStatus(status), message
+	}
+	return metav1.ConditionUnknown, ""
+}
+
+// KnativeServiceURL returns the URL the Knative Service is served at
+func KnativeServiceURL(u *unstructured.Unstructured) string {
+	url, _, _ := unstructured.NestedString(u.Object, "status", "url")
+	return url
+}
+
+func stringMapToInterface(m map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
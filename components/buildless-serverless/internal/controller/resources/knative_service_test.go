@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewKnativeService(t *testing.T) {
+	t.Run("create proper Knative Service", func(t *testing.T) {
+		f := minimalFunction()
+
+		r, err := NewKnativeService(f, minimalDeploymentForFunction(f))
+
+		require.NoError(t, err)
+		require.Equal(t, KnativeServiceGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "knative-service",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, r.GetLabels())
+		podLabels, _, _ := unstructured.NestedStringMap(r.Object, "spec", "template", "metadata", "labels")
+		require.Equal(t, f.PodLabels(), podLabels)
+		annotations, _, _ := unstructured.NestedStringMap(r.Object, "spec", "template", "metadata", "annotations")
+		require.Equal(t, "1", annotations["autoscaling.knative.dev/min-scale"])
+		require.NotContains(t, annotations, "autoscaling.knative.dev/max-scale")
+		containers, _, _ := unstructured.NestedSlice(r.Object, "spec", "template", "spec", "containers")
+		require.Len(t, containers, 1)
+		container := containers[0].(map[string]interface{})
+		require.Equal(t, "test-image-python312", container["image"])
+		require.NotContains(t, container, "startupProbe")
+		require.Contains(t, container, "readinessProbe")
+	})
+	t.Run("scale within scaleConfig", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.ScaleConfig = &serverlessv1alpha2.ScaleConfig{
+			MinReplicas: ptr.To[int32](0),
+			MaxReplicas: ptr.To[int32](10),
+		}
+
+		r, err := NewKnativeService(f, minimalDeploymentForFunction(f))
+
+		require.NoError(t, err)
+		annotations, _, _ := unstructured.NestedStringMap(r.Object, "spec", "template", "metadata", "annotations")
+		require.Equal(t, "0", annotations["autoscaling.knative.dev/min-scale"])
+		require.Equal(t, "10", annotations["autoscaling.knative.dev/max-scale"])
+	})
+}
+
+func TestKnativeServiceReadyStatus(t *testing.T) {
+	service := func(generation, observedGeneration int64, conditions ...interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"observedGeneration": observedGeneration,
+				"conditions":         conditions,
+				"url":                "http://test-function-name.test-function-namespace.example.com",
+			},
+		}}
+		u.SetGeneration(generation)
+		return u
+	}
+
+	t.Run("ready when Ready condition is true", func(t *testing.T) {
+		s := service(2, 2, map[string]interface{}{"type": "Ready", "status": "True"})
+
+		status, message := KnativeServiceReadyStatus(s)
+
+		require.Equal(t, metav1.ConditionTrue, status)
+		require.Empty(t, message)
+		require.Equal(t, "http://test-function-name.test-function-namespace.example.com", KnativeServiceURL(s))
+	})
+	t.Run("failed when Ready condition is false", func(t *testing.T) {
+		s := service(2, 2, map[string]interface{}{"type": "Ready", "status": "False", "message": "Revision failed"})
+
+		status, message := KnativeServiceReadyStatus(s)
+
+		require.Equal(t, metav1.ConditionFalse, status)
+		require.Equal(t, "Revision failed", message)
+	})
+	t.Run("unknown when last change isn't reconciled", func(t *testing.T) {
+		s := service(3, 2, map[string]interface{}{"type": "Ready", "status": "True"})
+
+		status, _ := KnativeServiceReadyStatus(s)
+
+		require.Equal(t, metav1.ConditionUnknown, status)
+	})
+	t.Run("unknown without Ready condition", func(t *testing.T) {
+		s := service(1, 1)
+
+		status, _ := KnativeServiceReadyStatus(s)
+
+		require.Equal(t, metav1.ConditionUnknown, status)
+	})
+}
@@ -0,0 +1,84 @@
+package resources
+
+import (
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+)
+
+const (
+	istioConfigAnnotationKey                      = "proxy.istio.io/config"
+	istioEnableHoldUntilProxyStartAnnotationValue = "{ \"holdApplicationUntilProxyStarts\": true }"
+	istioNativeSidecarAnnotationKey               = "sidecar.istio.io/nativeSidecar"
+	istioInjectAnnotationKey                      = "sidecar.istio.io/inject"
+
+	linkerdInjectAnnotationKey        = "linkerd.io/inject"
+	linkerdNativeSidecarAnnotationKey = "config.alpha.linkerd.io/proxy-enable-native-sidecar"
+	linkerdProxyAwaitAnnotationKey    = "config.linkerd.io/proxy-await"
+)
+
+// meshAnnotationKeys lists all annotations set by the controller for any mesh mode
+var meshAnnotationKeys = []string{
+	istioConfigAnnotationKey,
+	istioNativeSidecarAnnotationKey,
+	istioInjectAnnotationKey,
+	linkerdInjectAnnotationKey,
+	linkerdNativeSidecarAnnotationKey,
+	linkerdProxyAwaitAnnotationKey,
+}
+
+// meshMode returns the mesh mode configured for the cluster, istio is used when it's not set
+func meshMode(c *config.FunctionConfig) config.MeshMode {
+	switch c.MeshMode {
+	case config.MeshModeLinkerd, config.MeshModeNone:
+		return c.MeshMode
+	default:
+		return config.MeshModeIstio
+	}
+}
+
+// meshPodAnnotations returns annotations of the function's runtime Pod required by the configured mesh
+// the proxy runs as the native sidecar (init container), which is required for init containers of
+// git, oci and archive sourced functions to fetch sources, and the function starts after the proxy is ready
+func meshPodAnnotations(f *serverlessv1alpha2.Function, c *config.FunctionConfig) map[string]string {
+	result := meshJobAnnotations(f, c)
+	if f.IsMeshInjectionDisabled() {
+		return result
+	}
+	switch meshMode(c) {
+	case config.MeshModeIstio:
+		result[istioConfigAnnotationKey] = istioEnableHoldUntilProxyStartAnnotationValue
+	case config.MeshModeLinkerd:
+		result[linkerdProxyAwaitAnnotationKey] = "enabled"
+	}
+	return result
+}
+
+// meshJobAnnotations returns annotations of Pods run to completion required by the configured mesh
+// native sidecar doesn't keep the Pod running after its containers complete
+func meshJobAnnotations(f *serverlessv1alpha2.Function, c *config.FunctionConfig) map[string]string {
+	result := map[string]string{}
+	switch meshMode(c) {
+	case config.MeshModeIstio:
+		if f.IsMeshInjectionDisabled() {
+			result[istioInjectAnnotationKey] = "false"
+			break
+		}
+		result[istioNativeSidecarAnnotationKey] = "true"
+	case config.MeshModeLinkerd:
+		if f.IsMeshInjectionDisabled() {
+			result[linkerdInjectAnnotationKey] = "disabled"
+			break
+		}
+		result[linkerdNativeSidecarAnnotationKey] = "true"
+	}
+	return result
+}
+
+func isMeshAnnotation(key string) bool {
+	for _, meshKey := range meshAnnotationKeys {
+		if key == meshKey {
+			return true
+		}
+	}
+	return false
+}
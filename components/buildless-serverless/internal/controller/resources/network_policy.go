@@ -0,0 +1,179 @@
+package resources
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+// NetworkPolicyLabels returns labels of the NetworkPolicy restricting the traffic of the function's Pods
+func NetworkPolicyLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.FunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelNetworkPolicyValue,
+	})
+}
+
+// NewNetworkPolicy builds the NetworkPolicy selecting Pods of the function's Deployment
+// only the traffic directions set in the function's networkPolicy are restricted
+func NewNetworkPolicy(f *serverlessv1alpha2.Function, c *config.FunctionConfig) *networkingv1.NetworkPolicy {
+	spec := f.Spec.NetworkPolicy
+	policy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      f.GetName(),
+			Namespace: f.GetNamespace(),
+			Labels:    NetworkPolicyLabels(f),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: f.SelectorLabels(),
+			},
+		},
+	}
+
+	if spec.Ingress != nil {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+		policy.Spec.Ingress = ingressRules(f, c)
+	}
+	if spec.Egress != nil {
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		policy.Spec.Egress = egressRules(f, c)
+	}
+	return policy
+}
+
+func ingressRules(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []networkingv1.NetworkPolicyIngressRule {
+	var rules []networkingv1.NetworkPolicyIngressRule
+
+	sources := f.Spec.NetworkPolicy.Ingress.From
+	if len(sources) != 0 {
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(sources))
+		for _, source := range sources {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: source.NamespaceSelector,
+				PodSelector:       source.PodSelector,
+			})
+		}
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{From: peers})
+	}
+
+	// events of the function's subscriptions are delivered from the eventing namespace
+	if len(f.Spec.Subscriptions) != 0 {
+		if namespace, _, ok := endpointNamespaceAndPort(c.FunctionPublisherProxyAddress, f.GetNamespace()); ok && namespace != "" {
+			rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+				From: []networkingv1.NetworkPolicyPeer{namespacePeer(namespace)},
+			})
+		}
+	}
+	return rules
+}
+
+func egressRules(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []networkingv1.NetworkPolicyEgressRule {
+	// DNS names must be resolved before any allowed destination is called
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				networkPolicyPort(corev1.ProtocolUDP, 53),
+				networkPolicyPort(corev1.ProtocolTCP, 53),
+			},
+		},
+	}
+
+	// the function publishes events and traces to endpoints configured for the cluster
+	for _, endpoint := range []string{c.FunctionPublisherProxyAddress, c.FunctionTraceCollectorEndpoint} {
+		namespace, port, ok := endpointNamespaceAndPort(endpoint, f.GetNamespace())
+		if !ok {
+			continue
+		}
+		rule := networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{networkPolicyPort(corev1.ProtocolTCP, port)},
+		}
+		if namespace != "" {
+			rule.To = []networkingv1.NetworkPolicyPeer{namespacePeer(namespace)}
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, destination := range f.Spec.NetworkPolicy.Egress.To {
+		rule := networkingv1.NetworkPolicyEgressRule{}
+		for _, cidr := range destination.CIDRs {
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+		for _, port := range destination.Ports {
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			rule.Ports = append(rule.Ports, networkPolicyPort(protocol, port.Port))
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// endpointNamespaceAndPort returns the namespace of the in-cluster service serving the endpoint and the endpoint's port
+// the service without the namespace in its address is expected in the function's namespace,
+// and the namespace is empty for endpoints served outside the cluster
+func endpointNamespaceAndPort(endpoint, functionNamespace string) (string, int32, bool) {
+	if endpoint == "" {
+		return "", 0, false
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "", 0, false
+	}
+
+	port := int32(80)
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if u.Port() != "" {
+		p, err := strconv.ParseInt(u.Port(), 10, 32)
+		if err != nil {
+			return "", 0, false
+		}
+		port = int32(p)
+	}
+
+	hostParts := strings.Split(u.Hostname(), ".")
+	switch {
+	case len(hostParts) == 1:
+		return functionNamespace, port, true
+	case len(hostParts) == 2 || hostParts[2] == "svc":
+		return hostParts[1], port, true
+	default:
+		// the endpoint is served outside the cluster
+		return "", port, true
+	}
+}
+
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{namespaceNameLabel: namespace},
+		},
+	}
+}
+
+func networkPolicyPort(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+	p := intstr.FromInt32(port)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	}
+}
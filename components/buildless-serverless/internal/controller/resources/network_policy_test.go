@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewNetworkPolicy(t *testing.T) {
+	functionConfig := &config.FunctionConfig{
+		FunctionPublisherProxyAddress:  "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
+		FunctionTraceCollectorEndpoint: "http://telemetry-otlp-traces.kyma-system:4318/v1/traces",
+	}
+	port := func(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+		return networkingv1.NetworkPolicyPort{Protocol: ptr.To(protocol), Port: ptr.To(intstr.FromInt32(port))}
+	}
+	namespace := func(name string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": name}},
+		}
+	}
+
+	t.Run("create proper NetworkPolicy", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.NetworkPolicy = &serverlessv1alpha2.NetworkPolicy{
+			Ingress: &serverlessv1alpha2.NetworkPolicyIngress{},
+		}
+
+		r := NewNetworkPolicy(f, functionConfig)
+
+		require.Equal(t, "NetworkPolicy", r.Kind)
+		require.Equal(t, "networking.k8s.io/v1", r.APIVersion)
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "network-policy",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, r.GetLabels())
+		require.Equal(t, f.SelectorLabels(), r.Spec.PodSelector.MatchLabels)
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, r.Spec.PolicyTypes)
+		require.Empty(t, r.Spec.Ingress)
+		require.Nil(t, r.Spec.Egress)
+	})
+	t.Run("allow ingress from sources and eventing for subscribed function", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Subscriptions = []serverlessv1alpha2.Subscription{{Name: "orders"}}
+		frontend := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}
+		f.Spec.NetworkPolicy = &serverlessv1alpha2.NetworkPolicy{
+			Ingress: &serverlessv1alpha2.NetworkPolicyIngress{
+				From: []serverlessv1alpha2.NetworkPolicySource{{PodSelector: frontend}},
+			},
+		}
+
+		r := NewNetworkPolicy(f, functionConfig)
+
+		require.Equal(t, []networkingv1.NetworkPolicyIngressRule{
+			{From: []networkingv1.NetworkPolicyPeer{{PodSelector: frontend}}},
+			{From: []networkingv1.NetworkPolicyPeer{namespace("kyma-system")}},
+		}, r.Spec.Ingress)
+	})
+	t.Run("allow egress to DNS, eventing, tracing and destinations", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.NetworkPolicy = &serverlessv1alpha2.NetworkPolicy{
+			Egress: &serverlessv1alpha2.NetworkPolicyEgress{
+				To: []serverlessv1alpha2.NetworkPolicyDestination{
+					{CIDRs: []string{"10.0.0.0/16"}, Ports: []serverlessv1alpha2.NetworkPolicyPort{{Port: 5432}}},
+					{Ports: []serverlessv1alpha2.NetworkPolicyPort{{Port: 443}, {Port: 514, Protocol: corev1.ProtocolUDP}}},
+				},
+			},
+		}
+
+		r := NewNetworkPolicy(f, functionConfig)
+
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, r.Spec.PolicyTypes)
+		require.Nil(t, r.Spec.Ingress)
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			{Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolUDP, 53), port(corev1.ProtocolTCP, 53)}},
+			{To: []networkingv1.NetworkPolicyPeer{namespace("kyma-system")}, Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 80)}},
+			{To: []networkingv1.NetworkPolicyPeer{namespace("kyma-system")}, Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 4318)}},
+			{
+				To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}}},
+				Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 5432)},
+			},
+			{Ports: []networkingv1.NetworkPolicyPort{port(corev1.ProtocolTCP, 443), port(corev1.ProtocolUDP, 514)}},
+		}, r.Spec.Egress)
+	})
+}
+
+func Test_endpointNamespaceAndPort(t *testing.T) {
+	tests := []struct {
+		name          string
+		endpoint      string
+		wantNamespace string
+		wantPort      int32
+		wantOK        bool
+	}{
+		{
+			name:          "fully qualified service address",
+			endpoint:      "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
+			wantNamespace: "kyma-system",
+			wantPort:      80,
+			wantOK:        true,
+		},
+		{
+			name:          "service address with namespace and port",
+			endpoint:      "http://telemetry-otlp-traces.kyma-system:4318/v1/traces",
+			wantNamespace: "kyma-system",
+			wantPort:      4318,
+			wantOK:        true,
+		},
+		{
+			name:          "service in function's namespace",
+			endpoint:      "https://collector",
+			wantNamespace: "test-function-namespace",
+			wantPort:      443,
+			wantOK:        true,
+		},
+		{
+			name:          "endpoint outside the cluster",
+			endpoint:      "https://otel.example.com",
+			wantNamespace: "",
+			wantPort:      443,
+			wantOK:        true,
+		},
+		{
+			name:     "empty endpoint",
+			endpoint: "",
+			wantOK:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace, port, ok := endpointNamespaceAndPort(tt.endpoint, "test-function-namespace")
+
+			require.Equal(t, tt.wantNamespace, namespace)
+			require.Equal(t, tt.wantPort, port)
+			require.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package resources
+
+import (
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PodDisruptionBudgetLabels returns labels of the PodDisruptionBudget protecting the function's Pods
+func PodDisruptionBudgetLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.FunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelPodDisruptionBudgetValue,
+	})
+}
+
+// NewPodDisruptionBudget builds the PodDisruptionBudget selecting Pods of the function's Deployment
+func NewPodDisruptionBudget(f *serverlessv1alpha2.Function) *policyv1.PodDisruptionBudget {
+	budget := f.Spec.DisruptionBudget
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: "policy/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      f.GetName(),
+			Namespace: f.GetNamespace(),
+			Labels:    PodDisruptionBudgetLabels(f),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: f.SelectorLabels(),
+			},
+			MinAvailable:   budget.MinAvailable,
+			MaxUnavailable: budget.MaxUnavailable,
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewPodDisruptionBudget(t *testing.T) {
+	t.Run("create proper PodDisruptionBudget", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.DisruptionBudget = &serverlessv1alpha2.DisruptionBudget{
+			MinAvailable: ptr.To(intstr.FromString("50%")),
+		}
+
+		r := NewPodDisruptionBudget(f)
+
+		require.Equal(t, &policyv1.PodDisruptionBudget{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodDisruptionBudget",
+				APIVersion: "policy/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				Labels: map[string]string{
+					"serverless.kyma-project.io/function-name": "test-function-name",
+					"serverless.kyma-project.io/managed-by":    "function-controller",
+					"serverless.kyma-project.io/resource":      "pod-disruption-budget",
+					"serverless.kyma-project.io/uuid":          "test-uid",
+				},
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"serverless.kyma-project.io/function-name": "test-function-name",
+						"serverless.kyma-project.io/managed-by":    "function-controller",
+						"serverless.kyma-project.io/resource":      "deployment",
+						"serverless.kyma-project.io/uuid":          "test-uid",
+					},
+				},
+				MinAvailable: ptr.To(intstr.FromString("50%")),
+			},
+		}, r)
+	})
+	t.Run("use maxUnavailable", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.DisruptionBudget = &serverlessv1alpha2.DisruptionBudget{
+			MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+		}
+
+		r := NewPodDisruptionBudget(f)
+
+		require.Nil(t, r.Spec.MinAvailable)
+		require.Equal(t, ptr.To(intstr.FromInt32(1)), r.Spec.MaxUnavailable)
+	})
+}
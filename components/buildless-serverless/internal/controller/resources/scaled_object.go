@@ -0,0 +1,72 @@
+package resources
+
+import (
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var ScaledObjectGVK = schema.GroupVersionKind{Group: "keda.sh", Version: "v1alpha1", Kind: "ScaledObject"}
+
+// ScaledObjectLabels returns labels of the KEDA ScaledObject scaling the function
+func ScaledObjectLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.FunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelScaledObjectValue,
+	})
+}
+
+// NewScaledObject builds the KEDA ScaledObject scaling the function's Deployment with the function's triggers
+func NewScaledObject(f *serverlessv1alpha2.Function, deploymentName string) *unstructured.Unstructured {
+	scaleConfig := f.Spec.ScaleConfig
+
+	triggers := make([]interface{}, 0, len(scaleConfig.Triggers))
+	for _, trigger := range scaleConfig.Triggers {
+		triggers = append(triggers, scaleTrigger(trigger))
+	}
+
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"name":       deploymentName,
+		},
+		"triggers": triggers,
+	}
+	if scaleConfig.MinReplicas != nil {
+		spec["minReplicaCount"] = int64(*scaleConfig.MinReplicas)
+	}
+	if scaleConfig.MaxReplicas != nil {
+		spec["maxReplicaCount"] = int64(*scaleConfig.MaxReplicas)
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(ScaledObjectGVK)
+	u.SetName(f.GetName())
+	u.SetNamespace(f.GetNamespace())
+	u.SetLabels(ScaledObjectLabels(f))
+	u.Object["spec"] = spec
+	return u
+}
+
+func scaleTrigger(trigger serverlessv1alpha2.ScaleTrigger) map[string]interface{} {
+	result := map[string]interface{}{
+		"type":     trigger.Type,
+		"metadata": stringMapToInterface(trigger.Metadata),
+	}
+	if trigger.Name != "" {
+		result["name"] = trigger.Name
+	}
+	if trigger.AuthenticationRef != "" {
+		result["authenticationRef"] = map[string]interface{}{
+			"name": trigger.AuthenticationRef,
+		}
+	}
+	return result
+}
+
+// ScaledObjectReadyStatus returns the status and the message of the KEDA ScaledObject's Ready condition
+func ScaledObjectReadyStatus(u *unstructured.Unstructured) (metav1.ConditionStatus, string) {
+	return readyConditionStatus(u)
+}
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewScaledObject(t *testing.T) {
+	t.Run("create proper ScaledObject", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.ScaleConfig = &serverlessv1alpha2.ScaleConfig{
+			MinReplicas: ptr.To[int32](0),
+			MaxReplicas: ptr.To[int32](10),
+			Triggers: []serverlessv1alpha2.ScaleTrigger{
+				{
+					Type:     "cron",
+					Metadata: map[string]string{"timezone": "Europe/Warsaw", "start": "0 8 * * *", "end": "0 18 * * *", "desiredReplicas": "3"},
+				},
+				{
+					Type:              "kafka",
+					Name:              "orders-lag",
+					Metadata:          map[string]string{"topic": "orders", "lagThreshold": "50"},
+					AuthenticationRef: "kafka-auth",
+				},
+			},
+		}
+
+		r := NewScaledObject(f, "test-function-name-abcde")
+
+		require.Equal(t, ScaledObjectGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "scaled-object",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, r.GetLabels())
+		require.Equal(t, map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"name":       "test-function-name-abcde",
+			},
+			"minReplicaCount": int64(0),
+			"maxReplicaCount": int64(10),
+			"triggers": []interface{}{
+				map[string]interface{}{
+					"type": "cron",
+					"metadata": map[string]interface{}{
+						"timezone": "Europe/Warsaw", "start": "0 8 * * *", "end": "0 18 * * *", "desiredReplicas": "3",
+					},
+				},
+				map[string]interface{}{
+					"type":              "kafka",
+					"name":              "orders-lag",
+					"metadata":          map[string]interface{}{"topic": "orders", "lagThreshold": "50"},
+					"authenticationRef": map[string]interface{}{"name": "kafka-auth"},
+				},
+			},
+		}, r.Object["spec"])
+	})
+}
+
+func TestScaledObjectReadyStatus(t *testing.T) {
+	scaledObject := func(conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"conditions": conditions,
+			},
+		}}
+	}
+
+	t.Run("ready when Ready condition is true", func(t *testing.T) {
+		status, _ := ScaledObjectReadyStatus(scaledObject(map[string]interface{}{"type": "Ready", "status": "True"}))
+
+		require.Equal(t, metav1.ConditionTrue, status)
+	})
+	t.Run("failed when Ready condition is false", func(t *testing.T) {
+		status, message := ScaledObjectReadyStatus(scaledObject(
+			map[string]interface{}{"type": "Active", "status": "False"},
+			map[string]interface{}{"type": "Ready", "status": "False", "message": "Triggers defined in ScaledObject are not working correctly"},
+		))
+
+		require.Equal(t, metav1.ConditionFalse, status)
+		require.Equal(t, "Triggers defined in ScaledObject are not working correctly", message)
+	})
+	t.Run("unknown without Ready condition", func(t *testing.T) {
+		status, _ := ScaledObjectReadyStatus(scaledObject())
+
+		require.Equal(t, metav1.ConditionUnknown, status)
+	})
+}
@@ -1,10 +1,15 @@
 package resources
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 var (
@@ -13,6 +18,28 @@ var (
 
 type serviceOptions func(*Service)
 
+// functionServiceURL returns the in-cluster URL of the function's Service
+func functionServiceURL(f *serverlessv1alpha2.Function) string {
+	return ServiceURL(f.GetNamespace(), f.GetName())
+}
+
+// ServiceURL returns the in-cluster URL of the Service of the function with the given name
+func ServiceURL(namespace, name string) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local", name, namespace)
+}
+
+// IsNamespaceServiceURL checks if the URL points to a Service in the namespace by its cluster-local host name
+// (`{NAME}.{NAMESPACE}.svc` or `{NAME}.{NAMESPACE}.svc.cluster.local`), so requests sent to it don't leave the cluster
+func IsNamespaceServiceURL(rawURL, namespace string) bool {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return false
+	}
+	host := strings.TrimSuffix(u.Hostname(), ".cluster.local")
+	name, found := strings.CutSuffix(host, fmt.Sprintf(".%s.svc", namespace))
+	return found && len(utilvalidation.IsDNS1035Label(name)) == 0
+}
+
 // ServiceName - set the service name
 func ServiceName(name string) serviceOptions {
 	return func(s *Service) {
@@ -61,3 +61,26 @@ func TestNewService(t *testing.T) {
 		require.Equal(t, expectedSvc, s)
 	})
 }
+
+func TestIsNamespaceServiceURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want bool
+	}{
+		{name: "service host with cluster domain", url: "http://sink.default.svc.cluster.local/failed", want: true},
+		{name: "service host with port", url: "https://sink.default.svc:8443", want: true},
+		{name: "service in other namespace", url: "http://sink.kyma-system.svc.cluster.local", want: false},
+		{name: "external host", url: "https://example.com/sink", want: false},
+		{name: "host suffixed with service host", url: "http://sink.default.svc.cluster.local.example.com", want: false},
+		{name: "nested subdomain", url: "http://a.sink.default.svc", want: false},
+		{name: "ip address", url: "http://169.254.169.254/latest", want: false},
+		{name: "user info", url: "http://user@sink.default.svc", want: false},
+		{name: "other scheme", url: "ftp://sink.default.svc", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsNamespaceServiceURL(tt.url, "default"))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var SubscriptionGVK = schema.GroupVersionKind{Group: "eventing.kyma-project.io", Version: "v1alpha2", Kind: "Subscription"}
+
+// SubscriptionName returns the name of the Eventing Subscription created for the function's subscription
+func SubscriptionName(f *serverlessv1alpha2.Function, s serverlessv1alpha2.Subscription) string {
+	return fmt.Sprintf("%s-%s", f.GetName(), s.Name)
+}
+
+// SubscriptionLabels returns labels used to find all Eventing Subscriptions of the function
+func SubscriptionLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.InternalFunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelSubscriptionValue,
+	})
+}
+
+// NewSubscription builds the Eventing Subscription delivering events to the function's Service
+func NewSubscription(f *serverlessv1alpha2.Function, s serverlessv1alpha2.Subscription) *unstructured.Unstructured {
+	typeMatching := s.TypeMatching
+	if typeMatching == "" {
+		typeMatching = serverlessv1alpha2.SubscriptionTypeMatchingStandard
+	}
+
+	types := make([]interface{}, 0, len(s.Types))
+	for _, t := range s.Types {
+		types = append(types, t)
+	}
+
+	spec := map[string]interface{}{
+		"sink":         functionServiceURL(f),
+		"source":       s.Source,
+		"types":        types,
+		"typeMatching": string(typeMatching),
+	}
+	if len(s.Config) != 0 {
+		config := map[string]interface{}{}
+		for key, value := range s.Config {
+			config[key] = value
+		}
+		spec["config"] = config
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(SubscriptionGVK)
+	u.SetName(SubscriptionName(f, s))
+	u.SetNamespace(f.GetNamespace())
+	u.SetLabels(SubscriptionLabels(f))
+	u.Object["spec"] = spec
+	return u
+}
+
+// SubscriptionReady returns true when the Eventing Subscription reports it's ready to deliver events
+func SubscriptionReady(subscription *unstructured.Unstructured) bool {
+	ready, _, _ := unstructured.NestedBool(subscription.Object, "status", "ready")
+	return ready
+}
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewSubscription(t *testing.T) {
+	f := &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function-name",
+			Namespace: "test-function-namespace",
+			UID:       "test-uid",
+		},
+	}
+
+	t.Run("create proper Subscription", func(t *testing.T) {
+		s := serverlessv1alpha2.Subscription{
+			Name:         "orders",
+			Source:       "commerce",
+			Types:        []string{"order.created.v1", "order.updated.v1"},
+			TypeMatching: serverlessv1alpha2.SubscriptionTypeMatchingExact,
+			Config:       map[string]string{"maxInFlightMessages": "5"},
+		}
+
+		r := NewSubscription(f, s)
+
+		require.Equal(t, SubscriptionGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name-orders", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/resource":      "subscription",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, r.GetLabels())
+		require.Equal(t, map[string]interface{}{
+			"sink":         "http://test-function-name.test-function-namespace.svc.cluster.local",
+			"source":       "commerce",
+			"types":        []interface{}{"order.created.v1", "order.updated.v1"},
+			"typeMatching": "exact",
+			"config":       map[string]interface{}{"maxInFlightMessages": "5"},
+		}, r.Object["spec"])
+	})
+	t.Run("use standard type matching and skip empty config by default", func(t *testing.T) {
+		s := serverlessv1alpha2.Subscription{
+			Name:   "orders",
+			Source: "commerce",
+			Types:  []string{"order.created.v1"},
+		}
+
+		r := NewSubscription(f, s)
+
+		spec := r.Object["spec"].(map[string]interface{})
+		require.Equal(t, "standard", spec["typeMatching"])
+		require.NotContains(t, spec, "config")
+	})
+}
+
+func TestSubscriptionReady(t *testing.T) {
+	t.Run("ready when status says so", func(t *testing.T) {
+		s := &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"ready": true},
+		}}
+
+		require.True(t, SubscriptionReady(s))
+	})
+	t.Run("not ready without status", func(t *testing.T) {
+		s := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+		require.False(t, SubscriptionReady(s))
+	})
+}
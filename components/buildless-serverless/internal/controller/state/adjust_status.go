@@ -6,6 +6,7 @@ import (
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -13,9 +14,35 @@ import (
 func sFnAdjustStatus(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
 	s := &m.State.Function.Status
 	f := m.State.Function
-	s.Runtime = f.Spec.Runtime.SupportedRuntimeEquivalent()
+	s.Runtime = runWithRuntime(m)
 	s.RuntimeImage = m.State.BuiltDeployment.RuntimeImage()
-	s.Replicas = m.State.ClusterDeployment.Status.Replicas
+	s.RuntimeImageDigest = m.State.BuiltDeployment.RuntimeImageDigest()
+	s.RuntimeImageDigestResolvedAt = nil
+	if s.RuntimeImageDigest != "" {
+		s.RuntimeImageDigestResolvedAt = m.State.RuntimeImageDigestResolvedAt
+	}
+	// the `job` function has no Deployment
+	s.Replicas = 0
+	s.RollingUpdate = nil
+	if m.State.ClusterDeployment != nil {
+		s.Replicas = m.State.ClusterDeployment.Status.Replicas
+		if rollingUpdate := m.State.ClusterDeployment.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+			s.RollingUpdate = &serverlessv1alpha2.RollingUpdate{
+				MaxSurge:       rollingUpdate.MaxSurge,
+				MaxUnavailable: rollingUpdate.MaxUnavailable,
+			}
+		}
+	}
+
+	s.DisruptionBudget = nil
+	if budget := m.State.ClusterDisruptionBudget; budget != nil {
+		s.DisruptionBudget = &serverlessv1alpha2.DisruptionBudgetStatus{
+			Name:               budget.GetName(),
+			DisruptionsAllowed: budget.Status.DisruptionsAllowed,
+			CurrentHealthy:     budget.Status.CurrentHealthy,
+			DesiredHealthy:     budget.Status.DesiredHealthy,
+		}
+	}
 
 	// set scale sub-resource
 	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: f.SelectorLabels()})
@@ -47,5 +74,49 @@ func sFnAdjustStatus(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl
 		s.Commit = ""
 	}
 
+	if m.State.Function.HasConfigMapSources() {
+		s.ConfigMap = &serverlessv1alpha2.ConfigMapSourceStatus{
+			Name: f.Spec.Source.ConfigMap.Name,
+			Hash: m.State.SourceHash,
+		}
+	} else {
+		s.ConfigMap = nil
+	}
+
+	if m.State.Function.HasOCISources() {
+		s.OCI = &serverlessv1alpha2.OCISourceStatus{
+			Reference: f.Spec.Source.OCI.Reference,
+			Digest:    m.State.OCIDigest,
+		}
+	} else {
+		s.OCI = nil
+	}
+
+	if m.State.Function.HasArchiveSources() {
+		s.Archive = &serverlessv1alpha2.ArchiveSourceStatus{
+			URL:      f.Spec.Source.Archive.URL,
+			Revision: m.State.ArchiveRevision,
+			SHA256:   f.Spec.Source.Archive.SHA256,
+		}
+	} else {
+		s.Archive = nil
+	}
+
+	// staged runtime image upgrade is checked again sooner to start it when the rollout allows
+	if s.RuntimeImageUpgrade != nil {
+		return requeueAfter(m.FunctionConfig.RequeueDuration)
+	}
+
+	// Subscriptions and ScaledObjects aren't watched, so their readiness is checked again sooner
+	for _, conditionType := range []serverlessv1alpha2.ConditionType{
+		serverlessv1alpha2.ConditionSubscriptionsReady,
+		serverlessv1alpha2.ConditionScalingReady,
+	} {
+		condition := meta.FindStatusCondition(s.Conditions, string(conditionType))
+		if condition != nil && condition.Status != metav1.ConditionTrue {
+			return requeueAfter(m.FunctionConfig.RequeueDuration)
+		}
+	}
+
 	return requeueAfter(m.FunctionConfig.FunctionReadyRequeueDuration)
 }
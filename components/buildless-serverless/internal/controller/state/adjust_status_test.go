@@ -11,8 +11,11 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -250,4 +253,143 @@ func Test_sFnAdjustStatus(t *testing.T) {
 		require.Nil(t, next)
 		require.Equal(t, "frosty-aryabhata", m.State.Function.Status.FunctionResourceProfile)
 	})
+	t.Run("disruption budget and rolling update are reflected in status", func(t *testing.T) {
+		// Arrange
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "quirky-hopper"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: "brave-easley",
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "angry-newton"}}},
+			Status: serverlessv1alpha2.FunctionStatus{}}
+		fc := config.FunctionConfig{
+			FunctionReadyRequeueDuration: 3546,
+			ResourceConfig: config.ResourceConfig{
+				Function: config.FunctionResourceConfig{
+					Resources: config.Resources{
+						DefaultPreset: "zealous-grothendieck",
+						Presets: config.Preset{
+							"zealous-grothendieck": config.Resource{}}}}}}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:        f,
+				BuiltDeployment: resources.NewDeployment(&f, &fc, nil, "test-commit", nil, ""),
+				ClusterDeployment: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Strategy: appsv1.DeploymentStrategy{
+							RollingUpdate: &appsv1.RollingUpdateDeployment{
+								MaxSurge:       ptr.To(intstr.FromInt32(1)),
+								MaxUnavailable: ptr.To(intstr.FromInt32(0))}}}},
+				ClusterDisruptionBudget: &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "quirky-hopper"},
+					Status: policyv1.PodDisruptionBudgetStatus{
+						DisruptionsAllowed: 1,
+						CurrentHealthy:     3,
+						DesiredHealthy:     2}}},
+			FunctionConfig: fc,
+		}
+
+		// Act
+		_, _, err := sFnAdjustStatus(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &serverlessv1alpha2.RollingUpdate{
+			MaxSurge:       ptr.To(intstr.FromInt32(1)),
+			MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+		}, m.State.Function.Status.RollingUpdate)
+		require.Equal(t, &serverlessv1alpha2.DisruptionBudgetStatus{
+			Name:               "quirky-hopper",
+			DisruptionsAllowed: 1,
+			CurrentHealthy:     3,
+			DesiredHealthy:     2,
+		}, m.State.Function.Status.DisruptionBudget)
+	})
+	t.Run("requeue after short time when subscriptions aren't ready", func(t *testing.T) {
+		// Arrange
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "eager-lovelace"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: "brave-easley",
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "angry-newton"}}},
+			Status: serverlessv1alpha2.FunctionStatus{}}
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionsNotReady,
+			"Subscriptions not ready: orders")
+		fc := config.FunctionConfig{
+			RequeueDuration:              15,
+			FunctionReadyRequeueDuration: 3546,
+			ResourceConfig: config.ResourceConfig{
+				Function: config.FunctionResourceConfig{
+					Resources: config.Resources{
+						DefaultPreset: "zealous-grothendieck",
+						Presets: config.Preset{
+							"zealous-grothendieck": config.Resource{}}}}}}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:          f,
+				BuiltDeployment:   resources.NewDeployment(&f, &fc, nil, "test-commit", nil, ""),
+				ClusterDeployment: &appsv1.Deployment{}},
+			FunctionConfig: fc,
+		}
+
+		// Act
+		next, result, err := sFnAdjustStatus(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{RequeueAfter: 15}, *result)
+		require.Nil(t, next)
+	})
+	t.Run("requeue after short time when scaled object isn't ready", func(t *testing.T) {
+		// Arrange
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "eager-lovelace"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: "brave-easley",
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "angry-newton"}}},
+			Status: serverlessv1alpha2.FunctionStatus{}}
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScaledObjectNotReady,
+			"ScaledObject eager-lovelace is not ready yet")
+		fc := config.FunctionConfig{
+			RequeueDuration:              15,
+			FunctionReadyRequeueDuration: 3546,
+			ResourceConfig: config.ResourceConfig{
+				Function: config.FunctionResourceConfig{
+					Resources: config.Resources{
+						DefaultPreset: "zealous-grothendieck",
+						Presets: config.Preset{
+							"zealous-grothendieck": config.Resource{}}}}}}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:          f,
+				BuiltDeployment:   resources.NewDeployment(&f, &fc, nil, "test-commit", nil, ""),
+				ClusterDeployment: &appsv1.Deployment{}},
+			FunctionConfig: fc,
+		}
+
+		// Act
+		next, result, err := sFnAdjustStatus(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{RequeueAfter: 15}, *result)
+		require.Nil(t, next)
+	})
 }
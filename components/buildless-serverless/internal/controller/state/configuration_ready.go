@@ -3,9 +3,13 @@ package state
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/deprecation"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/validator"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,23 +18,60 @@ import (
 
 const (
 	configurationReadyMessage       = "Function configured"
-	warningNodejs20Deprecated       = "Warning: function configured, runtime Node.js 20 is deprecated and will be removed in the future"
 	warningUnsupportedRuntimeFormat = "Warning: invalid runtime value: cannot find runtime %s, using runtime %s as a fallback to migrate from legacy serverless"
+	warningDeprecatedRuntimeFormat  = "Warning: function configured, %s"
+	runtimeEndOfLifeFormat          = "Function can't be configured, %s"
 )
 
-func sFnConfigurationReady(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+func sFnConfigurationReady(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
 	condition := metav1.ConditionTrue
 	msg := configurationReadyMessage
 	reason := serverlessv1alpha2.ConditionReasonFunctionSpecValidated
 
-	if !m.State.Function.Spec.Runtime.IsRuntimeSupported() {
+	check := deprecation.Check(&m.State.Function, m.FunctionConfig.RuntimeLifecycle, time.Now())
+	m.State.RuntimeCheck = check
+
+	switch {
+	case check.Action == deprecation.ActionBlock:
+		// the function isn't changed until it's migrated, its current workload keeps running
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeEndOfLife,
+			fmt.Sprintf(runtimeEndOfLifeFormat, check.Message))
+		return stop()
+	case check.IsLegacy():
 		// warn users when runtime is not supported
-		msg = fmt.Sprintf(warningUnsupportedRuntimeFormat, m.State.Function.Spec.Runtime, m.State.Function.Spec.Runtime.SupportedRuntimeEquivalent())
+		msg = fmt.Sprintf(warningUnsupportedRuntimeFormat, check.Runtime, check.RunWith)
 		condition = metav1.ConditionFalse
 		reason = serverlessv1alpha2.ConditionReasonFunctionSpecRuntimeFallback
-	} else if m.State.Function.Spec.Runtime == serverlessv1alpha2.NodeJs20 {
+	case check.Action == deprecation.ActionUpgrade:
+		msg = fmt.Sprintf(warningDeprecatedRuntimeFormat, check.Message)
+		reason = serverlessv1alpha2.ConditionReasonRuntimeUpgraded
+	case check.Action == deprecation.ActionWarn:
 		// warn users when runtime is deprecated
-		msg = warningNodejs20Deprecated
+		msg = fmt.Sprintf(warningDeprecatedRuntimeFormat, check.Message)
+	case m.State.FunctionRuntime != nil && m.State.FunctionRuntime.Spec.Deprecated:
+		// warn users when runtime from the catalog is deprecated
+		msg = fmt.Sprintf(warningDeprecatedRuntimeFormat, deprecation.CatalogRuntimeMessage(m.State.FunctionRuntime))
+	}
+
+	// the upgraded function runs as described by the FunctionRuntime of the replacement runtime
+	if runWith := runWithRuntime(m); runWith != m.State.Function.Spec.Runtime.SupportedRuntimeEquivalent() {
+		functionRuntime, err := getFunctionRuntime(ctx, m, runWith)
+		if err != nil {
+			return stopWithError(err)
+		}
+		// the function is validated with the FunctionRuntime of its own runtime
+		if results := validator.New(&m.State.Function, m.FunctionConfig, functionRuntime).ValidateFunctionRuntimeImage(); len(results) != 0 {
+			m.State.Function.UpdateCondition(
+				serverlessv1alpha2.ConditionConfigurationReady,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonInvalidFunctionSpec,
+				strings.Join(results, ". "))
+			return stop()
+		}
+		m.State.FunctionRuntime = functionRuntime
 	}
 
 	m.State.Function.UpdateCondition(
@@ -40,5 +81,13 @@ func sFnConfigurationReady(_ context.Context, m *fsm.StateMachine) (fsm.StateFn,
 		msg)
 	metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionConfigurationReady)
 
-	return nextState(sFnHandleDeployment)
+	return nextState(sFnHandleInlineSources)
+}
+
+// runWithRuntime returns the runtime the function runs with, which differs from its spec when the runtime is upgraded
+func runWithRuntime(m *fsm.StateMachine) serverlessv1alpha2.Runtime {
+	if m.State.RuntimeCheck.RunWith != "" {
+		return m.State.RuntimeCheck.RunWith
+	}
+	return m.State.Function.Spec.Runtime.SupportedRuntimeEquivalent()
 }
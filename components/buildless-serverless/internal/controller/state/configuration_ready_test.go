@@ -3,11 +3,15 @@ package state
 import (
 	"context"
 	"testing"
+	"time"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_sFnConfigurationReady(t *testing.T) {
@@ -34,7 +38,7 @@ func Test_sFnConfigurationReady(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnHandleDeployment, next)
+		requireEqualFunc(t, sFnHandleInlineSources, next)
 		// function has proper condition
 		requireContainsCondition(t, m.State.Function.Status,
 			serverlessv1alpha2.ConditionConfigurationReady,
@@ -63,7 +67,7 @@ func Test_sFnConfigurationReady(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnHandleDeployment, next)
+		requireEqualFunc(t, sFnHandleInlineSources, next)
 		// function has proper condition
 		requireContainsCondition(t, m.State.Function.Status,
 			serverlessv1alpha2.ConditionConfigurationReady,
@@ -71,7 +75,7 @@ func Test_sFnConfigurationReady(t *testing.T) {
 			serverlessv1alpha2.ConditionReasonFunctionSpecRuntimeFallback,
 			"Warning: invalid runtime value: cannot find runtime nodejs14, using runtime nodejs20 as a fallback to migrate from legacy serverless")
 	})
-	t.Run("should set condition to false on old runtime and go to the next state", func(t *testing.T) {
+	t.Run("should warn about deprecated runtime and go to the next state", func(t *testing.T) {
 		// Arrange
 		// machine with our function
 		m := fsm.StateMachine{State: fsm.SystemState{
@@ -80,6 +84,50 @@ func Test_sFnConfigurationReady(t *testing.T) {
 					Runtime: serverlessv1alpha2.NodeJs20,
 				},
 			},
+		},
+			FunctionConfig: config.FunctionConfig{
+				RuntimeLifecycle: config.RuntimeLifecycle{
+					Policy:       config.RuntimePolicyWarn,
+					Deprecations: []config.RuntimeDeprecation{{Runtime: "nodejs20", ReplacedBy: "nodejs22"}},
+				},
+			}}
+
+		// Act
+		next, result, err := sFnConfigurationReady(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// without stopping processing
+		require.Nil(t, result)
+		// with expected next state
+		require.NotNil(t, next)
+		requireEqualFunc(t, sFnHandleInlineSources, next)
+		// function has proper condition
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonFunctionSpecValidated,
+			"Warning: function configured, runtime nodejs20 is deprecated, migrate the function to nodejs22")
+	})
+	t.Run("should warn about deprecated runtime from the catalog and go to the next state", func(t *testing.T) {
+		// Arrange
+		// machine with our function and its deprecated runtime
+		m := fsm.StateMachine{State: fsm.SystemState{
+			Function: serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs22,
+				},
+			},
+			FunctionRuntime: &serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nodejs22",
+				},
+				Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+					Deprecated:         true,
+					DeprecationMessage: "migrate the function to python312",
+				},
+			},
 		}}
 
 		// Act
@@ -92,12 +140,152 @@ func Test_sFnConfigurationReady(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnHandleDeployment, next)
+		requireEqualFunc(t, sFnHandleInlineSources, next)
 		// function has proper condition
 		requireContainsCondition(t, m.State.Function.Status,
 			serverlessv1alpha2.ConditionConfigurationReady,
 			metav1.ConditionTrue,
 			serverlessv1alpha2.ConditionReasonFunctionSpecValidated,
-			warningNodejs20Deprecated)
+			"Warning: function configured, runtime nodejs22 is deprecated: migrate the function to python312")
+	})
+	t.Run("should upgrade runtime after its end of life and go to the next state", func(t *testing.T) {
+		// Arrange
+		// scheme and fake client with the FunctionRuntime of the replacement runtime
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodejs22"},
+			Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "nodejs22-image"},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(functionRuntime).Build()
+		// machine with our function and the auto-upgrade policy
+		m := fsm.StateMachine{State: fsm.SystemState{
+			Function: serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs20,
+				},
+			},
+		},
+			Client: k8sClient,
+			FunctionConfig: config.FunctionConfig{
+				RuntimeLifecycle: config.RuntimeLifecycle{
+					Policy: config.RuntimePolicyAutoUpgrade,
+					Deprecations: []config.RuntimeDeprecation{{
+						Runtime:    "nodejs20",
+						EndOfLife:  config.Date{Time: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+						ReplacedBy: "nodejs22",
+					}},
+				},
+			}}
+
+		// Act
+		next, result, err := sFnConfigurationReady(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// without stopping processing
+		require.Nil(t, result)
+		// with expected next state
+		require.NotNil(t, next)
+		requireEqualFunc(t, sFnHandleInlineSources, next)
+		// function runs with the replacement runtime
+		require.Equal(t, serverlessv1alpha2.NodeJs22, runWithRuntime(&m))
+		// function runs as described by the FunctionRuntime of the replacement runtime
+		require.NotNil(t, m.State.FunctionRuntime)
+		require.Equal(t, "nodejs22-image", m.State.FunctionRuntime.Spec.Image)
+		// function has proper condition
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonRuntimeUpgraded,
+			"Warning: function configured, runtime nodejs20 is deprecated (end of life on 2026-04-30), upgraded to nodejs22")
+	})
+	t.Run("should stop when image of the replacement runtime isn't allowed", func(t *testing.T) {
+		// Arrange
+		// scheme and fake client with the FunctionRuntime of the replacement runtime
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodejs22"},
+			Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "docker.io/user/nodejs22:v1"},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(functionRuntime).Build()
+		// machine with our function, the auto-upgrade policy and the image policy
+		m := fsm.StateMachine{State: fsm.SystemState{
+			Function: serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs20,
+				},
+			},
+		},
+			Client: k8sClient,
+			FunctionConfig: config.FunctionConfig{
+				RuntimeLifecycle: config.RuntimeLifecycle{
+					Policy: config.RuntimePolicyAutoUpgrade,
+					Deprecations: []config.RuntimeDeprecation{{
+						Runtime:    "nodejs20",
+						EndOfLife:  config.Date{Time: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+						ReplacedBy: "nodejs22",
+					}},
+				},
+				ImagePolicy: config.ImagePolicy{
+					AllowedImages: []string{"europe-docker.pkg.dev/kyma-project/**"},
+				},
+			}}
+
+		// Act
+		next, result, err := sFnConfigurationReady(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// processing is stopped
+		require.Nil(t, result)
+		require.Nil(t, next)
+		// function has proper condition
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonInvalidFunctionSpec,
+			"invalid FunctionRuntime nodejs22 image: image docker.io/user/nodejs22 is not allowed by the image policy")
+	})
+	t.Run("should block runtime after its end of life and stop", func(t *testing.T) {
+		// Arrange
+		// machine with our function and the block policy
+		m := fsm.StateMachine{State: fsm.SystemState{
+			Function: serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs20,
+				},
+			},
+		},
+			FunctionConfig: config.FunctionConfig{
+				RuntimeLifecycle: config.RuntimeLifecycle{
+					Policy: config.RuntimePolicyBlock,
+					Deprecations: []config.RuntimeDeprecation{{
+						Runtime:    "nodejs20",
+						EndOfLife:  config.Date{Time: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)},
+						ReplacedBy: "nodejs22",
+					}},
+				},
+			}}
+
+		// Act
+		next, result, err := sFnConfigurationReady(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// no result because of stop
+		require.Nil(t, result)
+		// no next state (we will stop)
+		require.Nil(t, next)
+		// function has proper condition
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeEndOfLife,
+			"Function can't be configured, runtime nodejs20 is deprecated (end of life on 2026-04-30), migrate the function to another runtime, for example, nodejs22")
 	})
 }
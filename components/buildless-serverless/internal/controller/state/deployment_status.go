@@ -3,16 +3,20 @@ package state
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func sFnDeploymentStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
@@ -32,16 +36,23 @@ func sFnDeploymentStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 
 	// ready deployment
 	if isDeploymentReady(deployment) {
+		updateRuntimeImageUpgrade(m, true, false)
+
+		if isDeploymentRolledOut(deployment) {
+			if err := deleteStaleInlineSourcesConfigMaps(ctx, m); err != nil {
+				return stopWithError(errors.Wrap(err, "while deleting stale inline sources configmaps"))
+			}
+		}
 
 		// emit warning if runtime is legacy
-		if !m.State.Function.Spec.Runtime.IsRuntimeSupported() {
+		if runtime := m.State.Function.Spec.Runtime; runtime.IsRuntimeKnown() && !runtime.IsRuntimeSupported() {
 			m.Log.Info(fmt.Sprintf("deployment %s ready, using supported runtime", deploymentName))
 
 			m.State.Function.UpdateCondition(
 				serverlessv1alpha2.ConditionRunning,
 				metav1.ConditionTrue,
 				serverlessv1alpha2.ConditionReasonDeploymentReadyFallbackRuntime,
-				fmt.Sprintf("Warning: Deployment %s is ready, runtime %s too old, used %s as a fallback to migrate from legacy serverless", deploymentName, m.State.Function.Spec.Runtime, m.State.Function.Spec.Runtime.SupportedRuntimeEquivalent()))
+				fmt.Sprintf("Warning: Deployment %s is ready, runtime %s too old, used %s as a fallback to migrate from legacy serverless", deploymentName, m.State.Function.Spec.Runtime, runWithRuntime(m)))
 			metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionRunning)
 		} else {
 			m.Log.Info(fmt.Sprintf("deployment %s ready", deploymentName))
@@ -57,6 +68,28 @@ func sFnDeploymentStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 		return nextState(sFnAdjustStatus)
 	}
 
+	// typescript functions which can't be compiled are terminated before start
+	if m.State.Function.HasTypeScript() {
+		msg, err := compilationFailureMessage(ctx, m, deployment)
+		if err != nil {
+			return stopWithError(errors.Wrap(err, "while getting function pods"))
+		}
+		if msg != "" {
+			m.Log.Info(fmt.Sprintf("deployment %q sources compilation failed", deploymentName))
+
+			m.State.Function.UpdateCondition(
+				serverlessv1alpha2.ConditionRunning,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonCompilationFailed,
+				msg)
+
+			return stop()
+		}
+	}
+
+	// failed deployment doesn't progress anymore
+	updateRuntimeImageUpgrade(m, false, !hasDeploymentConditionTrueStatus(deployment.Status.Conditions, appsv1.DeploymentProgressing))
+
 	// unhealthy deployment
 	if hasDeploymentConditionFalseStatusWithReason(deployment.Status.Conditions, appsv1.DeploymentAvailable, MinimumReplicasUnavailable) {
 		m.Log.Info(fmt.Sprintf("deployment unhealthy: %q", deploymentName))
@@ -100,6 +133,35 @@ func sFnDeploymentStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 	return stop()
 }
 
+// compilationFailureMessage returns termination message of the function container which failed to compile sources
+// pods created for previous sources are skipped as they don't describe the current state
+func compilationFailureMessage(ctx context.Context, m *fsm.StateMachine, deployment appsv1.Deployment) (string, error) {
+	pods := &corev1.PodList{}
+	f := m.State.Function
+	err := m.Client.List(ctx, pods, client.InNamespace(f.GetNamespace()), client.MatchingLabels(f.SelectorLabels()))
+	if err != nil {
+		return "", err
+	}
+
+	sourceHash := deployment.Spec.Template.GetAnnotations()[resources.SourceHashAnnotationKey]
+	for _, pod := range pods.Items {
+		if pod.GetAnnotations()[resources.SourceHashAnnotationKey] != sourceHash {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != "function" {
+				continue
+			}
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && strings.HasPrefix(terminated.Message, resources.TypeScriptCompilationFailedMessage) {
+					return strings.TrimSpace(terminated.Message), nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
 const (
 	// Progressing:
 	// NewRSAvailableReason is added in a deployment when its newest replica set is made available
@@ -119,6 +181,16 @@ func isDeploymentReady(deployment appsv1.Deployment) bool {
 		hasDeploymentConditionTrueStatusWithReason(conditions, appsv1.DeploymentProgressing, NewRSAvailableReason)
 }
 
+// isDeploymentRolledOut checks if the current spec of the deployment is observed and Pods of previous ReplicaSets are gone
+func isDeploymentRolledOut(deployment appsv1.Deployment) bool {
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.GetGeneration() &&
+		status.UpdatedReplicas == replicas &&
+		status.AvailableReplicas == replicas &&
+		status.Replicas == replicas
+}
+
 func hasDeploymentConditionTrueStatusWithReason(conditions []appsv1.DeploymentCondition, conditionType appsv1.DeploymentConditionType, reason string) bool {
 	for _, condition := range conditions {
 		if condition.Type == conditionType {
@@ -15,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -80,6 +81,80 @@ func Test_sFnDeploymentStatus(t *testing.T) {
 		// observed generation is set to the function generation
 		require.Equal(t, int64(22), m.State.Function.Status.ObservedGeneration)
 	})
+	t.Run("when deployment is rolled out should remove stale inline sources configmaps", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		current := resources.NewInlineSourcesConfigMap(&f)
+		stale := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-inline-0123456789",
+				Namespace: "test-namespace",
+				Labels:    resources.InlineSourcesConfigMapLabels(&f)}}
+		newDeployment := func(generation int64, status appsv1.DeploymentStatus) *appsv1.Deployment {
+			status.Conditions = []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: MinimumReplicasAvailable},
+				{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: NewRSAvailableReason}}
+			return &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-function",
+					Namespace:  "test-namespace",
+					Generation: generation,
+					Labels:     f.InternalFunctionLabels()},
+				Spec:   appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+				Status: status}
+		}
+		tests := []struct {
+			name       string
+			deployment *appsv1.Deployment
+			want       []string
+		}{
+			{
+				name:       "rolled out",
+				deployment: newDeployment(3, appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}),
+				want:       []string{current.GetName()},
+			},
+			{
+				name:       "new generation not observed",
+				deployment: newDeployment(4, appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}),
+				want:       []string{current.GetName(), stale.GetName()},
+			},
+			{
+				name:       "pods of previous replicaset still running",
+				deployment: newDeployment(3, appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 3}),
+				want:       []string{current.GetName(), stale.GetName()},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				scheme := minimalInlineScheme(t)
+				require.NoError(t, appsv1.AddToScheme(scheme))
+				k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.deployment, current.DeepCopy(), stale.DeepCopy()).Build()
+				m := fsm.StateMachine{
+					State: fsm.SystemState{
+						BuiltDeployment:        &resources.Deployment{Deployment: &appsv1.Deployment{}},
+						Function:               f,
+						InlineSourcesConfigMap: current.GetName()},
+					Log:    zap.NewNop().Sugar(),
+					Client: k8sClient,
+					Scheme: scheme}
+
+				// Act
+				next, result, err := sFnDeploymentStatus(context.Background(), &m)
+
+				// Assert
+				require.Nil(t, err)
+				require.Nil(t, result)
+				requireEqualFunc(t, sFnAdjustStatus, next)
+				configMaps := &corev1.ConfigMapList{}
+				require.NoError(t, k8sClient.List(context.Background(), configMaps))
+				names := []string{}
+				for _, cm := range configMaps.Items {
+					names = append(names, cm.GetName())
+				}
+				require.ElementsMatch(t, tt.want, names)
+			})
+		}
+	})
 	t.Run("when deployment is ready with legacy runtimeshould go to the next state", func(t *testing.T) {
 		// Arrange
 		// our function
@@ -192,6 +267,134 @@ func Test_sFnDeploymentStatus(t *testing.T) {
 			serverlessv1alpha2.ConditionReasonMinReplicasNotAvailable,
 			"Minimum replicas not available for deployment peaceful-rhodes-name")
 	})
+	t.Run("when typescript function can't be compiled should stop processing", func(t *testing.T) {
+		// Arrange
+		// our function
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quirky-ritchie-name",
+				Namespace: "vigorous-hopper-ns"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:  serverlessv1alpha2.NodeJs22,
+				Language: serverlessv1alpha2.TypeScript}}
+		// deployment which will be returned from kubernetes
+		deployment := appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quirky-ritchie-name",
+				Namespace: "vigorous-hopper-ns",
+				Labels:    f.InternalFunctionLabels()},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{resources.SourceHashAnnotationKey: "current-hash"}}}},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, Reason: MinimumReplicasUnavailable}}}}
+		// pods of the current and previous sources
+		currentPod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "quirky-ritchie-current",
+				Namespace:   "vigorous-hopper-ns",
+				Labels:      f.SelectorLabels(),
+				Annotations: map[string]string{resources.SourceHashAnnotationKey: "current-hash"}},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "function",
+						LastTerminationState: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 1,
+								Message:  "TypeScript compilation failed:\nhandler.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.\n"}}}}}}
+		previousPod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "quirky-ritchie-previous",
+				Namespace:   "vigorous-hopper-ns",
+				Labels:      f.SelectorLabels(),
+				Annotations: map[string]string{resources.SourceHashAnnotationKey: "previous-hash"}},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name: "function",
+						State: corev1.ContainerState{
+							Terminated: &corev1.ContainerStateTerminated{
+								ExitCode: 1,
+								Message:  "TypeScript compilation failed:\nprevious error"}}}}}}
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, appsv1.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&deployment, &currentPod, &previousPod).Build()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnDeploymentStatus(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// we expect stop without requeue
+		require.Nil(t, result)
+		require.Nil(t, next)
+		// function has proper condition
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonCompilationFailed,
+			"TypeScript compilation failed:\nhandler.ts(1,7): error TS2322: Type 'string' is not assignable to type 'number'.")
+	})
+	t.Run("when typescript function pods are not terminated should requeue", func(t *testing.T) {
+		// Arrange
+		// our function
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "silly-lamport-name",
+				Namespace: "vigorous-hopper-ns"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:  serverlessv1alpha2.NodeJs22,
+				Language: serverlessv1alpha2.TypeScript}}
+		// deployment which will be returned from kubernetes
+		deployment := appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "silly-lamport-name",
+				Namespace: "vigorous-hopper-ns",
+				Labels:    f.InternalFunctionLabels()},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, Reason: MinimumReplicasUnavailable}}}}
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, appsv1.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&deployment).Build()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnDeploymentStatus(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonMinReplicasNotAvailable,
+			"Minimum replicas not available for deployment silly-lamport-name")
+	})
 	t.Run("when deployment is not ready should requeue", func(t *testing.T) {
 		// Arrange
 		// our function
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const archiveVerifyTimeout = 60 * time.Second
+
+// downloadArchive is a variable to allow replacing archive downloads in tests
+var downloadArchive = archive.Download
+
+func sFnHandleArchiveSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if !m.State.Function.HasArchiveSources() {
+		return nextState(sFnConfigurationReady)
+	}
+
+	archiveSource := m.State.Function.Spec.Source.Archive
+
+	if m.State.Function.HasArchiveAuth() {
+		archiveAuth, err := archive.NewAuth(ctx, m.Client, &m.State.Function)
+		if err != nil {
+			m.State.Function.UpdateCondition(
+				serverlessv1alpha2.ConditionConfigurationReady,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+				fmt.Sprintf("Getting archive authorization data failed: %s", err.Error()))
+			return stopWithError(err)
+		}
+		m.State.ArchiveAuth = archiveAuth
+	}
+
+	orderID := string(m.State.Function.GetUID())
+	m.ArchiveChecker.PlaceOrder(orderID, archiveSource.URL, m.State.ArchiveAuth)
+
+	result := m.ArchiveChecker.CollectOrder(orderID)
+	if result == nil {
+		// Revision check is still in progress, requeue the reconciliation
+		return requeueAfter(250 * time.Millisecond)
+	}
+
+	if result.Error != nil {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			fmt.Sprintf("Archive: %s source check failed: %s", archiveSource.URL, result.Error.Error()))
+		return stopWithError(result.Error)
+	}
+
+	status := m.State.Function.Status.Archive
+	changed := status == nil || status.URL != archiveSource.URL || status.Revision != result.Revision
+	// pods verify the checksum when they download the archive, so a new revision is verified before it's rolled out
+	// instead of letting the pods crash-loop on the mismatched archive
+	if archiveSource.SHA256 != "" && (changed || status.SHA256 != archiveSource.SHA256) {
+		if err := verifyArchive(ctx, m, archiveSource); err != nil {
+			m.State.Function.UpdateCondition(
+				serverlessv1alpha2.ConditionConfigurationReady,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+				fmt.Sprintf("Archive: %s revision %s verification failed: %s", archiveSource.URL, result.Revision, err.Error()))
+			return stopWithError(err)
+		}
+	}
+
+	if changed {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSourceUpdated,
+			"Function source updated")
+	}
+
+	m.State.ArchiveRevision = result.Revision
+
+	return nextState(sFnConfigurationReady)
+}
+
+func verifyArchive(ctx context.Context, m *fsm.StateMachine, archiveSource *serverlessv1alpha2.ArchiveSource) error {
+	downloadCtx, cancel := context.WithTimeout(ctx, archiveVerifyTimeout)
+	defer cancel()
+
+	_, err := downloadArchive(downloadCtx, archiveSource.URL, m.State.ArchiveAuth, archiveSource.SHA256)
+	return err
+}
@@ -0,0 +1,312 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/archive/automock"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sFnHandleArchiveSources(t *testing.T) {
+	t.Run("for non archive function move to the nextState", func(t *testing.T) {
+		// Arrange
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							Inline: &serverlessv1alpha2.InlineSource{
+								Source: "source"}}}}},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Empty(t, m.State.ArchiveRevision)
+	})
+	t.Run("for archive function requeue when revision check is in progress", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.Anything).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(nil)
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: minimalArchiveFunction()},
+			Log:            zap.NewNop().Sugar(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, next)
+		require.Equal(t, &ctrl.Result{RequeueAfter: 250 * time.Millisecond}, result)
+		require.Empty(t, m.State.ArchiveRevision)
+	})
+	t.Run("for archive function set revision and move to the nextState", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.Anything).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(&archive.OrderResult{
+			Revision: `"test-etag"`,
+		})
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: minimalArchiveFunction()},
+			Log:            zap.NewNop().Sugar(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSourceUpdated,
+			"Function source updated")
+		require.Equal(t, `"test-etag"`, m.State.ArchiveRevision)
+	})
+	t.Run("for archive function with auth read credentials", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.MatchedBy(func(a *archive.Auth) bool {
+			return a != nil
+		})).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(&archive.OrderResult{
+			Revision: `"test-etag"`,
+		})
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "archive-credentials",
+				Namespace: "default"},
+			Type: corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("pass")},
+		}
+		f := minimalArchiveFunction()
+		f.Spec.Source.Archive.Auth = &serverlessv1alpha2.ArchiveAuth{SecretName: "archive-credentials"}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         fake.NewClientBuilder().WithObjects(secret).Build(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.NotNil(t, m.State.ArchiveAuth)
+		archiveMock.AssertExpectations(t)
+	})
+	t.Run("when auth secret does not exist stop with condition", func(t *testing.T) {
+		// Arrange
+		f := minimalArchiveFunction()
+		f.Spec.Source.Archive.Auth = &serverlessv1alpha2.ArchiveAuth{SecretName: "archive-credentials"}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.NotNil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"Getting archive authorization data failed: failed to get secret: secrets \"archive-credentials\" not found")
+	})
+	t.Run("when revision check fails stop with condition", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.Anything).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(&archive.OrderResult{
+			Error: errors.New("test-error"),
+		})
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: minimalArchiveFunction()},
+			Log:            zap.NewNop().Sugar(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.ErrorContains(t, err, "test-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"Archive: https://artifacts.local/function.tar.gz source check failed: test-error")
+	})
+	t.Run("for archive function with sha256 verify new revision before moving to the nextState", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.Anything).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(&archive.OrderResult{
+			Revision: `"test-etag"`,
+		})
+		downloaded := false
+		stubDownloadArchive(t, func(_ context.Context, url string, _ *archive.Auth, sha256 string) ([]byte, error) {
+			require.Equal(t, "https://artifacts.local/function.tar.gz", url)
+			require.Equal(t, testArchiveSHA256, sha256)
+			downloaded = true
+			return []byte("archive"), nil
+		})
+		f := minimalArchiveFunction()
+		f.Spec.Source.Archive.SHA256 = testArchiveSHA256
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.True(t, downloaded)
+		require.Equal(t, `"test-etag"`, m.State.ArchiveRevision)
+	})
+	t.Run("for archive function with sha256 skip verification of already verified revision", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.Anything).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(&archive.OrderResult{
+			Revision: `"test-etag"`,
+		})
+		stubDownloadArchive(t, func(context.Context, string, *archive.Auth, string) ([]byte, error) {
+			require.Fail(t, "verified archive should not be downloaded again")
+			return nil, nil
+		})
+		f := minimalArchiveFunction()
+		f.Spec.Source.Archive.SHA256 = testArchiveSHA256
+		f.Status.Archive = &serverlessv1alpha2.ArchiveSourceStatus{
+			URL:      "https://artifacts.local/function.tar.gz",
+			Revision: `"test-etag"`,
+			SHA256:   testArchiveSHA256,
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.Equal(t, `"test-etag"`, m.State.ArchiveRevision)
+	})
+	t.Run("when new revision doesn't match sha256 stop with condition", func(t *testing.T) {
+		// Arrange
+		archiveMock := new(automock.AsyncLatestRevisionChecker)
+		archiveMock.On("PlaceOrder", "any-UID", "https://artifacts.local/function.tar.gz", mock.Anything).Return()
+		archiveMock.On("CollectOrder", "any-UID").Return(&archive.OrderResult{
+			Revision: `"new-etag"`,
+		})
+		stubDownloadArchive(t, func(context.Context, string, *archive.Auth, string) ([]byte, error) {
+			return nil, errors.New("checksum mismatch")
+		})
+		f := minimalArchiveFunction()
+		f.Spec.Source.Archive.SHA256 = testArchiveSHA256
+		f.Status.Archive = &serverlessv1alpha2.ArchiveSourceStatus{
+			URL:      "https://artifacts.local/function.tar.gz",
+			Revision: `"test-etag"`,
+			SHA256:   testArchiveSHA256,
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			ArchiveChecker: archiveMock,
+		}
+
+		// Act
+		next, result, err := sFnHandleArchiveSources(context.Background(), &m)
+
+		// Assert
+		require.ErrorContains(t, err, "checksum mismatch")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		require.Empty(t, m.State.ArchiveRevision)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"Archive: https://artifacts.local/function.tar.gz revision \"new-etag\" verification failed: checksum mismatch")
+	})
+}
+
+const testArchiveSHA256 = "9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+
+func minimalArchiveFunction() serverlessv1alpha2.Function {
+	return serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function",
+			Namespace: "default",
+			UID:       "any-UID"},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Runtime: serverlessv1alpha2.NodeJs22,
+			Source: serverlessv1alpha2.Source{
+				Archive: &serverlessv1alpha2.ArchiveSource{
+					URL: "https://artifacts.local/function.tar.gz"}}}}
+}
+
+func stubDownloadArchive(t *testing.T, fn func(context.Context, string, *archive.Auth, string) ([]byte, error)) {
+	original := downloadArchive
+	downloadArchive = fn
+	t.Cleanup(func() {
+		downloadArchive = original
+	})
+}
@@ -0,0 +1,184 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleAuthPolicy keeps the Istio RequestAuthentication and AuthorizationPolicy restricting calls to the function in sync with its auth
+func sFnHandleAuthPolicy(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	_, err := m.Client.RESTMapper().RESTMapping(resources.AuthorizationPolicyGVK.GroupKind(), resources.AuthorizationPolicyGVK.Version)
+	if meta.IsNoMatchError(err) {
+		if f.Spec.Auth == nil {
+			f.Status.Auth = nil
+			return nextState(sFnHandleSBOM)
+		}
+		// calls can't be restricted without Istio, so the function isn't considered running
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+			"Istio security CRDs are not installed")
+		return stop()
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to check if Istio security CRDs are installed")
+		return stopWithError(err)
+	}
+
+	if f.Spec.Auth == nil {
+		for _, gvk := range []schema.GroupVersionKind{resources.RequestAuthenticationGVK, resources.AuthorizationPolicyGVK} {
+			if err := deleteAuthPolicyObject(ctx, m, gvk); err != nil {
+				return stopWithError(err)
+			}
+		}
+		f.Status.Auth = nil
+		return nextState(sFnHandleSBOM)
+	}
+
+	status := resources.AuthStatus(f, &m.FunctionConfig)
+	builtObjects := []*unstructured.Unstructured{}
+	if f.Spec.Auth.JWT != nil {
+		builtObjects = append(builtObjects, resources.NewRequestAuthentication(f))
+	} else if err := deleteAuthPolicyObject(ctx, m, resources.RequestAuthenticationGVK); err != nil {
+		return stopWithError(err)
+	}
+	builtObjects = append(builtObjects, resources.NewAuthorizationPolicy(f, status))
+
+	for _, builtObject := range builtObjects {
+		clusterObject, err := getAuthPolicyObject(ctx, m, builtObject.GroupVersionKind())
+		if err != nil {
+			return stopWithError(err)
+		}
+		if clusterObject == nil {
+			result, errCreate := createAuthPolicyObject(ctx, m, builtObject)
+			return nil, result, errCreate
+		}
+		if !metav1.IsControlledBy(clusterObject, f) {
+			f.UpdateCondition(
+				serverlessv1alpha2.ConditionRunning,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+				fmt.Sprintf("%s %s already exists and isn't owned by the function", clusterObject.GetKind(), clusterObject.GetName()))
+			return stop()
+		}
+
+		requeueNeeded, errUpdate := updateAuthPolicyObjectIfNeeded(ctx, m, clusterObject, builtObject)
+		if errUpdate != nil {
+			return stopWithError(errUpdate)
+		}
+		if requeueNeeded {
+			return requeue()
+		}
+	}
+
+	f.Status.Auth = status
+	return nextState(sFnHandleSBOM)
+}
+
+func getAuthPolicyObject(ctx context.Context, m *fsm.StateMachine, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	f := m.State.Function
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	err := m.Client.Get(ctx, client.ObjectKey{Namespace: f.GetNamespace(), Name: f.GetName()}, object)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch Istio policy for Function", "Kind", gvk.Kind)
+		return nil, err
+	}
+	return object, nil
+}
+
+func createAuthPolicyObject(ctx context.Context, m *fsm.StateMachine, object *unstructured.Unstructured) (*ctrl.Result, error) {
+	kind := object.GetKind()
+	m.Log.Info("creating a new Istio policy", "Kind", kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+
+	// Set the ownerRef for the policy, ensuring that the policy
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, object, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new Istio policy", "Kind", kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+			fmt.Sprintf("%s %s create failed: %s", kind, object.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, object); err != nil {
+		m.Log.Error(err, "failed to create new Istio policy", "Kind", kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+			fmt.Sprintf("%s %s create failed: %s", kind, object.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonAuthPolicyCreated,
+		fmt.Sprintf("%s %s created", kind, object.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func updateAuthPolicyObjectIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterObject, builtObject *unstructured.Unstructured) (requeueNeeded bool, err error) {
+	if !exposeObjectChanged(clusterObject, builtObject) {
+		return false, nil
+	}
+
+	kind := clusterObject.GetKind()
+	m.Log.Info("updating Istio policy", "Kind", kind, "Namespace", clusterObject.GetNamespace(), "Name", clusterObject.GetName())
+	clusterObject.Object["spec"] = builtObject.Object["spec"]
+	clusterObject.SetLabels(builtObject.GetLabels())
+	if err := m.Client.Update(ctx, clusterObject); err != nil {
+		m.Log.Error(err, "failed to update Istio policy", "Kind", kind, "Namespace", clusterObject.GetNamespace(), "Name", clusterObject.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+			fmt.Sprintf("%s %s update failed: %s", kind, clusterObject.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonAuthPolicyUpdated,
+		fmt.Sprintf("%s %s updated", kind, clusterObject.GetName()))
+	return true, nil
+}
+
+// deleteAuthPolicyObject removes the Istio policy of the given kind when it's owned by the function
+func deleteAuthPolicyObject(ctx context.Context, m *fsm.StateMachine, gvk schema.GroupVersionKind) error {
+	object, err := getAuthPolicyObject(ctx, m, gvk)
+	if err != nil {
+		return err
+	}
+	if object == nil || !metav1.IsControlledBy(object, &m.State.Function) {
+		return nil
+	}
+	m.Log.Info("deleting Istio policy", "Kind", gvk.Kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+	err = m.Client.Delete(ctx, object)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete Istio policy", "Kind", gvk.Kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+		return err
+	}
+	return nil
+}
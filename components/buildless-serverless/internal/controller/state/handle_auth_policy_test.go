@@ -0,0 +1,243 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleAuthPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+
+	authFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Auth: &serverlessv1alpha2.Auth{
+					JWT: &serverlessv1alpha2.ExposeJWT{
+						Issuer:  "https://issuer.example.com",
+						JWKSURI: "https://issuer.example.com/jwks",
+					},
+				},
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, crdInstalled bool, objs ...client.Object) *fsm.StateMachine {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		if crdInstalled {
+			mapper.Add(resources.RequestAuthenticationGVK, meta.RESTScopeNamespace)
+			mapper.Add(resources.AuthorizationPolicyGVK, meta.RESTScopeNamespace)
+		}
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+	owned := func(t *testing.T, f serverlessv1alpha2.Function, object *unstructured.Unstructured) *unstructured.Unstructured {
+		require.NoError(t, controllerutil.SetControllerReference(&f, object, scheme))
+		return object
+	}
+	ownedPolicies := func(t *testing.T, f serverlessv1alpha2.Function) []client.Object {
+		return []client.Object{
+			owned(t, f, resources.NewRequestAuthentication(&f)),
+			owned(t, f, resources.NewAuthorizationPolicy(&f, resources.AuthStatus(&f, &config.FunctionConfig{}))),
+		}
+	}
+	getObject := func(m *fsm.StateMachine, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(gvk)
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function"}, object)
+		return object, err
+	}
+
+	t.Run("skip function without auth when Istio isn't installed", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		f.Spec.Auth = nil
+		m := newMachine(f, false)
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSBOM, next)
+		require.Nil(t, m.State.Function.Status.Auth)
+	})
+	t.Run("stop when Istio isn't installed", func(t *testing.T) {
+		// Arrange
+		m := newMachine(authFunction(), false)
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+			"Istio security CRDs are not installed")
+	})
+	t.Run("create RequestAuthentication when it doesn't exist", func(t *testing.T) {
+		// Arrange
+		m := newMachine(authFunction(), true)
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		requestAuthentication, err := getObject(m, resources.RequestAuthenticationGVK)
+		require.NoError(t, err)
+		require.Equal(t, "test-function", requestAuthentication.GetOwnerReferences()[0].Name)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonAuthPolicyCreated,
+			"RequestAuthentication test-function created")
+	})
+	t.Run("create AuthorizationPolicy when RequestAuthentication is up-to-date", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		m := newMachine(f, true, owned(t, f, resources.NewRequestAuthentication(&f)))
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		_, err = getObject(m, resources.AuthorizationPolicyGVK)
+		require.NoError(t, err)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonAuthPolicyCreated,
+			"AuthorizationPolicy test-function created")
+	})
+	t.Run("keep up-to-date policies and report effective policy", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		m := newMachine(f, true, ownedPolicies(t, f)...)
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSBOM, next)
+		require.Equal(t, &serverlessv1alpha2.AuthStatus{
+			RequestAuthentication: "test-function",
+			AuthorizationPolicy:   "test-function",
+			RequestPrincipals:     []string{"https://issuer.example.com/*"},
+		}, m.State.Function.Status.Auth)
+	})
+	t.Run("update changed AuthorizationPolicy", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		objs := ownedPolicies(t, f)
+		f.Spec.Auth.Namespaces = []string{"default"}
+		m := newMachine(f, true, objs...)
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		policy, err := getObject(m, resources.AuthorizationPolicyGVK)
+		require.NoError(t, err)
+		rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", "rules")
+		require.Len(t, rules, 2)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonAuthPolicyUpdated,
+			"AuthorizationPolicy test-function updated")
+	})
+	t.Run("don't take over RequestAuthentication owned by someone else", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		m := newMachine(f, true, resources.NewRequestAuthentication(&f))
+
+		// Act
+		next, result, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonAuthPolicyFailed,
+			"RequestAuthentication test-function already exists and isn't owned by the function")
+	})
+	t.Run("remove RequestAuthentication when JWT is removed", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		objs := ownedPolicies(t, f)
+		f.Spec.Auth = &serverlessv1alpha2.Auth{Namespaces: []string{"default"}}
+		m := newMachine(f, true, objs...)
+
+		// Act
+		_, _, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		_, err = getObject(m, resources.RequestAuthenticationGVK)
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+	t.Run("remove policies when auth is removed", func(t *testing.T) {
+		// Arrange
+		f := authFunction()
+		objs := ownedPolicies(t, f)
+		f.Spec.Auth = nil
+		f.Status.Auth = &serverlessv1alpha2.AuthStatus{AuthorizationPolicy: "test-function"}
+		m := newMachine(f, true, objs...)
+
+		// Act
+		next, _, err := sFnHandleAuthPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleSBOM, next)
+		require.Nil(t, m.State.Function.Status.Auth)
+		_, err = getObject(m, resources.RequestAuthenticationGVK)
+		require.True(t, k8serrors.IsNotFound(err))
+		_, err = getObject(m, resources.AuthorizationPolicyGVK)
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+}
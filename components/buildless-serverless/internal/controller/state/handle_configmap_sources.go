@@ -0,0 +1,55 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnHandleConfigMapSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if !m.State.Function.HasConfigMapSources() {
+		return nextState(sFnHandleOCISources)
+	}
+
+	cmName := m.State.Function.Spec.Source.ConfigMap.Name
+	cm := &corev1.ConfigMap{}
+	err := m.Client.Get(ctx, types.NamespacedName{Namespace: m.State.Function.GetNamespace(), Name: cmName}, cm)
+	if err != nil {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			fmt.Sprintf("ConfigMap %s source check failed: %s", cmName, err.Error()))
+		return stopWithError(err)
+	}
+
+	if len(cm.Data) == 0 && len(cm.BinaryData) == 0 {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			fmt.Sprintf("ConfigMap %s contains no source files", cmName))
+		return stop()
+	}
+
+	hash := resources.ConfigMapHash(cm)
+	status := m.State.Function.Status.ConfigMap
+	if status == nil || status.Name != cmName || status.Hash != hash {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSourceUpdated,
+			"Function source updated")
+	}
+
+	m.State.SourceHash = hash
+
+	return nextState(sFnConfigurationReady)
+}
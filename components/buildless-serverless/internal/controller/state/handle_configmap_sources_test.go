@@ -0,0 +1,183 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sFnHandleConfigMapSources(t *testing.T) {
+	t.Run("for non configmap function move to the nextState", func(t *testing.T) {
+		// Arrange
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							Inline: &serverlessv1alpha2.InlineSource{
+								Source: "source"}}}}},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleConfigMapSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleOCISources, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Empty(t, m.State.SourceHash)
+	})
+	t.Run("for configmap function calculate hash and move to the nextState", func(t *testing.T) {
+		// Arrange
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "function-sources",
+				Namespace: "default"},
+			Data: map[string]string{
+				"handler.js":   "module.exports = {}",
+				"package.json": "{}"},
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+								Name: "function-sources"}}}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithObjects(cm).Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleConfigMapSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSourceUpdated,
+			"Function source updated")
+		require.Equal(t, resources.ConfigMapHash(cm), m.State.SourceHash)
+	})
+	t.Run("for unchanged configmap do not update condition", func(t *testing.T) {
+		// Arrange
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "function-sources",
+				Namespace: "default"},
+			Data: map[string]string{
+				"handler.js": "module.exports = {}"},
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+								Name: "function-sources"}}},
+					Status: serverlessv1alpha2.FunctionStatus{
+						ConfigMap: &serverlessv1alpha2.ConfigMapSourceStatus{
+							Name: "function-sources",
+							Hash: resources.ConfigMapHash(cm)}}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithObjects(cm).Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleConfigMapSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Equal(t, resources.ConfigMapHash(cm), m.State.SourceHash)
+	})
+	t.Run("when configmap does not exist stop with condition", func(t *testing.T) {
+		// Arrange
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+								Name: "function-sources"}}}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleConfigMapSources(context.Background(), &m)
+
+		// Assert
+		require.NotNil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"ConfigMap function-sources source check failed: configmaps \"function-sources\" not found")
+	})
+	t.Run("when configmap is empty stop with condition", func(t *testing.T) {
+		// Arrange
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "function-sources",
+				Namespace: "default"},
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+								Name: "function-sources"}}}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithObjects(cm).Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleConfigMapSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"ConfigMap function-sources contains no source files")
+	})
+}
@@ -33,10 +33,22 @@ func sFnHandleDeployment(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 	}
 	m.State.ClusterDeployment = clusterDeployment
 
-	m.State.BuiltDeployment = resources.NewDeployment(&m.State.Function, &m.FunctionConfig, clusterDeployment, m.State.Commit, m.State.GitAuth, "")
+	m.State.BuiltDeployment = resources.NewDeployment(&m.State.Function, &m.FunctionConfig, clusterDeployment, m.State.Commit, m.State.GitAuth, "",
+		resources.DeploySetRuntime(runWithRuntime(m)),
+		resources.DeploySetFunctionRuntime(m.State.FunctionRuntime),
+		resources.DeploySetSourceHash(m.State.SourceHash),
+		resources.DeploySetOCIDigest(m.State.OCIDigest),
+		resources.DeploySetArchive(m.State.ArchiveRevision, m.State.ArchiveAuth),
+		resources.DeploySetInlineSourcesConfigMap(m.State.InlineSourcesConfigMap))
+	pinRuntimeImage(ctx, m)
+	stageRuntimeImageUpgrade(m, clusterDeployment)
+	verifyRuntimeImage(ctx, m)
 	builtDeployment := m.State.BuiltDeployment.Deployment
 
 	if m.State.ClusterDeployment == nil {
+		if err := requireVerifiedRuntimeImage(m); err != nil {
+			return stopWithError(err)
+		}
 		result, errCreate := createDeployment(ctx, m, builtDeployment)
 		if errCreate == nil {
 			m.State.Function.CopyAnnotationsToStatus()
@@ -109,10 +121,14 @@ func updateDeploymentIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterD
 	if !deploymentChanged(clusterDeployment, builtDeployment) {
 		return false, nil
 	}
+	if err := requireVerifiedRuntimeImage(m); err != nil {
+		return false, err
+	}
 
 	//TODO: think if it's better to update only some fields
 	clusterDeployment.Spec.Template = builtDeployment.Spec.Template
 	clusterDeployment.Spec.Replicas = builtDeployment.Spec.Replicas
+	clusterDeployment.Spec.Strategy = builtDeployment.Spec.Strategy
 	return updateDeployment(ctx, m, clusterDeployment)
 }
 
@@ -131,6 +147,7 @@ func deploymentChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
 	replicasChanged := (a.Spec.Replicas == nil && b.Spec.Replicas != nil) ||
 		(a.Spec.Replicas != nil && b.Spec.Replicas == nil) ||
 		(a.Spec.Replicas != nil && b.Spec.Replicas != nil && *a.Spec.Replicas != *b.Spec.Replicas)
+	strategyChanged := !reflect.DeepEqual(a.Spec.Strategy, b.Spec.Strategy)
 	workingDirChanged := !reflect.DeepEqual(aContainer.WorkingDir, bContainer.WorkingDir)
 	commandChanged := !reflect.DeepEqual(aContainer.Command, bContainer.Command)
 	resourcesChanged := !equalResources(aContainer.Resources, bContainer.Resources)
@@ -139,11 +156,13 @@ func deploymentChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
 	portsChanged := !reflect.DeepEqual(aContainer.Ports, bContainer.Ports)
 	podSecurityContextChanged := !reflect.DeepEqual(a.Spec.Template.Spec.SecurityContext, b.Spec.Template.Spec.SecurityContext)
 	containerSecurityContextChanged := !reflect.DeepEqual(aContainer.SecurityContext, bContainer.SecurityContext)
+	packageRegistryConfigChanged := resources.PackageRegistryConfigSecretName(a) != resources.PackageRegistryConfigSecretName(b)
 
 	return imageChanged ||
 		labelsChanged ||
 		annotationsChanged ||
 		replicasChanged ||
+		strategyChanged ||
 		workingDirChanged ||
 		commandChanged ||
 		resourcesChanged ||
@@ -152,11 +171,12 @@ func deploymentChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
 		portsChanged ||
 		podSecurityContextChanged ||
 		containerSecurityContextChanged ||
+		packageRegistryConfigChanged ||
 		initContainerChanged(a, b)
 }
 
 func initContainerChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
-	// there are no init containers for inline function and one init container for git function
+	// there are no init containers for inline and configmap functions and one init container for git, oci and archive functions
 	// when count of init containers is not equal function type has been changed
 	if len(a.Spec.Template.Spec.InitContainers) > 1 ||
 		len(b.Spec.Template.Spec.InitContainers) > 1 ||
@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -249,8 +250,8 @@ func Test_sFnHandleDeployment(t *testing.T) {
 		require.Empty(t, m.State.Function.Status.Conditions)
 		// fsm stores the generated deployment for next states
 		require.NotNil(t, m.State.BuiltDeployment)
-		require.Contains(t, m.State.BuiltDeployment.Deployment.Spec.Template.Spec.Containers[0].Env,
-			corev1.EnvVar{Name: "FUNC_HANDLER_SOURCE", Value: "affectionate-mclean"})
+		require.Equal(t, deployment.Spec.Template.Spec.Containers[0].Command,
+			m.State.BuiltDeployment.Deployment.Spec.Template.Spec.Containers[0].Command)
 		require.Equal(t, "boring-bartik", m.State.BuiltDeployment.Deployment.Spec.Template.Spec.Containers[0].Image)
 	})
 	t.Run("when deployment exists on kubernetes and we need changes should update it and requeue", func(t *testing.T) {
@@ -285,7 +286,9 @@ func Test_sFnHandleDeployment(t *testing.T) {
 		}).Build()
 		// machine with our function
 		m := fsm.StateMachine{
-			State: fsm.SystemState{Function: f},
+			State: fsm.SystemState{
+				Function:               f,
+				InlineSourcesConfigMap: "inspiring-haibt-name-inline-0123456789"},
 			FunctionConfig: config.FunctionConfig{
 				Images: config.ImagesConfig{Python312: "flamboyant-chatelet"}},
 			Log:    zap.NewNop().Sugar(),
@@ -319,8 +322,8 @@ func Test_sFnHandleDeployment(t *testing.T) {
 		}, updatedDeployment)
 		require.NoError(t, getErr)
 		// deployment should have updated some specific fields
-		require.Contains(t, updatedDeployment.Spec.Template.Spec.Containers[0].Env,
-			corev1.EnvVar{Name: "FUNC_HANDLER_SOURCE", Value: "brave-euclid"})
+		require.Equal(t, "inline-sources", updatedDeployment.Spec.Template.Spec.Volumes[3].Name)
+		require.Equal(t, "inspiring-haibt-name-inline-0123456789", updatedDeployment.Spec.Template.Spec.Volumes[3].ConfigMap.Name)
 		require.Equal(t, "flamboyant-chatelet", updatedDeployment.Spec.Template.Spec.Containers[0].Image)
 		// function status should be updated with annotations
 		require.Equal(t, map[string]string{"torvalds": "lucid"}, m.State.Function.Status.FunctionAnnotations)
@@ -649,6 +652,32 @@ func Test_deploymentChanged(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "when rolling update parameters are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Strategy: appsv1.DeploymentStrategy{
+							Type: appsv1.RollingUpdateDeploymentStrategyType,
+							RollingUpdate: &appsv1.RollingUpdateDeployment{
+								MaxSurge:       ptr.To(intstr.FromString("25%")),
+								MaxUnavailable: ptr.To(intstr.FromString("25%"))}},
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Strategy: appsv1.DeploymentStrategy{
+							Type: appsv1.RollingUpdateDeploymentStrategyType,
+							RollingUpdate: &appsv1.RollingUpdateDeployment{
+								MaxSurge:       ptr.To(intstr.FromInt32(1)),
+								MaxUnavailable: ptr.To(intstr.FromInt32(0))}},
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
 		{
 			name: "when workingDir are different should return true",
 			args: args{
@@ -1045,6 +1074,30 @@ func Test_deploymentChanged(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "when package registry config secrets are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{{
+									Name: "package-registry-config",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{SecretName: "serverless-package-registry-config"}}}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{{
+									Name: "package-registry-config",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{SecretName: "team-registry-config"}}}},
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
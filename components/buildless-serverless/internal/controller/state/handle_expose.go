@@ -0,0 +1,238 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleExpose exposes the function with the APIRule, or with the HTTPRoute when the APIRule CRD isn't installed
+// objects which are no longer needed (the expose block was removed or the backend was switched) are deleted
+func sFnHandleExpose(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	installedKinds, err := installedExposeKinds(m)
+	if err != nil {
+		m.Log.Error(err, "unable to check installed expose kinds")
+		return stopWithError(err)
+	}
+
+	f := &m.State.Function
+	var builtObject *unstructured.Unstructured
+	if f.HasExpose() {
+		builtObject, err = buildExposeObject(m, installedKinds)
+		if err != nil {
+			f.UpdateCondition(
+				serverlessv1alpha2.ConditionRunning,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonExposeFailed,
+				fmt.Sprintf("Function can't be exposed: %s", err.Error()))
+			return requeueAfter(m.FunctionConfig.RequeueDuration)
+		}
+	}
+
+	for _, gvk := range installedKinds {
+		if builtObject != nil && builtObject.GroupVersionKind() == gvk {
+			continue
+		}
+		if err := deleteExposeObject(ctx, m, gvk); err != nil {
+			return stopWithError(err)
+		}
+	}
+
+	if builtObject == nil {
+		f.Status.URL = ""
+		return nextState(sFnHandleSchedules)
+	}
+
+	clusterObject := &unstructured.Unstructured{}
+	clusterObject.SetGroupVersionKind(builtObject.GroupVersionKind())
+	err = m.Client.Get(ctx, client.ObjectKeyFromObject(builtObject), clusterObject)
+	if errors.IsNotFound(err) {
+		result, errCreate := createExposeObject(ctx, m, builtObject)
+		return nil, result, errCreate
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch expose object for Function", "Kind", builtObject.GetKind())
+		return stopWithError(err)
+	}
+
+	requeueNeeded, errUpdate := updateExposeObjectIfNeeded(ctx, m, clusterObject, builtObject)
+	if errUpdate != nil {
+		return stopWithError(errUpdate)
+	}
+	if requeueNeeded {
+		return requeue()
+	}
+
+	f.Status.URL = resources.ExposeURL(f)
+	return nextState(sFnHandleSchedules)
+}
+
+// installedExposeKinds returns kinds which can be used to expose functions in the cluster, in the order of preference
+func installedExposeKinds(m *fsm.StateMachine) ([]schema.GroupVersionKind, error) {
+	kinds := []schema.GroupVersionKind{}
+	for _, gvk := range []schema.GroupVersionKind{resources.APIRuleGVK, resources.HTTPRouteGVK} {
+		_, err := m.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		kinds = append(kinds, gvk)
+	}
+	return kinds, nil
+}
+
+func buildExposeObject(m *fsm.StateMachine, installedKinds []schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	f := &m.State.Function
+	if len(installedKinds) == 0 {
+		return nil, fmt.Errorf("neither %s nor %s CRD is installed", resources.APIRuleGVK.Kind, resources.HTTPRouteGVK.Kind)
+	}
+	if installedKinds[0] == resources.APIRuleGVK {
+		return resources.NewAPIRule(f, m.FunctionConfig.Expose.Gateway), nil
+	}
+	if f.Spec.Expose.Auth == serverlessv1alpha2.ExposeAuthJWT {
+		return nil, fmt.Errorf("%s auth requires %s CRD", serverlessv1alpha2.ExposeAuthJWT, resources.APIRuleGVK.Kind)
+	}
+	return resources.NewHTTPRoute(f, m.FunctionConfig.Expose.Gateway), nil
+}
+
+func createExposeObject(ctx context.Context, m *fsm.StateMachine, object *unstructured.Unstructured) (*ctrl.Result, error) {
+	m.Log.Info("creating a new expose object", "Kind", object.GetKind(), "Namespace", object.GetNamespace(), "Name", object.GetName())
+
+	// Set the ownerRef for the object, ensuring that the object
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, object, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new expose object", "Kind", object.GetKind(), "Namespace", object.GetNamespace(), "Name", object.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s create failed: %s", object.GetKind(), object.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, object); err != nil {
+		m.Log.Error(err, "failed to create new expose object", "Kind", object.GetKind(), "Namespace", object.GetNamespace(), "Name", object.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s create failed: %s", object.GetKind(), object.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonExposeCreated,
+		fmt.Sprintf("%s %s created", object.GetKind(), object.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func updateExposeObjectIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterObject, builtObject *unstructured.Unstructured) (requeueNeeded bool, err error) {
+	if !metav1.IsControlledBy(clusterObject, &m.State.Function) {
+		err := fmt.Errorf("%s %s already exists and isn't owned by the Function", clusterObject.GetKind(), clusterObject.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			err.Error())
+		return false, err
+	}
+
+	if !exposeObjectChanged(clusterObject, builtObject) {
+		return false, nil
+	}
+
+	clusterObject.Object["spec"] = builtObject.Object["spec"]
+	clusterObject.SetLabels(builtObject.GetLabels())
+	if err := m.Client.Update(ctx, clusterObject); err != nil {
+		m.Log.Error(err, "failed to update expose object", "Kind", clusterObject.GetKind(), "Namespace", clusterObject.GetNamespace(), "Name", clusterObject.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s update failed: %s", clusterObject.GetKind(), clusterObject.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonExposeUpdated,
+		fmt.Sprintf("%s %s updated", clusterObject.GetKind(), clusterObject.GetName()))
+	return true, nil
+}
+
+// exposeObjectChanged checks only fields set by the controller, as the API server defaults the rest of them
+func exposeObjectChanged(clusterObject, builtObject *unstructured.Unstructured) bool {
+	return !mapsEqual(clusterObject.GetLabels(), builtObject.GetLabels()) ||
+		!containsFields(clusterObject.Object["spec"], builtObject.Object["spec"])
+}
+
+// containsFields returns true when all fields of expected are present in actual with the same values
+func containsFields(actual, expected interface{}) bool {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range e {
+			if !containsFields(a[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !containsFields(a[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(actual, expected)
+	}
+}
+
+// deleteExposeObject removes the object of the given kind owned by the function
+func deleteExposeObject(ctx context.Context, m *fsm.StateMachine, gvk schema.GroupVersionKind) error {
+	f := &m.State.Function
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	err := m.Client.Get(ctx, client.ObjectKey{Namespace: f.GetNamespace(), Name: f.GetName()}, object)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch expose object for Function", "Kind", gvk.Kind)
+		return err
+	}
+	if !metav1.IsControlledBy(object, f) {
+		return nil
+	}
+
+	m.Log.Info("deleting expose object", "Kind", gvk.Kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+	if err := m.Client.Delete(ctx, object); err != nil && !errors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete expose object", "Kind", gvk.Kind, "Namespace", object.GetNamespace(), "Name", object.GetName())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleExpose(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+
+	exposedFunction := func(expose *serverlessv1alpha2.Expose) serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Expose:  expose,
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, kinds []schema.GroupVersionKind, objs ...client.Object) *fsm.StateMachine {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		for _, gvk := range kinds {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build(),
+			Scheme: scheme,
+			FunctionConfig: config.FunctionConfig{
+				RequeueDuration: time.Minute,
+				Expose:          config.ExposeConfig{Gateway: "kyma-system/kyma-gateway"},
+			},
+		}
+	}
+	ownedObject := func(t *testing.T, f serverlessv1alpha2.Function, object *unstructured.Unstructured) *unstructured.Unstructured {
+		require.NoError(t, controllerutil.SetControllerReference(&f, object, scheme))
+		return object
+	}
+	getObject := func(m *fsm.StateMachine, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(gvk)
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function"}, object)
+		return object, err
+	}
+
+	t.Run("move to the next state when function is not exposed", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(nil)
+		f.Status.URL = "https://fn.example.com/"
+		m := newMachine(f, nil)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		require.Empty(t, m.State.Function.Status.URL)
+	})
+	t.Run("create APIRule when its CRD is installed", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+		m := newMachine(f, []schema.GroupVersionKind{resources.APIRuleGVK, resources.HTTPRouteGVK})
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeCreated,
+			"APIRule test-function created")
+		apiRule, err := getObject(m, resources.APIRuleGVK)
+		require.NoError(t, err)
+		require.True(t, metav1.IsControlledBy(apiRule, &m.State.Function))
+		_, err = getObject(m, resources.HTTPRouteGVK)
+		require.True(t, errors.IsNotFound(err))
+	})
+	t.Run("create HTTPRoute when APIRule CRD is not installed", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+		m := newMachine(f, []schema.GroupVersionKind{resources.HTTPRouteGVK})
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeCreated,
+			"HTTPRoute test-function created")
+		_, err = getObject(m, resources.HTTPRouteGVK)
+		require.NoError(t, err)
+	})
+	t.Run("set URL and move to the next state when expose object is up to date", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com", Path: "/api"})
+		route := ownedObject(t, f, resources.NewHTTPRoute(&f, "kyma-system/kyma-gateway"))
+		// fields defaulted by the API server don't trigger the update
+		spec := route.Object["spec"].(map[string]interface{})
+		spec["parentRefs"].([]interface{})[0].(map[string]interface{})["group"] = "gateway.networking.k8s.io"
+		m := newMachine(f, []schema.GroupVersionKind{resources.HTTPRouteGVK}, route)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		require.Equal(t, "https://fn.example.com/api", m.State.Function.Status.URL)
+	})
+	t.Run("update expose object when function host changed", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "old.example.com"})
+		apiRule := ownedObject(t, f, resources.NewAPIRule(&f, "kyma-system/kyma-gateway"))
+		f.Spec.Expose.Host = "new.example.com"
+		m := newMachine(f, []schema.GroupVersionKind{resources.APIRuleGVK}, apiRule)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeUpdated,
+			"APIRule test-function updated")
+		updated, err := getObject(m, resources.APIRuleGVK)
+		require.NoError(t, err)
+		hosts, _, _ := unstructured.NestedSlice(updated.Object, "spec", "hosts")
+		require.Equal(t, []interface{}{"new.example.com"}, hosts)
+	})
+	t.Run("delete owned expose objects when expose is removed", func(t *testing.T) {
+		// Arrange
+		exposed := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+		route := ownedObject(t, exposed, resources.NewHTTPRoute(&exposed, "kyma-system/kyma-gateway"))
+		f := exposedFunction(nil)
+		f.Status.URL = "https://fn.example.com/"
+		m := newMachine(f, []schema.GroupVersionKind{resources.APIRuleGVK, resources.HTTPRouteGVK}, route)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		require.Empty(t, m.State.Function.Status.URL)
+		_, err = getObject(m, resources.HTTPRouteGVK)
+		require.True(t, errors.IsNotFound(err))
+	})
+	t.Run("don't delete expose objects not owned by the function", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(nil)
+		exposed := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+		route := resources.NewHTTPRoute(&exposed, "kyma-system/kyma-gateway")
+		m := newMachine(f, []schema.GroupVersionKind{resources.HTTPRouteGVK}, route)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		_, err = getObject(m, resources.HTTPRouteGVK)
+		require.NoError(t, err)
+	})
+	t.Run("stop when expose object is not owned by the function", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+		route := resources.NewHTTPRoute(&f, "kyma-system/kyma-gateway")
+		m := newMachine(f, []schema.GroupVersionKind{resources.HTTPRouteGVK}, route)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.EqualError(t, err, "HTTPRoute test-function already exists and isn't owned by the Function")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"HTTPRoute test-function already exists and isn't owned by the Function")
+	})
+	t.Run("requeue when no expose CRD is installed", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{Host: "fn.example.com"})
+		m := newMachine(f, nil)
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{RequeueAfter: time.Minute}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"Function can't be exposed: neither APIRule nor HTTPRoute CRD is installed")
+	})
+	t.Run("requeue when jwt auth is used without APIRule CRD", func(t *testing.T) {
+		// Arrange
+		f := exposedFunction(&serverlessv1alpha2.Expose{
+			Host: "fn.example.com",
+			Auth: serverlessv1alpha2.ExposeAuthJWT,
+			JWT:  &serverlessv1alpha2.ExposeJWT{Issuer: "https://issuer.example.com", JWKSURI: "https://issuer.example.com/keys"},
+		})
+		m := newMachine(f, []schema.GroupVersionKind{resources.HTTPRouteGVK})
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{RequeueAfter: time.Minute}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"Function can't be exposed: jwt auth requires APIRule CRD")
+	})
+}
@@ -16,7 +16,7 @@ import (
 
 func sFnHandleGitSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
 	if !m.State.Function.HasGitSources() {
-		return nextState(sFnConfigurationReady)
+		return nextState(sFnHandleConfigMapSources)
 	}
 
 	gitRepository := m.State.Function.Spec.Source.GitRepository
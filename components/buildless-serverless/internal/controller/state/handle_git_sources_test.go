@@ -49,7 +49,7 @@ func Test_sFnHandleGitSources(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnConfigurationReady, next)
+		requireEqualFunc(t, sFnHandleConfigMapSources, next)
 		// function conditions remain unchanged
 		require.Empty(t, m.State.Function.Status.Conditions)
 		// no commit change, it should be changed only for git functions
@@ -0,0 +1,107 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleInlineSources keeps inline sources in the owned ConfigMap mounted into function pods
+// ConfigMaps are named after hash of the sources, stale ones are removed once the workload doesn't use them anymore
+func sFnHandleInlineSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if !m.State.Function.HasInlineSources() {
+		return nextState(sFnHandleWorkload)
+	}
+	builtConfigMap := resources.NewInlineSourcesConfigMap(&m.State.Function)
+
+	clusterConfigMap := &corev1.ConfigMap{}
+	err := m.Client.Get(ctx, client.ObjectKeyFromObject(builtConfigMap), clusterConfigMap)
+	if errors.IsNotFound(err) {
+		err = createInlineSourcesConfigMap(ctx, m, builtConfigMap)
+	}
+	if err != nil {
+		return stopWithError(err)
+	}
+
+	m.State.InlineSourcesConfigMap = builtConfigMap.GetName()
+	m.State.SourceHash = resources.InlineSourcesHash(builtConfigMap, &m.State.Function, m.State.FunctionRuntime)
+
+	return nextState(sFnHandleWorkload)
+}
+
+// deleteStaleInlineSourcesConfigMaps removes inline sources ConfigMaps other than the one mounted by the current workload
+// it's called when the workload is rolled out, so Pods of the previous rollout don't lose their sources
+func deleteStaleInlineSourcesConfigMaps(ctx context.Context, m *fsm.StateMachine) error {
+	clusterConfigMaps, err := getInlineSourcesConfigMaps(ctx, m)
+	if err != nil {
+		return err
+	}
+	for i := range clusterConfigMaps.Items {
+		clusterConfigMap := &clusterConfigMaps.Items[i]
+		if clusterConfigMap.GetName() == m.State.InlineSourcesConfigMap {
+			continue
+		}
+		if err := deleteInlineSourcesConfigMap(ctx, m, clusterConfigMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getInlineSourcesConfigMaps(ctx context.Context, m *fsm.StateMachine) (*corev1.ConfigMapList, error) {
+	configMaps := &corev1.ConfigMapList{}
+	f := m.State.Function
+	labels := resources.InlineSourcesConfigMapLabels(&f)
+	err := m.Client.List(ctx, configMaps, client.InNamespace(f.GetNamespace()), client.MatchingLabels(labels))
+	if err != nil {
+		m.Log.Error(err, "unable to fetch inline sources ConfigMaps for Function")
+		return nil, err
+	}
+	return configMaps, nil
+}
+
+func createInlineSourcesConfigMap(ctx context.Context, m *fsm.StateMachine, configMap *corev1.ConfigMap) error {
+	m.Log.Info("creating a new inline sources ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+
+	// Set the ownerRef for the ConfigMap, ensuring that the ConfigMap
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, configMap, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			fmt.Sprintf("ConfigMap %s create failed: %s", configMap.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, configMap); err != nil && !errors.IsAlreadyExists(err) {
+		m.Log.Error(err, "failed to create new ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			fmt.Sprintf("ConfigMap %s create failed: %s", configMap.GetName(), err.Error()))
+		return err
+	}
+	return nil
+}
+
+func deleteInlineSourcesConfigMap(ctx context.Context, m *fsm.StateMachine, configMap *corev1.ConfigMap) error {
+	m.Log.Info("deleting stale inline sources ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+
+	if err := m.Client.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete stale ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_sFnHandleInlineSources(t *testing.T) {
+	t.Run("create configmap with inline sources and move to the nextState", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		scheme := minimalInlineScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme,
+		}
+
+		// Act
+		next, result, err := sFnHandleInlineSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleWorkload, next)
+		expected := resources.NewInlineSourcesConfigMap(&f)
+		require.Equal(t, expected.GetName(), m.State.InlineSourcesConfigMap)
+		require.Equal(t, resources.InlineSourcesHash(expected, &f, nil), m.State.SourceHash)
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+			Namespace: "test-namespace",
+			Name:      expected.GetName(),
+		}, cm))
+		require.Equal(t, expected.Data, cm.Data)
+		require.Len(t, cm.GetOwnerReferences(), 1)
+		require.Equal(t, "test-function", cm.GetOwnerReferences()[0].Name)
+	})
+	t.Run("keep existing and stale configmaps", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		current := resources.NewInlineSourcesConfigMap(&f)
+		stale := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-inline-0123456789",
+				Namespace: "test-namespace",
+				Labels:    resources.InlineSourcesConfigMapLabels(&f),
+			},
+		}
+		scheme := minimalInlineScheme(t)
+		createWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(current, stale).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				createWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme,
+		}
+
+		// Act
+		next, result, err := sFnHandleInlineSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleWorkload, next)
+		require.False(t, createWasCalled)
+		require.Equal(t, current.GetName(), m.State.InlineSourcesConfigMap)
+		configMaps := &corev1.ConfigMapList{}
+		require.NoError(t, k8sClient.List(context.Background(), configMaps))
+		// the stale configmap is still used by pods of the previous rollout
+		require.Len(t, configMaps.Items, 2)
+	})
+	t.Run("change source hash when file is renamed without changing its content", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		scheme := minimalInlineScheme(t)
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme: scheme,
+		}
+		_, _, err := sFnHandleInlineSources(context.Background(), &m)
+		require.NoError(t, err)
+		previousConfigMap, previousHash := m.State.InlineSourcesConfigMap, m.State.SourceHash
+		m.State.Function.Spec.Source.Inline.Files = map[string]string{
+			"utils/util.js": "module.exports = {}",
+		}
+
+		// Act
+		_, _, err = sFnHandleInlineSources(context.Background(), &m)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotEqual(t, previousConfigMap, m.State.InlineSourcesConfigMap)
+		require.NotEqual(t, previousHash, m.State.SourceHash)
+	})
+	t.Run("for non inline function move to the nextState", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		f.Spec.Source = serverlessv1alpha2.Source{
+			ConfigMap: &serverlessv1alpha2.ConfigMapSource{Name: "function-sources"}}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleInlineSources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleWorkload, next)
+		require.Empty(t, m.State.InlineSourcesConfigMap)
+	})
+	t.Run("stop and set condition when configmap can't be created", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		scheme := minimalInlineScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return errors.New("quota exceeded")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme,
+		}
+
+		// Act
+		next, result, err := sFnHandleInlineSources(context.Background(), &m)
+
+		// Assert
+		require.ErrorContains(t, err, "quota exceeded")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"ConfigMap "+resources.NewInlineSourcesConfigMap(&f).GetName()+" create failed: quota exceeded")
+	})
+}
+
+func Test_deleteStaleInlineSourcesConfigMaps(t *testing.T) {
+	t.Run("remove stale configmaps and keep the current one", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		current := resources.NewInlineSourcesConfigMap(&f)
+		stale := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-inline-0123456789",
+				Namespace: "test-namespace",
+				Labels:    resources.InlineSourcesConfigMapLabels(&f),
+			},
+		}
+		foreign := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-configmap",
+				Namespace: "test-namespace",
+			},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(minimalInlineScheme(t)).WithObjects(current, stale, foreign).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:               f,
+				InlineSourcesConfigMap: current.GetName()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+		}
+
+		// Act
+		err := deleteStaleInlineSourcesConfigMaps(context.Background(), &m)
+
+		// Assert
+		require.NoError(t, err)
+		configMaps := &corev1.ConfigMapList{}
+		require.NoError(t, k8sClient.List(context.Background(), configMaps))
+		require.Len(t, configMaps.Items, 2)
+		require.ElementsMatch(t,
+			[]string{current.GetName(), "user-configmap"},
+			[]string{configMaps.Items[0].GetName(), configMaps.Items[1].GetName()})
+	})
+	t.Run("remove configmaps left by inline sources for non inline function", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		stale := resources.NewInlineSourcesConfigMap(&f)
+		f.Spec.Source = serverlessv1alpha2.Source{
+			ConfigMap: &serverlessv1alpha2.ConfigMapSource{Name: "function-sources"}}
+		k8sClient := fake.NewClientBuilder().WithScheme(minimalInlineScheme(t)).WithObjects(stale).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+		}
+
+		// Act
+		err := deleteStaleInlineSourcesConfigMaps(context.Background(), &m)
+
+		// Assert
+		require.NoError(t, err)
+		configMaps := &corev1.ConfigMapList{}
+		require.NoError(t, k8sClient.List(context.Background(), configMaps))
+		require.Empty(t, configMaps.Items)
+	})
+}
+
+func minimalInlineFunction() serverlessv1alpha2.Function {
+	return serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function",
+			Namespace: "test-namespace",
+			UID:       "test-uid",
+		},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Runtime: serverlessv1alpha2.NodeJs22,
+			Source: serverlessv1alpha2.Source{
+				Inline: &serverlessv1alpha2.InlineSource{
+					Source:       "module.exports = { main: function() { return 'hello'; } }",
+					Dependencies: `{"name": "test"}`,
+					Files: map[string]string{
+						"utils/helpers.js": "module.exports = {}",
+					},
+				},
+			},
+		},
+	}
+}
+
+func minimalInlineScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return scheme
+}
@@ -0,0 +1,201 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleJob runs the `job` function to completion in the Job built from the function's Deployment
+// the Job is replaced when its pod template or the run identifier changes
+func sFnHandleJob(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	// the function could be switched from the service workload
+	if err := cleanupWorkloadBackends(ctx, m, ""); err != nil {
+		return stopWithError(err)
+	}
+
+	m.State.BuiltDeployment = resources.NewDeployment(f, &m.FunctionConfig, nil, m.State.Commit, m.State.GitAuth, "",
+		resources.DeploySetRuntime(runWithRuntime(m)),
+		resources.DeploySetFunctionRuntime(m.State.FunctionRuntime),
+		resources.DeploySetSourceHash(m.State.SourceHash),
+		resources.DeploySetOCIDigest(m.State.OCIDigest),
+		resources.DeploySetArchive(m.State.ArchiveRevision, m.State.ArchiveAuth),
+		resources.DeploySetInlineSourcesConfigMap(m.State.InlineSourcesConfigMap),
+		resources.DeploySetCmd(resources.JobCommand(f, m.State.FunctionRuntime)))
+	pinRuntimeImage(ctx, m)
+	verifyRuntimeImage(ctx, m)
+	builtJob := resources.NewJob(f, m.State.BuiltDeployment)
+
+	clusterJobs := &batchv1.JobList{}
+	err := m.Client.List(ctx, clusterJobs, client.InNamespace(f.GetNamespace()), client.MatchingLabels(resources.JobLabels(f)))
+	if err != nil {
+		return stopWithError(errors.Wrap(err, "while listing jobs"))
+	}
+
+	var clusterJob *batchv1.Job
+	for i := range clusterJobs.Items {
+		job := &clusterJobs.Items[i]
+		if job.GetName() == builtJob.GetName() {
+			clusterJob = job
+			continue
+		}
+		// the job was run for previous sources or configuration
+		if err := deleteJob(ctx, m, job); err != nil {
+			return stopWithError(err)
+		}
+	}
+
+	if clusterJob == nil {
+		if err := requireVerifiedRuntimeImage(m); err != nil {
+			return stopWithError(err)
+		}
+		result, errCreate := createJob(ctx, m, builtJob)
+		if errCreate == nil {
+			f.CopyAnnotationsToStatus()
+		}
+		return nil, result, errCreate
+	}
+	m.State.ClusterJob = clusterJob
+	f.CopyAnnotationsToStatus()
+
+	return nextState(sFnHandleExpose)
+}
+
+func createJob(ctx context.Context, m *fsm.StateMachine, job *batchv1.Job) (*ctrl.Result, error) {
+	m.Log.Info("creating a new Job", "Job.Namespace", job.GetNamespace(), "Job.Name", job.GetName())
+
+	if err := controllerutil.SetControllerReference(&m.State.Function, job, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new Job", "Job.Namespace", job.GetNamespace(), "Job.Name", job.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonJobFailed,
+			fmt.Sprintf("Job %s create failed: %s", job.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, job); err != nil {
+		m.Log.Error(err, "failed to create new Job", "Job.Namespace", job.GetNamespace(), "Job.Name", job.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonJobFailed,
+			fmt.Sprintf("Job %s create failed: %s", job.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonJobCreated,
+		fmt.Sprintf("Job %s created", job.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func deleteJob(ctx context.Context, m *fsm.StateMachine, job *batchv1.Job) error {
+	m.Log.Info("deleting Job", "Job.Namespace", job.GetNamespace(), "Job.Name", job.GetName())
+	err := m.Client.Delete(ctx, job, &client.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+	})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete Job", "Job.Namespace", job.GetNamespace(), "Job.Name", job.GetName())
+		return errors.Wrapf(err, "while deleting job %s", job.GetName())
+	}
+	return nil
+}
+
+// deleteJobs removes Jobs left by the function switched to the service workload
+func deleteJobs(ctx context.Context, m *fsm.StateMachine) error {
+	f := m.State.Function
+	jobs := &batchv1.JobList{}
+	err := m.Client.List(ctx, jobs, client.InNamespace(f.GetNamespace()), client.MatchingLabels(resources.JobLabels(&f)))
+	if err != nil {
+		return errors.Wrap(err, "while listing jobs")
+	}
+	for i := range jobs.Items {
+		if err := deleteJob(ctx, m, &jobs.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sFnJobStatus reflects the state of the Job running the `job` function in the function's status
+func sFnJobStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	// Jobs run for previous sources are already deleted
+	if err := deleteStaleInlineSourcesConfigMaps(ctx, m); err != nil {
+		return stopWithError(errors.Wrap(err, "while deleting stale inline sources configmaps"))
+	}
+
+	job := m.State.ClusterJob
+	jobName := job.GetName()
+	status := &serverlessv1alpha2.JobStatus{
+		Name:           jobName,
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
+		Logs:           resources.JobLogsCommand(job),
+	}
+	m.State.Function.Status.Job = status
+
+	if isJobConditionTrue(job, batchv1.JobComplete) {
+		m.Log.Info(fmt.Sprintf("job %s succeeded", jobName))
+
+		status.Phase = serverlessv1alpha2.JobPhaseSucceeded
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonJobSucceeded,
+			fmt.Sprintf("Job %s succeeded", jobName))
+		metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionRunning)
+		return nextState(sFnAdjustStatus)
+	}
+
+	if condition := findJobCondition(job, batchv1.JobFailed); condition != nil && condition.Status == corev1.ConditionTrue {
+		m.Log.Info(fmt.Sprintf("job %s failed", jobName))
+
+		status.Phase = serverlessv1alpha2.JobPhaseFailed
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonJobFailed,
+			fmt.Sprintf("Job %s failed: %s", jobName, condition.Message))
+		return nextState(sFnAdjustStatus)
+	}
+
+	status.Phase = serverlessv1alpha2.JobPhaseRunning
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonJobRunning,
+		fmt.Sprintf("Job %s is running", jobName))
+	return nextState(sFnAdjustStatus)
+}
+
+func isJobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	condition := findJobCondition(job, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+func findJobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType {
+			return &job.Status.Conditions[i]
+		}
+	}
+	return nil
+}
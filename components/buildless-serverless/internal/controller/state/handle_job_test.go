@@ -0,0 +1,253 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sFnHandleJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	jobFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:  serverlessv1alpha2.NodeJs22,
+				Workload: serverlessv1alpha2.WorkloadJob,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "module.exports = { main: function() {} }",
+					},
+				},
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, c client.Client) *fsm.StateMachine {
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: c,
+			Scheme: scheme,
+			FunctionConfig: config.FunctionConfig{
+				Images: config.ImagesConfig{NodeJs22: "test-image-nodejs22"},
+			},
+		}
+	}
+	builtJob := func(m *fsm.StateMachine) *batchv1.Job {
+		f := m.State.Function
+		d := resources.NewDeployment(&f, &m.FunctionConfig, nil, "", nil, "",
+			resources.DeploySetCmd(resources.JobCommand(&f, nil)))
+		return resources.NewJob(&f, d)
+	}
+
+	t.Run("create Job when it doesn't exist", func(t *testing.T) {
+		// Arrange
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := newMachine(jobFunction(), c)
+
+		// Act
+		next, result, err := sFnHandleJob(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		jobs := &batchv1.JobList{}
+		require.NoError(t, c.List(context.Background(), jobs))
+		require.Len(t, jobs.Items, 1)
+		job := jobs.Items[0]
+		require.Equal(t, builtJob(m).GetName(), job.GetName())
+		require.Equal(t, "test-function", job.GetOwnerReferences()[0].Name)
+		require.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonJobCreated,
+			"Job "+job.GetName()+" created")
+	})
+	t.Run("keep existing Job and go to the next state", func(t *testing.T) {
+		// Arrange
+		m := newMachine(jobFunction(), nil)
+		job := builtJob(m)
+		m.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).Build()
+
+		// Act
+		next, result, err := sFnHandleJob(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		require.Equal(t, job.GetName(), m.State.ClusterJob.GetName())
+	})
+	t.Run("replace Job run for previous sources", func(t *testing.T) {
+		// Arrange
+		f := jobFunction()
+		oldJob := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-0123456789",
+				Namespace: "test-namespace",
+				Labels:    resources.JobLabels(&f),
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(oldJob).Build()
+		m := newMachine(f, c)
+
+		// Act
+		next, result, err := sFnHandleJob(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		jobs := &batchv1.JobList{}
+		require.NoError(t, c.List(context.Background(), jobs))
+		require.Len(t, jobs.Items, 1)
+		require.Equal(t, builtJob(m).GetName(), jobs.Items[0].GetName())
+	})
+	t.Run("delete Deployment and Service of the service workload", func(t *testing.T) {
+		// Arrange
+		f := jobFunction()
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-abcde",
+				Namespace: "test-namespace",
+				Labels:    f.InternalFunctionLabels(),
+			},
+		}
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "serverless.kyma-project.io/v1alpha2", Kind: "Function", Name: "test-function", UID: "test-uid", Controller: ptr.To(true)},
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment, service).Build()
+		m := newMachine(f, c)
+
+		// Act
+		_, _, err := sFnHandleJob(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		deployments := &appsv1.DeploymentList{}
+		require.NoError(t, c.List(context.Background(), deployments))
+		require.Empty(t, deployments.Items)
+		services := &corev1.ServiceList{}
+		require.NoError(t, c.List(context.Background(), services))
+		require.Empty(t, services.Items)
+	})
+}
+
+func Test_sFnJobStatus(t *testing.T) {
+	startTime := metav1.Now()
+	newMachine := func(conditions ...batchv1.JobCondition) *fsm.StateMachine {
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Workload: serverlessv1alpha2.WorkloadJob,
+					},
+				},
+				ClusterJob: &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function-0123456789",
+						Namespace: "test-namespace",
+					},
+					Status: batchv1.JobStatus{
+						StartTime:  &startTime,
+						Conditions: conditions,
+					},
+				},
+			},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().Build(),
+		}
+	}
+
+	t.Run("set running phase when Job is active", func(t *testing.T) {
+		// Arrange
+		m := newMachine()
+
+		// Act
+		next, result, err := sFnJobStatus(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnAdjustStatus, next)
+		require.Equal(t, &serverlessv1alpha2.JobStatus{
+			Name:      "test-function-0123456789",
+			Phase:     serverlessv1alpha2.JobPhaseRunning,
+			StartTime: &startTime,
+			Logs:      "kubectl logs -n test-namespace job/test-function-0123456789 -c function",
+		}, m.State.Function.Status.Job)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonJobRunning,
+			"Job test-function-0123456789 is running")
+	})
+	t.Run("set succeeded phase when Job is complete", func(t *testing.T) {
+		// Arrange
+		m := newMachine(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})
+
+		// Act
+		next, _, err := sFnJobStatus(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnAdjustStatus, next)
+		require.Equal(t, serverlessv1alpha2.JobPhaseSucceeded, m.State.Function.Status.Job.Phase)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonJobSucceeded,
+			"Job test-function-0123456789 succeeded")
+	})
+	t.Run("set failed phase when Job failed", func(t *testing.T) {
+		// Arrange
+		m := newMachine(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "Job has reached the specified backoff limit"})
+
+		// Act
+		next, _, err := sFnJobStatus(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnAdjustStatus, next)
+		require.Equal(t, serverlessv1alpha2.JobPhaseFailed, m.State.Function.Status.Job.Phase)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonJobFailed,
+			"Job test-function-0123456789 failed: Job has reached the specified backoff limit")
+	})
+}
@@ -0,0 +1,212 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleKnativeService serves the function with the Knative Service running the pod built for the function's Deployment
+func sFnHandleKnativeService(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	_, err := m.Client.RESTMapper().RESTMapping(resources.KnativeServiceGVK.GroupKind(), resources.KnativeServiceGVK.Version)
+	if meta.IsNoMatchError(err) {
+		updateKnativeServiceCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			"Knative Service CRD is not installed")
+		return stop()
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to check if Knative Service CRD is installed")
+		return stopWithError(err)
+	}
+
+	m.State.BuiltDeployment = resources.NewDeployment(f, &m.FunctionConfig, nil, m.State.Commit, m.State.GitAuth, "",
+		resources.DeploySetRuntime(runWithRuntime(m)),
+		resources.DeploySetFunctionRuntime(m.State.FunctionRuntime),
+		resources.DeploySetSourceHash(m.State.SourceHash),
+		resources.DeploySetOCIDigest(m.State.OCIDigest),
+		resources.DeploySetArchive(m.State.ArchiveRevision, m.State.ArchiveAuth),
+		resources.DeploySetInlineSourcesConfigMap(m.State.InlineSourcesConfigMap))
+	pinRuntimeImage(ctx, m)
+	verifyRuntimeImage(ctx, m)
+	builtService, err := resources.NewKnativeService(f, m.State.BuiltDeployment)
+	if err != nil {
+		return stopWithError(errors.Wrap(err, "while building knative service"))
+	}
+
+	clusterService, err := getKnativeService(ctx, m)
+	if err != nil {
+		return stopWithError(err)
+	}
+	if clusterService == nil {
+		if err := requireVerifiedRuntimeImage(m); err != nil {
+			return stopWithError(err)
+		}
+		if err := createKnativeService(ctx, m, builtService); err != nil {
+			return stopWithError(err)
+		}
+		f.CopyAnnotationsToStatus()
+		return requeue()
+	}
+	if !metav1.IsControlledBy(clusterService, f) {
+		updateKnativeServiceCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			fmt.Sprintf("Knative Service %s already exists and isn't owned by the function", clusterService.GetName()))
+		return stop()
+	}
+
+	updated, err := updateKnativeServiceIfNeeded(ctx, m, clusterService, builtService)
+	if err != nil {
+		return stopWithError(err)
+	}
+	f.CopyAnnotationsToStatus()
+	if updated {
+		return requeue()
+	}
+	return nextState(sFnHandleExpose)
+}
+
+func getKnativeService(ctx context.Context, m *fsm.StateMachine) (*unstructured.Unstructured, error) {
+	f := m.State.Function
+	service := &unstructured.Unstructured{}
+	service.SetGroupVersionKind(resources.KnativeServiceGVK)
+	err := m.Client.Get(ctx, client.ObjectKey{Namespace: f.GetNamespace(), Name: f.GetName()}, service)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch Knative Service for Function")
+		return nil, err
+	}
+	return service, nil
+}
+
+func createKnativeService(ctx context.Context, m *fsm.StateMachine, service *unstructured.Unstructured) error {
+	m.Log.Info("creating a new Knative Service", "Service.Namespace", service.GetNamespace(), "Service.Name", service.GetName())
+
+	// Set the ownerRef for the Knative Service, ensuring that the Service
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, service, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new Knative Service", "Service.Namespace", service.GetNamespace(), "Service.Name", service.GetName())
+		updateKnativeServiceCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			fmt.Sprintf("Knative Service %s create failed: %s", service.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, service); err != nil {
+		m.Log.Error(err, "failed to create new Knative Service", "Service.Namespace", service.GetNamespace(), "Service.Name", service.GetName())
+		updateKnativeServiceCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			fmt.Sprintf("Knative Service %s create failed: %s", service.GetName(), err.Error()))
+		return err
+	}
+	updateKnativeServiceCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonKnativeServiceCreated,
+		fmt.Sprintf("Knative Service %s created", service.GetName()))
+	return nil
+}
+
+func updateKnativeServiceIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterService, builtService *unstructured.Unstructured) (bool, error) {
+	// Knative defaults the spec, so only fields set by the controller are compared
+	if !exposeObjectChanged(clusterService, builtService) {
+		return false, nil
+	}
+	if err := requireVerifiedRuntimeImage(m); err != nil {
+		return false, err
+	}
+
+	m.Log.Info("updating Knative Service", "Service.Namespace", clusterService.GetNamespace(), "Service.Name", clusterService.GetName())
+	clusterService.Object["spec"] = builtService.Object["spec"]
+	clusterService.SetLabels(builtService.GetLabels())
+	if err := m.Client.Update(ctx, clusterService); err != nil {
+		m.Log.Error(err, "failed to update Knative Service", "Service.Namespace", clusterService.GetNamespace(), "Service.Name", clusterService.GetName())
+		updateKnativeServiceCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			fmt.Sprintf("Knative Service %s update failed: %s", clusterService.GetName(), err.Error()))
+		return false, err
+	}
+	updateKnativeServiceCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonKnativeServiceUpdated,
+		fmt.Sprintf("Knative Service %s updated", clusterService.GetName()))
+	return true, nil
+}
+
+// deleteKnativeService removes the Knative Service owned by the function, it's skipped when Knative Serving isn't installed
+func deleteKnativeService(ctx context.Context, m *fsm.StateMachine) error {
+	_, err := m.Client.RESTMapper().RESTMapping(resources.KnativeServiceGVK.GroupKind(), resources.KnativeServiceGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	service, err := getKnativeService(ctx, m)
+	if err != nil {
+		return err
+	}
+	if service == nil || !metav1.IsControlledBy(service, &m.State.Function) {
+		return nil
+	}
+	m.Log.Info("deleting Knative Service", "Service.Namespace", service.GetNamespace(), "Service.Name", service.GetName())
+	err = m.Client.Delete(ctx, service)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// sFnKnativeServiceStatus reflects the readiness of the Knative Service in the function's status
+// Knative Services aren't watched, so the function is requeued until the Service is ready
+func sFnKnativeServiceStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	service, err := getKnativeService(ctx, m)
+	if err != nil {
+		return stopWithError(errors.Wrap(err, "while getting knative service"))
+	}
+	if service == nil {
+		return stopWithError(errors.New("knative service not found"))
+	}
+	serviceName := service.GetName()
+
+	switch status, message := resources.KnativeServiceReadyStatus(service); status {
+	case metav1.ConditionTrue:
+		m.Log.Info(fmt.Sprintf("knative service %s ready", serviceName))
+
+		updateKnativeServiceCondition(m, metav1.ConditionTrue, serverlessv1alpha2.ConditionReasonKnativeServiceReady,
+			fmt.Sprintf("Knative Service %s is ready", serviceName))
+		metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionRunning)
+		m.State.Function.Status.URL = resources.KnativeServiceURL(service)
+		if err := deleteStaleInlineSourcesConfigMaps(ctx, m); err != nil {
+			return stopWithError(errors.Wrap(err, "while deleting stale inline sources configmaps"))
+		}
+		return nextState(sFnAdjustStatus)
+	case metav1.ConditionFalse:
+		m.Log.Info(fmt.Sprintf("knative service %s failed", serviceName))
+
+		updateKnativeServiceCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			fmt.Sprintf("Knative Service %s failed: %s", serviceName, message))
+		return requeueAfter(m.FunctionConfig.RequeueDuration)
+	default:
+		m.Log.Info(fmt.Sprintf("knative service %s not ready", serviceName))
+
+		updateKnativeServiceCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonKnativeServiceWaiting,
+			fmt.Sprintf("Knative Service %s is not ready yet", serviceName))
+		return requeueAfter(m.FunctionConfig.RequeueDuration)
+	}
+}
+
+func updateKnativeServiceCondition(m *fsm.StateMachine, status metav1.ConditionStatus, reason serverlessv1alpha2.ConditionReason, msg string) {
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		status,
+		reason,
+		msg)
+}
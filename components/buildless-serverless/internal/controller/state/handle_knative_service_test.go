@@ -0,0 +1,256 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleKnativeService(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+
+	knativeFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Backend: serverlessv1alpha2.WorkloadBackendKnative,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "module.exports = { main: function() {} }",
+					},
+				},
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, crdInstalled bool, objs ...client.Object) *fsm.StateMachine {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		if crdInstalled {
+			mapper.Add(resources.KnativeServiceGVK, meta.RESTScopeNamespace)
+		}
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build(),
+			Scheme: scheme,
+			FunctionConfig: config.FunctionConfig{
+				Images:          config.ImagesConfig{NodeJs22: "test-image-nodejs22"},
+				RequeueDuration: time.Minute,
+			},
+		}
+	}
+	ownedService := func(t *testing.T, f serverlessv1alpha2.Function) *unstructured.Unstructured {
+		d := resources.NewDeployment(&f, &config.FunctionConfig{Images: config.ImagesConfig{NodeJs22: "test-image-nodejs22"}}, nil, "", nil, "")
+		service, err := resources.NewKnativeService(&f, d)
+		require.NoError(t, err)
+		require.NoError(t, controllerutil.SetControllerReference(&f, service, scheme))
+		return service
+	}
+	getService := func(m *fsm.StateMachine) (*unstructured.Unstructured, error) {
+		service := &unstructured.Unstructured{}
+		service.SetGroupVersionKind(resources.KnativeServiceGVK)
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function"}, service)
+		return service, err
+	}
+
+	t.Run("stop when Knative Serving isn't installed", func(t *testing.T) {
+		// Arrange
+		m := newMachine(knativeFunction(), false)
+
+		// Act
+		next, result, err := sFnHandleKnativeService(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			"Knative Service CRD is not installed")
+	})
+	t.Run("create Knative Service when it doesn't exist", func(t *testing.T) {
+		// Arrange
+		m := newMachine(knativeFunction(), true)
+
+		// Act
+		next, result, err := sFnHandleKnativeService(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		service, err := getService(m)
+		require.NoError(t, err)
+		require.Equal(t, "test-function", service.GetOwnerReferences()[0].Name)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonKnativeServiceCreated,
+			"Knative Service test-function created")
+	})
+	t.Run("keep up-to-date Knative Service and go to the next state", func(t *testing.T) {
+		// Arrange
+		f := knativeFunction()
+		m := newMachine(f, true, ownedService(t, f))
+
+		// Act
+		next, result, err := sFnHandleKnativeService(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+	})
+	t.Run("update changed Knative Service", func(t *testing.T) {
+		// Arrange
+		f := knativeFunction()
+		service := ownedService(t, f)
+		f.Spec.Env = append(f.Spec.Env, corev1.EnvVar{Name: "CHANGED", Value: "true"})
+		m := newMachine(f, true, service)
+
+		// Act
+		next, result, err := sFnHandleKnativeService(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonKnativeServiceUpdated,
+			"Knative Service test-function updated")
+	})
+	t.Run("don't take over Knative Service owned by someone else", func(t *testing.T) {
+		// Arrange
+		f := knativeFunction()
+		service := ownedService(t, f)
+		service.SetOwnerReferences(nil)
+		m := newMachine(f, true, service)
+
+		// Act
+		next, result, err := sFnHandleKnativeService(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			"Knative Service test-function already exists and isn't owned by the function")
+	})
+}
+
+func Test_sFnKnativeServiceStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	newMachine := func(conditionStatus string) *fsm.StateMachine {
+		service := &unstructured.Unstructured{}
+		service.SetGroupVersionKind(resources.KnativeServiceGVK)
+		service.SetName("test-function")
+		service.SetNamespace("test-namespace")
+		service.Object["status"] = map[string]interface{}{
+			"url": "http://test-function.test-namespace.example.com",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": conditionStatus, "message": "Revision failed"},
+			},
+		}
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(resources.KnativeServiceGVK, meta.RESTScopeNamespace)
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "test-namespace",
+					},
+				},
+			},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(service).Build(),
+			FunctionConfig: config.FunctionConfig{
+				RequeueDuration: time.Minute,
+			},
+		}
+	}
+
+	t.Run("set ready condition and url when Knative Service is ready", func(t *testing.T) {
+		// Arrange
+		m := newMachine("True")
+
+		// Act
+		next, result, err := sFnKnativeServiceStatus(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnAdjustStatus, next)
+		require.Equal(t, "http://test-function.test-namespace.example.com", m.State.Function.Status.URL)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonKnativeServiceReady,
+			"Knative Service test-function is ready")
+	})
+	t.Run("set failed condition and requeue when Knative Service failed", func(t *testing.T) {
+		// Arrange
+		m := newMachine("False")
+
+		// Act
+		next, result, err := sFnKnativeServiceStatus(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, time.Minute, result.RequeueAfter)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonKnativeServiceFailed,
+			"Knative Service test-function failed: Revision failed")
+	})
+	t.Run("set waiting condition and requeue when Knative Service isn't ready", func(t *testing.T) {
+		// Arrange
+		m := newMachine("Unknown")
+
+		// Act
+		next, result, err := sFnKnativeServiceStatus(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, time.Minute, result.RequeueAfter)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonKnativeServiceWaiting,
+			"Knative Service test-function is not ready yet")
+	})
+}
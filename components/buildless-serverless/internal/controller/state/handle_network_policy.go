@@ -0,0 +1,154 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleNetworkPolicy keeps the NetworkPolicy restricting the traffic of the function's Pods in sync with its networkPolicy
+func sFnHandleNetworkPolicy(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	clusterPolicy, err := getNetworkPolicy(ctx, m)
+	if err != nil {
+		return stopWithError(err)
+	}
+
+	if f.Spec.NetworkPolicy == nil {
+		if err := deleteNetworkPolicy(ctx, m, clusterPolicy); err != nil {
+			return stopWithError(err)
+		}
+		return nextState(sFnHandleAuthPolicy)
+	}
+
+	builtPolicy := resources.NewNetworkPolicy(f, &m.FunctionConfig)
+	if clusterPolicy == nil {
+		result, errCreate := createNetworkPolicy(ctx, m, builtPolicy)
+		return nil, result, errCreate
+	}
+	if !metav1.IsControlledBy(clusterPolicy, f) {
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyFailed,
+			fmt.Sprintf("NetworkPolicy %s already exists and isn't owned by the function", clusterPolicy.GetName()))
+		return stop()
+	}
+
+	requeueNeeded, errUpdate := updateNetworkPolicyIfNeeded(ctx, m, clusterPolicy, builtPolicy)
+	if errUpdate != nil {
+		return stopWithError(errUpdate)
+	}
+	if requeueNeeded {
+		return requeue()
+	}
+	return nextState(sFnHandleAuthPolicy)
+}
+
+func getNetworkPolicy(ctx context.Context, m *fsm.StateMachine) (*networkingv1.NetworkPolicy, error) {
+	policy := &networkingv1.NetworkPolicy{}
+	f := m.State.Function
+	err := m.Client.Get(ctx, client.ObjectKey{
+		Namespace: f.GetNamespace(),
+		Name:      f.GetName(),
+	}, policy)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch NetworkPolicy for Function")
+		return nil, err
+	}
+	return policy, nil
+}
+
+func createNetworkPolicy(ctx context.Context, m *fsm.StateMachine, policy *networkingv1.NetworkPolicy) (*ctrl.Result, error) {
+	m.Log.Info("creating a new NetworkPolicy", "NetworkPolicy.Namespace", policy.GetNamespace(), "NetworkPolicy.Name", policy.GetName())
+
+	// Set the ownerRef for the NetworkPolicy, ensuring that the NetworkPolicy
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, policy, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new NetworkPolicy", "NetworkPolicy.Namespace", policy.GetNamespace(), "NetworkPolicy.Name", policy.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyFailed,
+			fmt.Sprintf("NetworkPolicy %s create failed: %s", policy.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, policy); err != nil {
+		m.Log.Error(err, "failed to create new NetworkPolicy", "NetworkPolicy.Namespace", policy.GetNamespace(), "NetworkPolicy.Name", policy.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyFailed,
+			fmt.Sprintf("NetworkPolicy %s create failed: %s", policy.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonNetworkPolicyCreated,
+		fmt.Sprintf("NetworkPolicy %s created", policy.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func updateNetworkPolicyIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterPolicy, builtPolicy *networkingv1.NetworkPolicy) (requeueNeeded bool, err error) {
+	if !networkPolicyChanged(clusterPolicy, builtPolicy) {
+		return false, nil
+	}
+
+	m.Log.Info("updating NetworkPolicy", "NetworkPolicy.Namespace", clusterPolicy.GetNamespace(), "NetworkPolicy.Name", clusterPolicy.GetName())
+	clusterPolicy.Spec = builtPolicy.Spec
+	clusterPolicy.ObjectMeta.Labels = builtPolicy.GetLabels()
+	if err := m.Client.Update(ctx, clusterPolicy); err != nil {
+		m.Log.Error(err, "failed to update NetworkPolicy", "NetworkPolicy.Namespace", clusterPolicy.GetNamespace(), "NetworkPolicy.Name", clusterPolicy.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyFailed,
+			fmt.Sprintf("NetworkPolicy %s update failed: %s", clusterPolicy.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonNetworkPolicyUpdated,
+		fmt.Sprintf("NetworkPolicy %s updated", clusterPolicy.GetName()))
+	return true, nil
+}
+
+func networkPolicyChanged(a, b *networkingv1.NetworkPolicy) bool {
+	return !mapsEqual(a.Labels, b.Labels) ||
+		!reflect.DeepEqual(a.Spec.PodSelector, b.Spec.PodSelector) ||
+		!reflect.DeepEqual(a.Spec.PolicyTypes, b.Spec.PolicyTypes) ||
+		!reflect.DeepEqual(a.Spec.Ingress, b.Spec.Ingress) ||
+		!reflect.DeepEqual(a.Spec.Egress, b.Spec.Egress)
+}
+
+// deleteNetworkPolicy removes the given NetworkPolicy when it's owned by the function
+func deleteNetworkPolicy(ctx context.Context, m *fsm.StateMachine, policy *networkingv1.NetworkPolicy) error {
+	if policy == nil || !metav1.IsControlledBy(policy, &m.State.Function) {
+		return nil
+	}
+	m.Log.Info("deleting NetworkPolicy", "NetworkPolicy.Namespace", policy.GetNamespace(), "NetworkPolicy.Name", policy.GetName())
+	err := m.Client.Delete(ctx, policy)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete NetworkPolicy", "NetworkPolicy.Namespace", policy.GetNamespace(), "NetworkPolicy.Name", policy.GetName())
+		return err
+	}
+	return nil
+}
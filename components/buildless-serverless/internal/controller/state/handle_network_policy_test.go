@@ -0,0 +1,169 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleNetworkPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, networkingv1.AddToScheme(scheme))
+
+	restrictedFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.NetworkPolicy{
+					Ingress: &serverlessv1alpha2.NetworkPolicyIngress{},
+				},
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, objs ...client.Object) *fsm.StateMachine {
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			Scheme:         scheme,
+			FunctionConfig: config.FunctionConfig{},
+		}
+	}
+	ownedPolicy := func(t *testing.T, f serverlessv1alpha2.Function) *networkingv1.NetworkPolicy {
+		policy := resources.NewNetworkPolicy(&f, &config.FunctionConfig{})
+		require.NoError(t, controllerutil.SetControllerReference(&f, policy, scheme))
+		return policy
+	}
+	getPolicy := func(m *fsm.StateMachine) (*networkingv1.NetworkPolicy, error) {
+		policy := &networkingv1.NetworkPolicy{}
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function"}, policy)
+		return policy, err
+	}
+
+	t.Run("go to the next state when function has no network policy", func(t *testing.T) {
+		// Arrange
+		f := restrictedFunction()
+		f.Spec.NetworkPolicy = nil
+		m := newMachine(f)
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleAuthPolicy, next)
+	})
+	t.Run("create NetworkPolicy when it doesn't exist", func(t *testing.T) {
+		// Arrange
+		m := newMachine(restrictedFunction())
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		policy, err := getPolicy(m)
+		require.NoError(t, err)
+		require.Equal(t, "test-function", policy.GetOwnerReferences()[0].Name)
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyCreated,
+			"NetworkPolicy test-function created")
+	})
+	t.Run("keep up-to-date NetworkPolicy and go to the next state", func(t *testing.T) {
+		// Arrange
+		f := restrictedFunction()
+		m := newMachine(f, ownedPolicy(t, f))
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleAuthPolicy, next)
+	})
+	t.Run("update changed NetworkPolicy", func(t *testing.T) {
+		// Arrange
+		f := restrictedFunction()
+		policy := ownedPolicy(t, f)
+		f.Spec.NetworkPolicy.Egress = &serverlessv1alpha2.NetworkPolicyEgress{}
+		m := newMachine(f, policy)
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		policy, err = getPolicy(m)
+		require.NoError(t, err)
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyUpdated,
+			"NetworkPolicy test-function updated")
+	})
+	t.Run("don't take over NetworkPolicy owned by someone else", func(t *testing.T) {
+		// Arrange
+		f := restrictedFunction()
+		policy := ownedPolicy(t, f)
+		policy.SetOwnerReferences(nil)
+		m := newMachine(f, policy)
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyFailed,
+			"NetworkPolicy test-function already exists and isn't owned by the function")
+	})
+	t.Run("remove NetworkPolicy when network policy is removed", func(t *testing.T) {
+		// Arrange
+		f := restrictedFunction()
+		policy := ownedPolicy(t, f)
+		f.Spec.NetworkPolicy = nil
+		m := newMachine(f, policy)
+
+		// Act
+		next, _, err := sFnHandleNetworkPolicy(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleAuthPolicy, next)
+		_, err = getPolicy(m)
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+}
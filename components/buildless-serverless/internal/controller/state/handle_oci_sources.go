@@ -0,0 +1,66 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const ociResolveTimeout = 10 * time.Second
+
+// resolveOCIDigest is a variable to allow replacing registry calls in tests
+var resolveOCIDigest = oci.ResolveDigest
+
+func sFnHandleOCISources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if !m.State.Function.HasOCISources() {
+		return nextState(sFnHandleArchiveSources)
+	}
+
+	ociSource := m.State.Function.Spec.Source.OCI
+
+	var creds *oci.Credentials
+	if m.State.Function.HasOCIPullSecret() {
+		var err error
+		creds, err = oci.NewCredentials(ctx, m.Client, m.State.Function.GetNamespace(), ociSource.PullSecretName)
+		if err != nil {
+			m.State.Function.UpdateCondition(
+				serverlessv1alpha2.ConditionConfigurationReady,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+				fmt.Sprintf("Getting OCI registry credentials failed: %s", err.Error()))
+			return stopWithError(err)
+		}
+	}
+
+	resolveCtx, cancel := context.WithTimeout(ctx, ociResolveTimeout)
+	defer cancel()
+
+	digest, err := resolveOCIDigest(resolveCtx, ociSource.Reference, creds)
+	if err != nil {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			fmt.Sprintf("OCI artifact: %s source check failed: %s", ociSource.Reference, err.Error()))
+		return stopWithError(err)
+	}
+
+	status := m.State.Function.Status.OCI
+	if status == nil || status.Reference != ociSource.Reference || status.Digest != digest {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSourceUpdated,
+			"Function source updated")
+	}
+
+	m.State.OCIDigest = digest
+
+	return nextState(sFnConfigurationReady)
+}
@@ -0,0 +1,222 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testOCIDigest = "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+
+func Test_sFnHandleOCISources(t *testing.T) {
+	t.Run("for non oci function move to the nextState", func(t *testing.T) {
+		// Arrange
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							Inline: &serverlessv1alpha2.InlineSource{
+								Source: "source"}}}}},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleOCISources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleArchiveSources, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Empty(t, m.State.OCIDigest)
+	})
+	t.Run("for oci function resolve digest and move to the nextState", func(t *testing.T) {
+		// Arrange
+		stubResolveOCIDigest(t, func(_ context.Context, reference string, creds *oci.Credentials) (string, error) {
+			require.Equal(t, "ghcr.io/user/function:v1", reference)
+			require.Nil(t, creds)
+			return testOCIDigest, nil
+		})
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							OCI: &serverlessv1alpha2.OCISource{
+								Reference: "ghcr.io/user/function:v1"}}}}},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleOCISources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSourceUpdated,
+			"Function source updated")
+		require.Equal(t, testOCIDigest, m.State.OCIDigest)
+	})
+	t.Run("for unchanged digest do not update condition", func(t *testing.T) {
+		// Arrange
+		stubResolveOCIDigest(t, func(_ context.Context, _ string, _ *oci.Credentials) (string, error) {
+			return testOCIDigest, nil
+		})
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							OCI: &serverlessv1alpha2.OCISource{
+								Reference: "ghcr.io/user/function:v1"}}},
+					Status: serverlessv1alpha2.FunctionStatus{
+						OCI: &serverlessv1alpha2.OCISourceStatus{
+							Reference: "ghcr.io/user/function:v1",
+							Digest:    testOCIDigest}}}},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleOCISources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Equal(t, testOCIDigest, m.State.OCIDigest)
+	})
+	t.Run("for oci function with pull secret use credentials", func(t *testing.T) {
+		// Arrange
+		stubResolveOCIDigest(t, func(_ context.Context, _ string, creds *oci.Credentials) (string, error) {
+			require.NotNil(t, creds)
+			return testOCIDigest, nil
+		})
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "registry-credentials",
+				Namespace: "default"},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"ghcr.io":{"username":"user","password":"pass"}}}`)},
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							OCI: &serverlessv1alpha2.OCISource{
+								Reference:      "ghcr.io/user/function:v1",
+								PullSecretName: "registry-credentials"}}}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithObjects(secret).Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleOCISources(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnConfigurationReady, next)
+		require.Equal(t, testOCIDigest, m.State.OCIDigest)
+	})
+	t.Run("when pull secret does not exist stop with condition", func(t *testing.T) {
+		// Arrange
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							OCI: &serverlessv1alpha2.OCISource{
+								Reference:      "ghcr.io/user/function:v1",
+								PullSecretName: "registry-credentials"}}}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandleOCISources(context.Background(), &m)
+
+		// Assert
+		require.NotNil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"Getting OCI registry credentials failed: failed to get pull secret: secrets \"registry-credentials\" not found")
+	})
+	t.Run("when digest resolution fails stop with condition", func(t *testing.T) {
+		// Arrange
+		stubResolveOCIDigest(t, func(_ context.Context, _ string, _ *oci.Credentials) (string, error) {
+			return "", errors.New("test-error")
+		})
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							OCI: &serverlessv1alpha2.OCISource{
+								Reference: "ghcr.io/user/function:v1"}}}}},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandleOCISources(context.Background(), &m)
+
+		// Assert
+		require.ErrorContains(t, err, "test-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSourceUpdateFailed,
+			"OCI artifact: ghcr.io/user/function:v1 source check failed: test-error")
+	})
+}
+
+func stubResolveOCIDigest(t *testing.T, fn func(context.Context, string, *oci.Credentials) (string, error)) {
+	original := resolveOCIDigest
+	resolveOCIDigest = fn
+	t.Cleanup(func() {
+		resolveOCIDigest = original
+	})
+}
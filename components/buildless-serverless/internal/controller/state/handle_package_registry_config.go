@@ -0,0 +1,31 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/registry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// sFnHandlePackageRegistryConfig validates the package registry config Secret referenced by the function
+// the cluster-wide config is optional and isn't validated
+func sFnHandlePackageRegistryConfig(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if !m.State.Function.HasPackageRegistryConfig() {
+		return nextState(sFnHandleGitSources)
+	}
+
+	if err := registry.ValidateSecret(ctx, m.Client, &m.State.Function); err != nil {
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPackageRegistryConfigInvalid,
+			fmt.Sprintf("Package registry config %s is invalid: %s", m.State.Function.Spec.PackageRegistryConfig.SecretName, err.Error()))
+		return stopWithError(err)
+	}
+
+	return nextState(sFnHandleGitSources)
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sFnHandlePackageRegistryConfig(t *testing.T) {
+	t.Run("skip when function uses cluster-wide package registry config", func(t *testing.T) {
+		// Arrange
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: minimalInlineFunction()},
+			Log: zap.NewNop().Sugar(),
+		}
+
+		// Act
+		next, result, err := sFnHandlePackageRegistryConfig(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleGitSources, next)
+	})
+	t.Run("move to the nextState when function package registry config is valid", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		f.Spec.PackageRegistryConfig = &serverlessv1alpha2.PackageRegistryConfig{SecretName: "team-registry-config"}
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-registry-config", Namespace: "test-namespace"},
+			Data: map[string][]byte{
+				".npmrc": []byte("registry=https://npm.example.com/"),
+			},
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithObjects(s).Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandlePackageRegistryConfig(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleGitSources, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("stop and set condition when function package registry config is invalid", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		f.Spec.PackageRegistryConfig = &serverlessv1alpha2.PackageRegistryConfig{SecretName: "team-registry-config"}
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-registry-config", Namespace: "test-namespace"},
+			Data: map[string][]byte{
+				"pip.conf": []byte("[global]"),
+			},
+		}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithObjects(s).Build(),
+		}
+
+		// Act
+		next, result, err := sFnHandlePackageRegistryConfig(context.Background(), &m)
+
+		// Assert
+		require.EqualError(t, err, "missing '.npmrc'")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPackageRegistryConfigInvalid,
+			"Package registry config team-registry-config is invalid: missing '.npmrc'")
+	})
+}
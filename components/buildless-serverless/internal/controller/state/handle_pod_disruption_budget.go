@@ -0,0 +1,157 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandlePodDisruptionBudget keeps the PodDisruptionBudget protecting the function's Pods in sync with its disruptionBudget
+func sFnHandlePodDisruptionBudget(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	clusterBudget, err := getPodDisruptionBudget(ctx, m)
+	if err != nil {
+		return stopWithError(err)
+	}
+
+	if f.Spec.DisruptionBudget == nil {
+		if err := deletePodDisruptionBudget(ctx, m, clusterBudget); err != nil {
+			return stopWithError(err)
+		}
+		m.State.ClusterDisruptionBudget = nil
+		return nextState(sFnHandleScaledObject)
+	}
+
+	builtBudget := resources.NewPodDisruptionBudget(f)
+	if clusterBudget == nil {
+		result, errCreate := createPodDisruptionBudget(ctx, m, builtBudget)
+		return nil, result, errCreate
+	}
+	if !metav1.IsControlledBy(clusterBudget, f) {
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed,
+			fmt.Sprintf("PodDisruptionBudget %s already exists and isn't owned by the function", clusterBudget.GetName()))
+		return stop()
+	}
+
+	requeueNeeded, errUpdate := updatePodDisruptionBudgetIfNeeded(ctx, m, clusterBudget, builtBudget)
+	if errUpdate != nil {
+		return stopWithError(errUpdate)
+	}
+	if requeueNeeded {
+		return requeue()
+	}
+	m.State.ClusterDisruptionBudget = clusterBudget
+	return nextState(sFnHandleScaledObject)
+}
+
+func getPodDisruptionBudget(ctx context.Context, m *fsm.StateMachine) (*policyv1.PodDisruptionBudget, error) {
+	budget := &policyv1.PodDisruptionBudget{}
+	f := m.State.Function
+	err := m.Client.Get(ctx, client.ObjectKey{
+		Namespace: f.GetNamespace(),
+		Name:      f.GetName(),
+	}, budget)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch PodDisruptionBudget for Function")
+		return nil, err
+	}
+	return budget, nil
+}
+
+func createPodDisruptionBudget(ctx context.Context, m *fsm.StateMachine, budget *policyv1.PodDisruptionBudget) (*ctrl.Result, error) {
+	m.Log.Info("creating a new PodDisruptionBudget", "PodDisruptionBudget.Namespace", budget.GetNamespace(), "PodDisruptionBudget.Name", budget.GetName())
+
+	// Set the ownerRef for the PodDisruptionBudget, ensuring that the PodDisruptionBudget
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, budget, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new PodDisruptionBudget", "PodDisruptionBudget.Namespace", budget.GetNamespace(), "PodDisruptionBudget.Name", budget.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed,
+			fmt.Sprintf("PodDisruptionBudget %s create failed: %s", budget.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, budget); err != nil {
+		m.Log.Error(err, "failed to create new PodDisruptionBudget", "PodDisruptionBudget.Namespace", budget.GetNamespace(), "PodDisruptionBudget.Name", budget.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed,
+			fmt.Sprintf("PodDisruptionBudget %s create failed: %s", budget.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonPodDisruptionBudgetCreated,
+		fmt.Sprintf("PodDisruptionBudget %s created", budget.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func updatePodDisruptionBudgetIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterBudget, builtBudget *policyv1.PodDisruptionBudget) (requeueNeeded bool, err error) {
+	if !podDisruptionBudgetChanged(clusterBudget, builtBudget) {
+		return false, nil
+	}
+
+	m.Log.Info("updating PodDisruptionBudget", "PodDisruptionBudget.Namespace", clusterBudget.GetNamespace(), "PodDisruptionBudget.Name", clusterBudget.GetName())
+	clusterBudget.Spec.Selector = builtBudget.Spec.Selector
+	clusterBudget.Spec.MinAvailable = builtBudget.Spec.MinAvailable
+	clusterBudget.Spec.MaxUnavailable = builtBudget.Spec.MaxUnavailable
+	clusterBudget.ObjectMeta.Labels = builtBudget.GetLabels()
+	if err := m.Client.Update(ctx, clusterBudget); err != nil {
+		m.Log.Error(err, "failed to update PodDisruptionBudget", "PodDisruptionBudget.Namespace", clusterBudget.GetNamespace(), "PodDisruptionBudget.Name", clusterBudget.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed,
+			fmt.Sprintf("PodDisruptionBudget %s update failed: %s", clusterBudget.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonPodDisruptionBudgetUpdated,
+		fmt.Sprintf("PodDisruptionBudget %s updated", clusterBudget.GetName()))
+	return true, nil
+}
+
+func podDisruptionBudgetChanged(a, b *policyv1.PodDisruptionBudget) bool {
+	return !mapsEqual(a.Labels, b.Labels) ||
+		!reflect.DeepEqual(a.Spec.Selector, b.Spec.Selector) ||
+		!reflect.DeepEqual(a.Spec.MinAvailable, b.Spec.MinAvailable) ||
+		!reflect.DeepEqual(a.Spec.MaxUnavailable, b.Spec.MaxUnavailable)
+}
+
+// deletePodDisruptionBudget removes the given PodDisruptionBudget when it's owned by the function
+func deletePodDisruptionBudget(ctx context.Context, m *fsm.StateMachine, budget *policyv1.PodDisruptionBudget) error {
+	if budget == nil || !metav1.IsControlledBy(budget, &m.State.Function) {
+		return nil
+	}
+	m.Log.Info("deleting PodDisruptionBudget", "PodDisruptionBudget.Namespace", budget.GetNamespace(), "PodDisruptionBudget.Name", budget.GetName())
+	err := m.Client.Delete(ctx, budget)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete PodDisruptionBudget", "PodDisruptionBudget.Namespace", budget.GetNamespace(), "PodDisruptionBudget.Name", budget.GetName())
+		return err
+	}
+	return nil
+}
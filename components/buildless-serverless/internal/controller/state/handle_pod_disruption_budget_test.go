@@ -0,0 +1,174 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandlePodDisruptionBudget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	protectedFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				DisruptionBudget: &serverlessv1alpha2.DisruptionBudget{
+					MinAvailable: ptr.To(intstr.FromInt32(1)),
+				},
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, objs ...client.Object) *fsm.StateMachine {
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+	ownedBudget := func(t *testing.T, f serverlessv1alpha2.Function) *policyv1.PodDisruptionBudget {
+		budget := resources.NewPodDisruptionBudget(&f)
+		require.NoError(t, controllerutil.SetControllerReference(&f, budget, scheme))
+		return budget
+	}
+	getBudget := func(m *fsm.StateMachine) (*policyv1.PodDisruptionBudget, error) {
+		budget := &policyv1.PodDisruptionBudget{}
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function"}, budget)
+		return budget, err
+	}
+
+	t.Run("go to the next state when function has no disruption budget", func(t *testing.T) {
+		// Arrange
+		f := protectedFunction()
+		f.Spec.DisruptionBudget = nil
+		m := newMachine(f)
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		require.Nil(t, m.State.ClusterDisruptionBudget)
+	})
+	t.Run("create PodDisruptionBudget when it doesn't exist", func(t *testing.T) {
+		// Arrange
+		m := newMachine(protectedFunction())
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		budget, err := getBudget(m)
+		require.NoError(t, err)
+		require.Equal(t, "test-function", budget.GetOwnerReferences()[0].Name)
+		require.Equal(t, ptr.To(intstr.FromInt32(1)), budget.Spec.MinAvailable)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetCreated,
+			"PodDisruptionBudget test-function created")
+	})
+	t.Run("keep up-to-date PodDisruptionBudget and go to the next state", func(t *testing.T) {
+		// Arrange
+		f := protectedFunction()
+		m := newMachine(f, ownedBudget(t, f))
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		require.Equal(t, "test-function", m.State.ClusterDisruptionBudget.GetName())
+	})
+	t.Run("update changed PodDisruptionBudget", func(t *testing.T) {
+		// Arrange
+		f := protectedFunction()
+		budget := ownedBudget(t, f)
+		f.Spec.DisruptionBudget = &serverlessv1alpha2.DisruptionBudget{
+			MaxUnavailable: ptr.To(intstr.FromString("50%")),
+		}
+		m := newMachine(f, budget)
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, true, result.Requeue)
+		require.Nil(t, next)
+		budget, err = getBudget(m)
+		require.NoError(t, err)
+		require.Nil(t, budget.Spec.MinAvailable)
+		require.Equal(t, ptr.To(intstr.FromString("50%")), budget.Spec.MaxUnavailable)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetUpdated,
+			"PodDisruptionBudget test-function updated")
+	})
+	t.Run("don't take over PodDisruptionBudget owned by someone else", func(t *testing.T) {
+		// Arrange
+		f := protectedFunction()
+		budget := ownedBudget(t, f)
+		budget.SetOwnerReferences(nil)
+		m := newMachine(f, budget)
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed,
+			"PodDisruptionBudget test-function already exists and isn't owned by the function")
+	})
+	t.Run("remove PodDisruptionBudget when disruption budget is removed", func(t *testing.T) {
+		// Arrange
+		f := protectedFunction()
+		budget := ownedBudget(t, f)
+		f.Spec.DisruptionBudget = nil
+		m := newMachine(f, budget)
+
+		// Act
+		next, _, err := sFnHandlePodDisruptionBudget(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		_, err = getBudget(m)
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,75 @@
+package state
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/sbom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleSBOM stores the bill of materials of the function in the owned ConfigMap when it's enabled
+// failure of the SBOM generation doesn't block the function
+func sFnHandleSBOM(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if !m.FunctionConfig.SBOMConfigMapEnabled {
+		return nextState(sFnWorkloadStatus)
+	}
+
+	doc, err := sbom.Build(&m.State.Function, m.State.BuiltDeployment.RuntimeImage(), m.State.Commit)
+	if err != nil {
+		m.Log.Error(err, "failed to build SBOM for Function")
+		return nextState(sFnWorkloadStatus)
+	}
+
+	builtConfigMap, err := resources.NewSBOMConfigMap(&m.State.Function, doc)
+	if err != nil {
+		m.Log.Error(err, "failed to build SBOM ConfigMap for Function")
+		return nextState(sFnWorkloadStatus)
+	}
+
+	clusterConfigMap := &corev1.ConfigMap{}
+	err = m.Client.Get(ctx, client.ObjectKeyFromObject(builtConfigMap), clusterConfigMap)
+	if errors.IsNotFound(err) {
+		if err := createSBOMConfigMap(ctx, m, builtConfigMap); err != nil {
+			return stopWithError(err)
+		}
+		return nextState(sFnWorkloadStatus)
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch SBOM ConfigMap for Function")
+		return stopWithError(err)
+	}
+
+	if !reflect.DeepEqual(clusterConfigMap.Data, builtConfigMap.Data) {
+		m.Log.Info("updating SBOM ConfigMap", "ConfigMap.Namespace", clusterConfigMap.GetNamespace(), "ConfigMap.Name", clusterConfigMap.GetName())
+		clusterConfigMap.Data = builtConfigMap.Data
+		if err := m.Client.Update(ctx, clusterConfigMap); err != nil {
+			m.Log.Error(err, "failed to update SBOM ConfigMap", "ConfigMap.Namespace", clusterConfigMap.GetNamespace(), "ConfigMap.Name", clusterConfigMap.GetName())
+			return stopWithError(err)
+		}
+	}
+	return nextState(sFnWorkloadStatus)
+}
+
+func createSBOMConfigMap(ctx context.Context, m *fsm.StateMachine, configMap *corev1.ConfigMap) error {
+	m.Log.Info("creating a new SBOM ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+
+	// Set the ownerRef for the ConfigMap, ensuring that the ConfigMap
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, configMap, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+		return err
+	}
+
+	if err := m.Client.Create(ctx, configMap); err != nil && !errors.IsAlreadyExists(err) {
+		m.Log.Error(err, "failed to create new ConfigMap", "ConfigMap.Namespace", configMap.GetNamespace(), "ConfigMap.Name", configMap.GetName())
+		return err
+	}
+	return nil
+}
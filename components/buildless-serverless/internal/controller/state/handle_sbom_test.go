@@ -0,0 +1,150 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_sFnHandleSBOM(t *testing.T) {
+	// sbom is built from the runtime files placed in the components directory
+	t.Chdir("../../../..")
+
+	fc := config.FunctionConfig{
+		SBOMConfigMapEnabled: true,
+		Images: config.ImagesConfig{
+			NodeJs22: "function-runtime-nodejs22:1.2.3",
+		},
+	}
+
+	t.Run("skip when sbom configmap is disabled", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		scheme := minimalInlineScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: config.FunctionConfig{},
+		}
+
+		// Act
+		next, result, err := sFnHandleSBOM(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnWorkloadStatus, next)
+		configMaps := &corev1.ConfigMapList{}
+		require.NoError(t, k8sClient.List(context.Background(), configMaps))
+		require.Empty(t, configMaps.Items)
+	})
+	t.Run("create sbom configmap and move to the nextState", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		scheme := minimalInlineScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:        f,
+				BuiltDeployment: resources.NewDeployment(&f, &fc, nil, "", nil, "")},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fc,
+		}
+
+		// Act
+		next, result, err := sFnHandleSBOM(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnWorkloadStatus, next)
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+			Namespace: "test-namespace",
+			Name:      "test-function-sbom",
+		}, cm))
+		require.Contains(t, cm.Data[resources.SBOMKey], `"bomFormat": "CycloneDX"`)
+		require.Contains(t, cm.Data[resources.SBOMKey], `"bom-ref": "function-runtime-nodejs22:1.2.3"`)
+		require.Len(t, cm.GetOwnerReferences(), 1)
+		require.Equal(t, "test-function", cm.GetOwnerReferences()[0].Name)
+	})
+	t.Run("update outdated sbom configmap", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		outdated := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-sbom",
+				Namespace: "test-namespace",
+			},
+			Data: map[string]string{
+				resources.SBOMKey: "{}",
+			},
+		}
+		scheme := minimalInlineScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(outdated).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:        f,
+				BuiltDeployment: resources.NewDeployment(&f, &fc, nil, "", nil, "")},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fc,
+		}
+
+		// Act
+		next, result, err := sFnHandleSBOM(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnWorkloadStatus, next)
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(outdated), cm))
+		require.Contains(t, cm.Data[resources.SBOMKey], `"bomFormat": "CycloneDX"`)
+	})
+	t.Run("stop when sbom configmap can't be created", func(t *testing.T) {
+		// Arrange
+		f := minimalInlineFunction()
+		scheme := minimalInlineScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return errors.New("quota exceeded")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function:        f,
+				BuiltDeployment: resources.NewDeployment(&f, &fc, nil, "", nil, "")},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fc,
+		}
+
+		// Act
+		next, result, err := sFnHandleSBOM(context.Background(), &m)
+
+		// Assert
+		require.ErrorContains(t, err, "quota exceeded")
+		require.Nil(t, result)
+		require.Nil(t, next)
+	})
+}
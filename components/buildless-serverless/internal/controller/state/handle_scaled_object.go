@@ -0,0 +1,182 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleScaledObject keeps the KEDA ScaledObject scaling the function's Deployment in sync with its triggers
+// and reports its readiness in the ScalingReady condition
+func sFnHandleScaledObject(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	_, err := m.Client.RESTMapper().RESTMapping(resources.ScaledObjectGVK.GroupKind(), resources.ScaledObjectGVK.Version)
+	if meta.IsNoMatchError(err) {
+		if !f.HasScaleTriggers() {
+			meta.RemoveStatusCondition(&f.Status.Conditions, string(serverlessv1alpha2.ConditionScalingReady))
+			return nextState(sFnHandleExpose)
+		}
+		updateScalingCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			fmt.Sprintf("%s CRD is not installed", resources.ScaledObjectGVK.Kind))
+		return nextState(sFnHandleExpose)
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to check if ScaledObject CRD is installed")
+		return stopWithError(err)
+	}
+
+	clusterScaledObject, err := getScaledObject(ctx, m)
+	if err != nil {
+		return stopWithError(err)
+	}
+
+	if !f.HasScaleTriggers() {
+		if err := deleteScaledObject(ctx, m, clusterScaledObject); err != nil {
+			return stopWithError(err)
+		}
+		meta.RemoveStatusCondition(&f.Status.Conditions, string(serverlessv1alpha2.ConditionScalingReady))
+		return nextState(sFnHandleExpose)
+	}
+
+	builtScaledObject := resources.NewScaledObject(f, m.State.ClusterDeployment.GetName())
+	if clusterScaledObject == nil {
+		if err := createScaledObject(ctx, m, builtScaledObject); err != nil {
+			return stopWithError(err)
+		}
+		updateScalingCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonScaledObjectNotReady,
+			fmt.Sprintf("ScaledObject %s created", builtScaledObject.GetName()))
+		return nextState(sFnHandleExpose)
+	}
+	if !metav1.IsControlledBy(clusterScaledObject, f) {
+		updateScalingCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			fmt.Sprintf("ScaledObject %s already exists and isn't owned by the function", clusterScaledObject.GetName()))
+		return nextState(sFnHandleExpose)
+	}
+
+	updated, err := updateScaledObjectIfNeeded(ctx, m, clusterScaledObject, builtScaledObject)
+	if err != nil {
+		return stopWithError(err)
+	}
+	if updated {
+		updateScalingCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonScaledObjectNotReady,
+			fmt.Sprintf("ScaledObject %s updated", clusterScaledObject.GetName()))
+		return nextState(sFnHandleExpose)
+	}
+
+	switch status, message := resources.ScaledObjectReadyStatus(clusterScaledObject); status {
+	case metav1.ConditionTrue:
+		updateScalingCondition(m, metav1.ConditionTrue, serverlessv1alpha2.ConditionReasonScaledObjectReady,
+			fmt.Sprintf("ScaledObject %s is ready", clusterScaledObject.GetName()))
+	case metav1.ConditionFalse:
+		updateScalingCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			fmt.Sprintf("ScaledObject %s failed: %s", clusterScaledObject.GetName(), message))
+	default:
+		updateScalingCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonScaledObjectNotReady,
+			fmt.Sprintf("ScaledObject %s is not ready yet", clusterScaledObject.GetName()))
+	}
+	return nextState(sFnHandleExpose)
+}
+
+func getScaledObject(ctx context.Context, m *fsm.StateMachine) (*unstructured.Unstructured, error) {
+	f := m.State.Function
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetGroupVersionKind(resources.ScaledObjectGVK)
+	err := m.Client.Get(ctx, client.ObjectKey{Namespace: f.GetNamespace(), Name: f.GetName()}, scaledObject)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch ScaledObject for Function")
+		return nil, err
+	}
+	return scaledObject, nil
+}
+
+func createScaledObject(ctx context.Context, m *fsm.StateMachine, scaledObject *unstructured.Unstructured) error {
+	m.Log.Info("creating a new ScaledObject", "ScaledObject.Namespace", scaledObject.GetNamespace(), "ScaledObject.Name", scaledObject.GetName())
+
+	// Set the ownerRef for the ScaledObject, ensuring that the ScaledObject
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, scaledObject, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new ScaledObject", "ScaledObject.Namespace", scaledObject.GetNamespace(), "ScaledObject.Name", scaledObject.GetName())
+		updateScalingCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			fmt.Sprintf("ScaledObject %s create failed: %s", scaledObject.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, scaledObject); err != nil {
+		m.Log.Error(err, "failed to create new ScaledObject", "ScaledObject.Namespace", scaledObject.GetNamespace(), "ScaledObject.Name", scaledObject.GetName())
+		updateScalingCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			fmt.Sprintf("ScaledObject %s create failed: %s", scaledObject.GetName(), err.Error()))
+		return err
+	}
+	return nil
+}
+
+func updateScaledObjectIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterScaledObject, builtScaledObject *unstructured.Unstructured) (bool, error) {
+	// KEDA defaults the spec, so only fields set by the controller are compared
+	if !exposeObjectChanged(clusterScaledObject, builtScaledObject) {
+		return false, nil
+	}
+
+	m.Log.Info("updating ScaledObject", "ScaledObject.Namespace", clusterScaledObject.GetNamespace(), "ScaledObject.Name", clusterScaledObject.GetName())
+	clusterScaledObject.Object["spec"] = builtScaledObject.Object["spec"]
+	clusterScaledObject.SetLabels(builtScaledObject.GetLabels())
+	if err := m.Client.Update(ctx, clusterScaledObject); err != nil {
+		m.Log.Error(err, "failed to update ScaledObject", "ScaledObject.Namespace", clusterScaledObject.GetNamespace(), "ScaledObject.Name", clusterScaledObject.GetName())
+		updateScalingCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			fmt.Sprintf("ScaledObject %s update failed: %s", clusterScaledObject.GetName(), err.Error()))
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteScaledObject removes the given ScaledObject when it's owned by the function
+func deleteScaledObject(ctx context.Context, m *fsm.StateMachine, scaledObject *unstructured.Unstructured) error {
+	if scaledObject == nil || !metav1.IsControlledBy(scaledObject, &m.State.Function) {
+		return nil
+	}
+	m.Log.Info("deleting ScaledObject", "ScaledObject.Namespace", scaledObject.GetNamespace(), "ScaledObject.Name", scaledObject.GetName())
+	err := m.Client.Delete(ctx, scaledObject)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		m.Log.Error(err, "failed to delete ScaledObject", "ScaledObject.Namespace", scaledObject.GetNamespace(), "ScaledObject.Name", scaledObject.GetName())
+		return err
+	}
+	return nil
+}
+
+// cleanupScaledObject removes the ScaledObject owned by the function, it's skipped when KEDA isn't installed
+func cleanupScaledObject(ctx context.Context, m *fsm.StateMachine) error {
+	_, err := m.Client.RESTMapper().RESTMapping(resources.ScaledObjectGVK.GroupKind(), resources.ScaledObjectGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	scaledObject, err := getScaledObject(ctx, m)
+	if err != nil {
+		return err
+	}
+	return deleteScaledObject(ctx, m, scaledObject)
+}
+
+func updateScalingCondition(m *fsm.StateMachine, status metav1.ConditionStatus, reason serverlessv1alpha2.ConditionReason, msg string) {
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionScalingReady,
+		status,
+		reason,
+		msg)
+}
@@ -0,0 +1,228 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleScaledObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+
+	scaledFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				ScaleConfig: &serverlessv1alpha2.ScaleConfig{
+					MinReplicas: ptr.To[int32](0),
+					MaxReplicas: ptr.To[int32](5),
+					Triggers: []serverlessv1alpha2.ScaleTrigger{
+						{Type: "prometheus", Metadata: map[string]string{"query": "sum(rate(http_requests_total[1m]))", "threshold": "10"}},
+					},
+				},
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, crdInstalled bool, objs ...client.Object) *fsm.StateMachine {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		if crdInstalled {
+			mapper.Add(resources.ScaledObjectGVK, meta.RESTScopeNamespace)
+		}
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f,
+				ClusterDeployment: &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-function-abcde", Namespace: "test-namespace"},
+				},
+			},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+	ownedScaledObject := func(t *testing.T, f serverlessv1alpha2.Function, conditions ...interface{}) *unstructured.Unstructured {
+		scaledObject := resources.NewScaledObject(&f, "test-function-abcde")
+		require.NoError(t, controllerutil.SetControllerReference(&f, scaledObject, scheme))
+		scaledObject.Object["status"] = map[string]interface{}{"conditions": conditions}
+		return scaledObject
+	}
+	getScaledObject := func(m *fsm.StateMachine) (*unstructured.Unstructured, error) {
+		scaledObject := &unstructured.Unstructured{}
+		scaledObject.SetGroupVersionKind(resources.ScaledObjectGVK)
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function"}, scaledObject)
+		return scaledObject, err
+	}
+
+	t.Run("skip function without triggers when KEDA isn't installed", func(t *testing.T) {
+		// Arrange
+		f := scaledFunction()
+		f.Spec.ScaleConfig = nil
+		m := newMachine(f, false)
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("set failed condition when KEDA isn't installed", func(t *testing.T) {
+		// Arrange
+		m := newMachine(scaledFunction(), false)
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			"ScaledObject CRD is not installed")
+	})
+	t.Run("create ScaledObject targeting function's deployment", func(t *testing.T) {
+		// Arrange
+		m := newMachine(scaledFunction(), true)
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		scaledObject, err := getScaledObject(m)
+		require.NoError(t, err)
+		require.Equal(t, "test-function", scaledObject.GetOwnerReferences()[0].Name)
+		target, _, _ := unstructured.NestedString(scaledObject.Object, "spec", "scaleTargetRef", "name")
+		require.Equal(t, "test-function-abcde", target)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScaledObjectNotReady,
+			"ScaledObject test-function created")
+	})
+	t.Run("update changed ScaledObject", func(t *testing.T) {
+		// Arrange
+		f := scaledFunction()
+		scaledObject := ownedScaledObject(t, f)
+		f.Spec.ScaleConfig.MaxReplicas = ptr.To[int32](10)
+		m := newMachine(f, true, scaledObject)
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		scaledObject, err = getScaledObject(m)
+		require.NoError(t, err)
+		maxReplicas, _, _ := unstructured.NestedInt64(scaledObject.Object, "spec", "maxReplicaCount")
+		require.Equal(t, int64(10), maxReplicas)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScaledObjectNotReady,
+			"ScaledObject test-function updated")
+	})
+	t.Run("set ready condition when ScaledObject is ready", func(t *testing.T) {
+		// Arrange
+		f := scaledFunction()
+		m := newMachine(f, true, ownedScaledObject(t, f, map[string]interface{}{"type": "Ready", "status": "True"}))
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonScaledObjectReady,
+			"ScaledObject test-function is ready")
+	})
+	t.Run("set failed condition when ScaledObject failed", func(t *testing.T) {
+		// Arrange
+		f := scaledFunction()
+		m := newMachine(f, true, ownedScaledObject(t, f,
+			map[string]interface{}{"type": "Ready", "status": "False", "message": "ScaledObject doesn't have correct triggers specification"}))
+
+		// Act
+		next, _, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			"ScaledObject test-function failed: ScaledObject doesn't have correct triggers specification")
+	})
+	t.Run("don't take over ScaledObject owned by someone else", func(t *testing.T) {
+		// Arrange
+		f := scaledFunction()
+		scaledObject := ownedScaledObject(t, f)
+		scaledObject.SetOwnerReferences(nil)
+		m := newMachine(f, true, scaledObject)
+
+		// Act
+		next, _, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionScalingReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			"ScaledObject test-function already exists and isn't owned by the function")
+	})
+	t.Run("remove ScaledObject and condition when triggers are removed", func(t *testing.T) {
+		// Arrange
+		f := scaledFunction()
+		scaledObject := ownedScaledObject(t, f)
+		f.Spec.ScaleConfig = nil
+		f.UpdateCondition(serverlessv1alpha2.ConditionScalingReady, metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonScaledObjectReady, "ScaledObject test-function is ready")
+		m := newMachine(f, true, scaledObject)
+
+		// Act
+		next, _, err := sFnHandleScaledObject(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		_, err = getScaledObject(m)
+		require.True(t, k8serrors.IsNotFound(err))
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+}
@@ -0,0 +1,158 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleSchedules keeps CronJobs invoking the function in sync with its schedules
+// and reports the last run of every schedule in the function's status
+func sFnHandleSchedules(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	clusterCronJobs := &batchv1.CronJobList{}
+	err := m.Client.List(ctx, clusterCronJobs,
+		client.InNamespace(f.GetNamespace()),
+		client.MatchingLabels(resources.ScheduleCronJobLabels(f)))
+	if err != nil {
+		m.Log.Error(err, "unable to list schedule CronJobs for Function")
+		return stopWithError(err)
+	}
+
+	clusterCronJobsByName := map[string]*batchv1.CronJob{}
+	for i := range clusterCronJobs.Items {
+		cronJob := &clusterCronJobs.Items[i]
+		clusterCronJobsByName[cronJob.GetName()] = cronJob
+	}
+
+	statuses := []serverlessv1alpha2.ScheduleStatus{}
+	for _, schedule := range f.Spec.Schedules {
+		builtCronJob := resources.NewScheduleCronJob(f, schedule, &m.FunctionConfig)
+		clusterCronJob, found := clusterCronJobsByName[builtCronJob.GetName()]
+		delete(clusterCronJobsByName, builtCronJob.GetName())
+
+		if !found {
+			if err := createScheduleCronJob(ctx, m, builtCronJob); err != nil {
+				return stopWithError(err)
+			}
+			statuses = append(statuses, scheduleStatus(schedule, builtCronJob))
+			continue
+		}
+
+		if err := updateScheduleCronJobIfNeeded(ctx, m, clusterCronJob, builtCronJob); err != nil {
+			return stopWithError(err)
+		}
+		statuses = append(statuses, scheduleStatus(schedule, clusterCronJob))
+	}
+
+	// remaining CronJobs belong to removed schedules
+	for _, cronJob := range clusterCronJobsByName {
+		m.Log.Info("deleting schedule CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+		err := m.Client.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			m.Log.Error(err, "failed to delete schedule CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+			return stopWithError(err)
+		}
+	}
+
+	if len(statuses) == 0 {
+		statuses = nil
+	}
+	f.Status.Schedules = statuses
+	return nextState(sFnHandleSubscriptions)
+}
+
+func createScheduleCronJob(ctx context.Context, m *fsm.StateMachine, cronJob *batchv1.CronJob) error {
+	m.Log.Info("creating a new schedule CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+
+	// Set the ownerRef for the CronJob, ensuring that the CronJob
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, cronJob, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+		updateScheduleFailedCondition(m, fmt.Sprintf("CronJob %s create failed: %s", cronJob.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, cronJob); err != nil {
+		m.Log.Error(err, "failed to create new CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+		updateScheduleFailedCondition(m, fmt.Sprintf("CronJob %s create failed: %s", cronJob.GetName(), err.Error()))
+		return err
+	}
+	return nil
+}
+
+func updateScheduleCronJobIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterCronJob, builtCronJob *batchv1.CronJob) error {
+	if !scheduleCronJobChanged(clusterCronJob, builtCronJob) {
+		return nil
+	}
+
+	m.Log.Info("updating schedule CronJob", "CronJob.Namespace", clusterCronJob.GetNamespace(), "CronJob.Name", clusterCronJob.GetName())
+	clusterCronJob.Spec.Schedule = builtCronJob.Spec.Schedule
+	clusterCronJob.Spec.TimeZone = builtCronJob.Spec.TimeZone
+	clusterCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers = builtCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+	clusterCronJob.Spec.JobTemplate.Spec.Template.Annotations = builtCronJob.Spec.JobTemplate.Spec.Template.Annotations
+	if err := m.Client.Update(ctx, clusterCronJob); err != nil {
+		m.Log.Error(err, "failed to update CronJob", "CronJob.Namespace", clusterCronJob.GetNamespace(), "CronJob.Name", clusterCronJob.GetName())
+		updateScheduleFailedCondition(m, fmt.Sprintf("CronJob %s update failed: %s", clusterCronJob.GetName(), err.Error()))
+		return err
+	}
+	return nil
+}
+
+// scheduleCronJobChanged compares only fields set from the function's schedule
+func scheduleCronJobChanged(a, b *batchv1.CronJob) bool {
+	aContainers := a.Spec.JobTemplate.Spec.Template.Spec.Containers
+	bContainers := b.Spec.JobTemplate.Spec.Template.Spec.Containers
+	if len(aContainers) != 1 || len(bContainers) != 1 {
+		return true
+	}
+
+	return a.Spec.Schedule != b.Spec.Schedule ||
+		!equality.Semantic.DeepEqual(a.Spec.TimeZone, b.Spec.TimeZone) ||
+		!equality.Semantic.DeepEqual(a.Spec.JobTemplate.Spec.Template.Annotations, b.Spec.JobTemplate.Spec.Template.Annotations) ||
+		aContainers[0].Image != bContainers[0].Image ||
+		!equality.Semantic.DeepEqual(aContainers[0].Command, bContainers[0].Command) ||
+		!equality.Semantic.DeepEqual(aContainers[0].Env, bContainers[0].Env)
+}
+
+func updateScheduleFailedCondition(m *fsm.StateMachine, msg string) {
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionFalse,
+		serverlessv1alpha2.ConditionReasonScheduleFailed,
+		msg)
+}
+
+// scheduleStatus returns the outcome of the last run based on the CronJob's status
+// the run is failed when the last scheduled job isn't active and didn't succeed
+func scheduleStatus(schedule serverlessv1alpha2.Schedule, cronJob *batchv1.CronJob) serverlessv1alpha2.ScheduleStatus {
+	status := serverlessv1alpha2.ScheduleStatus{
+		Name:               schedule.Name,
+		CronJobName:        cronJob.GetName(),
+		LastScheduleTime:   cronJob.Status.LastScheduleTime,
+		LastSuccessfulTime: cronJob.Status.LastSuccessfulTime,
+	}
+
+	switch {
+	case len(cronJob.Status.Active) != 0:
+		status.LastRunOutcome = serverlessv1alpha2.ScheduleRunOutcomeRunning
+	case cronJob.Status.LastScheduleTime == nil:
+		// not run yet
+	case cronJob.Status.LastSuccessfulTime != nil && !cronJob.Status.LastSuccessfulTime.Before(cronJob.Status.LastScheduleTime):
+		status.LastRunOutcome = serverlessv1alpha2.ScheduleRunOutcomeSucceeded
+	default:
+		status.LastRunOutcome = serverlessv1alpha2.ScheduleRunOutcomeFailed
+	}
+	return status
+}
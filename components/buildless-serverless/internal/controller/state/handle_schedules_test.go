@@ -0,0 +1,211 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_sFnHandleSchedules(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+
+	scheduledFunction := func(schedules ...serverlessv1alpha2.Schedule) serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:   serverlessv1alpha2.NodeJs22,
+				Schedules: schedules,
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, c client.Client) *fsm.StateMachine {
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: c,
+			Scheme: scheme,
+			FunctionConfig: config.FunctionConfig{
+				Images: config.ImagesConfig{ScheduleInvoker: "curlimages/curl:8.11.1"},
+			},
+		}
+	}
+	cleanup := serverlessv1alpha2.Schedule{Name: "cleanup", Cron: "*/15 * * * *"}
+
+	t.Run("move to the next state when function has no schedules", func(t *testing.T) {
+		// Arrange
+		m := newMachine(scheduledFunction(), fake.NewClientBuilder().WithScheme(scheme).Build())
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSubscriptions, next)
+		require.Nil(t, m.State.Function.Status.Schedules)
+	})
+	t.Run("create CronJob for new schedule", func(t *testing.T) {
+		// Arrange
+		f := scheduledFunction(cleanup)
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := newMachine(f, c)
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSubscriptions, next)
+		cronJob := &batchv1.CronJob{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function-cleanup"}, cronJob))
+		require.True(t, metav1.IsControlledBy(cronJob, &m.State.Function))
+		require.Equal(t, "*/15 * * * *", cronJob.Spec.Schedule)
+		require.Equal(t, []serverlessv1alpha2.ScheduleStatus{
+			{Name: "cleanup", CronJobName: "test-function-cleanup"},
+		}, m.State.Function.Status.Schedules)
+	})
+	t.Run("update CronJob when schedule changed and report last run", func(t *testing.T) {
+		// Arrange
+		f := scheduledFunction(cleanup)
+		clusterCronJob := resources.NewScheduleCronJob(&f, cleanup, &config.FunctionConfig{Images: config.ImagesConfig{ScheduleInvoker: "curlimages/curl:8.11.1"}})
+		lastSchedule := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+		clusterCronJob.Status = batchv1.CronJobStatus{
+			LastScheduleTime:   &lastSchedule,
+			LastSuccessfulTime: &lastSchedule,
+		}
+		f.Spec.Schedules[0].Cron = "@hourly"
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterCronJob).Build()
+		m := newMachine(f, c)
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSubscriptions, next)
+		cronJob := &batchv1.CronJob{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-function-cleanup"}, cronJob))
+		require.Equal(t, "@hourly", cronJob.Spec.Schedule)
+		require.Len(t, m.State.Function.Status.Schedules, 1)
+		require.Equal(t, serverlessv1alpha2.ScheduleRunOutcomeSucceeded, m.State.Function.Status.Schedules[0].LastRunOutcome)
+	})
+	t.Run("delete CronJob of removed schedule", func(t *testing.T) {
+		// Arrange
+		scheduled := scheduledFunction(cleanup)
+		clusterCronJob := resources.NewScheduleCronJob(&scheduled, cleanup, &config.FunctionConfig{Images: config.ImagesConfig{ScheduleInvoker: "curlimages/curl:8.11.1"}})
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterCronJob).Build()
+		m := newMachine(scheduledFunction(), c)
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSubscriptions, next)
+		cronJobs := &batchv1.CronJobList{}
+		require.NoError(t, c.List(context.Background(), cronJobs))
+		require.Empty(t, cronJobs.Items)
+		require.Nil(t, m.State.Function.Status.Schedules)
+	})
+	t.Run("stop when CronJob can't be created", func(t *testing.T) {
+		// Arrange
+		c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return errors.New("create error")
+			},
+		}).Build()
+		m := newMachine(scheduledFunction(cleanup), c)
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), m)
+
+		// Assert
+		require.EqualError(t, err, "create error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			"CronJob test-function-cleanup create failed: create error")
+	})
+}
+
+func Test_scheduleStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	schedule := serverlessv1alpha2.Schedule{Name: "cleanup"}
+
+	tests := []struct {
+		name   string
+		status batchv1.CronJobStatus
+		want   serverlessv1alpha2.ScheduleRunOutcome
+	}{
+		{
+			name:   "not run yet",
+			status: batchv1.CronJobStatus{},
+			want:   "",
+		},
+		{
+			name: "running",
+			status: batchv1.CronJobStatus{
+				Active:           []corev1.ObjectReference{{Name: "test-function-cleanup-123"}},
+				LastScheduleTime: &later,
+			},
+			want: serverlessv1alpha2.ScheduleRunOutcomeRunning,
+		},
+		{
+			name:   "succeeded",
+			status: batchv1.CronJobStatus{LastScheduleTime: &later, LastSuccessfulTime: &later},
+			want:   serverlessv1alpha2.ScheduleRunOutcomeSucceeded,
+		},
+		{
+			name:   "failed after previous success",
+			status: batchv1.CronJobStatus{LastScheduleTime: &later, LastSuccessfulTime: &earlier},
+			want:   serverlessv1alpha2.ScheduleRunOutcomeFailed,
+		},
+		{
+			name:   "failed without any success",
+			status: batchv1.CronJobStatus{LastScheduleTime: &later},
+			want:   serverlessv1alpha2.ScheduleRunOutcomeFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cronJob := &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-function-cleanup"},
+				Status:     tt.status,
+			}
+
+			got := scheduleStatus(schedule, cronJob)
+
+			require.Equal(t, tt.want, got.LastRunOutcome)
+			require.Equal(t, "test-function-cleanup", got.CronJobName)
+		})
+	}
+}
@@ -34,7 +34,7 @@ func sFnHandleService(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *c
 	if requeueNeeded {
 		return requeue()
 	}
-	return nextState(sFnDeploymentStatus)
+	return nextState(sFnHandlePodDisruptionBudget)
 }
 
 func getService(ctx context.Context, m *fsm.StateMachine) (*corev1.Service, error) {
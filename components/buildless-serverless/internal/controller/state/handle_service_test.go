@@ -212,7 +212,7 @@ func Test_sFnHandleService(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnDeploymentStatus, next)
+		requireEqualFunc(t, sFnHandlePodDisruptionBudget, next)
 		// service has not been created or updated
 		require.False(t, createOrUpdateWasCalled)
 		// function conditions remain unchanged
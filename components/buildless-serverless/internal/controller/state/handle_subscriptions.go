@@ -0,0 +1,147 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// sFnHandleSubscriptions keeps Eventing Subscriptions targeting the function in sync with its subscriptions
+// and reports their readiness in the SubscriptionsReady condition
+func sFnHandleSubscriptions(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+
+	_, err := m.Client.RESTMapper().RESTMapping(resources.SubscriptionGVK.GroupKind(), resources.SubscriptionGVK.Version)
+	if meta.IsNoMatchError(err) {
+		if len(f.Spec.Subscriptions) == 0 {
+			meta.RemoveStatusCondition(&f.Status.Conditions, string(serverlessv1alpha2.ConditionSubscriptionsReady))
+			return nextState(sFnHandleNetworkPolicy)
+		}
+		updateSubscriptionsCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("%s CRD is not installed", resources.SubscriptionGVK.Kind))
+		return nextState(sFnHandleNetworkPolicy)
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to check if Subscription CRD is installed")
+		return stopWithError(err)
+	}
+
+	clusterSubscriptions := &unstructured.UnstructuredList{}
+	clusterSubscriptions.SetGroupVersionKind(resources.SubscriptionGVK.GroupVersion().WithKind(resources.SubscriptionGVK.Kind + "List"))
+	err = m.Client.List(ctx, clusterSubscriptions,
+		client.InNamespace(f.GetNamespace()),
+		client.MatchingLabels(resources.SubscriptionLabels(f)))
+	if err != nil {
+		m.Log.Error(err, "unable to list Subscriptions for Function")
+		return stopWithError(err)
+	}
+
+	clusterSubscriptionsByName := map[string]*unstructured.Unstructured{}
+	for i := range clusterSubscriptions.Items {
+		subscription := &clusterSubscriptions.Items[i]
+		clusterSubscriptionsByName[subscription.GetName()] = subscription
+	}
+
+	notReady := []string{}
+	for _, subscription := range f.Spec.Subscriptions {
+		builtSubscription := resources.NewSubscription(f, subscription)
+		clusterSubscription, found := clusterSubscriptionsByName[builtSubscription.GetName()]
+		delete(clusterSubscriptionsByName, builtSubscription.GetName())
+
+		if !found {
+			if err := createSubscription(ctx, m, builtSubscription); err != nil {
+				return stopWithError(err)
+			}
+			notReady = append(notReady, subscription.Name)
+			continue
+		}
+
+		updated, err := updateSubscriptionIfNeeded(ctx, m, clusterSubscription, builtSubscription)
+		if err != nil {
+			return stopWithError(err)
+		}
+		if updated || !resources.SubscriptionReady(clusterSubscription) {
+			notReady = append(notReady, subscription.Name)
+		}
+	}
+
+	// remaining Subscriptions belong to removed subscriptions
+	for _, subscription := range clusterSubscriptionsByName {
+		m.Log.Info("deleting Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+		err := m.Client.Delete(ctx, subscription)
+		if err != nil && !errors.IsNotFound(err) {
+			m.Log.Error(err, "failed to delete Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+			return stopWithError(err)
+		}
+	}
+
+	switch {
+	case len(f.Spec.Subscriptions) == 0:
+		meta.RemoveStatusCondition(&f.Status.Conditions, string(serverlessv1alpha2.ConditionSubscriptionsReady))
+	case len(notReady) != 0:
+		updateSubscriptionsCondition(m, metav1.ConditionUnknown, serverlessv1alpha2.ConditionReasonSubscriptionsNotReady,
+			fmt.Sprintf("Subscriptions not ready: %s", strings.Join(notReady, ", ")))
+	default:
+		updateSubscriptionsCondition(m, metav1.ConditionTrue, serverlessv1alpha2.ConditionReasonSubscriptionsReady,
+			"All subscriptions are ready")
+	}
+	return nextState(sFnHandleNetworkPolicy)
+}
+
+func createSubscription(ctx context.Context, m *fsm.StateMachine, subscription *unstructured.Unstructured) error {
+	m.Log.Info("creating a new Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+
+	// Set the ownerRef for the Subscription, ensuring that the Subscription
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, subscription, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+		updateSubscriptionsCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s create failed: %s", subscription.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, subscription); err != nil {
+		m.Log.Error(err, "failed to create new Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+		updateSubscriptionsCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s create failed: %s", subscription.GetName(), err.Error()))
+		return err
+	}
+	return nil
+}
+
+func updateSubscriptionIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterSubscription, builtSubscription *unstructured.Unstructured) (bool, error) {
+	// Eventing defaults the config, so only fields set by the controller are compared
+	if !exposeObjectChanged(clusterSubscription, builtSubscription) {
+		return false, nil
+	}
+
+	m.Log.Info("updating Subscription", "Subscription.Namespace", clusterSubscription.GetNamespace(), "Subscription.Name", clusterSubscription.GetName())
+	clusterSubscription.Object["spec"] = builtSubscription.Object["spec"]
+	clusterSubscription.SetLabels(builtSubscription.GetLabels())
+	if err := m.Client.Update(ctx, clusterSubscription); err != nil {
+		m.Log.Error(err, "failed to update Subscription", "Subscription.Namespace", clusterSubscription.GetNamespace(), "Subscription.Name", clusterSubscription.GetName())
+		updateSubscriptionsCondition(m, metav1.ConditionFalse, serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s update failed: %s", clusterSubscription.GetName(), err.Error()))
+		return false, err
+	}
+	return true, nil
+}
+
+func updateSubscriptionsCondition(m *fsm.StateMachine, status metav1.ConditionStatus, reason serverlessv1alpha2.ConditionReason, msg string) {
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionSubscriptionsReady,
+		status,
+		reason,
+		msg)
+}
@@ -0,0 +1,181 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleSubscriptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+
+	subscribedFunction := func(subscriptions ...serverlessv1alpha2.Subscription) serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:       serverlessv1alpha2.NodeJs22,
+				Subscriptions: subscriptions,
+			},
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, crdInstalled bool, objs ...client.Object) *fsm.StateMachine {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		if crdInstalled {
+			mapper.Add(resources.SubscriptionGVK, meta.RESTScopeNamespace)
+		}
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build(),
+			Scheme: scheme,
+		}
+	}
+	ownedSubscription := func(t *testing.T, f serverlessv1alpha2.Function, s serverlessv1alpha2.Subscription, ready bool) *unstructured.Unstructured {
+		subscription := resources.NewSubscription(&f, s)
+		require.NoError(t, controllerutil.SetControllerReference(&f, subscription, scheme))
+		subscription.Object["status"] = map[string]interface{}{"ready": ready}
+		return subscription
+	}
+	getSubscription := func(m *fsm.StateMachine, name string) (*unstructured.Unstructured, error) {
+		subscription := &unstructured.Unstructured{}
+		subscription.SetGroupVersionKind(resources.SubscriptionGVK)
+		err := m.Client.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: name}, subscription)
+		return subscription, err
+	}
+	orders := serverlessv1alpha2.Subscription{Name: "orders", Source: "commerce", Types: []string{"order.created.v1"}}
+
+	t.Run("move to the next state when function has no subscriptions and CRD is not installed", func(t *testing.T) {
+		// Arrange
+		f := subscribedFunction()
+		f.UpdateCondition(serverlessv1alpha2.ConditionSubscriptionsReady, metav1.ConditionTrue, serverlessv1alpha2.ConditionReasonSubscriptionsReady, "")
+		m := newMachine(f, false)
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		require.Nil(t, meta.FindStatusCondition(m.State.Function.Status.Conditions, string(serverlessv1alpha2.ConditionSubscriptionsReady)))
+	})
+	t.Run("set condition when CRD is not installed", func(t *testing.T) {
+		// Arrange
+		m := newMachine(subscribedFunction(orders), false)
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			"Subscription CRD is not installed")
+	})
+	t.Run("create Subscription", func(t *testing.T) {
+		// Arrange
+		m := newMachine(subscribedFunction(orders), true)
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionsNotReady,
+			"Subscriptions not ready: orders")
+		subscription, err := getSubscription(m, "test-function-orders")
+		require.NoError(t, err)
+		require.True(t, metav1.IsControlledBy(subscription, &m.State.Function))
+		sink, _, _ := unstructured.NestedString(subscription.Object, "spec", "sink")
+		require.Equal(t, "http://test-function.test-namespace.svc.cluster.local", sink)
+	})
+	t.Run("set ready condition when all Subscriptions are ready", func(t *testing.T) {
+		// Arrange
+		f := subscribedFunction(orders)
+		m := newMachine(f, true, ownedSubscription(t, f, orders, true))
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSubscriptionsReady,
+			"All subscriptions are ready")
+	})
+	t.Run("update Subscription when its types changed", func(t *testing.T) {
+		// Arrange
+		f := subscribedFunction(orders)
+		subscription := ownedSubscription(t, f, orders, true)
+		f.Spec.Subscriptions[0].Types = []string{"order.deleted.v1"}
+		m := newMachine(f, true, subscription)
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionsNotReady,
+			"Subscriptions not ready: orders")
+		updated, err := getSubscription(m, "test-function-orders")
+		require.NoError(t, err)
+		types, _, _ := unstructured.NestedStringSlice(updated.Object, "spec", "types")
+		require.Equal(t, []string{"order.deleted.v1"}, types)
+	})
+	t.Run("delete Subscription of removed subscription", func(t *testing.T) {
+		// Arrange
+		subscribed := subscribedFunction(orders)
+		subscription := ownedSubscription(t, subscribed, orders, true)
+		f := subscribedFunction()
+		f.UpdateCondition(serverlessv1alpha2.ConditionSubscriptionsReady, metav1.ConditionTrue, serverlessv1alpha2.ConditionReasonSubscriptionsReady, "")
+		m := newMachine(f, true, subscription)
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		require.Nil(t, meta.FindStatusCondition(m.State.Function.Status.Conditions, string(serverlessv1alpha2.ConditionSubscriptionsReady)))
+		_, err = getSubscription(m, "test-function-orders")
+		require.True(t, errors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,152 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	runtimeImageResolveTimeout = 10 * time.Second
+	// functionServiceAccountName is the ServiceAccount the Function's Pods run with
+	functionServiceAccountName = "default"
+
+	warningRuntimeImageResolutionFailedFormat = "Warning: runtime image %s resolution failed, the function runs with %s: %s"
+)
+
+// resolveRuntimeImageDigest is a variable to allow replacing registry calls in tests
+var resolveRuntimeImageDigest = oci.ResolveImageDigest
+
+// pinRuntimeImage runs the Function with the runtime image pinned to its digest
+// the digest from the status is reused while the image doesn't change and the resolve interval doesn't pass,
+// so restarted Pods run the same image even if its tag is moved
+func pinRuntimeImage(ctx context.Context, m *fsm.StateMachine) {
+	if !m.FunctionConfig.PinsRuntimeImages() {
+		return
+	}
+
+	f := &m.State.Function
+	image := m.State.BuiltDeployment.RuntimeImage()
+	if f.Status.RuntimeImage == image && f.Status.RuntimeImageDigest != "" && !isRuntimeImageDigestExpired(m, time.Now()) {
+		pinnedImage, err := oci.PinnedImage(image, f.Status.RuntimeImageDigest)
+		if err == nil {
+			m.State.BuiltDeployment.PinRuntimeImage(pinnedImage, f.Status.RuntimeImageDigest)
+			m.State.RuntimeImageDigestResolvedAt = f.Status.RuntimeImageDigestResolvedAt
+			return
+		}
+	}
+
+	digest, err := resolveRuntimeImage(ctx, m, image)
+	if err != nil {
+		keepLastKnownRuntimeImage(m, image, err)
+		return
+	}
+	pinnedImage, err := oci.PinnedImage(image, digest)
+	if err != nil {
+		keepLastKnownRuntimeImage(m, image, err)
+		return
+	}
+	m.State.BuiltDeployment.PinRuntimeImage(pinnedImage, digest)
+	m.State.RuntimeImageDigestResolvedAt = &metav1.Time{Time: time.Now()}
+}
+
+func resolveRuntimeImage(ctx context.Context, m *fsm.StateMachine, image string) (string, error) {
+	creds, err := runtimeImageCredentials(ctx, m)
+	if err != nil {
+		return "", err
+	}
+
+	resolveCtx, cancel := context.WithTimeout(ctx, runtimeImageResolveTimeout)
+	defer cancel()
+
+	return resolveRuntimeImageDigest(resolveCtx, image, creds, m.FunctionConfig.ImageDigests.PlainHTTPRegistries)
+}
+
+// isRuntimeImageDigestExpired checks if the runtime image has to be resolved again, for example, because its tag could be moved
+func isRuntimeImageDigestExpired(m *fsm.StateMachine, now time.Time) bool {
+	interval := m.FunctionConfig.ImageDigests.ResolveInterval
+	resolvedAt := m.State.Function.Status.RuntimeImageDigestResolvedAt
+	if interval <= 0 || resolvedAt == nil {
+		return false
+	}
+	return !now.Before(resolvedAt.Add(interval))
+}
+
+// keepLastKnownRuntimeImage runs the Function with the image it was running with when the runtime image can't be resolved,
+// so unavailable registry doesn't stop the Function's reconciliation
+// the image of the same runtime is kept pinned to its last known digest, other images are run by the tag
+func keepLastKnownRuntimeImage(m *fsm.StateMachine, image string, err error) {
+	f := &m.State.Function
+	lastKnownImage := image
+	if f.Status.RuntimeImageDigest != "" && isLastKnownRuntimeImageKept(m) {
+		pinnedImage, pinErr := oci.PinnedImage(f.Status.RuntimeImage, f.Status.RuntimeImageDigest)
+		if pinErr == nil {
+			m.State.BuiltDeployment.HoldRuntimeImage(f.Status.RuntimeImage, pinnedImage, f.Status.RuntimeImageDigest)
+			m.State.RuntimeImageDigestResolvedAt = f.Status.RuntimeImageDigestResolvedAt
+			lastKnownImage = pinnedImage
+		}
+	}
+
+	m.Log.Error(err, "unable to resolve runtime image", "image", image, "runWith", lastKnownImage)
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionConfigurationReady,
+		metav1.ConditionTrue,
+		serverlessv1alpha2.ConditionReasonRuntimeImageResolutionFailed,
+		fmt.Sprintf(warningRuntimeImageResolutionFailedFormat, image, lastKnownImage, err.Error()))
+}
+
+// isLastKnownRuntimeImageKept checks if the image from the status is the previous image of the same runtime,
+// changes of the runtime or the runtime image override made by the user aren't reverted
+func isLastKnownRuntimeImageKept(m *fsm.StateMachine) bool {
+	f := m.State.Function
+	if f.Status.RuntimeImage == "" || f.Status.Runtime != runWithRuntime(m) {
+		return false
+	}
+	return f.Spec.RuntimeImageOverride == "" || f.Spec.RuntimeImageOverride == f.Status.RuntimeImage
+}
+
+// runtimeImageCredentials returns credentials to the runtime images' registries
+// image pull secrets of the ServiceAccount the Function's Pods run with take precedence over the configured pull secret
+func runtimeImageCredentials(ctx context.Context, m *fsm.StateMachine) (*oci.Credentials, error) {
+	namespace := m.State.Function.GetNamespace()
+	all := []*oci.Credentials{}
+
+	sa := &corev1.ServiceAccount{}
+	err := m.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: functionServiceAccountName}, sa)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "while getting service account")
+	}
+	for _, ref := range sa.ImagePullSecrets {
+		creds, err := oci.NewCredentials(ctx, m.Client, namespace, ref.Name)
+		if err != nil {
+			// kubelet skips missing image pull secrets too
+			m.Log.Info(fmt.Sprintf("skipping image pull secret %s: %s", ref.Name, err.Error()))
+			continue
+		}
+		all = append(all, creds)
+	}
+
+	if pullSecret := m.FunctionConfig.ImageDigests.PullSecret; pullSecret != "" {
+		secretNamespace, secretName, found := strings.Cut(pullSecret, "/")
+		if !found {
+			return nil, errors.New(fmt.Sprintf("invalid pull secret %s, expected namespace/name", pullSecret))
+		}
+		creds, err := oci.NewCredentials(ctx, m.Client, secretNamespace, secretName)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, creds)
+	}
+
+	return oci.MergeCredentials(all...), nil
+}
@@ -0,0 +1,286 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testRuntimeImageDigest      = "sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0"
+	testMovedRuntimeImageDigest = "sha256:1f5b0a9c8e3d7b2a4c6e8f0a1b3c5d7e9f1a3b5c7d9e1f3a5b7c9d1e3f5a7b9c"
+)
+
+func Test_pinRuntimeImage(t *testing.T) {
+	t.Run("should pin resolved runtime image", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(_ context.Context, image string, _ *oci.Credentials, plainHTTPRegistries []string) (string, error) {
+			require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", image)
+			require.Equal(t, []string{"localhost:5000"}, plainHTTPRegistries)
+			return testRuntimeImageDigest, nil
+		})
+		m := digestTestMachine(digestTestFunction())
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", m.State.BuiltDeployment.RuntimeImage())
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@"+testRuntimeImageDigest,
+			m.State.BuiltDeployment.Spec.Template.Spec.Containers[0].Image)
+	})
+	t.Run("should reuse digest of unchanged runtime image", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			require.Fail(t, "digest of unchanged image should not be resolved")
+			return "", nil
+		})
+		f := digestTestFunction()
+		f.Status.RuntimeImage = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main"
+		f.Status.RuntimeImageDigest = testRuntimeImageDigest
+		m := digestTestMachine(f)
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+	})
+	t.Run("should not pin runtime image when digests are disabled", func(t *testing.T) {
+		// Arrange
+		m := digestTestMachine(digestTestFunction())
+		m.FunctionConfig.ImageDigests.Enabled = false
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Empty(t, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main",
+			m.State.BuiltDeployment.Spec.Template.Spec.Containers[0].Image)
+	})
+	t.Run("should use service account pull secrets before configured pull secret", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(ctx context.Context, _ string, creds *oci.Credentials, _ []string) (string, error) {
+			require.Equal(t, oci.MergeCredentials(
+				parseTestCredentials(t, "sa-user"),
+				parseTestCredentials(t, "config-user"),
+			), creds)
+			return testRuntimeImageDigest, nil
+		})
+		m := digestTestMachine(digestTestFunction(),
+			&corev1.ServiceAccount{
+				ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "test-ns"},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "sa-pull-secret"}, {Name: "missing-pull-secret"}},
+			},
+			digestTestPullSecret("test-ns", "sa-pull-secret", "sa-user"),
+			digestTestPullSecret("kyma-system", "runtime-pull-secret", "config-user"))
+		m.FunctionConfig.ImageDigests.PullSecret = "kyma-system/runtime-pull-secret"
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+	})
+	t.Run("should resolve digest of unchanged runtime image again after resolve interval", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			return testMovedRuntimeImageDigest, nil
+		})
+		f := digestTestFunction()
+		f.Status.Runtime = serverlessv1alpha2.Python312
+		f.Status.RuntimeImage = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main"
+		f.Status.RuntimeImageDigest = testRuntimeImageDigest
+		f.Status.RuntimeImageDigestResolvedAt = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		m := digestTestMachine(f)
+		m.FunctionConfig.ImageDigests.ResolveInterval = time.Hour
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, testMovedRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.WithinDuration(t, time.Now(), m.State.RuntimeImageDigestResolvedAt.Time, time.Minute)
+	})
+	t.Run("should reuse digest of unchanged runtime image within resolve interval", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			require.Fail(t, "digest of unchanged image should not be resolved")
+			return "", nil
+		})
+		resolvedAt := &metav1.Time{Time: time.Now().Add(-30 * time.Minute)}
+		f := digestTestFunction()
+		f.Status.RuntimeImage = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main"
+		f.Status.RuntimeImageDigest = testRuntimeImageDigest
+		f.Status.RuntimeImageDigestResolvedAt = resolvedAt
+		m := digestTestMachine(f)
+		m.FunctionConfig.ImageDigests.ResolveInterval = time.Hour
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, resolvedAt, m.State.RuntimeImageDigestResolvedAt)
+	})
+	t.Run("should keep last known runtime image when runtime image can't be resolved", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			return "", errors.New("manifest unknown")
+		})
+		f := digestTestFunction()
+		f.Status.Runtime = serverlessv1alpha2.Python312
+		f.Status.RuntimeImage = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:old"
+		f.Status.RuntimeImageDigest = testRuntimeImageDigest
+		m := digestTestMachine(f)
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:old", m.State.BuiltDeployment.RuntimeImage())
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@"+testRuntimeImageDigest,
+			m.State.BuiltDeployment.Spec.Template.Spec.Containers[0].Image)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonRuntimeImageResolutionFailed,
+			"Warning: runtime image europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main resolution failed, "+
+				"the function runs with europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@"+testRuntimeImageDigest+": manifest unknown")
+	})
+	t.Run("should run runtime image by tag when it can't be resolved and no image is known", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			return "", errors.New("manifest unknown")
+		})
+		m := digestTestMachine(digestTestFunction())
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Empty(t, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main",
+			m.State.BuiltDeployment.Spec.Template.Spec.Containers[0].Image)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonRuntimeImageResolutionFailed,
+			"Warning: runtime image europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main resolution failed, "+
+				"the function runs with europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main: manifest unknown")
+	})
+	t.Run("should not keep runtime image of another runtime when runtime image can't be resolved", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			return "", errors.New("manifest unknown")
+		})
+		f := digestTestFunction()
+		f.Status.Runtime = serverlessv1alpha2.NodeJs20
+		f.Status.RuntimeImage = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs20:main"
+		f.Status.RuntimeImageDigest = testRuntimeImageDigest
+		m := digestTestMachine(f)
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", m.State.BuiltDeployment.RuntimeImage())
+		require.Empty(t, m.State.BuiltDeployment.RuntimeImageDigest())
+	})
+	t.Run("should keep last known runtime image when configured pull secret is missing", func(t *testing.T) {
+		// Arrange
+		m := digestTestMachine(digestTestFunction())
+		m.FunctionConfig.ImageDigests.PullSecret = "kyma-system/runtime-pull-secret"
+
+		// Act
+		pinRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.Empty(t, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, serverlessv1alpha2.ConditionReasonRuntimeImageResolutionFailed,
+			serverlessv1alpha2.ConditionReason(m.State.Function.Status.Conditions[0].Reason))
+		require.Contains(t, m.State.Function.Status.Conditions[0].Message, "failed to get pull secret")
+	})
+}
+
+func stubResolveRuntimeImageDigest(t *testing.T, fn func(context.Context, string, *oci.Credentials, []string) (string, error)) {
+	original := resolveRuntimeImageDigest
+	resolveRuntimeImageDigest = fn
+	t.Cleanup(func() {
+		resolveRuntimeImageDigest = original
+	})
+}
+
+func digestTestFunction() serverlessv1alpha2.Function {
+	return serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function",
+			Namespace: "test-ns",
+		},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Runtime: serverlessv1alpha2.Python312,
+			Source: serverlessv1alpha2.Source{
+				Inline: &serverlessv1alpha2.InlineSource{
+					Source: "def main(event, context):\n  return 'ok'"},
+			},
+		},
+	}
+}
+
+func digestTestMachine(f serverlessv1alpha2.Function, objs ...client.Object) *fsm.StateMachine {
+	m := &fsm.StateMachine{
+		State: fsm.SystemState{
+			Function: f,
+		},
+		FunctionConfig: config.FunctionConfig{
+			Images: config.ImagesConfig{
+				Python312: "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main",
+			},
+			ImageDigests: config.ImageDigests{
+				Enabled:             true,
+				PlainHTTPRegistries: []string{"localhost:5000"},
+			},
+		},
+		Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
+		Log:    zap.NewNop().Sugar(),
+	}
+	m.State.BuiltDeployment = resources.NewDeployment(&m.State.Function, &m.FunctionConfig, nil, "", nil, "",
+		resources.DeploySetRuntime(runWithRuntime(m)))
+	return m
+}
+
+func digestTestPullSecret(namespace, name, username string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: digestTestDockerConfig(username),
+		},
+	}
+}
+
+func digestTestDockerConfig(username string) []byte {
+	return []byte(`{"auths":{"europe-docker.pkg.dev":{"username":"` + username + `","password":"password"}}}`)
+}
+
+func parseTestCredentials(t *testing.T, username string) *oci.Credentials {
+	creds, err := oci.ParseCredentials(digestTestDockerConfig(username))
+	require.NoError(t, err)
+	return creds
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// verifyRuntimeImageSignature is a variable to allow replacing registry calls in tests
+var verifyRuntimeImageSignature = oci.VerifyImageSignature
+
+// verifyRuntimeImage checks on every reconciliation if the pinned runtime image is signed with one of the public keys from the image policy,
+// the failed verification is reported in the ConfigurationReady condition, digests with verified signatures are cached
+func verifyRuntimeImage(ctx context.Context, m *fsm.StateMachine) {
+	m.State.RuntimeImageSignatureErr = nil
+	if !m.FunctionConfig.ImagePolicy.RequiresSignature() {
+		return
+	}
+
+	image := m.State.BuiltDeployment.RuntimeImage()
+	digest := m.State.BuiltDeployment.RuntimeImageDigest()
+	if err := verifyRuntimeImageDigest(ctx, m, image, digest); err != nil {
+		m.Log.Error(err, "unable to verify runtime image signature", "image", image, "digest", digest)
+		m.State.RuntimeImageSignatureErr = err
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeImageSignatureInvalid,
+			fmt.Sprintf("Runtime image %s signature verification failed: %s", image, err.Error()))
+	}
+}
+
+func verifyRuntimeImageDigest(ctx context.Context, m *fsm.StateMachine, image, digest string) error {
+	if digest == "" {
+		// the image runs by the tag, for example, because it can't be resolved
+		return errors.New("runtime image isn't pinned to a digest")
+	}
+	if m.SignatureCache.IsVerified(image, digest) {
+		return nil
+	}
+
+	keys, err := oci.ParsePublicKeys(m.FunctionConfig.ImagePolicy.PublicKeys)
+	if err != nil {
+		return err
+	}
+	creds, err := runtimeImageCredentials(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	verifyCtx, cancel := context.WithTimeout(ctx, runtimeImageResolveTimeout)
+	defer cancel()
+
+	err = verifyRuntimeImageSignature(verifyCtx, image, digest, creds, m.FunctionConfig.ImageDigests.PlainHTTPRegistries, keys)
+	if err != nil {
+		return err
+	}
+	m.SignatureCache.Verified(image, digest)
+	return nil
+}
+
+// requireVerifiedRuntimeImage is called before the Function's workload is created or updated,
+// so workloads aren't changed to run images without verified signatures
+func requireVerifiedRuntimeImage(m *fsm.StateMachine) error {
+	if err := m.State.RuntimeImageSignatureErr; err != nil {
+		return errors.Wrap(err, "while verifying runtime image signature")
+	}
+	return nil
+}
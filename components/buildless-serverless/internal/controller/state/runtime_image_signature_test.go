@@ -0,0 +1,221 @@
+package state
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_verifyRuntimeImage(t *testing.T) {
+	publicKey := testPublicKey(t)
+	pinnedImage := "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@" + testRuntimeImageDigest
+
+	t.Run("should skip verification when image policy has no keys", func(t *testing.T) {
+		// Arrange
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			require.Fail(t, "signature should not be verified")
+			return nil
+		})
+		m := digestTestMachine(digestTestFunction())
+
+		// Act
+		verifyRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.NoError(t, requireVerifiedRuntimeImage(m))
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("should verify pinned runtime image", func(t *testing.T) {
+		// Arrange
+		stubVerifyRuntimeImageSignature(t, func(_ context.Context, image, digest string, _ *oci.Credentials, _ []string, keys []crypto.PublicKey) error {
+			require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", image)
+			require.Equal(t, testRuntimeImageDigest, digest)
+			require.Len(t, keys, 1)
+			return nil
+		})
+		m := digestTestMachine(digestTestFunction())
+		m.SignatureCache = oci.NewSignatureCache()
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{publicKey}
+		m.State.BuiltDeployment.PinRuntimeImage(pinnedImage, testRuntimeImageDigest)
+
+		// Act
+		verifyRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.NoError(t, requireVerifiedRuntimeImage(m))
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.True(t, m.SignatureCache.IsVerified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", testRuntimeImageDigest))
+	})
+	t.Run("should not verify cached digest again", func(t *testing.T) {
+		// Arrange
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			require.Fail(t, "signature of cached digest should not be verified")
+			return nil
+		})
+		m := digestTestMachine(digestTestFunction())
+		m.SignatureCache = oci.NewSignatureCache()
+		m.SignatureCache.Verified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", testRuntimeImageDigest)
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{publicKey}
+		m.State.BuiltDeployment.PinRuntimeImage(pinnedImage, testRuntimeImageDigest)
+
+		// Act
+		verifyRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.NoError(t, requireVerifiedRuntimeImage(m))
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("should fail when signature is invalid", func(t *testing.T) {
+		// Arrange
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			return errors.New("image is not signed")
+		})
+		m := digestTestMachine(digestTestFunction())
+		m.SignatureCache = oci.NewSignatureCache()
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{publicKey}
+		m.State.BuiltDeployment.PinRuntimeImage(pinnedImage, testRuntimeImageDigest)
+
+		// Act
+		verifyRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.ErrorContains(t, requireVerifiedRuntimeImage(m), "image is not signed")
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeImageSignatureInvalid,
+			"Runtime image europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main signature verification failed: image is not signed")
+		require.False(t, m.SignatureCache.IsVerified("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main", testRuntimeImageDigest))
+	})
+	t.Run("should fail when runtime image isn't pinned", func(t *testing.T) {
+		// Arrange
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			require.Fail(t, "signature of image without digest should not be verified")
+			return nil
+		})
+		m := digestTestMachine(digestTestFunction())
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{publicKey}
+
+		// Act
+		verifyRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.ErrorContains(t, requireVerifiedRuntimeImage(m), "runtime image isn't pinned to a digest")
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeImageSignatureInvalid,
+			"Runtime image europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main signature verification failed: runtime image isn't pinned to a digest")
+	})
+	t.Run("should fail when public key is invalid", func(t *testing.T) {
+		// Arrange
+		m := digestTestMachine(digestTestFunction())
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{"not a key"}
+		m.State.BuiltDeployment.PinRuntimeImage(pinnedImage, testRuntimeImageDigest)
+
+		// Act
+		verifyRuntimeImage(context.Background(), m)
+
+		// Assert
+		require.ErrorContains(t, requireVerifiedRuntimeImage(m), "public key 0 is not PEM encoded")
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeImageSignatureInvalid,
+			"Runtime image europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main signature verification failed: public key 0 is not PEM encoded")
+	})
+}
+
+func Test_sFnHandleDeployment_unsignedRuntimeImage(t *testing.T) {
+	t.Run("should not create deployment with unsigned runtime image", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			return testRuntimeImageDigest, nil
+		})
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			return errors.New("image is not signed")
+		})
+		m := digestTestMachine(digestTestFunction())
+		m.FunctionConfig.ImageDigests.Enabled = false
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{testPublicKey(t)}
+
+		// Act
+		next, result, err := sFnHandleDeployment(context.Background(), m)
+
+		// Assert
+		require.ErrorContains(t, err, "image is not signed")
+		require.Nil(t, next)
+		require.Nil(t, result)
+		deployments := &appsv1.DeploymentList{}
+		require.NoError(t, m.Client.List(context.Background(), deployments))
+		require.Empty(t, deployments.Items)
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+	})
+	t.Run("should report invalid signature of running deployment without changing it", func(t *testing.T) {
+		// Arrange
+		stubResolveRuntimeImageDigest(t, func(context.Context, string, *oci.Credentials, []string) (string, error) {
+			return testRuntimeImageDigest, nil
+		})
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			return nil
+		})
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, appsv1.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		m := digestTestMachine(digestTestFunction())
+		m.Scheme = scheme
+		m.Client = fake.NewClientBuilder().WithScheme(scheme).Build()
+		m.FunctionConfig.ImagePolicy.PublicKeys = []string{testPublicKey(t)}
+		_, _, err := sFnHandleDeployment(context.Background(), m)
+		require.NoError(t, err)
+		stubVerifyRuntimeImageSignature(t, func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error {
+			return errors.New("image is not signed")
+		})
+		m.State.Function.Status.RuntimeImage = m.State.BuiltDeployment.RuntimeImage()
+		m.State.Function.Status.RuntimeImageDigest = m.State.BuiltDeployment.RuntimeImageDigest()
+
+		// Act
+		next, _, err := sFnHandleDeployment(context.Background(), m)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonRuntimeImageSignatureInvalid,
+			"Runtime image europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main signature verification failed: image is not signed")
+	})
+}
+
+func stubVerifyRuntimeImageSignature(t *testing.T, fn func(context.Context, string, string, *oci.Credentials, []string, []crypto.PublicKey) error) {
+	original := verifyRuntimeImageSignature
+	verifyRuntimeImageSignature = fn
+	t.Cleanup(func() {
+		verifyRuntimeImageSignature = original
+	})
+}
+
+func testPublicKey(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
@@ -0,0 +1,115 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stageRuntimeImageUpgrade keeps the previous runtime image in the built Deployment until the rollout lets the Function upgrade
+// only images changed in the Function Controller's configuration or their changed digests are staged,
+// changes of the Function itself are applied at once
+func stageRuntimeImageUpgrade(m *fsm.StateMachine, clusterDeployment *appsv1.Deployment) {
+	f := &m.State.Function
+	key := client.ObjectKeyFromObject(f).String()
+
+	if !isRuntimeImageUpgrade(m, clusterDeployment) {
+		// the function doesn't wait for the upgrade anymore, for example, because its runtime was changed
+		if upgrade := f.Status.RuntimeImageUpgrade; upgrade != nil && !isRuntimeImageUpgradeStarted(upgrade) {
+			m.Rollout.Forget(key)
+			f.Status.RuntimeImageUpgrade = nil
+		}
+		return
+	}
+
+	image := m.State.BuiltDeployment.RuntimeImage()
+	digest := m.State.BuiltDeployment.RuntimeImageDigest()
+	upgradeState := m.Rollout.Start(key, f.GetNamespace(), time.Now())
+	f.Status.RuntimeImageUpgrade = &serverlessv1alpha2.RuntimeImageUpgrade{
+		Image:  image,
+		Digest: digest,
+		State:  upgradeState,
+	}
+	if upgradeState != serverlessv1alpha2.RuntimeImageUpgradeInProgress {
+		m.Log.Info(fmt.Sprintf("runtime image upgrade to %s is %s", image, upgradeState))
+		holdRuntimeImage(m)
+	}
+}
+
+// isRuntimeImageUpgrade checks if the deployed Function gets the new image of the same runtime,
+// the same image pinned to another digest, for example, when pinning is enabled or the tag is moved, is upgraded too
+func isRuntimeImageUpgrade(m *fsm.StateMachine, clusterDeployment *appsv1.Deployment) bool {
+	f := m.State.Function
+	if !m.Rollout.IsStaged() || clusterDeployment == nil ||
+		f.Spec.RuntimeImageOverride != "" {
+		return false
+	}
+
+	previousImage := f.Status.RuntimeImage
+	if previousImage == "" || f.Status.Runtime != runWithRuntime(m) {
+		return false
+	}
+
+	return m.State.BuiltDeployment.RuntimeImage() != previousImage ||
+		m.State.BuiltDeployment.RuntimeImageDigest() != f.Status.RuntimeImageDigest
+}
+
+// holdRuntimeImage keeps the previous runtime image, pinned to the previous digest if it was pinned
+func holdRuntimeImage(m *fsm.StateMachine) {
+	f := m.State.Function
+	pinnedImage := ""
+	if f.Status.RuntimeImageDigest != "" {
+		var err error
+		pinnedImage, err = oci.PinnedImage(f.Status.RuntimeImage, f.Status.RuntimeImageDigest)
+		if err != nil {
+			m.Log.Error(err, "unable to pin previous runtime image", "image", f.Status.RuntimeImage)
+		}
+	}
+	if pinnedImage == "" {
+		m.State.BuiltDeployment.HoldRuntimeImage(f.Status.RuntimeImage, "", "")
+		m.State.RuntimeImageDigestResolvedAt = nil
+		return
+	}
+	m.State.BuiltDeployment.HoldRuntimeImage(f.Status.RuntimeImage, pinnedImage, f.Status.RuntimeImageDigest)
+	m.State.RuntimeImageDigestResolvedAt = f.Status.RuntimeImageDigestResolvedAt
+}
+
+func isRuntimeImageUpgradeStarted(upgrade *serverlessv1alpha2.RuntimeImageUpgrade) bool {
+	return upgrade.State == serverlessv1alpha2.RuntimeImageUpgradeInProgress ||
+		upgrade.State == serverlessv1alpha2.RuntimeImageUpgradeFailed
+}
+
+// updateRuntimeImageUpgrade reports the result of the started upgrade to the rollout
+func updateRuntimeImageUpgrade(m *fsm.StateMachine, ready, failed bool) {
+	f := &m.State.Function
+	upgrade := f.Status.RuntimeImageUpgrade
+	if upgrade == nil || !isRuntimeImageUpgradeStarted(upgrade) {
+		return
+	}
+
+	key := client.ObjectKeyFromObject(f).String()
+	if m.State.BuiltDeployment.RuntimeImage() != upgrade.Image || m.State.BuiltDeployment.RuntimeImageDigest() != upgrade.Digest {
+		// the function doesn't run the new image anymore
+		m.Rollout.Forget(key)
+		f.Status.RuntimeImageUpgrade = nil
+		return
+	}
+
+	switch {
+	case ready:
+		m.Rollout.Succeed(key)
+		f.Status.RuntimeImageUpgrade = nil
+	case failed:
+		m.Rollout.Fail(key)
+		upgrade.State = serverlessv1alpha2.RuntimeImageUpgradeFailed
+	default:
+		// the upgrade could be started by the previous instance of the controller
+		m.Rollout.Resume(key)
+		upgrade.State = serverlessv1alpha2.RuntimeImageUpgradeInProgress
+	}
+}
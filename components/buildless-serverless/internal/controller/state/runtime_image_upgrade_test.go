@@ -0,0 +1,260 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/upgrade"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_stageRuntimeImageUpgrade(t *testing.T) {
+	t.Run("should hold new runtime image when the rollout has no free slot", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("other-ns/other-function", "other-ns", metav1.Now().Time)
+		m := upgradeTestMachine(upgradeTestFunction(), rollout)
+		clusterDeployment := upgradeTestDeployment("old-python-image")
+
+		// Act
+		stageRuntimeImageUpgrade(m, clusterDeployment)
+
+		// Assert
+		require.Equal(t, "old-python-image", m.State.BuiltDeployment.RuntimeImage())
+		require.Equal(t, &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image: "new-python-image",
+			State: serverlessv1alpha2.RuntimeImageUpgradePending,
+		}, m.State.Function.Status.RuntimeImageUpgrade)
+		require.Equal(t, 1, rollout.Progress().Pending)
+	})
+	t.Run("should start upgrade when the rollout has free slot", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		m := upgradeTestMachine(upgradeTestFunction(), rollout)
+		clusterDeployment := upgradeTestDeployment("old-python-image")
+
+		// Act
+		stageRuntimeImageUpgrade(m, clusterDeployment)
+
+		// Assert
+		require.Equal(t, "new-python-image", m.State.BuiltDeployment.RuntimeImage())
+		require.Equal(t, &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image: "new-python-image",
+			State: serverlessv1alpha2.RuntimeImageUpgradeInProgress,
+		}, m.State.Function.Status.RuntimeImageUpgrade)
+		require.Equal(t, 1, rollout.Progress().InProgress)
+	})
+	t.Run("should stage pinning of the runtime image to its digest", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("other-ns/other-function", "other-ns", metav1.Now().Time)
+		f := upgradeTestFunction()
+		f.Status.RuntimeImage = "new-python-image"
+		m := upgradeTestMachine(f, rollout)
+		m.State.BuiltDeployment.PinRuntimeImage("new-python-image@"+testRuntimeImageDigest, testRuntimeImageDigest)
+		clusterDeployment := upgradeTestDeployment("new-python-image")
+
+		// Act
+		stageRuntimeImageUpgrade(m, clusterDeployment)
+
+		// Assert
+		require.Equal(t, "new-python-image", m.State.BuiltDeployment.Spec.Template.Spec.Containers[0].Image)
+		require.Empty(t, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image:  "new-python-image",
+			Digest: testRuntimeImageDigest,
+			State:  serverlessv1alpha2.RuntimeImageUpgradePending,
+		}, m.State.Function.Status.RuntimeImageUpgrade)
+	})
+	t.Run("should hold previous digest of the moved runtime image tag", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("other-ns/other-function", "other-ns", metav1.Now().Time)
+		resolvedAt := &metav1.Time{Time: metav1.Now().Add(-time.Hour)}
+		f := upgradeTestFunction()
+		f.Status.RuntimeImage = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main"
+		f.Status.RuntimeImageDigest = testRuntimeImageDigest
+		f.Status.RuntimeImageDigestResolvedAt = resolvedAt
+		m := upgradeTestMachine(f, rollout)
+		m.FunctionConfig.Images.Python312 = "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main"
+		m.State.BuiltDeployment = resources.NewDeployment(&m.State.Function, &m.FunctionConfig, nil, "", nil, "",
+			resources.DeploySetRuntime(runWithRuntime(m)))
+		m.State.BuiltDeployment.PinRuntimeImage("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@"+testMovedRuntimeImageDigest,
+			testMovedRuntimeImageDigest)
+		clusterDeployment := upgradeTestDeployment("europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@" + testRuntimeImageDigest)
+
+		// Act
+		stageRuntimeImageUpgrade(m, clusterDeployment)
+
+		// Assert
+		require.Equal(t, "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312@"+testRuntimeImageDigest,
+			m.State.BuiltDeployment.Spec.Template.Spec.Containers[0].Image)
+		require.Equal(t, testRuntimeImageDigest, m.State.BuiltDeployment.RuntimeImageDigest())
+		require.Equal(t, resolvedAt, m.State.RuntimeImageDigestResolvedAt)
+		require.Equal(t, testMovedRuntimeImageDigest, m.State.Function.Status.RuntimeImageUpgrade.Digest)
+	})
+	t.Run("should not stage image set in the function", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("other-ns/other-function", "other-ns", metav1.Now().Time)
+		f := upgradeTestFunction()
+		f.Spec.RuntimeImageOverride = "custom-python-image"
+		f.Status.RuntimeImageUpgrade = &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image: "new-python-image",
+			State: serverlessv1alpha2.RuntimeImageUpgradePending,
+		}
+		m := upgradeTestMachine(f, rollout)
+		clusterDeployment := upgradeTestDeployment("old-python-image")
+
+		// Act
+		stageRuntimeImageUpgrade(m, clusterDeployment)
+
+		// Assert
+		require.Equal(t, "custom-python-image", m.State.BuiltDeployment.RuntimeImage())
+		require.Nil(t, m.State.Function.Status.RuntimeImageUpgrade)
+	})
+	t.Run("should not stage upgrades when staged rollout is disabled", func(t *testing.T) {
+		// Arrange
+		m := upgradeTestMachine(upgradeTestFunction(), upgrade.NewRollout(config.RuntimeUpgrade{}))
+		clusterDeployment := upgradeTestDeployment("old-python-image")
+
+		// Act
+		stageRuntimeImageUpgrade(m, clusterDeployment)
+
+		// Assert
+		require.Equal(t, "new-python-image", m.State.BuiltDeployment.RuntimeImage())
+		require.Nil(t, m.State.Function.Status.RuntimeImageUpgrade)
+	})
+}
+
+func Test_updateRuntimeImageUpgrade(t *testing.T) {
+	t.Run("should finish upgrade when deployment is ready", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("test-ns/test-function", "test-ns", metav1.Now().Time)
+		f := upgradeTestFunction()
+		f.Status.RuntimeImageUpgrade = &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image: "new-python-image",
+			State: serverlessv1alpha2.RuntimeImageUpgradeInProgress,
+		}
+		m := upgradeTestMachine(f, rollout)
+
+		// Act
+		updateRuntimeImageUpgrade(m, true, false)
+
+		// Assert
+		require.Nil(t, m.State.Function.Status.RuntimeImageUpgrade)
+		require.Equal(t, upgrade.Progress{}, rollout.Progress())
+	})
+	t.Run("should count failed upgrade", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1, MaxFailures: 1})
+		rollout.Start("test-ns/test-function", "test-ns", metav1.Now().Time)
+		f := upgradeTestFunction()
+		f.Status.RuntimeImageUpgrade = &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image: "new-python-image",
+			State: serverlessv1alpha2.RuntimeImageUpgradeInProgress,
+		}
+		m := upgradeTestMachine(f, rollout)
+
+		// Act
+		updateRuntimeImageUpgrade(m, false, true)
+
+		// Assert
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeFailed, m.State.Function.Status.RuntimeImageUpgrade.State)
+		require.Equal(t, upgrade.Progress{Failed: 1, Paused: true}, rollout.Progress())
+	})
+	t.Run("should forget upgrade when digest of the runtime image changes", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("test-ns/test-function", "test-ns", metav1.Now().Time)
+		f := upgradeTestFunction()
+		f.Status.RuntimeImageUpgrade = &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image:  "new-python-image",
+			Digest: testRuntimeImageDigest,
+			State:  serverlessv1alpha2.RuntimeImageUpgradeInProgress,
+		}
+		m := upgradeTestMachine(f, rollout)
+		m.State.BuiltDeployment.PinRuntimeImage("new-python-image@"+testMovedRuntimeImageDigest, testMovedRuntimeImageDigest)
+
+		// Act
+		updateRuntimeImageUpgrade(m, true, false)
+
+		// Assert
+		require.Nil(t, m.State.Function.Status.RuntimeImageUpgrade)
+		require.Equal(t, upgrade.Progress{}, rollout.Progress())
+	})
+	t.Run("should resume upgrade started before restart", func(t *testing.T) {
+		// Arrange
+		rollout := upgrade.NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		f := upgradeTestFunction()
+		f.Status.RuntimeImageUpgrade = &serverlessv1alpha2.RuntimeImageUpgrade{
+			Image: "new-python-image",
+			State: serverlessv1alpha2.RuntimeImageUpgradeInProgress,
+		}
+		m := upgradeTestMachine(f, rollout)
+
+		// Act
+		updateRuntimeImageUpgrade(m, false, false)
+
+		// Assert
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, m.State.Function.Status.RuntimeImageUpgrade.State)
+		require.Equal(t, upgrade.Progress{InProgress: 1}, rollout.Progress())
+	})
+}
+
+func upgradeTestFunction() serverlessv1alpha2.Function {
+	return serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function",
+			Namespace: "test-ns",
+		},
+		Spec: serverlessv1alpha2.FunctionSpec{
+			Runtime: serverlessv1alpha2.Python312,
+			Source: serverlessv1alpha2.Source{
+				Inline: &serverlessv1alpha2.InlineSource{
+					Source: "def main(event, context):\n  return 'ok'"},
+			},
+		},
+		Status: serverlessv1alpha2.FunctionStatus{
+			Runtime:      serverlessv1alpha2.Python312,
+			RuntimeImage: "old-python-image",
+		},
+	}
+}
+
+func upgradeTestMachine(f serverlessv1alpha2.Function, rollout *upgrade.Rollout) *fsm.StateMachine {
+	m := &fsm.StateMachine{
+		State: fsm.SystemState{
+			Function: f,
+		},
+		FunctionConfig: config.FunctionConfig{
+			Images: config.ImagesConfig{Python312: "new-python-image"},
+		},
+		Log:     zap.NewNop().Sugar(),
+		Rollout: rollout,
+	}
+	m.State.BuiltDeployment = resources.NewDeployment(&m.State.Function, &m.FunctionConfig, nil, "", nil, "",
+		resources.DeploySetRuntime(runWithRuntime(m)))
+	return m
+}
+
+func upgradeTestDeployment(image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "function", Image: image}},
+				},
+			},
+		},
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/catalog"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/validator"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -12,11 +13,17 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func sFnValidateFunction(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+func sFnValidateFunction(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
 	//TODO: It is a temporary solution to delete obsolete condition. It should be removed after migration from old serverless
 	meta.RemoveStatusCondition(&m.State.Function.Status.Conditions, "BuildReady")
 
-	v := validator.New(&m.State.Function, m.FunctionConfig)
+	functionRuntime, err := getFunctionRuntime(ctx, m, m.State.Function.Spec.Runtime.SupportedRuntimeEquivalent())
+	if err != nil {
+		return stopWithError(err)
+	}
+	m.State.FunctionRuntime = functionRuntime
+
+	v := validator.New(&m.State.Function, m.FunctionConfig, functionRuntime)
 	validationResults := v.Validate()
 	if len(validationResults) != 0 {
 		m.State.Function.UpdateCondition(
@@ -27,5 +34,21 @@ func sFnValidateFunction(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *
 		return stop()
 	}
 
-	return nextState(sFnHandleGitSources)
+	return nextState(sFnHandlePackageRegistryConfig)
+}
+
+// getFunctionRuntime fetches the FunctionRuntime describing the runtime the function runs with
+// the function runs with the built-in configuration of the runtime when it's missing,
+// runtimes which aren't built-in can't run without their FunctionRuntimes, they are reported by the validator
+func getFunctionRuntime(ctx context.Context, m *fsm.StateMachine, runtime serverlessv1alpha2.Runtime) (*serverlessv1alpha2.FunctionRuntime, error) {
+	if runtime == "" {
+		return nil, nil
+	}
+
+	functionRuntime, err := catalog.Get(ctx, m.Client, runtime)
+	if err != nil {
+		m.Log.Error(err, "unable to fetch FunctionRuntime", "FunctionRuntime", runtime)
+		return nil, err
+	}
+	return functionRuntime, nil
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"testing"
 )
 
@@ -30,14 +32,95 @@ func Test_sFnValidateFunction(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnHandleGitSources, next)
+		requireEqualFunc(t, sFnHandlePackageRegistryConfig, next)
 		// function has unchanged conditions
 		require.Empty(t, m.State.Function.Status.Conditions)
 	})
+	t.Run("when function's runtime is described in the catalog should go to the next state", func(t *testing.T) {
+		// Arrange
+		// runtime from the catalog
+		functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nodejs22"},
+			Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+				Image:        "custom-nodejs22:v1",
+				StartCommand: "node server.mjs"}}
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(functionRuntime).Build()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "elated-turing-name",
+						Namespace: "mystifying-snyder-ns"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22}}},
+			Client: k8sClient}
+
+		// Act
+		next, result, err := sFnValidateFunction(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// without stopping processing
+		require.Nil(t, result)
+		// with expected next state
+		require.NotNil(t, next)
+		requireEqualFunc(t, sFnHandlePackageRegistryConfig, next)
+		// runtime from the catalog is kept for next states
+		require.NotNil(t, m.State.FunctionRuntime)
+		require.Equal(t, "custom-nodejs22:v1", m.State.FunctionRuntime.Spec.Image)
+	})
+	t.Run("when function's runtime isn't built-in and is described in the catalog should go to the next state", func(t *testing.T) {
+		// Arrange
+		// runtime added by the platform team
+		functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nodejs24"},
+			Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+				Image: "custom-nodejs24:v1"}}
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(functionRuntime).Build()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "elated-turing-name",
+						Namespace: "mystifying-snyder-ns"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: "nodejs24"}}},
+			Client: k8sClient}
+
+		// Act
+		next, result, err := sFnValidateFunction(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// without stopping processing
+		require.Nil(t, result)
+		// with expected next state
+		requireEqualFunc(t, sFnHandlePackageRegistryConfig, next)
+		// the runtime isn't mapped to the built-in one
+		require.NotNil(t, m.State.FunctionRuntime)
+		require.Equal(t, "custom-nodejs24:v1", m.State.FunctionRuntime.Spec.Image)
+	})
 	t.Run("when function is invalid should stop processing", func(t *testing.T) {
 		// Arrange
+		// scheme and fake client without the runtime from the catalog
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 		// machine with our function
 		m := fsm.StateMachine{
+			Client: k8sClient,
 			State: fsm.SystemState{
 				Function: serverlessv1alpha2.Function{
 					ObjectMeta: metav1.ObjectMeta{
@@ -61,6 +144,6 @@ func Test_sFnValidateFunction(t *testing.T) {
 			serverlessv1alpha2.ConditionConfigurationReady,
 			metav1.ConditionFalse,
 			serverlessv1alpha2.ConditionReasonInvalidFunctionSpec,
-			"invalid runtime value: cannot find runtime: gracious-bardeen")
+			"invalid runtime value: cannot find runtime: gracious-bardeen, FunctionRuntime gracious-bardeen doesn't exist")
 	})
 }
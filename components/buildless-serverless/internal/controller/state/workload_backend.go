@@ -0,0 +1,151 @@
+package state
+
+import (
+	"context"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apilabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// workloadBackend serves the `service` function from the pod built for the function's Deployment
+type workloadBackend interface {
+	// handleState returns the state creating or updating resources serving the function
+	handleState() fsm.StateFn
+	// statusState returns the state reflecting the readiness of resources serving the function in its status
+	statusState() fsm.StateFn
+	// cleanup removes resources left when the function is switched to another backend or workload
+	cleanup(ctx context.Context, m *fsm.StateMachine) error
+}
+
+var workloadBackends = map[serverlessv1alpha2.WorkloadBackend]workloadBackend{
+	serverlessv1alpha2.WorkloadBackendDeployment: deploymentBackend{},
+	serverlessv1alpha2.WorkloadBackendKnative:    knativeBackend{},
+}
+
+// functionWorkloadBackend returns the backend set in the function's spec or the one configured for the cluster
+func functionWorkloadBackend(m *fsm.StateMachine) serverlessv1alpha2.WorkloadBackend {
+	if m.State.Function.Spec.Backend != "" {
+		return m.State.Function.Spec.Backend
+	}
+	backend := serverlessv1alpha2.WorkloadBackend(m.FunctionConfig.WorkloadBackend)
+	if _, ok := workloadBackends[backend]; !ok {
+		return serverlessv1alpha2.WorkloadBackendDeployment
+	}
+	return backend
+}
+
+// cleanupWorkloadBackends removes resources of all backends except the given one
+func cleanupWorkloadBackends(ctx context.Context, m *fsm.StateMachine, except serverlessv1alpha2.WorkloadBackend) error {
+	for name, backend := range workloadBackends {
+		if name == except {
+			continue
+		}
+		if err := backend.cleanup(ctx, m); err != nil {
+			return errors.Wrapf(err, "while cleaning up %s backend", name)
+		}
+	}
+	return nil
+}
+
+func sFnHandleWorkload(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	if m.State.Function.IsJob() {
+		return nextState(sFnHandleJob)
+	}
+
+	// the function could be switched from the job workload
+	if err := deleteJobs(ctx, m); err != nil {
+		return stopWithError(err)
+	}
+
+	name := functionWorkloadBackend(m)
+	if err := cleanupWorkloadBackends(ctx, m, name); err != nil {
+		return stopWithError(err)
+	}
+	return nextState(workloadBackends[name].handleState())
+}
+
+func sFnWorkloadStatus(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	m.State.Function.Status.ObservedGeneration = m.State.Function.GetGeneration()
+
+	if m.State.Function.IsJob() {
+		return nextState(sFnJobStatus)
+	}
+	m.State.Function.Status.Job = nil
+
+	return nextState(workloadBackends[functionWorkloadBackend(m)].statusState())
+}
+
+type deploymentBackend struct{}
+
+func (deploymentBackend) handleState() fsm.StateFn {
+	return sFnHandleDeployment
+}
+
+func (deploymentBackend) statusState() fsm.StateFn {
+	return sFnDeploymentStatus
+}
+
+// cleanup removes the Deployment, the ScaledObject, the PodDisruptionBudget and the Service of the function
+func (deploymentBackend) cleanup(ctx context.Context, m *fsm.StateMachine) error {
+	f := m.State.Function
+	if err := cleanupScaledObject(ctx, m); err != nil {
+		return errors.Wrap(err, "while deleting scaled object")
+	}
+
+	budget, err := getPodDisruptionBudget(ctx, m)
+	if err != nil {
+		return errors.Wrap(err, "while getting pod disruption budget")
+	}
+	if err := deletePodDisruptionBudget(ctx, m, budget); err != nil {
+		return errors.Wrap(err, "while deleting pod disruption budget")
+	}
+
+	err = m.Client.DeleteAllOf(ctx, &appsv1.Deployment{}, &client.DeleteAllOfOptions{
+		ListOptions: client.ListOptions{
+			LabelSelector: apilabels.SelectorFromSet(f.InternalFunctionLabels()),
+			Namespace:     f.GetNamespace(),
+		},
+		DeleteOptions: client.DeleteOptions{
+			PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "while deleting deployments")
+	}
+
+	service, err := getService(ctx, m)
+	if err != nil {
+		return errors.Wrap(err, "while getting service")
+	}
+	// the Service named after the function could be created by another backend
+	if service == nil || !metav1.IsControlledBy(service, &f) {
+		return nil
+	}
+	err = m.Client.Delete(ctx, service)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "while deleting service")
+	}
+	return nil
+}
+
+type knativeBackend struct{}
+
+func (knativeBackend) handleState() fsm.StateFn {
+	return sFnHandleKnativeService
+}
+
+func (knativeBackend) statusState() fsm.StateFn {
+	return sFnKnativeServiceStatus
+}
+
+func (knativeBackend) cleanup(ctx context.Context, m *fsm.StateMachine) error {
+	return deleteKnativeService(ctx, m)
+}
@@ -0,0 +1,235 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_sFnHandleWorkload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, batchv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	function := func(spec serverlessv1alpha2.FunctionSpec) serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+				UID:       "test-uid",
+			},
+			Spec: spec,
+		}
+	}
+	newMachine := func(f serverlessv1alpha2.Function, configBackend string, objs ...client.Object) *fsm.StateMachine {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(resources.KnativeServiceGVK, meta.RESTScopeNamespace)
+		return &fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).WithObjects(objs...).Build(),
+			Scheme: scheme,
+			FunctionConfig: config.FunctionConfig{
+				WorkloadBackend: configBackend,
+			},
+		}
+	}
+	controllerRef := []metav1.OwnerReference{
+		{APIVersion: "serverless.kyma-project.io/v1alpha2", Kind: "Function", Name: "test-function", UID: "test-uid", Controller: ptr.To(true)},
+	}
+
+	t.Run("go to the job state for job workload", func(t *testing.T) {
+		// Arrange
+		m := newMachine(function(serverlessv1alpha2.FunctionSpec{Workload: serverlessv1alpha2.WorkloadJob}), "knative")
+
+		// Act
+		next, result, err := sFnHandleWorkload(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleJob, next)
+	})
+	t.Run("use deployment backend by default", func(t *testing.T) {
+		// Arrange
+		m := newMachine(function(serverlessv1alpha2.FunctionSpec{}), "")
+
+		// Act
+		next, result, err := sFnHandleWorkload(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleDeployment, next)
+	})
+	t.Run("use backend configured for cluster", func(t *testing.T) {
+		// Arrange
+		m := newMachine(function(serverlessv1alpha2.FunctionSpec{}), "knative")
+
+		// Act
+		next, result, err := sFnHandleWorkload(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleKnativeService, next)
+	})
+	t.Run("prefer backend set in function's spec", func(t *testing.T) {
+		// Arrange
+		m := newMachine(function(serverlessv1alpha2.FunctionSpec{Backend: serverlessv1alpha2.WorkloadBackendDeployment}), "knative")
+
+		// Act
+		next, result, err := sFnHandleWorkload(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleDeployment, next)
+	})
+	t.Run("remove resources of other backend and job workload", func(t *testing.T) {
+		// Arrange
+		f := function(serverlessv1alpha2.FunctionSpec{Backend: serverlessv1alpha2.WorkloadBackendKnative})
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-abcde",
+				Namespace: "test-namespace",
+				Labels:    f.InternalFunctionLabels(),
+			},
+		}
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-function",
+				Namespace:       "test-namespace",
+				OwnerReferences: controllerRef,
+			},
+		}
+		budget := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-function",
+				Namespace:       "test-namespace",
+				OwnerReferences: controllerRef,
+			},
+		}
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-0123456789",
+				Namespace: "test-namespace",
+				Labels:    resources.JobLabels(&f),
+			},
+		}
+		m := newMachine(f, "", deployment, service, budget, job)
+
+		// Act
+		next, _, err := sFnHandleWorkload(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		requireEqualFunc(t, sFnHandleKnativeService, next)
+		deployments := &appsv1.DeploymentList{}
+		require.NoError(t, m.Client.List(context.Background(), deployments))
+		require.Empty(t, deployments.Items)
+		services := &corev1.ServiceList{}
+		require.NoError(t, m.Client.List(context.Background(), services))
+		require.Empty(t, services.Items)
+		budgets := &policyv1.PodDisruptionBudgetList{}
+		require.NoError(t, m.Client.List(context.Background(), budgets))
+		require.Empty(t, budgets.Items)
+		jobs := &batchv1.JobList{}
+		require.NoError(t, m.Client.List(context.Background(), jobs))
+		require.Empty(t, jobs.Items)
+	})
+	t.Run("remove owned Knative Service when switched to deployment backend", func(t *testing.T) {
+		// Arrange
+		f := function(serverlessv1alpha2.FunctionSpec{})
+		knativeService := &unstructured.Unstructured{}
+		knativeService.SetGroupVersionKind(resources.KnativeServiceGVK)
+		knativeService.SetName("test-function")
+		knativeService.SetNamespace("test-namespace")
+		knativeService.SetOwnerReferences(controllerRef)
+		m := newMachine(f, "", knativeService)
+
+		// Act
+		_, _, err := sFnHandleWorkload(context.Background(), m)
+
+		// Assert
+		require.Nil(t, err)
+		services := &unstructured.UnstructuredList{}
+		services.SetGroupVersionKind(resources.KnativeServiceGVK.GroupVersion().WithKind("ServiceList"))
+		require.NoError(t, m.Client.List(context.Background(), services))
+		require.Empty(t, services.Items)
+	})
+}
+
+func Test_sFnWorkloadStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          serverlessv1alpha2.FunctionSpec
+		configBackend string
+		want          fsm.StateFn
+	}{
+		{
+			name: "go to the job status for job workload",
+			spec: serverlessv1alpha2.FunctionSpec{Workload: serverlessv1alpha2.WorkloadJob},
+			want: sFnJobStatus,
+		},
+		{
+			name: "go to the deployment status for deployment backend",
+			spec: serverlessv1alpha2.FunctionSpec{},
+			want: sFnDeploymentStatus,
+		},
+		{
+			name:          "go to the knative service status for knative backend",
+			spec:          serverlessv1alpha2.FunctionSpec{},
+			configBackend: "knative",
+			want:          sFnKnativeServiceStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			m := &fsm.StateMachine{
+				State: fsm.SystemState{
+					Function: serverlessv1alpha2.Function{
+						ObjectMeta: metav1.ObjectMeta{Generation: 3},
+						Spec:       tt.spec,
+						Status: serverlessv1alpha2.FunctionStatus{
+							Job: &serverlessv1alpha2.JobStatus{Name: "test-function-0123456789"},
+						},
+					},
+				},
+				FunctionConfig: config.FunctionConfig{WorkloadBackend: tt.configBackend},
+			}
+
+			// Act
+			next, result, err := sFnWorkloadStatus(context.Background(), m)
+
+			// Assert
+			require.Nil(t, err)
+			require.Nil(t, result)
+			requireEqualFunc(t, tt.want, next)
+			require.Equal(t, int64(3), m.State.Function.Status.ObservedGeneration)
+			require.Equal(t, tt.spec.Workload == serverlessv1alpha2.WorkloadJob, m.State.Function.Status.Job != nil)
+		})
+	}
+}
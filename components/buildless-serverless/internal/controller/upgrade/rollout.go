@@ -0,0 +1,221 @@
+package upgrade
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// pendingEventsBuffer limits requeue events waiting for the controller, further events are dropped
+// and pending Functions are checked again with the periodic requeue
+const pendingEventsBuffer = 1000
+
+// Progress summarizes the rollout of runtime images
+type Progress struct {
+	Pending    int
+	InProgress int
+	Failed     int
+	Paused     bool
+}
+
+// Rollout limits Functions upgraded to the new runtime image at the same time
+// it's shared by all workers of the Function Controller, in-progress and failed upgrades are persisted by the Store
+type Rollout struct {
+	mu         sync.Mutex
+	config     config.RuntimeUpgrade
+	pending    sets.Set[string]
+	inProgress sets.Set[string]
+	failed     sets.Set[string]
+	// restored is false until the Store restores the persisted state, upgrades aren't started before
+	restored bool
+	// changed notifies the Store about changes to persist
+	changed chan struct{}
+	// events requeue pending Functions when they can be upgraded
+	events chan event.GenericEvent
+}
+
+func NewRollout(c config.RuntimeUpgrade) *Rollout {
+	return &Rollout{
+		config:     c,
+		pending:    sets.New[string](),
+		inProgress: sets.New[string](),
+		failed:     sets.New[string](),
+		restored:   true,
+		changed:    make(chan struct{}, 1),
+		events:     make(chan event.GenericEvent, pendingEventsBuffer),
+	}
+}
+
+// Events returns the channel with requeue events of pending Functions
+// they are sent when the upgrade slot is released or the paused rollout is resumed
+func (r *Rollout) Events() <-chan event.GenericEvent {
+	return r.events
+}
+
+// IsStaged checks if the rollout limits upgrades at all
+func (r *Rollout) IsStaged() bool {
+	return r != nil && r.config.IsStaged()
+}
+
+// Start decides if the Function can be upgraded now
+// Functions which can't be upgraded are remembered as pending until they start or are forgotten
+func (r *Rollout) Start(key, namespace string, now time.Time) serverlessv1alpha2.RuntimeImageUpgradeState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.inProgress.Has(key) {
+		return serverlessv1alpha2.RuntimeImageUpgradeInProgress
+	}
+	if !r.restored {
+		r.pending.Insert(key)
+		return serverlessv1alpha2.RuntimeImageUpgradePending
+	}
+	if r.isPaused() {
+		r.pending.Insert(key)
+		return serverlessv1alpha2.RuntimeImageUpgradePaused
+	}
+	if !r.config.WindowFor(namespace).IsOpen(now) {
+		r.pending.Insert(key)
+		return serverlessv1alpha2.RuntimeImageUpgradePending
+	}
+	if r.config.MaxConcurrent > 0 && r.inProgress.Len() >= r.config.MaxConcurrent {
+		r.pending.Insert(key)
+		return serverlessv1alpha2.RuntimeImageUpgradePending
+	}
+
+	r.pending.Delete(key)
+	r.inProgress.Insert(key)
+	r.notifyChanged()
+	return serverlessv1alpha2.RuntimeImageUpgradeInProgress
+}
+
+// Resume counts the upgrade started before, for example, by the previous instance of the Function Controller
+func (r *Rollout) Resume(key string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending.Delete(key)
+	if !r.inProgress.Has(key) {
+		r.inProgress.Insert(key)
+		r.notifyChanged()
+	}
+}
+
+// Succeed releases the upgrade slot of the Function
+func (r *Rollout) Succeed(key string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending.Delete(key)
+	r.release(key, false)
+}
+
+// Fail releases the upgrade slot of the Function and counts its failure
+// the rollout is paused until enough failed Functions are fixed or removed
+func (r *Rollout) Fail(key string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending.Delete(key)
+	r.release(key, true)
+}
+
+// Forget removes the Function which doesn't wait for the upgrade anymore
+func (r *Rollout) Forget(key string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending.Delete(key)
+	r.release(key, false)
+}
+
+func (r *Rollout) Progress() Progress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Progress{
+		Pending:    r.pending.Len(),
+		InProgress: r.inProgress.Len(),
+		Failed:     r.failed.Len(),
+		Paused:     r.isPaused(),
+	}
+}
+
+// release frees the upgrade slot of the Function and requeues pending Functions when they can be upgraded
+func (r *Rollout) release(key string, failed bool) {
+	wasInProgress, wasFailed, wasPaused := r.inProgress.Has(key), r.failed.Has(key), r.isPaused()
+	r.inProgress.Delete(key)
+	if failed {
+		r.failed.Insert(key)
+	} else {
+		r.failed.Delete(key)
+	}
+	if wasInProgress || wasFailed != failed {
+		r.notifyChanged()
+	}
+	if !r.isPaused() && (wasInProgress || wasPaused) {
+		r.requeuePending()
+	}
+}
+
+// restore sets upgrades persisted by the Store and requeues Functions which waited for it
+func (r *Rollout) restore(inProgress, failed []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inProgress.Insert(inProgress...)
+	r.failed.Insert(failed...)
+	r.pending.Delete(inProgress...)
+	r.restored = true
+	r.requeuePending()
+}
+
+// snapshot returns the state persisted by the Store
+func (r *Rollout) snapshot() (inProgress, failed []string, paused bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return sets.List(r.inProgress), sets.List(r.failed), r.isPaused()
+}
+
+// notifyChanged never blocks, a single notification is enough for the Store to persist the latest state
+func (r *Rollout) notifyChanged() {
+	select {
+	case r.changed <- struct{}{}:
+	default:
+	}
+}
+
+// requeuePending sends events of pending Functions in a stable order, events which don't fit into the buffer are dropped
+func (r *Rollout) requeuePending() {
+	for _, key := range sets.List(r.pending) {
+		namespace, name, _ := strings.Cut(key, "/")
+		select {
+		case r.events <- event.GenericEvent{Object: &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		}}:
+		default:
+			return
+		}
+	}
+}
+
+func (r *Rollout) isPaused() bool {
+	return r.config.MaxFailures > 0 && r.failed.Len() >= r.config.MaxFailures
+}
@@ -0,0 +1,110 @@
+package upgrade
+
+import (
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollout(t *testing.T) {
+	// monday
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should limit concurrent upgrades", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 2})
+
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-1", "ns", now))
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-2", "ns", now))
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradePending, rollout.Start("ns/fn-3", "ns", now))
+		// started upgrade is not counted twice
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-1", "ns", now))
+		require.Equal(t, Progress{Pending: 1, InProgress: 2}, rollout.Progress())
+
+		rollout.Succeed("ns/fn-1")
+
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-3", "ns", now))
+		require.Equal(t, Progress{InProgress: 2}, rollout.Progress())
+	})
+	t.Run("should upgrade only within the namespace window", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{
+			Window: config.MaintenanceWindow{Days: []string{"mon"}, Start: "10:00", Duration: 4 * time.Hour},
+			NamespaceWindows: map[string]config.MaintenanceWindow{
+				"prod": {Days: []string{"sat"}, Start: "10:00", Duration: 4 * time.Hour},
+			},
+		})
+
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("dev/fn", "dev", now))
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradePending, rollout.Start("prod/fn", "prod", now))
+	})
+	t.Run("should pause after failed upgrades", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 5, MaxFailures: 2})
+		rollout.Start("ns/fn-1", "ns", now)
+		rollout.Start("ns/fn-2", "ns", now)
+
+		rollout.Fail("ns/fn-1")
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-3", "ns", now))
+		rollout.Fail("ns/fn-2")
+
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradePaused, rollout.Start("ns/fn-4", "ns", now))
+		require.Equal(t, Progress{Pending: 1, InProgress: 1, Failed: 2, Paused: true}, rollout.Progress())
+
+		// fixed function resumes the rollout
+		rollout.Succeed("ns/fn-1")
+
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-4", "ns", now))
+	})
+	t.Run("should forget deleted function", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("ns/fn-1", "ns", now)
+		rollout.Start("ns/fn-2", "ns", now)
+
+		rollout.Forget("ns/fn-1")
+
+		require.Equal(t, Progress{Pending: 1}, rollout.Progress())
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradeInProgress, rollout.Start("ns/fn-2", "ns", now))
+	})
+	t.Run("should requeue pending functions when upgrade slot is released", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1})
+		rollout.Start("ns/fn-1", "ns", now)
+		rollout.Start("ns/fn-2", "ns", now)
+		require.Empty(t, rollout.Events())
+
+		rollout.Succeed("ns/fn-1")
+
+		require.Len(t, rollout.Events(), 1)
+		e := <-rollout.Events()
+		require.Equal(t, "ns", e.Object.GetNamespace())
+		require.Equal(t, "fn-2", e.Object.GetName())
+	})
+	t.Run("should requeue pending functions when paused rollout is resumed", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 5, MaxFailures: 1})
+		rollout.Start("ns/fn-1", "ns", now)
+		rollout.Fail("ns/fn-1")
+		rollout.Start("ns/fn-2", "ns", now)
+		require.Empty(t, rollout.Events())
+
+		rollout.Forget("ns/fn-1")
+
+		require.Len(t, rollout.Events(), 1)
+		require.Equal(t, "fn-2", (<-rollout.Events()).Object.GetName())
+	})
+	t.Run("should wait for restored state", func(t *testing.T) {
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 1, MaxFailures: 1})
+		rollout.restored = false
+
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradePending, rollout.Start("ns/fn-1", "ns", now))
+
+		rollout.restore(nil, []string{"ns/fn-2"})
+
+		require.Equal(t, "fn-1", (<-rollout.Events()).Object.GetName())
+		require.Equal(t, serverlessv1alpha2.RuntimeImageUpgradePaused, rollout.Start("ns/fn-1", "ns", now))
+		require.Equal(t, Progress{Pending: 1, Failed: 1, Paused: true}, rollout.Progress())
+	})
+	t.Run("should not stage upgrades by default", func(t *testing.T) {
+		require.False(t, NewRollout(config.RuntimeUpgrade{}).IsStaged())
+		require.False(t, (*Rollout)(nil).IsStaged())
+	})
+}
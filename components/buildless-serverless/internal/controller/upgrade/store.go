@@ -0,0 +1,164 @@
+package upgrade
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	inProgressKey = "inProgress"
+	failedKey     = "failed"
+	pausedKey     = "paused"
+
+	retryInterval = 5 * time.Second
+)
+
+// Store persists in-progress and failed upgrades of the rollout in the ConfigMap
+// so the limits and the pause of the rollout survive restarts of the Function Controller
+type Store struct {
+	client    client.Client
+	rollout   *Rollout
+	configMap types.NamespacedName
+	log       *zap.SugaredLogger
+}
+
+// NewStore creates the Store of the rollout, the rollout doesn't start upgrades until the Store restores its state
+func NewStore(client client.Client, rollout *Rollout, configMap string, log *zap.SugaredLogger) (*Store, error) {
+	namespace, name, found := strings.Cut(configMap, "/")
+	if !found || namespace == "" || name == "" {
+		return nil, errors.Errorf("invalid state ConfigMap %q, expected namespace/name", configMap)
+	}
+
+	rollout.mu.Lock()
+	rollout.restored = false
+	rollout.mu.Unlock()
+
+	return &Store{
+		client:    client,
+		rollout:   rollout,
+		configMap: types.NamespacedName{Namespace: namespace, Name: name},
+		log:       log,
+	}, nil
+}
+
+// Start restores the rollout and persists its changes until the context is done, it implements the manager's Runnable
+func (s *Store) Start(ctx context.Context) error {
+	err := wait.PollUntilContextCancel(ctx, retryInterval, true, func(ctx context.Context) (bool, error) {
+		if err := s.restore(ctx); err != nil {
+			s.log.Error(err, "unable to restore runtime image upgrade rollout")
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		// the context is done before the rollout is restored
+		return nil
+	}
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.rollout.changed:
+		case <-retry:
+		}
+		retry = nil
+		if err := s.save(ctx); err != nil {
+			s.log.Error(err, "unable to persist runtime image upgrade rollout")
+			retry = time.After(retryInterval)
+		}
+	}
+}
+
+// NeedLeaderElection persists the rollout only in the leading replica, which reconciles Functions
+func (s *Store) NeedLeaderElection() bool {
+	return true
+}
+
+func (s *Store) restore(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, s.configMap, configMap)
+	if client.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, "while fetching rollout state")
+	}
+
+	inProgress, err := s.existingFunctions(ctx, splitKeys(configMap.Data[inProgressKey]))
+	if err != nil {
+		return err
+	}
+	failed, err := s.existingFunctions(ctx, splitKeys(configMap.Data[failedKey]))
+	if err != nil {
+		return err
+	}
+	s.log.Infof("restoring runtime image upgrade rollout with %d upgrades in progress and %d failed", len(inProgress), len(failed))
+	s.rollout.restore(inProgress, failed)
+	return nil
+}
+
+func (s *Store) save(ctx context.Context) error {
+	inProgress, failed, paused := s.rollout.snapshot()
+	data := map[string]string{
+		inProgressKey: strings.Join(inProgress, "\n"),
+		failedKey:     strings.Join(failed, "\n"),
+		pausedKey:     strconv.FormatBool(paused),
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, s.configMap, configMap)
+	if k8serrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.configMap.Name,
+				Namespace: s.configMap.Namespace,
+			},
+			Data: data,
+		}
+		return errors.Wrap(s.client.Create(ctx, configMap), "while creating rollout state")
+	}
+	if err != nil {
+		return errors.Wrap(err, "while fetching rollout state")
+	}
+
+	configMap.Data = data
+	return errors.Wrap(s.client.Update(ctx, configMap), "while updating rollout state")
+}
+
+// existingFunctions skips Functions deleted while the controller wasn't running, so they don't hold upgrade slots
+func (s *Store) existingFunctions(ctx context.Context, keys []string) ([]string, error) {
+	existing := []string{}
+	for _, key := range keys {
+		namespace, name, _ := strings.Cut(key, "/")
+		err := s.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &serverlessv1alpha2.Function{})
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "while fetching function %s", key)
+		}
+		existing = append(existing, key)
+	}
+	return existing, nil
+}
+
+func splitKeys(value string) []string {
+	keys := []string{}
+	for _, key := range strings.Split(value, "\n") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
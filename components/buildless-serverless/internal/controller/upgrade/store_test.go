@@ -0,0 +1,93 @@
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+	key := client.ObjectKey{Namespace: "kyma-system", Name: "rollout-state"}
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should restore rollout and persist its changes", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Data: map[string]string{
+				"inProgress": "ns/fn-1\nns/deleted-fn",
+				"failed":     "ns/fn-2\nns/fn-3",
+				"paused":     "true",
+			},
+		}, function("fn-1"), function("fn-2"), function("fn-3")).Build()
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 5, MaxFailures: 2})
+		store, err := NewStore(k8sClient, rollout, "kyma-system/rollout-state", zap.NewNop().Sugar())
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = store.Start(ctx)
+		}()
+
+		// rollout is paused by failures from before the restart
+		require.Eventually(t, func() bool {
+			return rollout.Start("ns/fn-4", "ns", now) == serverlessv1alpha2.RuntimeImageUpgradePaused
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, Progress{Pending: 1, InProgress: 1, Failed: 2, Paused: true}, rollout.Progress())
+
+		rollout.Succeed("ns/fn-2")
+
+		require.Eventually(t, func() bool {
+			configMap := &corev1.ConfigMap{}
+			require.NoError(t, k8sClient.Get(context.Background(), key, configMap))
+			return configMap.Data["failed"] == "ns/fn-3" && configMap.Data["paused"] == "false"
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("should create missing state", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		rollout := NewRollout(config.RuntimeUpgrade{MaxConcurrent: 5})
+		store, err := NewStore(k8sClient, rollout, "kyma-system/rollout-state", zap.NewNop().Sugar())
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = store.Start(ctx)
+		}()
+		require.Eventually(t, func() bool {
+			return rollout.Start("ns/fn-1", "ns", now) == serverlessv1alpha2.RuntimeImageUpgradeInProgress
+		}, time.Second, 10*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			configMap := &corev1.ConfigMap{}
+			if err := k8sClient.Get(context.Background(), key, configMap); err != nil {
+				return false
+			}
+			return configMap.Data["inProgress"] == "ns/fn-1"
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("should reject invalid ConfigMap name", func(t *testing.T) {
+		_, err := NewStore(nil, NewRollout(config.RuntimeUpgrade{}), "rollout-state", zap.NewNop().Sugar())
+
+		require.EqualError(t, err, `invalid state ConfigMap "rollout-state", expected namespace/name`)
+	})
+}
+
+func function(name string) *serverlessv1alpha2.Function {
+	return &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+	}
+}
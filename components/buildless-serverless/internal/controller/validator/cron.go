@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	// time zones of schedules are validated also when the controller image has no tzdata
+	_ "time/tzdata"
+)
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronFields = []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		}},
+		{name: "day of week", min: 0, max: 6, names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		}},
+	}
+
+	// cronDescriptors are predefined schedules supported by CronJobs
+	cronDescriptors = map[string]bool{
+		"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+		"@daily": true, "@midnight": true, "@hourly": true,
+	}
+)
+
+// validateCron checks the standard (five fields) cron expression the same way as CronJob does
+func validateCron(expression string) error {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "TZ=") || strings.HasPrefix(expression, "CRON_TZ=") {
+		return errors.New("time zone can't be set in the cron expression, use timeZone instead")
+	}
+	if strings.HasPrefix(expression, "@") {
+		return validateCronDescriptor(expression)
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields, found %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		if err := validateCronField(field, cronFields[i]); err != nil {
+			return fmt.Errorf("invalid %s field %s: %s", cronFields[i].name, field, err.Error())
+		}
+	}
+	return nil
+}
+
+func validateCronDescriptor(expression string) error {
+	if cronDescriptors[expression] {
+		return nil
+	}
+	if every, found := strings.CutPrefix(expression, "@every "); found {
+		duration, err := time.ParseDuration(every)
+		if err != nil {
+			return fmt.Errorf("invalid @every duration %s", every)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("@every duration %s must be positive", every)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown descriptor %s", expression)
+}
+
+// validateCronField checks comma separated list of values, ranges and steps, for example, `1,10-20/2,*/15`
+func validateCronField(field string, f cronField) error {
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			stepValue, err := strconv.Atoi(step)
+			if err != nil || stepValue <= 0 {
+				return fmt.Errorf("invalid step %s", step)
+			}
+		}
+
+		if rangePart == "*" || rangePart == "?" {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(rangePart, "-")
+		startValue, err := parseCronValue(start, f)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		endValue, err := parseCronValue(end, f)
+		if err != nil {
+			return err
+		}
+		if startValue > endValue {
+			return fmt.Errorf("range start %s is greater than its end %s", start, end)
+		}
+	}
+	return nil
+}
+
+func parseCronValue(value string, f cronField) (int, error) {
+	if v, ok := f.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s", value)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+	return v, nil
+}
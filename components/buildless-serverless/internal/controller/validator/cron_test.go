@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateCron(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "every minute", expression: "* * * * *"},
+		{name: "steps, ranges and lists", expression: "*/15 8-18/2 1,15 * mon-fri"},
+		{name: "month names", expression: "0 0 1 JAN,JUL ?"},
+		{name: "descriptor", expression: "@daily"},
+		{name: "every descriptor", expression: "@every 1h30m"},
+		{
+			name:       "too few fields",
+			expression: "* * * *",
+			wantErr:    "expected 5 fields, found 4",
+		},
+		{
+			name:       "seconds field",
+			expression: "0 * * * * *",
+			wantErr:    "expected 5 fields, found 6",
+		},
+		{
+			name:       "value out of range",
+			expression: "60 * * * *",
+			wantErr:    "invalid minute field 60: value 60 out of range [0, 59]",
+		},
+		{
+			name:       "invalid day of week",
+			expression: "0 0 * * 7",
+			wantErr:    "invalid day of week field 7: value 7 out of range [0, 6]",
+		},
+		{
+			name:       "invalid step",
+			expression: "*/0 * * * *",
+			wantErr:    "invalid minute field */0: invalid step 0",
+		},
+		{
+			name:       "reversed range",
+			expression: "0 18-8 * * *",
+			wantErr:    "invalid hour field 18-8: range start 18 is greater than its end 8",
+		},
+		{
+			name:       "invalid value",
+			expression: "0 0 * foo *",
+			wantErr:    "invalid month field foo: invalid value foo",
+		},
+		{
+			name:       "unknown descriptor",
+			expression: "@sometimes",
+			wantErr:    "unknown descriptor @sometimes",
+		},
+		{
+			name:       "time zone in expression",
+			expression: "TZ=Europe/Warsaw 0 0 * * *",
+			wantErr:    "time zone can't be set in the cron expression, use timeZone instead",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCron(tt.expression)
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
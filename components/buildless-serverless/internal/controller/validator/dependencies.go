@@ -0,0 +1,179 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	pyprojectFileName   = "pyproject.toml"
+	poetryLockFileName  = "poetry.lock"
+	packageLockFileName = "package-lock.json"
+)
+
+var (
+	// simplified PEP 508 requirement: name, extras, version specifiers and environment markers
+	pythonRequirementRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?(\[[A-Za-z0-9._,\s-]*\])?\s*(\(?\s*(===|~=|==|!=|<=|>=|<|>)\s*[^\s,;()]+(\s*,\s*(===|~=|==|!=|<=|>=|<|>)\s*[^\s,;()]+)*\s*\)?)?\s*(;.+)?$`)
+	// requirement referenced directly by url (name @ url) or url/path only
+	pythonDirectRequirementRegex = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9._-]*[A-Za-z0-9])?(\[[A-Za-z0-9._,\s-]*\])?\s*@\s*)?([a-z+]+://|\.{0,2}/)\S+(\s*;.+)?$`)
+	pythonPinnedRequirementRegex = regexp.MustCompile(`===?\s*[^\s,;()]+\s*\)?\s*(;.+)?$`)
+	pipOptionRegex               = regexp.MustCompile(`^--?[a-zA-Z][a-zA-Z0-9-]*([=\s].*)?$`)
+	pipHashOptionRegex           = regexp.MustCompile(`^--hash[=\s](sha256|sha384|sha512):[0-9a-fA-F]+$`)
+)
+
+// validatePythonRequirements checks syntax of the requirements.txt content
+// when any requirement is hashed, or --require-hashes is used, all requirements must be pinned and hashed
+func validatePythonRequirements(requirements string) error {
+	type requirement struct {
+		spec   string
+		hashed bool
+	}
+	var parsed []requirement
+	requireHashes := false
+	for _, line := range pythonRequirementsLines(requirements) {
+		if strings.HasPrefix(line, "-") {
+			if !pipOptionRegex.MatchString(line) {
+				return fmt.Errorf("invalid option %q", line)
+			}
+			if line == "--require-hashes" {
+				requireHashes = true
+			}
+			continue
+		}
+
+		spec, options := splitPythonRequirementOptions(line)
+		if !pythonRequirementRegex.MatchString(spec) && !pythonDirectRequirementRegex.MatchString(spec) {
+			return fmt.Errorf("invalid requirement %q", spec)
+		}
+		hashed := false
+		for _, option := range options {
+			if strings.HasPrefix(option, "--hash") {
+				if !pipHashOptionRegex.MatchString(option) {
+					return fmt.Errorf("invalid hash %q for requirement %q", option, spec)
+				}
+				hashed = true
+			}
+		}
+		requireHashes = requireHashes || hashed
+		parsed = append(parsed, requirement{spec: spec, hashed: hashed})
+	}
+
+	if !requireHashes {
+		return nil
+	}
+	for _, r := range parsed {
+		if !r.hashed {
+			return fmt.Errorf("requirement %q has no hash while hashes are required", r.spec)
+		}
+		if !pythonPinnedRequirementRegex.MatchString(r.spec) && !pythonDirectRequirementRegex.MatchString(r.spec) {
+			return fmt.Errorf("requirement %q must be pinned with '==' while hashes are required", r.spec)
+		}
+	}
+	return nil
+}
+
+// pythonRequirementsLines returns non-empty lines without comments and with joined line continuations
+func pythonRequirementsLines(requirements string) []string {
+	joined := strings.ReplaceAll(requirements, "\\\r\n", " ")
+	joined = strings.ReplaceAll(joined, "\\\n", " ")
+
+	var lines []string
+	for _, line := range strings.Split(joined, "\n") {
+		if i := strings.Index(line, "#"); i == 0 || (i > 0 && (line[i-1] == ' ' || line[i-1] == '\t')) {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitPythonRequirementOptions separates the requirement specifier from per-requirement options like --hash
+func splitPythonRequirementOptions(line string) (string, []string) {
+	i := strings.Index(line, " -")
+	if i < 0 {
+		return line, nil
+	}
+	var options []string
+	for _, field := range strings.Fields(line[i:]) {
+		if strings.HasPrefix(field, "-") || len(options) == 0 {
+			options = append(options, field)
+			continue
+		}
+		// value of the option separated with space
+		options[len(options)-1] = options[len(options)-1] + " " + field
+	}
+	return strings.TrimSpace(line[:i]), options
+}
+
+// validatePyProject checks syntax of pyproject.toml and its project and poetry dependencies
+// poetry projects without poetry.lock are installed from their [tool.poetry] dependencies
+func validatePyProject(content string) error {
+	pyproject := struct {
+		Project *struct {
+			Dependencies []string `toml:"dependencies"`
+		} `toml:"project"`
+		Tool struct {
+			Poetry *struct {
+				Dependencies map[string]any `toml:"dependencies"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}{}
+	if _, err := toml.Decode(content, &pyproject); err != nil {
+		return err
+	}
+
+	if pyproject.Project == nil && pyproject.Tool.Poetry == nil {
+		return errors.New("neither [project] nor [tool.poetry] table is defined")
+	}
+	if pyproject.Project != nil {
+		for _, dependency := range pyproject.Project.Dependencies {
+			dependency = strings.TrimSpace(dependency)
+			if !pythonRequirementRegex.MatchString(dependency) && !pythonDirectRequirementRegex.MatchString(dependency) {
+				return fmt.Errorf("invalid dependency %q", dependency)
+			}
+		}
+	}
+	if pyproject.Tool.Poetry != nil {
+		for name, value := range pyproject.Tool.Poetry.Dependencies {
+			// poetry dependency is a version constraint, a table or a list of tables with multiple constraints
+			switch value.(type) {
+			case string, map[string]any, []map[string]any, []any:
+			default:
+				return fmt.Errorf("invalid poetry dependency %q", name)
+			}
+		}
+	}
+	return nil
+}
+
+// validatePoetryLock checks syntax of poetry.lock
+func validatePoetryLock(content string) error {
+	lock := map[string]any{}
+	if _, err := toml.Decode(content, &lock); err != nil {
+		return err
+	}
+	if _, ok := lock["metadata"]; !ok {
+		return errors.New("lockfile has no [metadata] table")
+	}
+	return nil
+}
+
+// validatePackageLock checks syntax of package-lock.json used by npm ci
+func validatePackageLock(content string) error {
+	lock := map[string]any{}
+	if err := json.Unmarshal([]byte(content), &lock); err != nil {
+		return err
+	}
+	if _, ok := lock["lockfileVersion"]; !ok {
+		return errors.New("lockfile has no lockfileVersion")
+	}
+	return nil
+}
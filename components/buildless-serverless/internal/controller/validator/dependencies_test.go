@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validatePythonRequirements(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements string
+		wantErr      string
+	}{
+		{
+			name:         "empty requirements",
+			requirements: "",
+		},
+		{
+			name: "requirements with specifiers, extras, markers and comments",
+			requirements: `# web
+requests[security]==2.31.0 # pinned
+flask>=2.0,<3.0
+numpy ; python_version >= "3.10"
+-i https://pypi.org/simple
+--extra-index-url https://example.com/simple
+`,
+		},
+		{
+			name:         "direct references",
+			requirements: "mylib @ https://example.com/mylib-1.0.tar.gz\ngit+https://github.com/org/repo.git@main\n./local-package",
+		},
+		{
+			name: "hashed requirements with line continuations",
+			requirements: `--require-hashes
+requests==2.31.0 \
+    --hash=sha256:58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f \
+    --hash=sha256:942c5a758f98d790eaed1a29cb6eefc7ffb0d1cf7af05c3d2791656dbd6ad1e1
+`,
+		},
+		{
+			name:         "invalid requirement",
+			requirements: "requests ?? 2",
+			wantErr:      `invalid requirement "requests ?? 2"`,
+		},
+		{
+			name:         "invalid option",
+			requirements: "-- index",
+			wantErr:      `invalid option "-- index"`,
+		},
+		{
+			name:         "invalid hash",
+			requirements: "requests==2.31.0 --hash=md5:abc",
+			wantErr:      `invalid hash "--hash=md5:abc" for requirement "requests==2.31.0"`,
+		},
+		{
+			name:         "missing hash when hashes are required",
+			requirements: "--require-hashes\nrequests==2.31.0",
+			wantErr:      `requirement "requests==2.31.0" has no hash while hashes are required`,
+		},
+		{
+			name:         "missing hash when other requirement is hashed",
+			requirements: "requests==2.31.0 --hash=sha256:58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f\nflask==3.0.0",
+			wantErr:      `requirement "flask==3.0.0" has no hash while hashes are required`,
+		},
+		{
+			name:         "not pinned requirement when hashes are required",
+			requirements: "requests>=2.31.0 --hash=sha256:58cd2187c01e70e6e26505bca751777aa9f2ee0b7f4300988b709f44e013003f",
+			wantErr:      `requirement "requests>=2.31.0" must be pinned with '==' while hashes are required`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePythonRequirements(tt.requirements)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_validatePyProject(t *testing.T) {
+	t.Run("invalid toml", func(t *testing.T) {
+		err := validatePyProject("[project")
+		require.Error(t, err)
+	})
+	t.Run("no project and poetry tables", func(t *testing.T) {
+		err := validatePyProject("[tool.black]\nline-length = 100\n")
+		require.EqualError(t, err, "neither [project] nor [tool.poetry] table is defined")
+	})
+	t.Run("poetry dependencies", func(t *testing.T) {
+		err := validatePyProject(`[tool.poetry.dependencies]
+python = "^3.12"
+requests = "^2.31"
+flask = { version = "^3.0", extras = ["async"] }
+numpy = [
+  { version = "^1.26", python = "<3.13" },
+  { version = "^2.0", python = ">=3.13" },
+]
+`)
+		require.NoError(t, err)
+	})
+	t.Run("invalid poetry dependency", func(t *testing.T) {
+		err := validatePyProject("[tool.poetry.dependencies]\nrequests = 2\n")
+		require.EqualError(t, err, "invalid poetry dependency \"requests\"")
+	})
+}
+
+func Test_validatePoetryLock(t *testing.T) {
+	t.Run("valid lockfile", func(t *testing.T) {
+		err := validatePoetryLock("[[package]]\nname = \"requests\"\nversion = \"2.31.0\"\n\n[metadata]\nlock-version = \"2.0\"\n")
+		require.NoError(t, err)
+	})
+	t.Run("lockfile without metadata", func(t *testing.T) {
+		err := validatePoetryLock("[[package]]\nname = \"requests\"\n")
+		require.EqualError(t, err, "lockfile has no [metadata] table")
+	})
+}
@@ -0,0 +1,503 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver/v3"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+)
+
+const (
+	npmEcosystem  = "npm"
+	pypiEcosystem = "pypi"
+
+	npmDefaultRegistry  = "https://registry.npmjs.org/"
+	pypiDefaultRegistry = "https://pypi.org/simple"
+
+	npmrcFileName = ".npmrc"
+
+	inlineDependenciesField = "source.inline.dependencies"
+)
+
+var (
+	pythonRequirementNameRegex   = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*(.*)$`)
+	pythonPinnedVersionRegex     = regexp.MustCompile(`^\(?\s*===?\s*([^\s,;()*]+)\s*\)?\s*(;.*)?$`)
+	pythonNameNormalizationRegex = regexp.MustCompile(`[-_.]+`)
+	npmExactVersionRegex         = regexp.MustCompile(`^[=v]*\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+	poetryExactVersionRegex      = regexp.MustCompile(`^(==)?\s*\d+(\.\d+)*([a-z0-9.+-]*)$`)
+)
+
+// policyPackage is a package declared in the Function's dependencies or lockfiles
+type policyPackage struct {
+	name string
+	// version is known only for pinned or locked packages
+	version string
+	// spec is the declared version specifier used in messages when version is unknown
+	spec   string
+	direct bool
+	field  string
+}
+
+func (p policyPackage) String() string {
+	if p.version != "" {
+		return fmt.Sprintf("%s@%s", p.name, p.version)
+	}
+	return p.name
+}
+
+// policyRegistry is a registry or url the Function's dependencies are installed from
+type policyRegistry struct {
+	url   string
+	field string
+	// index replaces the default registry of the ecosystem
+	index bool
+}
+
+type parsedPackageRule struct {
+	config.PackageRule
+	constraints *semver.Constraints
+}
+
+func (r parsedPackageRule) String() string {
+	if r.Versions == "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s (%s)", r.Name, r.Versions)
+}
+
+func (r parsedPackageRule) matchesName(ecosystem, name string) bool {
+	if r.Ecosystem != "" && r.Ecosystem != ecosystem {
+		return false
+	}
+	matched, _ := path.Match(r.Name, name)
+	return matched
+}
+
+func (v *validator) validateDependencyPolicy() []string {
+	policy := v.fnConfig.DependencyPolicy
+	inlineSource := v.instance.Spec.Source.Inline
+	if inlineSource == nil || policy.IsEmpty() {
+		return []string{}
+	}
+
+	allowed, err := parsePackageRules(policy.Allowed)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid dependency policy: %s", err.Error())}
+	}
+	denied, err := parsePackageRules(policy.Denied)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid dependency policy: %s", err.Error())}
+	}
+
+	var ecosystem string
+	var packages []policyPackage
+	var registries []policyRegistry
+	runtime := v.instance.Spec.Runtime
+	if runtime.IsRuntimeNodejs() {
+		ecosystem = npmEcosystem
+		packages, registries, err = npmPolicyPackages(inlineSource.Dependencies, inlineSource.Files)
+	} else if runtime.IsRuntimePython() {
+		ecosystem = pypiEcosystem
+		packages, registries, err = pypiPolicyPackages(inlineSource.Dependencies, inlineSource.Files)
+	} else {
+		return []string{}
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("dependencies can't be checked against the dependency policy: %s", err.Error())}
+	}
+
+	unique := map[string]struct{}{}
+	for _, r := range registries {
+		if msg := checkPolicyRegistry(policy.AllowedRegistries, r); msg != "" {
+			unique[msg] = struct{}{}
+		}
+	}
+	for _, p := range packages {
+		if msg := checkPolicyPackage(ecosystem, allowed, denied, p); msg != "" {
+			unique[msg] = struct{}{}
+		}
+	}
+
+	result := []string{}
+	for msg := range unique {
+		result = append(result, msg)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func parsePackageRules(rules []config.PackageRule) ([]parsedPackageRule, error) {
+	parsed := make([]parsedPackageRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Ecosystem != "" && rule.Ecosystem != npmEcosystem && rule.Ecosystem != pypiEcosystem {
+			return nil, fmt.Errorf("rule %s has unknown ecosystem %s", rule.Name, rule.Ecosystem)
+		}
+		if _, err := path.Match(rule.Name, ""); err != nil {
+			return nil, fmt.Errorf("rule %s has invalid name pattern: %s", rule.Name, err.Error())
+		}
+		p := parsedPackageRule{PackageRule: rule}
+		if rule.Versions != "" {
+			constraints, err := semver.NewConstraint(rule.Versions)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s has invalid versions %s: %s", rule.Name, rule.Versions, err.Error())
+			}
+			p.constraints = constraints
+		}
+		parsed = append(parsed, p)
+	}
+	return parsed, nil
+}
+
+func checkPolicyRegistry(allowedRegistries []string, r policyRegistry) string {
+	if len(allowedRegistries) == 0 {
+		return ""
+	}
+	u, err := url.Parse(r.url)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("%s: registry %s can't be checked against the dependency policy", r.field, r.url)
+	}
+	for _, pattern := range allowedRegistries {
+		if matched, _ := path.Match(pattern, u.Host); matched {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s: registry %s is not allowed by the dependency policy", r.field, u.Host)
+}
+
+func checkPolicyPackage(ecosystem string, allowed, denied []parsedPackageRule, p policyPackage) string {
+	var version *semver.Version
+	if p.version != "" {
+		version, _ = semver.NewVersion(p.version)
+	}
+
+	for _, rule := range denied {
+		if !rule.matchesName(ecosystem, p.name) {
+			continue
+		}
+		if rule.constraints == nil {
+			return fmt.Sprintf("%s: package %s is denied by the dependency policy rule %s", p.field, p, rule)
+		}
+		if version == nil {
+			return unpinnedPackageMessage(p)
+		}
+		if rule.constraints.Check(version) {
+			return fmt.Sprintf("%s: package %s is denied by the dependency policy rule %s", p.field, p, rule)
+		}
+	}
+
+	// allow list applies only to direct dependencies, transitive ones are resolved by the package manager
+	if len(allowed) == 0 || !p.direct {
+		return ""
+	}
+	var allowedVersions []string
+	for _, rule := range allowed {
+		if !rule.matchesName(ecosystem, p.name) {
+			continue
+		}
+		if rule.constraints == nil {
+			return ""
+		}
+		allowedVersions = append(allowedVersions, rule.Versions)
+	}
+	if len(allowedVersions) == 0 {
+		return fmt.Sprintf("%s: package %s is not allowed by the dependency policy", p.field, p.name)
+	}
+	if version == nil {
+		return unpinnedPackageMessage(p)
+	}
+	for _, rule := range allowed {
+		if rule.matchesName(ecosystem, p.name) && rule.constraints.Check(version) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s: package %s version is not allowed by the dependency policy, allowed versions: %s",
+		p.field, p, strings.Join(allowedVersions, ", "))
+}
+
+func unpinnedPackageMessage(p policyPackage) string {
+	return fmt.Sprintf("%s: package %s version %s can't be checked against the dependency policy, pin the exact version",
+		p.field, p.name, p.spec)
+}
+
+// npmPolicyPackages returns packages declared in package.json and package-lock.json
+// and registries configured in .npmrc
+func npmPolicyPackages(dependencies string, files map[string]string) ([]policyPackage, []policyRegistry, error) {
+	if strings.TrimSpace(dependencies) == "" {
+		return nil, nil, nil
+	}
+
+	packageJSON := struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}{}
+	if err := json.Unmarshal([]byte(dependencies), &packageJSON); err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", inlineDependenciesField, err.Error())
+	}
+
+	lockField := inlineFilesField(packageLockFileName)
+	lock := struct {
+		Packages map[string]struct {
+			Version  string `json:"version"`
+			Resolved string `json:"resolved"`
+			Link     bool   `json:"link"`
+		} `json:"packages"`
+	}{}
+	if content, ok := files[packageLockFileName]; ok {
+		if err := json.Unmarshal([]byte(content), &lock); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", lockField, err.Error())
+		}
+	}
+
+	registries := npmRegistries(files)
+	var packages []policyPackage
+	for _, deps := range []map[string]string{packageJSON.Dependencies, packageJSON.DevDependencies, packageJSON.OptionalDependencies} {
+		for name, spec := range deps {
+			p := policyPackage{name: name, spec: spec, direct: true, field: inlineDependenciesField}
+			if alias, ok := strings.CutPrefix(spec, "npm:"); ok {
+				if i := strings.LastIndex(alias, "@"); i > 0 {
+					p.name, p.spec = alias[:i], alias[i+1:]
+				} else {
+					p.name, p.spec = alias, ""
+				}
+			}
+			if u := npmDependencyURL(p.spec); u != "" {
+				registries = append(registries, policyRegistry{url: u, field: inlineDependenciesField})
+			} else if npmExactVersionRegex.MatchString(p.spec) {
+				p.version = strings.TrimLeft(p.spec, "=v")
+			} else if locked, ok := lock.Packages["node_modules/"+name]; ok {
+				p.version = locked.Version
+			}
+			packages = append(packages, p)
+		}
+	}
+
+	for key, locked := range lock.Packages {
+		i := strings.LastIndex(key, "node_modules/")
+		if i < 0 || locked.Link {
+			continue
+		}
+		packages = append(packages, policyPackage{
+			name:    key[i+len("node_modules/"):],
+			version: locked.Version,
+			spec:    locked.Version,
+			field:   lockField,
+		})
+		if locked.Resolved != "" {
+			registries = append(registries, policyRegistry{url: locked.Resolved, field: lockField})
+		}
+	}
+
+	if len(packages) != 0 && !hasIndexRegistry(registries) {
+		registries = append(registries, policyRegistry{url: npmDefaultRegistry, field: inlineDependenciesField})
+	}
+	return packages, registries, nil
+}
+
+// npmRegistries returns registries configured in .npmrc
+func npmRegistries(files map[string]string) []policyRegistry {
+	field := inlineFilesField(npmrcFileName)
+	registries := []policyRegistry{}
+	for _, line := range strings.Split(files[npmrcFileName], "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "registry" || strings.HasSuffix(key, ":registry") {
+			registries = append(registries, policyRegistry{url: strings.TrimSpace(value), field: field, index: key == "registry"})
+		}
+	}
+	return registries
+}
+
+// npmDependencyURL returns url of the dependency installed from outside of the registry
+func npmDependencyURL(spec string) string {
+	switch {
+	case strings.HasPrefix(spec, "github:"):
+		return "https://github.com/" + strings.TrimPrefix(spec, "github:")
+	case strings.HasPrefix(spec, "gitlab:"):
+		return "https://gitlab.com/" + strings.TrimPrefix(spec, "gitlab:")
+	case strings.HasPrefix(spec, "bitbucket:"):
+		return "https://bitbucket.org/" + strings.TrimPrefix(spec, "bitbucket:")
+	case strings.Contains(spec, "://"):
+		return strings.TrimPrefix(spec, "git+")
+	}
+	return ""
+}
+
+// pypiPolicyPackages returns packages and indexes declared in requirements, pyproject.toml and poetry.lock
+func pypiPolicyPackages(dependencies string, files map[string]string) ([]policyPackage, []policyRegistry, error) {
+	packages, registries := pythonRequirementsPackages(dependencies, inlineDependenciesField)
+
+	pyprojectField := inlineFilesField(pyprojectFileName)
+	if content, ok := files[pyprojectFileName]; ok {
+		pyproject := struct {
+			Project struct {
+				Dependencies []string `toml:"dependencies"`
+			} `toml:"project"`
+			Tool struct {
+				Poetry struct {
+					Dependencies map[string]any `toml:"dependencies"`
+					Source       []struct {
+						URL string `toml:"url"`
+					} `toml:"source"`
+				} `toml:"poetry"`
+			} `toml:"tool"`
+		}{}
+		if _, err := toml.Decode(content, &pyproject); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", pyprojectField, err.Error())
+		}
+		projectPackages, projectRegistries := pythonRequirementsPackages(strings.Join(pyproject.Project.Dependencies, "\n"), pyprojectField)
+		packages = append(packages, projectPackages...)
+		registries = append(registries, projectRegistries...)
+		for name, value := range pyproject.Tool.Poetry.Dependencies {
+			if name == "python" {
+				continue
+			}
+			packages = append(packages, poetryDependencyPackage(name, value, pyprojectField))
+			registries = append(registries, poetryDependencyRegistries(value, pyprojectField)...)
+		}
+		for _, source := range pyproject.Tool.Poetry.Source {
+			registries = append(registries, policyRegistry{url: source.URL, field: pyprojectField, index: true})
+		}
+	}
+
+	lockField := inlineFilesField(poetryLockFileName)
+	if content, ok := files[poetryLockFileName]; ok {
+		lock := struct {
+			Package []struct {
+				Name    string `toml:"name"`
+				Version string `toml:"version"`
+				Source  struct {
+					Type string `toml:"type"`
+					URL  string `toml:"url"`
+				} `toml:"source"`
+			} `toml:"package"`
+		}{}
+		if _, err := toml.Decode(content, &lock); err != nil {
+			return nil, nil, fmt.Errorf("%s: %s", lockField, err.Error())
+		}
+		for _, locked := range lock.Package {
+			packages = append(packages, policyPackage{
+				name:    normalizePythonPackageName(locked.Name),
+				version: locked.Version,
+				spec:    locked.Version,
+				field:   lockField,
+			})
+			if locked.Source.URL != "" && locked.Source.Type != "directory" && locked.Source.Type != "file" {
+				registries = append(registries, policyRegistry{url: locked.Source.URL, field: lockField})
+			}
+		}
+	}
+
+	if len(packages) != 0 && !hasIndexRegistry(registries) {
+		registries = append(registries, policyRegistry{url: pypiDefaultRegistry, field: inlineDependenciesField})
+	}
+	return packages, registries, nil
+}
+
+// pythonRequirementsPackages returns packages and index options from requirements.txt content
+func pythonRequirementsPackages(requirements, field string) ([]policyPackage, []policyRegistry) {
+	var packages []policyPackage
+	var registries []policyRegistry
+	for _, line := range pythonRequirementsLines(requirements) {
+		if strings.HasPrefix(line, "-") {
+			option, value, _ := strings.Cut(strings.Replace(line, "=", " ", 1), " ")
+			switch option {
+			case "-i", "--index-url":
+				registries = append(registries, policyRegistry{url: strings.TrimSpace(value), field: field, index: true})
+			case "--extra-index-url", "-f", "--find-links":
+				registries = append(registries, policyRegistry{url: strings.TrimSpace(value), field: field})
+			}
+			continue
+		}
+
+		spec, _ := splitPythonRequirementOptions(line)
+		if pythonDirectRequirementRegex.MatchString(spec) {
+			_, u, found := strings.Cut(spec, "@")
+			if !found {
+				u = spec
+			}
+			u, _, _ = strings.Cut(strings.TrimSpace(u), ";")
+			u = strings.TrimPrefix(strings.TrimSpace(u), "git+")
+			if strings.Contains(u, "://") {
+				registries = append(registries, policyRegistry{url: u, field: field})
+			}
+			if !found {
+				continue
+			}
+		}
+
+		matches := pythonRequirementNameRegex.FindStringSubmatch(spec)
+		if matches == nil {
+			continue
+		}
+		p := policyPackage{
+			name:   normalizePythonPackageName(matches[1]),
+			spec:   strings.TrimSpace(matches[3]),
+			direct: true,
+			field:  field,
+		}
+		if pinned := pythonPinnedVersionRegex.FindStringSubmatch(p.spec); pinned != nil {
+			p.version = pinned[1]
+		}
+		packages = append(packages, p)
+	}
+	return packages, registries
+}
+
+func poetryDependencyPackage(name string, value any, field string) policyPackage {
+	p := policyPackage{name: normalizePythonPackageName(name), direct: true, field: field}
+	switch v := value.(type) {
+	case string:
+		p.spec = v
+	case map[string]any:
+		if version, ok := v["version"].(string); ok {
+			p.spec = version
+		}
+	}
+	if poetryExactVersionRegex.MatchString(p.spec) {
+		p.version = strings.TrimSpace(strings.TrimPrefix(p.spec, "=="))
+	}
+	return p
+}
+
+func poetryDependencyRegistries(value any, field string) []policyRegistry {
+	v, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	var registries []policyRegistry
+	for _, key := range []string{"git", "url"} {
+		if u, ok := v[key].(string); ok {
+			registries = append(registries, policyRegistry{url: strings.TrimPrefix(u, "git+"), field: field})
+		}
+	}
+	return registries
+}
+
+func hasIndexRegistry(registries []policyRegistry) bool {
+	for _, r := range registries {
+		if r.index {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePythonPackageName normalizes the name according to PEP 503
+func normalizePythonPackageName(name string) string {
+	return strings.ToLower(pythonNameNormalizationRegex.ReplaceAllString(name, "-"))
+}
+
+func inlineFilesField(fileName string) string {
+	return fmt.Sprintf("source.inline.files key %s", fileName)
+}
@@ -0,0 +1,241 @@
+package validator
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validator_validateDependencyPolicy(t *testing.T) {
+	policy := config.DependencyPolicy{
+		AllowedRegistries: []string{"registry.npmjs.org", "pypi.org", "*.example.com"},
+		Allowed: []config.PackageRule{
+			{Ecosystem: "npm", Name: "lodash"},
+			{Ecosystem: "npm", Name: "@kyma/*"},
+			{Ecosystem: "npm", Name: "express", Versions: ">=4.18.0"},
+			{Ecosystem: "pypi", Name: "requests", Versions: ">=2.31.0"},
+			{Ecosystem: "pypi", Name: "flask"},
+		},
+		Denied: []config.PackageRule{
+			{Name: "left-pad"},
+			{Ecosystem: "npm", Name: "lodash", Versions: "<4.17.21"},
+			{Ecosystem: "npm", Name: "event-stream", Versions: "3.3.6"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		spec   serverlessv1alpha2.FunctionSpec
+		policy config.DependencyPolicy
+		want   []string
+	}{
+		{
+			name: "when policy is empty then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"left-pad": "1.3.0"}}`,
+					},
+				},
+			},
+			policy: config.DependencyPolicy{},
+			want:   []string{},
+		},
+		{
+			name: "when nodejs dependencies follow the policy then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "4.17.21", "@kyma/sdk": "^1.0.0", "express": "4.18.2"}}`,
+					},
+				},
+			},
+			policy: policy,
+			want:   []string{},
+		},
+		{
+			name: "when nodejs dependencies break the policy then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "4.17.20", "left-pad": "^1.3.0", "axios": "1.6.0", "express": "4.17.1"}, "devDependencies": {"mylib": "git+https://github.com/org/mylib.git"}}`,
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"source.inline.dependencies: package axios is not allowed by the dependency policy",
+				"source.inline.dependencies: package express@4.17.1 version is not allowed by the dependency policy, allowed versions: >=4.18.0",
+				"source.inline.dependencies: package left-pad is denied by the dependency policy rule left-pad",
+				"source.inline.dependencies: package lodash@4.17.20 is denied by the dependency policy rule lodash (<4.17.21)",
+				"source.inline.dependencies: package mylib is not allowed by the dependency policy",
+				"source.inline.dependencies: registry github.com is not allowed by the dependency policy",
+			},
+		},
+		{
+			name: "when nodejs dependency is not pinned then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "^4.17.0"}}`,
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"source.inline.dependencies: package lodash version ^4.17.0 can't be checked against the dependency policy, pin the exact version",
+			},
+		},
+		{
+			name: "when nodejs lockfile is provided then check locked versions and transitive dependencies",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "^4.17.0"}}`,
+						Files: map[string]string{
+							"package-lock.json": `{"lockfileVersion": 3, "packages": {
+								"": {"name": "function"},
+								"node_modules/lodash": {"version": "4.17.21", "resolved": "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz"},
+								"node_modules/lodash/node_modules/event-stream": {"version": "3.3.6", "resolved": "https://mirror.evil.io/event-stream/-/event-stream-3.3.6.tgz"}
+							}}`,
+						},
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"source.inline.files key package-lock.json: package event-stream@3.3.6 is denied by the dependency policy rule event-stream (3.3.6)",
+				"source.inline.files key package-lock.json: registry mirror.evil.io is not allowed by the dependency policy",
+			},
+		},
+		{
+			name: "when nodejs registry is configured in .npmrc then check it",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "4.17.21", "@kyma/sdk": "1.0.0"}}`,
+						Files: map[string]string{
+							".npmrc": "registry=https://npm.example.com/\n@kyma:registry=https://npm.other.io/",
+						},
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"source.inline.files key .npmrc: registry npm.other.io is not allowed by the dependency policy",
+			},
+		},
+		{
+			name: "when python requirements follow the policy then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: "Requests==2.31.0\nFlask>=2.0",
+					},
+				},
+			},
+			policy: policy,
+			want:   []string{},
+		},
+		{
+			name: "when python requirements break the policy then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: "--index-url https://pypi.mirror.io/simple\nrequests==2.30.0\nleft_pad==1.0\nnumpy\nmylib @ https://files.example.com/mylib-1.0.tar.gz",
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"source.inline.dependencies: package left-pad@1.0 is denied by the dependency policy rule left-pad",
+				"source.inline.dependencies: package mylib is not allowed by the dependency policy",
+				"source.inline.dependencies: package numpy is not allowed by the dependency policy",
+				"source.inline.dependencies: package requests@2.30.0 version is not allowed by the dependency policy, allowed versions: >=2.31.0",
+				"source.inline.dependencies: registry pypi.mirror.io is not allowed by the dependency policy",
+			},
+		},
+		{
+			name: "when python poetry project is provided then check pyproject.toml and poetry.lock",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\nrequests = \"^2.31\"\n",
+							"poetry.lock":    "[[package]]\nname = \"requests\"\nversion = \"2.31.0\"\n\n[[package]]\nname = \"left-pad\"\nversion = \"1.0.0\"\n\n[metadata]\nlock-version = \"2.0\"\n",
+						},
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"source.inline.files key poetry.lock: package left-pad@1.0.0 is denied by the dependency policy rule left-pad",
+				"source.inline.files key pyproject.toml: package requests version ^2.31 can't be checked against the dependency policy, pin the exact version",
+			},
+		},
+		{
+			name: "when policy is invalid then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+					},
+				},
+			},
+			policy: config.DependencyPolicy{
+				Denied: []config.PackageRule{{Name: "lodash", Versions: "not-a-version"}},
+			},
+			want: []string{
+				"invalid dependency policy: rule lodash has invalid versions not-a-version: improper constraint: not-a-version",
+			},
+		},
+		{
+			name: "when dependencies can't be parsed then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": []}`,
+					},
+				},
+			},
+			policy: policy,
+			want: []string{
+				"dependencies can't be checked against the dependency policy: source.inline.dependencies: json: cannot unmarshal array into Go struct field .dependencies of type map[string]string",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: tt.spec,
+			}
+
+			v := New(f, config.FunctionConfig{DependencyPolicy: tt.policy}, nil)
+			r := v.validateDependencyPolicy()
+			require.Equal(t, tt.want, r)
+		})
+	}
+}
@@ -3,12 +3,20 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
@@ -18,14 +26,18 @@ import (
 )
 
 type validator struct {
-	instance *serverlessv1alpha2.Function
-	fnConfig config.FunctionConfig
+	instance        *serverlessv1alpha2.Function
+	fnConfig        config.FunctionConfig
+	functionRuntime *serverlessv1alpha2.FunctionRuntime
 }
 
-func New(instance *serverlessv1alpha2.Function, fnConfig config.FunctionConfig) *validator {
+// New returns the validator of the function, functionRuntime is the FunctionRuntime describing the function's runtime
+// or nil when it's missing
+func New(instance *serverlessv1alpha2.Function, fnConfig config.FunctionConfig, functionRuntime *serverlessv1alpha2.FunctionRuntime) *validator {
 	return &validator{
-		instance: instance,
-		fnConfig: fnConfig,
+		instance:        instance,
+		fnConfig:        fnConfig,
+		functionRuntime: functionRuntime,
 	}
 }
 
@@ -33,11 +45,31 @@ func (v *validator) Validate() []string {
 	fns := []func() []string{
 		v.validateEnvs,
 		v.validateInlineDeps,
+		v.validateInlineFiles,
+		v.validateInlineDependencyFiles,
+		v.validateDependencyPolicy,
+		v.validateInlineSourcesSize,
 		v.validateRuntime,
+		v.validateRuntimeImageOverride,
+		v.ValidateFunctionRuntimeImage,
+		v.validateLanguage,
 		v.validateSecretMounts,
+		v.validatePackageRegistryConfig,
+		v.validateExpose,
+		v.validateSchedules,
+		v.validateSubscriptions,
+		v.validateAsync,
+		v.validateWorkload,
+		v.validateBackend,
+		v.validateScaleTriggers,
+		v.validateNetworkPolicy,
+		v.validateAuth,
 		v.validateFunctionLabels,
 		v.validateFunctionAnnotations,
 		v.validateGitRepoURL,
+		v.validateConfigMapSource,
+		v.validateOCISource,
+		v.validateArchiveSource,
 		v.validateFunctionResources,
 	}
 
@@ -73,14 +105,178 @@ func (v *validator) validateInlineDeps() []string {
 	return []string{}
 }
 
+func (v *validator) validateInlineFiles() []string {
+	inlineSource := v.instance.Spec.Source.Inline
+	if inlineSource == nil {
+		return []string{}
+	}
+	reserved := reservedInlineFileNames(v.instance.Spec.Runtime, v.functionRuntime)
+	result := []string{}
+	for filePath := range inlineSource.Files {
+		if err := validateInlineFilePath(filePath, reserved); err != nil {
+			result = append(result, fmt.Sprintf("invalid source.inline.files key %s: %s", filePath, err.Error()))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (v *validator) validateInlineDependencyFiles() []string {
+	inlineSource := v.instance.Spec.Source.Inline
+	if inlineSource == nil {
+		return []string{}
+	}
+	result := []string{}
+	files := inlineSource.Files
+	if v.instance.Spec.Runtime.IsRuntimePython() {
+		if content, ok := files[pyprojectFileName]; ok {
+			if err := validatePyProject(content); err != nil {
+				result = append(result, fmt.Sprintf("invalid source.inline.files key %s: %s", pyprojectFileName, err.Error()))
+			}
+		}
+		if content, ok := files[poetryLockFileName]; ok {
+			if _, hasPyProject := files[pyprojectFileName]; !hasPyProject {
+				result = append(result, fmt.Sprintf("invalid source.inline.files key %s: lockfile requires %s", poetryLockFileName, pyprojectFileName))
+			} else if err := validatePoetryLock(content); err != nil {
+				result = append(result, fmt.Sprintf("invalid source.inline.files key %s: %s", poetryLockFileName, err.Error()))
+			}
+		}
+	}
+	if v.instance.Spec.Runtime.IsRuntimeNodejs() {
+		if content, ok := files[packageLockFileName]; ok {
+			if inlineSource.Dependencies == "" {
+				result = append(result, fmt.Sprintf("invalid source.inline.files key %s: lockfile requires source.inline.dependencies", packageLockFileName))
+			} else if err := validatePackageLock(content); err != nil {
+				result = append(result, fmt.Sprintf("invalid source.inline.files key %s: %s", packageLockFileName, err.Error()))
+			}
+		}
+	}
+	return result
+}
+
+func (v *validator) validateInlineSourcesSize() []string {
+	inlineSource := v.instance.Spec.Source.Inline
+	if inlineSource == nil {
+		return []string{}
+	}
+	maxSize := v.fnConfig.InlineSourcesMaxSize.Quantity
+	if maxSize.IsZero() {
+		return []string{}
+	}
+	size := len(inlineSource.Source) + len(inlineSource.Dependencies)
+	for filePath, content := range inlineSource.Files {
+		size += len(filePath) + len(content)
+	}
+	if int64(size) > maxSize.Value() {
+		return []string{
+			fmt.Sprintf("inline sources size (%d bytes) exceeds the limit (%s)", size, maxSize.String()),
+		}
+	}
+	return []string{}
+}
+
 func (v *validator) validateRuntime() []string {
 	runtime := v.instance.Spec.Runtime
 
-	if err := validateRuntime(runtime); err != nil {
+	if err := validateRuntime(runtime, v.functionRuntime); err != nil {
 		return []string{
 			fmt.Sprintf("invalid runtime value: %s", err.Error()),
 		}
 	}
+	return v.validateFunctionRuntimeSpec()
+}
+
+// validateFunctionRuntimeSpec checks if the FunctionRuntime describes how to run the function
+// when its runtime isn't based on Node.js or Python and has no built-in behavior to fall back to
+func (v *validator) validateFunctionRuntimeSpec() []string {
+	runtime := v.instance.Spec.Runtime
+	if v.functionRuntime == nil || runtime.IsRuntimeNodejs() || runtime.IsRuntimePython() {
+		return []string{}
+	}
+
+	spec := v.functionRuntime.Spec
+	required := []struct {
+		name  string
+		value string
+		used  bool
+	}{
+		{name: "workingDir", value: spec.WorkingDir, used: true},
+		{name: "startCommand", value: spec.StartCommand, used: true},
+		{name: "handlerFile", value: spec.HandlerFile, used: v.instance.HasInlineSources()},
+		{name: "dependenciesFile", value: spec.DependenciesFile, used: v.instance.HasInlineSources() && v.instance.Spec.Source.Inline.Dependencies != ""},
+	}
+	result := []string{}
+	for _, field := range required {
+		if field.used && field.value == "" {
+			result = append(result, fmt.Sprintf("invalid FunctionRuntime %s: spec.%s must be set for runtime %s, which isn't based on Node.js or Python",
+				v.functionRuntime.GetName(), field.name, runtime))
+		}
+	}
+	return result
+}
+
+// validateRuntimeImageOverride checks if the image set by the user is allowed by the image policy
+func (v *validator) validateRuntimeImageOverride() []string {
+	image := v.instance.Spec.RuntimeImageOverride
+	if image == "" {
+		return []string{}
+	}
+	return v.validateAllowedImage("runtimeImageOverride value", image)
+}
+
+// ValidateFunctionRuntimeImage checks if the image of the FunctionRuntime the function runs with is allowed by the image policy
+// it's exported to validate the FunctionRuntime of the replacement runtime, which is fetched after the function is validated
+func (v *validator) ValidateFunctionRuntimeImage() []string {
+	if v.functionRuntime == nil || v.functionRuntime.Spec.Image == "" || v.instance.Spec.RuntimeImageOverride != "" {
+		return []string{}
+	}
+	return v.validateAllowedImage(fmt.Sprintf("FunctionRuntime %s image", v.functionRuntime.GetName()), v.functionRuntime.Spec.Image)
+}
+
+func (v *validator) validateAllowedImage(subject, image string) []string {
+	allowedImages := v.fnConfig.ImagePolicy.AllowedImages
+	if len(allowedImages) == 0 {
+		return []string{}
+	}
+	ref, err := oci.ParseImage(image)
+	if err != nil {
+		return []string{
+			fmt.Sprintf("invalid %s: %s", subject, err.Error()),
+		}
+	}
+	name := fmt.Sprintf("%s/%s", ref.Registry, ref.Repository)
+	for _, pattern := range allowedImages {
+		if matchImagePattern(pattern, name) {
+			return []string{}
+		}
+	}
+	return []string{
+		fmt.Sprintf("invalid %s: image %s is not allowed by the image policy", subject, name),
+	}
+}
+
+// matchImagePattern matches the image name with the glob pattern, the trailing `/**` matches all repositories under the path
+func matchImagePattern(pattern, name string) bool {
+	if prefix, found := strings.CutSuffix(pattern, "/**"); found {
+		segments := strings.Count(prefix, "/") + 1
+		parts := strings.SplitN(name, "/", segments+1)
+		if len(parts) <= segments {
+			return false
+		}
+		matched, _ := path.Match(prefix, strings.Join(parts[:segments], "/"))
+		return matched
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+func (v *validator) validateLanguage() []string {
+	spec := v.instance.Spec
+	if spec.Language == serverlessv1alpha2.TypeScript && !spec.Runtime.IsRuntimeNodejs() {
+		return []string{
+			fmt.Sprintf("invalid language value: language %s is supported only for Node.js runtimes", spec.Language),
+		}
+	}
 	return []string{}
 }
 
@@ -102,6 +298,237 @@ func (v *validator) validateSecretMounts() []string {
 	}
 }
 
+func (v *validator) validatePackageRegistryConfig() []string {
+	registryConfig := v.instance.Spec.PackageRegistryConfig
+	if registryConfig == nil {
+		return []string{}
+	}
+	return enrichErrors(utilvalidation.IsDNS1123Subdomain(registryConfig.SecretName), "spec.packageRegistryConfig.secretName", registryConfig.SecretName)
+}
+
+func (v *validator) validateExpose() []string {
+	expose := v.instance.Spec.Expose
+	if expose == nil {
+		return []string{}
+	}
+	return enrichErrors(utilvalidation.IsDNS1123Subdomain(expose.Host), "spec.expose.host", expose.Host)
+}
+
+func (v *validator) validateSchedules() []string {
+	result := []string{}
+	names := map[string]bool{}
+	for _, schedule := range v.instance.Spec.Schedules {
+		path := fmt.Sprintf("spec.schedules[%s]", schedule.Name)
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Label(schedule.Name), "spec.schedules.name", schedule.Name)...)
+		if names[schedule.Name] {
+			result = append(result, fmt.Sprintf("%s: schedule names should be unique", path))
+		}
+		names[schedule.Name] = true
+		// CronJob adds 11 characters to the names of its Jobs
+		if cronJobName := fmt.Sprintf("%s-%s", v.instance.GetName(), schedule.Name); len(cronJobName) > 52 {
+			result = append(result, fmt.Sprintf("%s: CronJob name %s must be no more than 52 characters", path, cronJobName))
+		}
+		if err := validateCron(schedule.Cron); err != nil {
+			result = append(result, fmt.Sprintf("%s: invalid cron value %s: %s", path, schedule.Cron, err.Error()))
+		}
+		if schedule.TimeZone != "" {
+			if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+				result = append(result, fmt.Sprintf("%s: invalid timeZone value %s", path, schedule.TimeZone))
+			}
+		}
+	}
+	return result
+}
+
+func (v *validator) validateSubscriptions() []string {
+	result := []string{}
+	names := map[string]bool{}
+	for _, subscription := range v.instance.Spec.Subscriptions {
+		path := fmt.Sprintf("spec.subscriptions[%s]", subscription.Name)
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Label(subscription.Name), "spec.subscriptions.name", subscription.Name)...)
+		if names[subscription.Name] {
+			result = append(result, fmt.Sprintf("%s: subscription names should be unique", path))
+		}
+		names[subscription.Name] = true
+		if strings.TrimSpace(subscription.Source) == "" {
+			result = append(result, fmt.Sprintf("%s: source should not be empty", path))
+		}
+		if len(subscription.Types) == 0 {
+			result = append(result, fmt.Sprintf("%s: at least one type should be set", path))
+		}
+		for _, eventType := range subscription.Types {
+			if strings.TrimSpace(eventType) == "" {
+				result = append(result, fmt.Sprintf("%s: types should not be empty", path))
+				break
+			}
+		}
+	}
+	return result
+}
+
+func (v *validator) validateAsync() []string {
+	async := v.instance.Spec.Async
+	if async == nil {
+		return []string{}
+	}
+	result := []string{}
+	// the Function Controller delivering async requests isn't in the mesh, so the function's AuthorizationPolicy accepts them only by the JWT
+	if auth := v.instance.Spec.Auth; auth != nil && (len(auth.Namespaces) != 0 || len(auth.Principals) != 0) {
+		result = append(result, "spec.async: async invocation can't be combined with spec.auth.namespaces or spec.auth.principals, use spec.auth.jwt")
+	}
+	return append(result, v.validateDeadLetter()...)
+}
+
+func (v *validator) validateDeadLetter() []string {
+	deadLetter := v.instance.Spec.Async.DeadLetter
+	if deadLetter == nil {
+		return []string{}
+	}
+	if deadLetter.Function != "" {
+		result := enrichErrors(utilvalidation.IsDNS1123Subdomain(deadLetter.Function), "spec.async.deadLetter.function", deadLetter.Function)
+		if deadLetter.Function == v.instance.GetName() {
+			result = append(result, "spec.async.deadLetter.function: dead letter function must be different from the function itself")
+		}
+		return result
+	}
+	if _, err := url.ParseRequestURI(deadLetter.URL); err != nil {
+		return []string{fmt.Sprintf("spec.async.deadLetter.url: %s. Err: %s", deadLetter.URL, err.Error())}
+	}
+	// the Function Controller sends dead-lettered requests, so they can't be sent outside of the function's namespace
+	if !resources.IsNamespaceServiceURL(deadLetter.URL, v.instance.GetNamespace()) {
+		return []string{fmt.Sprintf("spec.async.deadLetter.url: %s. Err: url must point to a Service in the %s namespace, for example, http://{NAME}.%s.svc.cluster.local",
+			deadLetter.URL, v.instance.GetNamespace(), v.instance.GetNamespace())}
+	}
+	return []string{}
+}
+
+// validateWorkload rejects features requiring the function's Service when the function runs to completion in the Job
+func (v *validator) validateWorkload() []string {
+	spec := v.instance.Spec
+	if !v.instance.IsJob() {
+		if spec.Job != nil {
+			return []string{"spec.job: job settings are allowed only for the job workload"}
+		}
+		return []string{}
+	}
+
+	result := []string{}
+	if spec.Backend != "" {
+		result = append(result, "spec.backend: job workload doesn't use the backend")
+	}
+	if spec.Expose != nil {
+		result = append(result, "spec.expose: job workload can't be exposed")
+	}
+	if len(spec.Schedules) > 0 {
+		result = append(result, "spec.schedules: job workload can't be scheduled")
+	}
+	if len(spec.Subscriptions) > 0 {
+		result = append(result, "spec.subscriptions: job workload can't subscribe to events")
+	}
+	if spec.Async != nil {
+		result = append(result, "spec.async: job workload can't be invoked asynchronously")
+	}
+	if spec.ScaleConfig != nil {
+		result = append(result, "spec.scaleConfig: job workload can't be scaled")
+	}
+	if spec.DisruptionBudget != nil {
+		result = append(result, "spec.disruptionBudget: job workload can't have a disruption budget")
+	}
+	if spec.RollingUpdate != nil {
+		result = append(result, "spec.rollingUpdate: job workload isn't updated with rolling updates")
+	}
+	if spec.Auth != nil {
+		result = append(result, "spec.auth: job workload isn't called")
+	}
+	return result
+}
+
+// validateBackend rejects features the function's backend doesn't support
+func (v *validator) validateBackend() []string {
+	backend := v.instance.Spec.Backend
+	if backend == "" {
+		backend = serverlessv1alpha2.WorkloadBackend(v.fnConfig.WorkloadBackend)
+	}
+	if v.instance.IsJob() || backend != serverlessv1alpha2.WorkloadBackendKnative {
+		return []string{}
+	}
+
+	result := []string{}
+	if v.instance.Spec.Expose != nil {
+		result = append(result, "spec.expose: function served by knative backend is exposed by Knative Serving")
+	}
+	if v.instance.HasScaleTriggers() {
+		result = append(result, "spec.scaleConfig.triggers: function served by knative backend is scaled by Knative Serving")
+	}
+	if v.instance.Spec.DisruptionBudget != nil {
+		result = append(result, "spec.disruptionBudget: function served by knative backend has no Deployment to protect")
+	}
+	if v.instance.Spec.RollingUpdate != nil {
+		result = append(result, "spec.rollingUpdate: function served by knative backend is rolled out by Knative Serving")
+	}
+	return result
+}
+
+func (v *validator) validateScaleTriggers() []string {
+	if !v.instance.HasScaleTriggers() {
+		return []string{}
+	}
+	result := []string{}
+	names := map[string]bool{}
+	for i, trigger := range v.instance.Spec.ScaleConfig.Triggers {
+		path := fmt.Sprintf("spec.scaleConfig.triggers[%d]", i)
+		if trigger.Name != "" {
+			if names[trigger.Name] {
+				result = append(result, fmt.Sprintf("%s: trigger names should be unique", path))
+			}
+			names[trigger.Name] = true
+		}
+		if len(trigger.Metadata) == 0 {
+			result = append(result, fmt.Sprintf("%s: metadata should not be empty", path))
+		}
+	}
+	return result
+}
+
+func (v *validator) validateNetworkPolicy() []string {
+	networkPolicy := v.instance.Spec.NetworkPolicy
+	if networkPolicy == nil || networkPolicy.Egress == nil {
+		return []string{}
+	}
+	result := []string{}
+	for i, destination := range networkPolicy.Egress.To {
+		for _, cidr := range destination.CIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				result = append(result, fmt.Sprintf("spec.networkPolicy.egress.to[%d].cidrs: invalid CIDR %q", i, cidr))
+			}
+		}
+	}
+	return result
+}
+
+func (v *validator) validateAuth() []string {
+	auth := v.instance.Spec.Auth
+	if auth == nil {
+		return []string{}
+	}
+	result := []string{}
+	if mode := v.fnConfig.MeshMode; mode != "" && mode != config.MeshModeIstio {
+		result = append(result, fmt.Sprintf("spec.auth: Istio policies can't be used with the %s mesh mode", mode))
+	}
+	if v.instance.IsMeshInjectionDisabled() {
+		result = append(result, "spec.auth: Istio policies can't be used when the mesh injection is disabled")
+	}
+	if auth.JWT != nil {
+		if u, err := url.ParseRequestURI(auth.JWT.JWKSURI); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			result = append(result, fmt.Sprintf("spec.auth.jwt.jwksUri: invalid URL %q", auth.JWT.JWKSURI))
+		}
+	}
+	for i, namespace := range auth.Namespaces {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Label(namespace), fmt.Sprintf("spec.auth.namespaces[%d]", i), namespace)...)
+	}
+	return result
+}
+
 func (v *validator) validateFunctionLabels() []string {
 	labels := v.instance.Spec.Labels
 	path := "spec.labels"
@@ -149,6 +576,46 @@ func (v *validator) validateGitRepoURL() []string {
 	return result
 }
 
+func (v *validator) validateConfigMapSource() []string {
+	configMap := v.instance.Spec.Source.ConfigMap
+	if configMap == nil {
+		return []string{}
+	}
+	return enrichErrors(utilvalidation.IsDNS1123Subdomain(configMap.Name), "source.configMap.name", configMap.Name)
+}
+
+func (v *validator) validateOCISource() []string {
+	ociSource := v.instance.Spec.Source.OCI
+	if ociSource == nil {
+		return []string{}
+	}
+	result := []string{}
+	if _, err := oci.ParseReference(ociSource.Reference); err != nil {
+		result = append(result, fmt.Sprintf("source.oci.reference: %s", err.Error()))
+	}
+	if ociSource.PullSecretName != "" {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Subdomain(ociSource.PullSecretName), "source.oci.pullSecretName", ociSource.PullSecretName)...)
+	}
+	return result
+}
+
+func (v *validator) validateArchiveSource() []string {
+	archiveSource := v.instance.Spec.Source.Archive
+	if archiveSource == nil {
+		return []string{}
+	}
+	result := []string{}
+	if u, err := url.ParseRequestURI(archiveSource.URL); err != nil {
+		result = append(result, fmt.Sprintf("source.archive.url: %v", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		result = append(result, fmt.Sprintf("source.archive.url: unsupported scheme %s", u.Scheme))
+	}
+	if archiveSource.Auth != nil {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Subdomain(archiveSource.Auth.SecretName), "source.archive.auth.secretName", archiveSource.Auth.SecretName)...)
+	}
+	return result
+}
+
 func (v *validator) validateFunctionResources() []string {
 	rc := v.instance.Spec.ResourceConfiguration
 	minCPU := v.fnConfig.ResourceConfig.Function.Resources.MinRequestCPU.Quantity
@@ -167,9 +634,55 @@ func validateDependencies(runtime serverlessv1alpha2.Runtime, dependencies strin
 		return validateNodeJSDependencies(dependencies)
 	}
 	if runtime.IsRuntimePython() {
-		return nil
+		return validatePythonRequirements(dependencies)
+	}
+	// the format of dependencies of other runtimes is known only to their images
+	return nil
+}
+
+var inlineFilePathRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]+(/[a-zA-Z0-9._-]+)*$`)
+
+func validateInlineFilePath(filePath string, reserved []string) error {
+	if !inlineFilePathRegex.MatchString(filePath) {
+		return errors.New("path must consist of alphanumeric characters, '.', '_', '-' or '/'")
+	}
+	if !filepath.IsLocal(filePath) || path.Clean(filePath) != filePath {
+		return errors.New("path must be relative and point inside the sources directory")
+	}
+	for _, name := range reserved {
+		dir, isDir := strings.CutSuffix(name, "/")
+		if filePath == name || (isDir && (filePath == dir || strings.HasPrefix(filePath, name))) {
+			return errors.New("path is reserved for the runtime's files")
+		}
+	}
+	return nil
+}
+
+// runtimeFileNames are files and directories (with the trailing '/') provided by the built-in runtimes next to the Function's sources
+// in the runtime image, in the Function's Pods or in the ejected project
+var (
+	commonRuntimeFileNames = []string{"Dockerfile", "Makefile", "README.md", ".gitignore", ".dockerignore", "lib/", "package-registry-config/"}
+	nodejsRuntimeFileNames = []string{"handler.js", "handler.ts", "package.json", "server.mjs", "openssl.cnf", "node_modules/"}
+	pythonRuntimeFileNames = []string{"handler.py", "requirements.txt", "server.py"}
+)
+
+// reservedInlineFileNames also reserves the handler and dependencies files set in the FunctionRuntime instead of the built-in ones
+func reservedInlineFileNames(runtime serverlessv1alpha2.Runtime, functionRuntime *serverlessv1alpha2.FunctionRuntime) []string {
+	var reserved []string
+	switch {
+	case runtime.IsRuntimeNodejs():
+		reserved = slices.Concat(nodejsRuntimeFileNames, commonRuntimeFileNames)
+	case runtime.IsRuntimePython():
+		reserved = slices.Concat(pythonRuntimeFileNames, commonRuntimeFileNames)
+	}
+	if functionRuntime != nil {
+		for _, name := range []string{functionRuntime.Spec.HandlerFile, functionRuntime.Spec.DependenciesFile} {
+			if name != "" {
+				reserved = append(reserved, name)
+			}
+		}
 	}
-	return fmt.Errorf("cannot find runtime: %s", runtime)
+	return reserved
 }
 
 func validateNodeJSDependencies(dependencies string) error {
@@ -179,14 +692,15 @@ func validateNodeJSDependencies(dependencies string) error {
 	return nil
 }
 
-func validateRuntime(runtime serverlessv1alpha2.Runtime) error {
+// validateRuntime checks if the runtime is built-in or described by its FunctionRuntime
+func validateRuntime(runtime serverlessv1alpha2.Runtime, functionRuntime *serverlessv1alpha2.FunctionRuntime) error {
 	if len(runtime) == 0 {
 		return nil
 	}
-	if runtime.IsRuntimeKnown() {
+	if runtime.IsRuntimeKnown() || functionRuntime != nil {
 		return nil
 	}
-	return fmt.Errorf("cannot find runtime: %s", runtime)
+	return fmt.Errorf("cannot find runtime: %s, FunctionRuntime %s doesn't exist", runtime, runtime)
 }
 
 func secretNamesAreUnique(secretMounts []serverlessv1alpha2.SecretMount) bool {
@@ -18,7 +18,7 @@ func TestNewFunctionValidator(t *testing.T) {
 				Name:      "compassionate-villani-name",
 				Namespace: "vigorous-jang-ns"}}
 
-		r := New(f, config.FunctionConfig{})
+		r := New(f, config.FunctionConfig{}, nil)
 
 		require.NotNil(t, r)
 		require.NotNil(t, r.instance)
@@ -29,7 +29,7 @@ func TestNewFunctionValidator(t *testing.T) {
 
 func Test_functionValidator_Validate(t *testing.T) {
 	t.Run("when function is valid should return empty list", func(t *testing.T) {
-		v := New(&serverlessv1alpha2.Function{}, config.FunctionConfig{})
+		v := New(&serverlessv1alpha2.Function{}, config.FunctionConfig{}, nil)
 
 		r := v.Validate()
 
@@ -43,14 +43,13 @@ func Test_functionValidator_Validate(t *testing.T) {
 				Runtime: "upbeat-boyd",
 			}}
 
-		v := New(f, config.FunctionConfig{})
+		v := New(f, config.FunctionConfig{}, nil)
 
 		r := v.Validate()
 
 		require.ElementsMatch(t, []string{
 			"spec.env: goofy-kare;;;;;. Err: a valid environment variable name must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*')",
-			"invalid source.inline.dependencies value: cannot find runtime: upbeat-boyd",
-			"invalid runtime value: cannot find runtime: upbeat-boyd",
+			"invalid runtime value: cannot find runtime: upbeat-boyd, FunctionRuntime upbeat-boyd doesn't exist",
 		}, r)
 	})
 }
@@ -93,7 +92,7 @@ func Test_functionValidator_validateEnvs(t *testing.T) {
 					Env: tt.envs,
 				}}
 
-			v := New(f, config.FunctionConfig{})
+			v := New(f, config.FunctionConfig{}, nil)
 			r := v.validateEnvs()
 			require.ElementsMatch(t, tt.want, r)
 		})
@@ -112,25 +111,40 @@ func Test_functionValidator_validateInlineDeps(t *testing.T) {
 			want: []string{},
 		},
 		{
-			name: "when unknown runtime then return error",
+			name: "when runtime isn't based on Node.js or Python then no errors",
 			spec: serverlessv1alpha2.FunctionSpec{
 				Runtime: "pedantic-lewin",
 				Source: serverlessv1alpha2.Source{
-					Inline: &serverlessv1alpha2.InlineSource{},
+					Inline: &serverlessv1alpha2.InlineSource{
+						Dependencies: "module example.com/function",
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when python runtime with invalid dependencies then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "sweet-goldstine",
+						Dependencies: "`1234567890-=qwertyuiop[]asdfghjkl;'\\zxcvbnm,./~!@#$%^&*()_+{}:|<>?",
+					},
 				},
 			},
 			want: []string{
-				"invalid source.inline.dependencies value: cannot find runtime: pedantic-lewin",
+				"invalid source.inline.dependencies value: invalid requirement \"`1234567890-=qwertyuiop[]asdfghjkl;'\\\\zxcvbnm,./~!@#$%^&*()_+{}:|<>?\"",
 			},
 		},
 		{
-			name: "when python runtime then no errors",
+			name: "when python runtime with valid dependencies then no errors",
 			spec: serverlessv1alpha2.FunctionSpec{
 				Runtime: serverlessv1alpha2.Python312,
 				Source: serverlessv1alpha2.Source{
 					Inline: &serverlessv1alpha2.InlineSource{
 						Source:       "sweet-goldstine",
-						Dependencies: "`1234567890-=qwertyuiop[]asdfghjkl;'\\zxcvbnm,./~!@#$%^&*()_+{}:|<>?",
+						Dependencies: "requests==2.31.0\nflask>=2.0,<3.0",
 					},
 				},
 			},
@@ -171,7 +185,7 @@ func Test_functionValidator_validateInlineDeps(t *testing.T) {
 				Spec: tt.spec,
 			}
 
-			v := New(f, config.FunctionConfig{})
+			v := New(f, config.FunctionConfig{}, nil)
 			r := v.validateInlineDeps()
 			require.ElementsMatch(t, tt.want, r)
 		})
@@ -180,9 +194,10 @@ func Test_functionValidator_validateInlineDeps(t *testing.T) {
 
 func Test_functionValidator_validateRuntime(t *testing.T) {
 	type testData struct {
-		name    string
-		runtime serverlessv1alpha2.Runtime
-		want    []string
+		name            string
+		runtime         serverlessv1alpha2.Runtime
+		functionRuntime *serverlessv1alpha2.FunctionRuntime
+		want            []string
 	}
 	tests := []testData{
 		{
@@ -194,7 +209,41 @@ func Test_functionValidator_validateRuntime(t *testing.T) {
 			name:    "when unknown runtime then return error",
 			runtime: "practical-panini",
 			want: []string{
-				"invalid runtime value: cannot find runtime: practical-panini",
+				"invalid runtime value: cannot find runtime: practical-panini, FunctionRuntime practical-panini doesn't exist",
+			},
+		},
+		{
+			name:    "when runtime is described by its FunctionRuntime then no errors",
+			runtime: "go123",
+			functionRuntime: &serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "go123"},
+				Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+					Image:        "custom-go123:v1",
+					WorkingDir:   "/app",
+					StartCommand: "go run .",
+				},
+			},
+			want: []string{},
+		},
+		{
+			name:    "when Node.js runtime which isn't built-in is described by its FunctionRuntime then no errors",
+			runtime: "nodejs24",
+			functionRuntime: &serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "nodejs24"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "custom-nodejs24:v1"},
+			},
+			want: []string{},
+		},
+		{
+			name:    "when FunctionRuntime of runtime which isn't based on Node.js or Python doesn't describe how to run it then return errors",
+			runtime: "go123",
+			functionRuntime: &serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "go123"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: "custom-go123:v1"},
+			},
+			want: []string{
+				"invalid FunctionRuntime go123: spec.workingDir must be set for runtime go123, which isn't based on Node.js or Python",
+				"invalid FunctionRuntime go123: spec.startCommand must be set for runtime go123, which isn't based on Node.js or Python",
 			},
 		},
 	}
@@ -213,13 +262,249 @@ func Test_functionValidator_validateRuntime(t *testing.T) {
 				},
 			}
 
-			v := New(f, config.FunctionConfig{})
+			v := New(f, config.FunctionConfig{}, tt.functionRuntime)
 			r := v.validateRuntime()
 			require.ElementsMatch(t, tt.want, r)
 		})
 	}
 }
 
+func Test_validator_validateFunctionRuntimeSpec(t *testing.T) {
+	functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+		ObjectMeta: metav1.ObjectMeta{Name: "go123"},
+		Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+			Image:        "custom-go123:v1",
+			WorkingDir:   "/app",
+			StartCommand: "go run .",
+		},
+	}
+	t.Run("require file names of inline sources", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: "go123",
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "package main",
+						Dependencies: "module example.com/function",
+					},
+				},
+			},
+		}
+
+		r := New(f, config.FunctionConfig{}, functionRuntime).validateFunctionRuntimeSpec()
+
+		require.Equal(t, []string{
+			"invalid FunctionRuntime go123: spec.handlerFile must be set for runtime go123, which isn't based on Node.js or Python",
+			"invalid FunctionRuntime go123: spec.dependenciesFile must be set for runtime go123, which isn't based on Node.js or Python",
+		}, r)
+	})
+	t.Run("accept inline sources when file names are set", func(t *testing.T) {
+		withFiles := functionRuntime.DeepCopy()
+		withFiles.Spec.HandlerFile = "main.go"
+		withFiles.Spec.DependenciesFile = "go.mod"
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: "go123",
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "package main",
+						Dependencies: "module example.com/function",
+					},
+				},
+			},
+		}
+
+		r := New(f, config.FunctionConfig{}, withFiles).validateFunctionRuntimeSpec()
+
+		require.Empty(t, r)
+	})
+}
+
+func Test_validator_validateRuntimeImageOverride(t *testing.T) {
+	allowedImages := []string{
+		"europe-docker.pkg.dev/kyma-project/**",
+		"ghcr.io/*/python-runtime",
+	}
+	tests := []struct {
+		name          string
+		image         string
+		allowedImages []string
+		want          []string
+	}{
+		{
+			name:          "when no override then no errors",
+			allowedImages: allowedImages,
+			want:          []string{},
+		},
+		{
+			name:  "when image policy is empty then no errors",
+			image: "docker.io/user/custom-runtime:v1",
+			want:  []string{},
+		},
+		{
+			name:          "when image is under allowed path then no errors",
+			image:         "europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs22:main",
+			allowedImages: allowedImages,
+			want:          []string{},
+		},
+		{
+			name:          "when pinned image matches pattern then no errors",
+			image:         "ghcr.io/team/python-runtime@sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0",
+			allowedImages: allowedImages,
+			want:          []string{},
+		},
+		{
+			name:          "when image doesn't match any pattern then return error",
+			image:         "user/custom-runtime:v1",
+			allowedImages: allowedImages,
+			want: []string{
+				"invalid runtimeImageOverride value: image docker.io/user/custom-runtime is not allowed by the image policy",
+			},
+		},
+		{
+			name:          "when image is the allowed path itself then return error",
+			image:         "europe-docker.pkg.dev/kyma-project:v1",
+			allowedImages: allowedImages,
+			want: []string{
+				"invalid runtimeImageOverride value: image europe-docker.pkg.dev/kyma-project is not allowed by the image policy",
+			},
+		},
+		{
+			name:          "when image is invalid then return error",
+			image:         "Not An Image",
+			allowedImages: allowedImages,
+			want: []string{
+				"invalid runtimeImageOverride value: invalid reference: invalid repository \"library/Not An Image\"",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime:              serverlessv1alpha2.Python312,
+					RuntimeImageOverride: tt.image,
+				},
+			}
+			fnConfig := config.FunctionConfig{
+				ImagePolicy: config.ImagePolicy{AllowedImages: tt.allowedImages},
+			}
+
+			v := New(f, fnConfig, nil)
+			r := v.validateRuntimeImageOverride()
+			require.ElementsMatch(t, tt.want, r)
+		})
+	}
+}
+
+func Test_validator_ValidateFunctionRuntimeImage(t *testing.T) {
+	allowedImages := []string{"europe-docker.pkg.dev/kyma-project/**"}
+	tests := []struct {
+		name          string
+		image         string
+		override      string
+		allowedImages []string
+		want          []string
+	}{
+		{
+			name:          "when image is allowed then no errors",
+			image:         "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main",
+			allowedImages: allowedImages,
+			want:          []string{},
+		},
+		{
+			name:  "when image policy is empty then no errors",
+			image: "docker.io/user/custom-runtime:v1",
+			want:  []string{},
+		},
+		{
+			name:          "when function overrides image then no errors",
+			image:         "docker.io/user/custom-runtime:v1",
+			override:      "europe-docker.pkg.dev/kyma-project/prod/function-runtime-python312:main",
+			allowedImages: allowedImages,
+			want:          []string{},
+		},
+		{
+			name:          "when image doesn't match any pattern then return error",
+			image:         "docker.io/user/custom-runtime:v1",
+			allowedImages: allowedImages,
+			want: []string{
+				"invalid FunctionRuntime python312 image: image docker.io/user/custom-runtime is not allowed by the image policy",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime:              serverlessv1alpha2.Python312,
+					RuntimeImageOverride: tt.override,
+				},
+			}
+			functionRuntime := &serverlessv1alpha2.FunctionRuntime{
+				ObjectMeta: metav1.ObjectMeta{Name: "python312"},
+				Spec:       serverlessv1alpha2.FunctionRuntimeSpec{Image: tt.image},
+			}
+			fnConfig := config.FunctionConfig{
+				ImagePolicy: config.ImagePolicy{AllowedImages: tt.allowedImages},
+			}
+
+			v := New(f, fnConfig, functionRuntime)
+			r := v.ValidateFunctionRuntimeImage()
+			require.ElementsMatch(t, tt.want, r)
+		})
+	}
+}
+
+func Test_validator_validateLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		runtime  serverlessv1alpha2.Runtime
+		language serverlessv1alpha2.Language
+		want     []string
+	}{
+		{
+			name:    "when empty language then no errors",
+			runtime: serverlessv1alpha2.Python312,
+			want:    []string{},
+		},
+		{
+			name:     "when typescript for nodejs then no errors",
+			runtime:  serverlessv1alpha2.NodeJs22,
+			language: serverlessv1alpha2.TypeScript,
+			want:     []string{},
+		},
+		{
+			name:     "when javascript for python then no errors",
+			runtime:  serverlessv1alpha2.Python312,
+			language: serverlessv1alpha2.JavaScript,
+			want:     []string{},
+		},
+		{
+			name:     "when typescript for python then return error",
+			runtime:  serverlessv1alpha2.Python312,
+			language: serverlessv1alpha2.TypeScript,
+			want: []string{
+				"invalid language value: language typescript is supported only for Node.js runtimes",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime:  tt.runtime,
+					Language: tt.language,
+				},
+			}
+
+			v := New(f, config.FunctionConfig{}, nil)
+			r := v.validateLanguage()
+			require.ElementsMatch(t, tt.want, r)
+		})
+	}
+}
+
 func Test_validator_validateSecretMounts(t *testing.T) {
 	type testData struct {
 		name         string
@@ -275,159 +560,1347 @@ func Test_validator_validateSecretMounts(t *testing.T) {
 	}
 }
 
-func Test_validator_validateFunctionLabels(t *testing.T) {
+func Test_validator_validatePackageRegistryConfig(t *testing.T) {
 	type testData struct {
-		name   string
-		labels map[string]string
-		want   []string
+		name           string
+		registryConfig *serverlessv1alpha2.PackageRegistryConfig
+		want           []string
 	}
 	tests := []testData{
 		{
-			name:   "when no labels then no errors",
-			labels: map[string]string{},
-			want:   []string{},
+			name:           "when no package registry config then no errors",
+			registryConfig: nil,
+			want:           []string{},
 		},
 		{
-			name: "when valid labels then no errors",
-			labels: map[string]string{
-				"valid-label-1": "chlebek",
-				"valid-label-2": "chlebek2",
-			},
-			want: []string{},
+			name:           "when secret name is valid then no errors",
+			registryConfig: &serverlessv1alpha2.PackageRegistryConfig{SecretName: "team-registry-config"},
+			want:           []string{},
 		},
 		{
-			name: "when invalid label key then return error",
-			labels: map[string]string{
-				"Invalid_Label@Key!": "value",
-			},
+			name:           "when secret name is invalid then return error",
+			registryConfig: &serverlessv1alpha2.PackageRegistryConfig{SecretName: "invalid_secret_name@#!"},
 			want: []string{
-				"spec.labels: Invalid value: \"Invalid_Label@Key!\": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')",
+				"spec.packageRegistryConfig.secretName: invalid_secret_name@#!. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
 			},
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						PackageRegistryConfig: tt.registryConfig,
+					},
+				},
+			}
+			got := v.validatePackageRegistryConfig()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateExpose(t *testing.T) {
+	type testData struct {
+		name   string
+		expose *serverlessv1alpha2.Expose
+		want   []string
+	}
+	tests := []testData{
+		{
+			name:   "when function is not exposed then no errors",
+			expose: nil,
+			want:   []string{},
+		},
 		{
-			name: "when invalid label value then return error",
-			labels: map[string]string{
-				"valid-label": "Invalid_ChlEbek!",
-			},
+			name:   "when host is valid then no errors",
+			expose: &serverlessv1alpha2.Expose{Host: "fn.example.com"},
+			want:   []string{},
+		},
+		{
+			name:   "when host is invalid then return error",
+			expose: &serverlessv1alpha2.Expose{Host: "https://fn.example.com"},
 			want: []string{
-				"spec.labels: Invalid value: \"Invalid_ChlEbek!\": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')",
+				"spec.expose.host: https://fn.example.com. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
 			},
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := &validator{
 				instance: &serverlessv1alpha2.Function{
 					Spec: serverlessv1alpha2.FunctionSpec{
-						Labels: tt.labels,
+						Expose: tt.expose,
 					},
 				},
 			}
-			got := v.validateFunctionLabels()
+			got := v.validateExpose()
 			require.ElementsMatch(t, tt.want, got)
 		})
 	}
 }
 
-func Test_validator_validateFunctionAnnotations(t *testing.T) {
+func Test_validator_validateSchedules(t *testing.T) {
 	type testData struct {
-		name        string
-		annotations map[string]string
-		want        []string
+		name         string
+		functionName string
+		schedules    []serverlessv1alpha2.Schedule
+		want         []string
 	}
 	tests := []testData{
 		{
-			name:        "when no annotations then no errors",
-			annotations: map[string]string{},
-			want:        []string{},
+			name:      "when no schedules then no errors",
+			schedules: nil,
+			want:      []string{},
 		},
 		{
-			name: "when valid annotations then no errors",
-			annotations: map[string]string{
-				"valid-annotation-1": "chlebek",
-				"valid-annotation-2": "chleb",
+			name: "when schedules are valid then no errors",
+			schedules: []serverlessv1alpha2.Schedule{
+				{Name: "cleanup", Cron: "*/15 * * * *"},
+				{Name: "report", Cron: "0 8 * * mon-fri", TimeZone: "Europe/Warsaw", Payload: `{"type": "daily"}`},
 			},
 			want: []string{},
 		},
 		{
-			name: "when invalid annotation key then return error",
-			annotations: map[string]string{
-				"Invalid_Annotation@Key!": "value",
+			name: "when schedule is invalid then return errors",
+			schedules: []serverlessv1alpha2.Schedule{
+				{Name: "cleanup", Cron: "*/15 * * *", TimeZone: "Mars/Olympus"},
+				{Name: "cleanup", Cron: "@daily"},
 			},
 			want: []string{
-				"spec.annotations: Invalid value: \"Invalid_Annotation@Key!\": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')",
+				"spec.schedules[cleanup]: invalid cron value */15 * * *: expected 5 fields, found 4",
+				"spec.schedules[cleanup]: invalid timeZone value Mars/Olympus",
+				"spec.schedules[cleanup]: schedule names should be unique",
 			},
 		},
 		{
-			name: "when invalid annotation value then no errors",
-			annotations: map[string]string{
-				"valid-annotation": "Invalid_Value!@",
+			name:         "when CronJob name is too long then return error",
+			functionName: "function-with-a-really-long-name-for-cron",
+			schedules: []serverlessv1alpha2.Schedule{
+				{Name: "cleanup-everything", Cron: "@hourly"},
+			},
+			want: []string{
+				"spec.schedules[cleanup-everything]: CronJob name function-with-a-really-long-name-for-cron-cleanup-everything must be no more than 52 characters",
 			},
-			want: []string{},
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := &validator{
 				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: tt.functionName,
+					},
 					Spec: serverlessv1alpha2.FunctionSpec{
-						Annotations: tt.annotations,
+						Schedules: tt.schedules,
 					},
 				},
 			}
-			got := v.validateFunctionAnnotations()
+			got := v.validateSchedules()
 			require.ElementsMatch(t, tt.want, got)
 		})
 	}
 }
 
-func Test_validator_validateGitRepoURL(t *testing.T) {
+func Test_validator_validateSubscriptions(t *testing.T) {
 	type testData struct {
-		name string
-		URL  string
-		want []string
+		name          string
+		subscriptions []serverlessv1alpha2.Subscription
+		want          []string
 	}
 	tests := []testData{
 		{
-			name: "when Git repo URL is valid SSH then no errors",
-			URL:  "git@github.com:user/repo.git",
-			want: []string{},
+			name:          "when no subscriptions then no errors",
+			subscriptions: nil,
+			want:          []string{},
 		},
 		{
-			name: "when Git repo URL is valid HTTPS then no errors",
-			URL:  "https://github.com/user/repo.git",
+			name: "when subscriptions are valid then no errors",
+			subscriptions: []serverlessv1alpha2.Subscription{
+				{Name: "orders", Source: "commerce", Types: []string{"order.created.v1"}},
+				{Name: "payments", Source: "payments", Types: []string{"payment.done.v1"}, TypeMatching: serverlessv1alpha2.SubscriptionTypeMatchingExact},
+			},
 			want: []string{},
 		},
 		{
-			name: "when Git repo URL is invalid then return error",
-			URL:  "invalid-url",
+			name: "when subscription is invalid then return errors",
+			subscriptions: []serverlessv1alpha2.Subscription{
+				{Name: "orders", Source: " ", Types: []string{"order.created.v1", ""}},
+				{Name: "orders", Source: "commerce"},
+			},
 			want: []string{
-				"source.gitRepository.URL: parse \"invalid-url\": invalid URI for request",
+				"spec.subscriptions[orders]: source should not be empty",
+				"spec.subscriptions[orders]: types should not be empty",
+				"spec.subscriptions[orders]: subscription names should be unique",
+				"spec.subscriptions[orders]: at least one type should be set",
 			},
 		},
 		{
-			name: "when Git repo URL is empty then return error",
-			URL:  "source.gitRepository.URL: parse \\\"\\\": empty url",
-			want: []string{},
+			name: "when subscription name is invalid then return error",
+			subscriptions: []serverlessv1alpha2.Subscription{
+				{Name: "Orders", Source: "commerce", Types: []string{"order.created.v1"}},
+			},
+			want: []string{
+				"spec.subscriptions.name: Orders. Err: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+			},
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v := &validator{
 				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-function",
+					},
 					Spec: serverlessv1alpha2.FunctionSpec{
-						Source: serverlessv1alpha2.Source{
-							GitRepository: &serverlessv1alpha2.GitRepositorySource{
-								URL: tt.URL,
-							},
-						},
+						Subscriptions: tt.subscriptions,
 					},
 				},
 			}
-			got := v.validateGitRepoURL()
+			got := v.validateSubscriptions()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateAsync(t *testing.T) {
+	type testData struct {
+		name  string
+		async *serverlessv1alpha2.AsyncInvocation
+		auth  *serverlessv1alpha2.Auth
+		want  []string
+	}
+	tests := []testData{
+		{
+			name:  "when async is not set then no errors",
+			async: nil,
+			want:  []string{},
+		},
+		{
+			name:  "when dead letter is not set then no errors",
+			async: &serverlessv1alpha2.AsyncInvocation{},
+			want:  []string{},
+		},
+		{
+			name: "when dead letter function is valid then no errors",
+			async: &serverlessv1alpha2.AsyncInvocation{
+				DeadLetter: &serverlessv1alpha2.DeadLetter{Function: "failed-orders"},
+			},
+			want: []string{},
+		},
+		{
+			name: "when dead letter url is valid then no errors",
+			async: &serverlessv1alpha2.AsyncInvocation{
+				DeadLetter: &serverlessv1alpha2.DeadLetter{URL: "http://sink.default.svc.cluster.local/failed"},
+			},
+			want: []string{},
+		},
+		{
+			name: "when dead letter function is the function itself then return error",
+			async: &serverlessv1alpha2.AsyncInvocation{
+				DeadLetter: &serverlessv1alpha2.DeadLetter{Function: "test-function"},
+			},
+			want: []string{
+				"spec.async.deadLetter.function: dead letter function must be different from the function itself",
+			},
+		},
+		{
+			name: "when dead letter url is invalid then return error",
+			async: &serverlessv1alpha2.AsyncInvocation{
+				DeadLetter: &serverlessv1alpha2.DeadLetter{URL: "sink"},
+			},
+			want: []string{
+				"spec.async.deadLetter.url: sink. Err: parse \"sink\": invalid URI for request",
+			},
+		},
+		{
+			name: "when dead letter url points outside of the namespace then return error",
+			async: &serverlessv1alpha2.AsyncInvocation{
+				DeadLetter: &serverlessv1alpha2.DeadLetter{URL: "http://169.254.169.254/latest/meta-data"},
+			},
+			want: []string{
+				"spec.async.deadLetter.url: http://169.254.169.254/latest/meta-data. Err: url must point to a Service in the default namespace, for example, http://{NAME}.default.svc.cluster.local",
+			},
+		},
+		{
+			name:  "when auth accepts jwt then no errors",
+			async: &serverlessv1alpha2.AsyncInvocation{},
+			auth: &serverlessv1alpha2.Auth{
+				JWT: &serverlessv1alpha2.ExposeJWT{Issuer: "https://issuer.example.com", JWKSURI: "https://issuer.example.com/jwks"},
+			},
+			want: []string{},
+		},
+		{
+			name:  "when auth allows namespaces then return error",
+			async: &serverlessv1alpha2.AsyncInvocation{},
+			auth:  &serverlessv1alpha2.Auth{Namespaces: []string{"clients"}},
+			want: []string{
+				"spec.async: async invocation can't be combined with spec.auth.namespaces or spec.auth.principals, use spec.auth.jwt",
+			},
+		},
+		{
+			name:  "when auth allows principals then return error",
+			async: &serverlessv1alpha2.AsyncInvocation{},
+			auth: &serverlessv1alpha2.Auth{
+				JWT:        &serverlessv1alpha2.ExposeJWT{Issuer: "https://issuer.example.com", JWKSURI: "https://issuer.example.com/jwks"},
+				Principals: []string{"cluster.local/ns/default/sa/client"},
+			},
+			want: []string{
+				"spec.async: async invocation can't be combined with spec.auth.namespaces or spec.auth.principals, use spec.auth.jwt",
+			},
+		},
+		{
+			name:  "when auth allows namespaces without async then no errors",
+			async: nil,
+			auth:  &serverlessv1alpha2.Auth{Namespaces: []string{"clients"}},
+			want:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-function",
+						Namespace: "default",
+					},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Async: tt.async,
+						Auth:  tt.auth,
+					},
+				},
+			}
+			got := v.validateAsync()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateWorkload(t *testing.T) {
+	type testData struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}
+	tests := []testData{
+		{
+			name: "when service workload is used then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Expose: &serverlessv1alpha2.Expose{},
+			},
+			want: []string{},
+		},
+		{
+			name: "when job settings are set for service workload then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Workload: serverlessv1alpha2.WorkloadService,
+				Job:      &serverlessv1alpha2.JobSettings{RunID: "1"},
+			},
+			want: []string{
+				"spec.job: job settings are allowed only for the job workload",
+			},
+		},
+		{
+			name: "when job workload is used with job settings then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Workload: serverlessv1alpha2.WorkloadJob,
+				Job:      &serverlessv1alpha2.JobSettings{RunID: "1"},
+			},
+			want: []string{},
+		},
+		{
+			name: "when job workload is used with service features then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Workload:         serverlessv1alpha2.WorkloadJob,
+				Expose:           &serverlessv1alpha2.Expose{},
+				Schedules:        []serverlessv1alpha2.Schedule{{Name: "nightly", Cron: "0 0 * * *"}},
+				Subscriptions:    []serverlessv1alpha2.Subscription{{Name: "orders", Source: "shop", Types: []string{"order.created.v1"}}},
+				Async:            &serverlessv1alpha2.AsyncInvocation{},
+				ScaleConfig:      &serverlessv1alpha2.ScaleConfig{},
+				DisruptionBudget: &serverlessv1alpha2.DisruptionBudget{},
+				RollingUpdate:    &serverlessv1alpha2.RollingUpdate{},
+				Auth:             &serverlessv1alpha2.Auth{},
+			},
+			want: []string{
+				"spec.expose: job workload can't be exposed",
+				"spec.schedules: job workload can't be scheduled",
+				"spec.subscriptions: job workload can't subscribe to events",
+				"spec.async: job workload can't be invoked asynchronously",
+				"spec.scaleConfig: job workload can't be scaled",
+				"spec.disruptionBudget: job workload can't have a disruption budget",
+				"spec.rollingUpdate: job workload isn't updated with rolling updates",
+				"spec.auth: job workload isn't called",
+			},
+		},
+		{
+			name: "when job workload is used with backend then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Workload: serverlessv1alpha2.WorkloadJob,
+				Backend:  serverlessv1alpha2.WorkloadBackendKnative,
+			},
+			want: []string{
+				"spec.backend: job workload doesn't use the backend",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-function",
+					},
+					Spec: tt.spec,
+				},
+			}
+			got := v.validateWorkload()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateBackend(t *testing.T) {
+	type testData struct {
+		name          string
+		spec          serverlessv1alpha2.FunctionSpec
+		configBackend string
+		want          []string
+	}
+	tests := []testData{
+		{
+			name: "when deployment backend is exposed then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Backend: serverlessv1alpha2.WorkloadBackendDeployment,
+				Expose:  &serverlessv1alpha2.Expose{},
+			},
+			configBackend: "knative",
+			want:          []string{},
+		},
+		{
+			name: "when knative backend isn't exposed then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Backend: serverlessv1alpha2.WorkloadBackendKnative,
+			},
+			want: []string{},
+		},
+		{
+			name: "when knative backend is exposed then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Backend: serverlessv1alpha2.WorkloadBackendKnative,
+				Expose:  &serverlessv1alpha2.Expose{},
+			},
+			want: []string{
+				"spec.expose: function served by knative backend is exposed by Knative Serving",
+			},
+		},
+		{
+			name: "when knative backend is configured for cluster and function is exposed then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Expose: &serverlessv1alpha2.Expose{},
+			},
+			configBackend: "knative",
+			want: []string{
+				"spec.expose: function served by knative backend is exposed by Knative Serving",
+			},
+		},
+		{
+			name: "when knative backend has scale triggers then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Backend: serverlessv1alpha2.WorkloadBackendKnative,
+				ScaleConfig: &serverlessv1alpha2.ScaleConfig{
+					Triggers: []serverlessv1alpha2.ScaleTrigger{{Type: "cron", Metadata: map[string]string{"start": "0 8 * * *"}}},
+				},
+			},
+			want: []string{
+				"spec.scaleConfig.triggers: function served by knative backend is scaled by Knative Serving",
+			},
+		},
+		{
+			name: "when knative backend has disruption budget and rolling update then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Backend:          serverlessv1alpha2.WorkloadBackendKnative,
+				DisruptionBudget: &serverlessv1alpha2.DisruptionBudget{},
+				RollingUpdate:    &serverlessv1alpha2.RollingUpdate{},
+			},
+			want: []string{
+				"spec.disruptionBudget: function served by knative backend has no Deployment to protect",
+				"spec.rollingUpdate: function served by knative backend is rolled out by Knative Serving",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-function",
+					},
+					Spec: tt.spec,
+				},
+				fnConfig: config.FunctionConfig{
+					WorkloadBackend: tt.configBackend,
+				},
+			}
+			got := v.validateBackend()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateScaleTriggers(t *testing.T) {
+	type testData struct {
+		name     string
+		triggers []serverlessv1alpha2.ScaleTrigger
+		want     []string
+	}
+	tests := []testData{
+		{
+			name:     "when there are no triggers then no errors",
+			triggers: nil,
+			want:     []string{},
+		},
+		{
+			name: "when triggers are valid then no errors",
+			triggers: []serverlessv1alpha2.ScaleTrigger{
+				{Type: "cron", Name: "office-hours", Metadata: map[string]string{"start": "0 8 * * *"}},
+				{Type: "prometheus", Metadata: map[string]string{"query": "sum(up)"}},
+			},
+			want: []string{},
+		},
+		{
+			name: "when trigger names are duplicated then return error",
+			triggers: []serverlessv1alpha2.ScaleTrigger{
+				{Type: "cron", Name: "office-hours", Metadata: map[string]string{"start": "0 8 * * *"}},
+				{Type: "cron", Name: "office-hours", Metadata: map[string]string{"start": "0 9 * * *"}},
+			},
+			want: []string{
+				"spec.scaleConfig.triggers[1]: trigger names should be unique",
+			},
+		},
+		{
+			name: "when trigger has no metadata then return error",
+			triggers: []serverlessv1alpha2.ScaleTrigger{
+				{Type: "kafka"},
+			},
+			want: []string{
+				"spec.scaleConfig.triggers[0]: metadata should not be empty",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-function",
+					},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						ScaleConfig: &serverlessv1alpha2.ScaleConfig{Triggers: tt.triggers},
+					},
+				},
+			}
+			got := v.validateScaleTriggers()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateNetworkPolicy(t *testing.T) {
+	type testData struct {
+		name          string
+		networkPolicy *serverlessv1alpha2.NetworkPolicy
+		want          []string
+	}
+	tests := []testData{
+		{
+			name:          "when there is no network policy then no errors",
+			networkPolicy: nil,
+			want:          []string{},
+		},
+		{
+			name: "when CIDRs are valid then no errors",
+			networkPolicy: &serverlessv1alpha2.NetworkPolicy{
+				Egress: &serverlessv1alpha2.NetworkPolicyEgress{
+					To: []serverlessv1alpha2.NetworkPolicyDestination{
+						{CIDRs: []string{"10.0.0.0/16", "2001:db8::/32"}},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when CIDR is invalid then return error",
+			networkPolicy: &serverlessv1alpha2.NetworkPolicy{
+				Egress: &serverlessv1alpha2.NetworkPolicyEgress{
+					To: []serverlessv1alpha2.NetworkPolicyDestination{
+						{CIDRs: []string{"10.0.0.0/16"}},
+						{CIDRs: []string{"10.0.0.1"}},
+					},
+				},
+			},
+			want: []string{
+				"spec.networkPolicy.egress.to[1].cidrs: invalid CIDR \"10.0.0.1\"",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-function",
+					},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						NetworkPolicy: tt.networkPolicy,
+					},
+				},
+			}
+			got := v.validateNetworkPolicy()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateAuth(t *testing.T) {
+	type testData struct {
+		name          string
+		auth          *serverlessv1alpha2.Auth
+		meshInjection serverlessv1alpha2.MeshInjection
+		meshMode      config.MeshMode
+		want          []string
+	}
+	tests := []testData{
+		{
+			name: "when there is no auth then no errors",
+			auth: nil,
+			want: []string{},
+		},
+		{
+			name: "when auth is valid then no errors",
+			auth: &serverlessv1alpha2.Auth{
+				JWT:        &serverlessv1alpha2.ExposeJWT{Issuer: "https://issuer.example.com", JWKSURI: "https://issuer.example.com/jwks"},
+				Principals: []string{"cluster.local/ns/default/sa/client"},
+				Namespaces: []string{"default"},
+			},
+			want: []string{},
+		},
+		{
+			name: "when JWKS URI is invalid then return error",
+			auth: &serverlessv1alpha2.Auth{
+				JWT: &serverlessv1alpha2.ExposeJWT{Issuer: "https://issuer.example.com", JWKSURI: "issuer.example.com/jwks"},
+			},
+			want: []string{
+				"spec.auth.jwt.jwksUri: invalid URL \"issuer.example.com/jwks\"",
+			},
+		},
+		{
+			name: "when namespace is invalid then return error",
+			auth: &serverlessv1alpha2.Auth{
+				Namespaces: []string{"default", "Invalid_Namespace"},
+			},
+			want: []string{
+				"spec.auth.namespaces[1]: Invalid_Namespace. Err: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+			},
+		},
+		{
+			name:     "when mesh mode isn't istio then return error",
+			auth:     &serverlessv1alpha2.Auth{Namespaces: []string{"default"}},
+			meshMode: config.MeshModeLinkerd,
+			want: []string{
+				"spec.auth: Istio policies can't be used with the linkerd mesh mode",
+			},
+		},
+		{
+			name:          "when mesh injection is disabled then return error",
+			auth:          &serverlessv1alpha2.Auth{Namespaces: []string{"default"}},
+			meshInjection: serverlessv1alpha2.MeshInjectionDisabled,
+			want: []string{
+				"spec.auth: Istio policies can't be used when the mesh injection is disabled",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-function",
+					},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Auth:          tt.auth,
+						MeshInjection: tt.meshInjection,
+					},
+				},
+				fnConfig: config.FunctionConfig{MeshMode: tt.meshMode},
+			}
+			got := v.validateAuth()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateFunctionLabels(t *testing.T) {
+	type testData struct {
+		name   string
+		labels map[string]string
+		want   []string
+	}
+	tests := []testData{
+		{
+			name:   "when no labels then no errors",
+			labels: map[string]string{},
+			want:   []string{},
+		},
+		{
+			name: "when valid labels then no errors",
+			labels: map[string]string{
+				"valid-label-1": "chlebek",
+				"valid-label-2": "chlebek2",
+			},
+			want: []string{},
+		},
+		{
+			name: "when invalid label key then return error",
+			labels: map[string]string{
+				"Invalid_Label@Key!": "value",
+			},
+			want: []string{
+				"spec.labels: Invalid value: \"Invalid_Label@Key!\": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')",
+			},
+		},
+		{
+			name: "when invalid label value then return error",
+			labels: map[string]string{
+				"valid-label": "Invalid_ChlEbek!",
+			},
+			want: []string{
+				"spec.labels: Invalid value: \"Invalid_ChlEbek!\": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Labels: tt.labels,
+					},
+				},
+			}
+			got := v.validateFunctionLabels()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateFunctionAnnotations(t *testing.T) {
+	type testData struct {
+		name        string
+		annotations map[string]string
+		want        []string
+	}
+	tests := []testData{
+		{
+			name:        "when no annotations then no errors",
+			annotations: map[string]string{},
+			want:        []string{},
+		},
+		{
+			name: "when valid annotations then no errors",
+			annotations: map[string]string{
+				"valid-annotation-1": "chlebek",
+				"valid-annotation-2": "chleb",
+			},
+			want: []string{},
+		},
+		{
+			name: "when invalid annotation key then return error",
+			annotations: map[string]string{
+				"Invalid_Annotation@Key!": "value",
+			},
+			want: []string{
+				"spec.annotations: Invalid value: \"Invalid_Annotation@Key!\": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')",
+			},
+		},
+		{
+			name: "when invalid annotation value then no errors",
+			annotations: map[string]string{
+				"valid-annotation": "Invalid_Value!@",
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Annotations: tt.annotations,
+					},
+				},
+			}
+			got := v.validateFunctionAnnotations()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateGitRepoURL(t *testing.T) {
+	type testData struct {
+		name string
+		URL  string
+		want []string
+	}
+	tests := []testData{
+		{
+			name: "when Git repo URL is valid SSH then no errors",
+			URL:  "git@github.com:user/repo.git",
+			want: []string{},
+		},
+		{
+			name: "when Git repo URL is valid HTTPS then no errors",
+			URL:  "https://github.com/user/repo.git",
+			want: []string{},
+		},
+		{
+			name: "when Git repo URL is invalid then return error",
+			URL:  "invalid-url",
+			want: []string{
+				"source.gitRepository.URL: parse \"invalid-url\": invalid URI for request",
+			},
+		},
+		{
+			name: "when Git repo URL is empty then return error",
+			URL:  "source.gitRepository.URL: parse \\\"\\\": empty url",
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Source: serverlessv1alpha2.Source{
+							GitRepository: &serverlessv1alpha2.GitRepositorySource{
+								URL: tt.URL,
+							},
+						},
+					},
+				},
+			}
+			got := v.validateGitRepoURL()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateInlineFiles(t *testing.T) {
+	tests := []struct {
+		name            string
+		runtime         serverlessv1alpha2.Runtime
+		functionRuntime *serverlessv1alpha2.FunctionRuntime
+		files           map[string]string
+		want            []string
+	}{
+		{
+			name:    "when paths are valid then no errors",
+			runtime: serverlessv1alpha2.NodeJs22,
+			files: map[string]string{
+				"utils.js":         "",
+				"utils/helpers.js": "",
+				"tsconfig.json":    "",
+			},
+			want: []string{},
+		},
+		{
+			name:    "when path points outside of sources then return error",
+			runtime: serverlessv1alpha2.NodeJs22,
+			files: map[string]string{
+				"../server.mjs": "",
+			},
+			want: []string{
+				"invalid source.inline.files key ../server.mjs: path must be relative and point inside the sources directory",
+			},
+		},
+		{
+			name:    "when path is not clean then return error",
+			runtime: serverlessv1alpha2.NodeJs22,
+			files: map[string]string{
+				"lib/../utils.js": "",
+			},
+			want: []string{
+				"invalid source.inline.files key lib/../utils.js: path must be relative and point inside the sources directory",
+			},
+		},
+		{
+			name:    "when path contains forbidden characters then return error",
+			runtime: serverlessv1alpha2.Python312,
+			files: map[string]string{
+				"/etc/utils'.py": "",
+			},
+			want: []string{
+				"invalid source.inline.files key /etc/utils'.py: path must consist of alphanumeric characters, '.', '_', '-' or '/'",
+			},
+		},
+		{
+			name:    "when path overwrites handler then return error",
+			runtime: serverlessv1alpha2.Python312,
+			files: map[string]string{
+				"handler.py": "",
+			},
+			want: []string{
+				"invalid source.inline.files key handler.py: path is reserved for the runtime's files",
+			},
+		},
+		{
+			name:    "when path overwrites runtime files then return error",
+			runtime: serverlessv1alpha2.NodeJs22,
+			files: map[string]string{
+				"server.mjs":         "",
+				"openssl.cnf":        "",
+				"node_modules/a.js":  "",
+				"lib":                "",
+				"lib/ce.js":          "",
+				"library/helpers.js": "",
+				"Dockerfile":         "",
+			},
+			want: []string{
+				"invalid source.inline.files key Dockerfile: path is reserved for the runtime's files",
+				"invalid source.inline.files key lib/ce.js: path is reserved for the runtime's files",
+				"invalid source.inline.files key lib: path is reserved for the runtime's files",
+				"invalid source.inline.files key node_modules/a.js: path is reserved for the runtime's files",
+				"invalid source.inline.files key openssl.cnf: path is reserved for the runtime's files",
+				"invalid source.inline.files key server.mjs: path is reserved for the runtime's files",
+			},
+		},
+		{
+			name:    "when path overwrites python runtime files then return error",
+			runtime: serverlessv1alpha2.Python312,
+			files: map[string]string{
+				"server.py":                        "",
+				"package-registry-config/pip.conf": "",
+				"pyproject.toml":                   "",
+			},
+			want: []string{
+				"invalid source.inline.files key package-registry-config/pip.conf: path is reserved for the runtime's files",
+				"invalid source.inline.files key server.py: path is reserved for the runtime's files",
+			},
+		},
+		{
+			name:    "when path overwrites files set in the function runtime then return error",
+			runtime: serverlessv1alpha2.Python312,
+			functionRuntime: &serverlessv1alpha2.FunctionRuntime{
+				Spec: serverlessv1alpha2.FunctionRuntimeSpec{
+					HandlerFile:      "main.py",
+					DependenciesFile: "deps.txt",
+				},
+			},
+			files: map[string]string{
+				"main.py":    "",
+				"deps.txt":   "",
+				"handler.py": "",
+			},
+			want: []string{
+				"invalid source.inline.files key deps.txt: path is reserved for the runtime's files",
+				"invalid source.inline.files key handler.py: path is reserved for the runtime's files",
+				"invalid source.inline.files key main.py: path is reserved for the runtime's files",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: tt.runtime,
+						Source: serverlessv1alpha2.Source{
+							Inline: &serverlessv1alpha2.InlineSource{
+								Source: "source",
+								Files:  tt.files,
+							},
+						},
+					},
+				},
+				functionRuntime: tt.functionRuntime,
+			}
+			got := v.validateInlineFiles()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateInlineDependencyFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when no inline source then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{},
+			want: []string{},
+		},
+		{
+			name: "when python runtime with pyproject.toml then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"pyproject.toml": "[project]\nname = \"function\"\ndependencies = [\"requests==2.31.0\"]\n",
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when python runtime with poetry project and lockfile then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\n",
+							"poetry.lock":    "[metadata]\nlock-version = \"2.0\"\n",
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when python runtime with invalid pyproject.toml then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"pyproject.toml": "[project]\ndependencies = [\"requests ?? 2\"]\n",
+						},
+					},
+				},
+			},
+			want: []string{
+				"invalid source.inline.files key pyproject.toml: invalid dependency \"requests ?? 2\"",
+			},
+		},
+		{
+			name: "when python runtime with poetry project without lockfile then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\n",
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when python runtime with poetry.lock without pyproject.toml then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"poetry.lock": "[metadata]\n",
+						},
+					},
+				},
+			},
+			want: []string{
+				"invalid source.inline.files key poetry.lock: lockfile requires pyproject.toml",
+			},
+		},
+		{
+			name: "when nodejs runtime with package-lock.json then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "4.17.21"}}`,
+						Files: map[string]string{
+							"package-lock.json": `{"lockfileVersion": 3, "packages": {}}`,
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when nodejs runtime with invalid package-lock.json then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "4.17.21"}}`,
+						Files: map[string]string{
+							"package-lock.json": `{"packages": {}}`,
+						},
+					},
+				},
+			},
+			want: []string{
+				"invalid source.inline.files key package-lock.json: lockfile has no lockfileVersion",
+			},
+		},
+		{
+			name: "when nodejs runtime with package-lock.json without dependencies then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Source: serverlessv1alpha2.Source{
+					Inline: &serverlessv1alpha2.InlineSource{
+						Source: "source",
+						Files: map[string]string{
+							"package-lock.json": `{"lockfileVersion": 3}`,
+						},
+					},
+				},
+			},
+			want: []string{
+				"invalid source.inline.files key package-lock.json: lockfile requires source.inline.dependencies",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: tt.spec,
+			}
+
+			v := New(f, config.FunctionConfig{}, nil)
+			r := v.validateInlineDependencyFiles()
+			require.ElementsMatch(t, tt.want, r)
+		})
+	}
+}
+
+func Test_validator_validateInlineSourcesSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize string
+		inline  serverlessv1alpha2.InlineSource
+		want    []string
+	}{
+		{
+			name:    "when sources are smaller than limit then no errors",
+			maxSize: "16",
+			inline: serverlessv1alpha2.InlineSource{
+				Source: "source",
+				Files:  map[string]string{"a.js": "12"},
+			},
+			want: []string{},
+		},
+		{
+			name:    "when sources are bigger than limit then return error",
+			maxSize: "16",
+			inline: serverlessv1alpha2.InlineSource{
+				Source:       "source",
+				Dependencies: "{}",
+				Files:        map[string]string{"a.js": "12345"},
+			},
+			want: []string{
+				"inline sources size (17 bytes) exceeds the limit (16)",
+			},
+		},
+		{
+			name:    "when limit is not set then no errors",
+			maxSize: "0",
+			inline: serverlessv1alpha2.InlineSource{
+				Source: "source",
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Runtime: serverlessv1alpha2.NodeJs22,
+						Source: serverlessv1alpha2.Source{
+							Inline: &tt.inline,
+						},
+					},
+				},
+				fnConfig: config.FunctionConfig{
+					InlineSourcesMaxSize: config.Quantity{Quantity: resource.MustParse(tt.maxSize)},
+				},
+			}
+			got := v.validateInlineSourcesSize()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateConfigMapSource(t *testing.T) {
+	tests := []struct {
+		name          string
+		configMapName string
+		want          []string
+	}{
+		{
+			name:          "when ConfigMap name is valid then no errors",
+			configMapName: "function-sources",
+			want:          []string{},
+		},
+		{
+			name:          "when ConfigMap name is invalid then return error",
+			configMapName: "Function_Sources",
+			want: []string{
+				"source.configMap.name: Function_Sources. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Source: serverlessv1alpha2.Source{
+							ConfigMap: &serverlessv1alpha2.ConfigMapSource{
+								Name: tt.configMapName,
+							},
+						},
+					},
+				},
+			}
+			got := v.validateConfigMapSource()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateOCISource(t *testing.T) {
+	tests := []struct {
+		name      string
+		ociSource serverlessv1alpha2.OCISource
+		want      []string
+	}{
+		{
+			name: "when reference points to tag then no errors",
+			ociSource: serverlessv1alpha2.OCISource{
+				Reference: "ghcr.io/user/function:v1",
+			},
+			want: []string{},
+		},
+		{
+			name: "when reference points to digest and pull secret is valid then no errors",
+			ociSource: serverlessv1alpha2.OCISource{
+				Reference:      "ghcr.io/user/function@sha256:9834876dcfb05cb167a5c24953eba58c4ac89b1adf57f28f2f9d09af107ee8f0",
+				PullSecretName: "registry-credentials",
+			},
+			want: []string{},
+		},
+		{
+			name: "when reference has no tag then return error",
+			ociSource: serverlessv1alpha2.OCISource{
+				Reference: "ghcr.io/user/function",
+			},
+			want: []string{
+				"source.oci.reference: reference ghcr.io/user/function has no tag or digest",
+			},
+		},
+		{
+			name: "when pull secret name is invalid then return error",
+			ociSource: serverlessv1alpha2.OCISource{
+				Reference:      "ghcr.io/user/function:v1",
+				PullSecretName: "Registry_Credentials",
+			},
+			want: []string{
+				"source.oci.pullSecretName: Registry_Credentials. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Source: serverlessv1alpha2.Source{
+							OCI: &tt.ociSource,
+						},
+					},
+				},
+			}
+			got := v.validateOCISource()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateArchiveSource(t *testing.T) {
+	tests := []struct {
+		name          string
+		archiveSource serverlessv1alpha2.ArchiveSource
+		want          []string
+	}{
+		{
+			name: "when URL is valid HTTPS then no errors",
+			archiveSource: serverlessv1alpha2.ArchiveSource{
+				URL: "https://artifacts.local/function.tar.gz",
+			},
+			want: []string{},
+		},
+		{
+			name: "when URL is invalid then return error",
+			archiveSource: serverlessv1alpha2.ArchiveSource{
+				URL: "invalid-url",
+			},
+			want: []string{
+				"source.archive.url: parse \"invalid-url\": invalid URI for request",
+			},
+		},
+		{
+			name: "when URL has unsupported scheme then return error",
+			archiveSource: serverlessv1alpha2.ArchiveSource{
+				URL: "ftp://artifacts.local/function.tar.gz",
+			},
+			want: []string{
+				"source.archive.url: unsupported scheme ftp",
+			},
+		},
+		{
+			name: "when auth secret name is invalid then return error",
+			archiveSource: serverlessv1alpha2.ArchiveSource{
+				URL:  "https://artifacts.local/function.tar.gz",
+				Auth: &serverlessv1alpha2.ArchiveAuth{SecretName: "Archive_Credentials"},
+			},
+			want: []string{
+				"source.archive.auth.secretName: Archive_Credentials. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Source: serverlessv1alpha2.Source{
+							Archive: &tt.archiveSource,
+						},
+					},
+				},
+			}
+			got := v.validateArchiveSource()
 			require.ElementsMatch(t, tt.want, got)
 		})
 	}
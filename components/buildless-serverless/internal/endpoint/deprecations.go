@@ -0,0 +1,21 @@
+package endpoint
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/deprecation"
+	"github.com/pkg/errors"
+)
+
+func (s *Server) handleDeprecationsRequest(w http.ResponseWriter, r *http.Request) {
+	s.log.Info("handling runtime deprecations request")
+
+	reports, err := deprecation.Report(s.ctx, s.k8s, s.functionConfig.RuntimeLifecycle, time.Now())
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "failed to list functions using deprecated runtimes"))
+		return
+	}
+
+	s.writeDeprecationsResponse(w, reports)
+}
@@ -1,10 +1,13 @@
 package endpoint
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/catalog"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/registry"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/runtime"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -30,7 +33,18 @@ func (s *Server) handleFunctionRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resourceFiles, err := runtime.BuildResources(&s.functionConfig, &function, appName)
+	if err := s.inlineSources(&function); err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "failed to read sources of function '%s/%s'", ns, name))
+		return
+	}
+
+	functionRuntime, err := catalog.Get(s.ctx, s.k8s, function.Spec.Runtime.SupportedRuntimeEquivalent())
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "failed to get runtime of function '%s/%s'", ns, name))
+		return
+	}
+
+	resourceFiles, err := runtime.BuildResources(&s.functionConfig, &function, functionRuntime, appName)
 	if err != nil {
 		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "failed to get resource files for function '%s/%s'", ns, name))
 		return
@@ -42,7 +56,7 @@ func (s *Server) handleFunctionRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeFilesListResponse(w, append(resourceFiles, runtimeFiles...), getOutputMessage())
+	s.writeFilesListResponse(w, append(resourceFiles, runtimeFiles...), getOutputMessage(&function, registry.SecretName(&function, &s.functionConfig)))
 }
 
 func validateFunctionParams(ns string, name string, appName string) error {
@@ -65,12 +79,26 @@ func validateFunctionParams(ns string, name string, appName string) error {
 	return nil
 }
 
-func getOutputMessage() string {
+func getOutputMessage(f *v1alpha2.Function, registrySecretName string) string {
 	return "The proposed code structure contains:\n" +
 		"- functions code and dependencies\n" +
 		"- server code with its built-in functionalities (like cloudevents or tracing)\n" +
 		"- resources required to deploy the application on the cluster\n" +
 		"- scripts and automations to easily manage the application lifecycle\n" +
 		"\n" +
+		getPackageRegistryMessage(f, registrySecretName) +
 		"Read more about next steps and possibilities in the 'README.md' file.\n\n"
 }
+
+func getPackageRegistryMessage(f *v1alpha2.Function, registrySecretName string) string {
+	if registrySecretName == "" {
+		return ""
+	}
+	secretID, key := runtime.NpmrcBuildSecretID, registry.NpmrcKey
+	if f.HasPythonRuntime() {
+		secretID, key = runtime.PipConfBuildSecretID, registry.PipConfKey
+	}
+	return fmt.Sprintf("Dependencies are installed using the package registry config from the '%s' Secret. "+
+		"To build the image with it, save its '%s' key and run:\n"+
+		"  docker build --secret id=%s,src=%s .\n\n", registrySecretName, key, secretID, key)
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/deprecation"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/types"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/sbom"
 	"github.com/pkg/errors"
 )
 
@@ -44,3 +46,33 @@ func (s *Server) writeFilesListResponse(w http.ResponseWriter, data []types.File
 	w.Header().Set("Content-Type", "application/json")
 	fmt.Fprint(w, buf.String())
 }
+
+func (s *Server) writeSBOMResponse(w http.ResponseWriter, doc *sbom.Document) {
+	buf := bytes.NewBuffer([]byte{})
+	err := json.NewEncoder(buf).Encode(doc)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "failed to encode response"))
+		return
+	}
+
+	s.log.Debugf("writing sbom response with %d components", len(doc.Components))
+	w.Header().Set("Content-Type", sbom.MediaType)
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, buf.String())
+}
+
+func (s *Server) writeDeprecationsResponse(w http.ResponseWriter, reports []deprecation.FunctionReport) {
+	buf := bytes.NewBuffer([]byte{})
+	err := json.NewEncoder(buf).Encode(types.DeprecationsResponse{
+		Functions: reports,
+	})
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "failed to encode response"))
+		return
+	}
+
+	s.log.Debugf("writing deprecations response with %d functions", len(reports))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, buf.String())
+}
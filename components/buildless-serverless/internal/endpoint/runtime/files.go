@@ -4,8 +4,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
 
 	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/packagejson"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/types"
 	"github.com/pkg/errors"
@@ -14,11 +17,114 @@ import (
 func ReadFiles(f *v1alpha2.Function) ([]types.FileResponse, error) {
 	runtimeDir := fmt.Sprintf("runtimes/%s", f.Spec.Runtime)
 
-	if f.HasPythonRuntime() {
-		return readPythonFiles(f.Spec.Source.Inline, runtimeDir)
+	var files []types.FileResponse
+	var err error
+	switch {
+	case f.HasPythonRuntime():
+		files, err = readPythonFiles(f.Spec.Source.Inline, runtimeDir)
+	case f.HasTypeScript():
+		files, err = readTypeScriptFiles(f.Spec.Source.Inline, runtimeDir)
+	default:
+		files, err = readNodejsFiles(f.Spec.Source.Inline, runtimeDir)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		if files[i].Name != "Dockerfile" {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(files[i].Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode Dockerfile")
+		}
+		files[i].Data = base64.StdEncoding.EncodeToString(addPackageRegistryConfigMount(data))
+	}
+	return files, nil
+}
+
+const (
+	NpmrcBuildSecretID   = "npmrc"
+	PipConfBuildSecretID = "pipconf"
+)
+
+var (
+	dockerfileNpmInstallRegex = regexp.MustCompile(`(?m)^RUN npm install`)
+	dockerfilePipInstallRegex = regexp.MustCompile(`(?m)^RUN pip install`)
+)
+
+// addPackageRegistryConfigMount passes the package registry config to the dependencies installation as an optional build secret
+// as the function's pods get it from the mounted Secret
+func addPackageRegistryConfigMount(dockerfile []byte) []byte {
+	dockerfile = dockerfileNpmInstallRegex.ReplaceAll(dockerfile,
+		[]byte(fmt.Sprintf("RUN --mount=type=secret,id=%s,target=/usr/src/app/.npmrc npm install", NpmrcBuildSecretID)))
+	return dockerfilePipInstallRegex.ReplaceAll(dockerfile,
+		[]byte(fmt.Sprintf("RUN --mount=type=secret,id=%s,target=/etc/pip.conf pip install", PipConfBuildSecretID)))
+}
+
+// typeScriptPackageJSON is merged with the function package.json to build the ejected TypeScript project
+const typeScriptPackageJSON = `{
+  "scripts": {
+    "build": "tsc --project tsconfig.json"
+  },
+  "devDependencies": {
+    "typescript": "5.9.3"
+  }
+}`
+
+// readTypeScriptFiles returns nodejs runtime files with the handler.ts and the tsconfig-based build step
+func readTypeScriptFiles(inline *v1alpha2.InlineSource, runtimeDir string) ([]types.FileResponse, error) {
+	files, err := readNodejsFiles(inline, runtimeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		switch files[i].Name {
+		case "handler.js":
+			files[i].Name = "handler.ts"
+		case "package.json":
+			data, err := base64.StdEncoding.DecodeString(files[i].Data)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode package.json")
+			}
+			// function dependencies take precedence over the default TypeScript setup
+			data, err = packagejson.Merge(data, []byte(typeScriptPackageJSON))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to merge package.json")
+			}
+			files[i].Data = base64.StdEncoding.EncodeToString(data)
+		case "Dockerfile":
+			data, err := base64.StdEncoding.DecodeString(files[i].Data)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decode Dockerfile")
+			}
+			files[i].Data = base64.StdEncoding.EncodeToString(addTypeScriptBuildStep(data))
+		}
+	}
+
+	if _, ok := inline.Files["tsconfig.json"]; ok {
+		// function provides its own tsconfig.json
+		return files, nil
+	}
+	return append(files, types.FileResponse{
+		Name: "tsconfig.json",
+		Data: base64.StdEncoding.EncodeToString([]byte(resources.DefaultTypeScriptConfig)),
+	}), nil
+}
 
-	return readNodejsFiles(f.Spec.Source.Inline, runtimeDir)
+var dockerfileCmdRegex = regexp.MustCompile(`(?m)^CMD `)
+
+// addTypeScriptBuildStep transpiles sources in the image before the runtime command
+func addTypeScriptBuildStep(dockerfile []byte) []byte {
+	loc := dockerfileCmdRegex.FindIndex(dockerfile)
+	if loc == nil {
+		return append(dockerfile, []byte("\nRUN npm run build\n")...)
+	}
+	result := append([]byte{}, dockerfile[:loc[0]]...)
+	result = append(result, []byte("RUN npm run build\n\n")...)
+	return append(result, dockerfile[loc[0]:]...)
 }
 
 func readNodejsFiles(inline *v1alpha2.InlineSource, runtimeDir string) ([]types.FileResponse, error) {
@@ -52,12 +158,12 @@ func readNodejsFiles(inline *v1alpha2.InlineSource, runtimeDir string) ([]types.
 		return nil, errors.Wrap(err, "failed to read openssl.cnf")
 	}
 
-	return append(commonFiles, []types.FileResponse{
+	return append(append(commonFiles, []types.FileResponse{
 		{Name: "package.json", Data: base64.StdEncoding.EncodeToString(packagejsonFile)},
 		{Name: "server.mjs", Data: base64.StdEncoding.EncodeToString(serverFile)},
 		{Name: "handler.js", Data: base64.StdEncoding.EncodeToString([]byte(inline.Source))},
 		{Name: "openssl.cnf", Data: base64.StdEncoding.EncodeToString(opensslFile)},
-	}...), nil
+	}...), inlineFiles(inline)...), nil
 }
 
 func readPythonFiles(inline *v1alpha2.InlineSource, runtimeDir string) ([]types.FileResponse, error) {
@@ -82,11 +188,26 @@ func readPythonFiles(inline *v1alpha2.InlineSource, runtimeDir string) ([]types.
 		return nil, errors.Wrap(err, "failed to read server.py")
 	}
 
-	return append(commonFiles, []types.FileResponse{
+	return append(append(commonFiles, []types.FileResponse{
 		{Name: "requirements.txt", Data: base64.StdEncoding.EncodeToString(requirementsFile)},
 		{Name: "server.py", Data: base64.StdEncoding.EncodeToString(serverFile)},
 		{Name: "handler.py", Data: base64.StdEncoding.EncodeToString([]byte(inline.Source))},
-	}...), nil
+	}...), inlineFiles(inline)...), nil
+}
+
+// inlineFiles returns additional inline files sorted by path
+func inlineFiles(inline *v1alpha2.InlineSource) []types.FileResponse {
+	paths := make([]string, 0, len(inline.Files))
+	for filePath := range inline.Files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	files := make([]types.FileResponse, 0, len(paths))
+	for _, filePath := range paths {
+		files = append(files, types.FileResponse{Name: filePath, Data: base64.StdEncoding.EncodeToString([]byte(inline.Files[filePath]))})
+	}
+	return files
 }
 
 func readCommonFiles(runtimeDir string) ([]types.FileResponse, error) {
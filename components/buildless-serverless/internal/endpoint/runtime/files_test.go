@@ -1,6 +1,7 @@
 package runtime
 
 import (
+	"encoding/base64"
 	"fmt"
 	"testing"
 
@@ -44,6 +45,22 @@ func Test_readNodejsFiles(t *testing.T) {
 		require.Contains(t, gotList, types.FileResponse{Name: "handler.js", Data: handlerBase64Data})
 	})
 
+	t.Run("read nodejs22 runtime files with additional inline files", func(t *testing.T) {
+		inline := &v1alpha2.InlineSource{
+			Source:       handlerData,
+			Dependencies: "{}",
+			Files: map[string]string{
+				"utils/helpers.js": handlerData,
+			},
+		}
+		runtimeDir := fmt.Sprintf("%s/%s", runtimesDir, "nodejs22")
+
+		gotList, gotErr := readNodejsFiles(inline, runtimeDir)
+		require.NoError(t, gotErr)
+		require.Len(t, gotList, 14)
+		require.Contains(t, gotList, types.FileResponse{Name: "utils/helpers.js", Data: handlerBase64Data})
+	})
+
 	t.Run("runtime dir does not exist", func(t *testing.T) {
 		inline := &v1alpha2.InlineSource{
 			Source:       handlerData,
@@ -85,6 +102,57 @@ func Test_readPythonFiles(t *testing.T) {
 	})
 }
 
+func Test_readTypeScriptFiles(t *testing.T) {
+	t.Run("read nodejs22 runtime files for typescript function", func(t *testing.T) {
+		inline := &v1alpha2.InlineSource{
+			Source:       handlerData,
+			Dependencies: `{"devDependencies": {"typescript": "5.4.5"}}`,
+		}
+		runtimeDir := fmt.Sprintf("%s/%s", runtimesDir, "nodejs22")
+
+		gotList, gotErr := readTypeScriptFiles(inline, runtimeDir)
+		require.NoError(t, gotErr)
+		require.Len(t, gotList, 14)
+		require.Contains(t, gotList, types.FileResponse{Name: "handler.ts", Data: handlerBase64Data})
+		requireFileWithName(t, gotList, "tsconfig.json")
+
+		packageJSON := fileData(t, gotList, "package.json")
+		require.Contains(t, packageJSON, `"build": "tsc --project tsconfig.json"`)
+		// function dependencies take precedence
+		require.Contains(t, packageJSON, `"typescript": "5.4.5"`)
+
+		dockerfile := fileData(t, gotList, "Dockerfile")
+		require.Contains(t, dockerfile, "RUN npm run build\n\nCMD [\"npm\", \"start\"]")
+	})
+
+	t.Run("keep tsconfig.json provided by function", func(t *testing.T) {
+		inline := &v1alpha2.InlineSource{
+			Source: handlerData,
+			Files: map[string]string{
+				"tsconfig.json": "{}",
+			},
+		}
+		runtimeDir := fmt.Sprintf("%s/%s", runtimesDir, "nodejs22")
+
+		gotList, gotErr := readTypeScriptFiles(inline, runtimeDir)
+		require.NoError(t, gotErr)
+		require.Len(t, gotList, 14)
+		require.Equal(t, "{}", fileData(t, gotList, "tsconfig.json"))
+	})
+}
+
+func fileData(t *testing.T, files []types.FileResponse, name string) string {
+	for _, f := range files {
+		if f.Name == name {
+			data, err := base64.StdEncoding.DecodeString(f.Data)
+			require.NoError(t, err)
+			return string(data)
+		}
+	}
+	require.Fail(t, fmt.Sprintf("file %s not found", name))
+	return ""
+}
+
 func requireFileWithName(t *testing.T, files []types.FileResponse, name string) {
 	for _, f := range files {
 		if f.Name == name {
@@ -93,3 +161,20 @@ func requireFileWithName(t *testing.T, files []types.FileResponse, name string)
 	}
 	require.Fail(t, fmt.Sprintf("file %s not found", name))
 }
+
+func Test_addPackageRegistryConfigMount(t *testing.T) {
+	t.Run("mount npmrc build secret", func(t *testing.T) {
+		dockerfile := []byte("WORKDIR /usr/src/app\nRUN npm install && npm cache clean --force\nCMD [\"npm\", \"start\"]\n")
+
+		got := addPackageRegistryConfigMount(dockerfile)
+
+		require.Equal(t, "WORKDIR /usr/src/app\nRUN --mount=type=secret,id=npmrc,target=/usr/src/app/.npmrc npm install && npm cache clean --force\nCMD [\"npm\", \"start\"]\n", string(got))
+	})
+	t.Run("mount pip.conf build secret", func(t *testing.T) {
+		dockerfile := []byte("RUN pip install --no-cache-dir -r /kubeless/requirements.txt\n")
+
+		got := addPackageRegistryConfigMount(dockerfile)
+
+		require.Equal(t, "RUN --mount=type=secret,id=pipconf,target=/etc/pip.conf pip install --no-cache-dir -r /kubeless/requirements.txt\n", string(got))
+	})
+}
@@ -13,13 +13,14 @@ import (
 	"go.yaml.in/yaml/v2"
 )
 
-func BuildResources(functionConfig *config.FunctionConfig, f *v1alpha2.Function, appName string) ([]types.FileResponse, error) {
+// BuildResources returns manifests of the ejected function, functionRuntime is the FunctionRuntime of its runtime or nil when it's missing
+func BuildResources(functionConfig *config.FunctionConfig, f *v1alpha2.Function, functionRuntime *v1alpha2.FunctionRuntime, appName string) ([]types.FileResponse, error) {
 	svc, err := buildServiceFileData(f, appName)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to build service")
 	}
 
-	deployment, err := buildDeploymentFileData(functionConfig, f, appName)
+	deployment, err := buildDeploymentFileData(functionConfig, f, functionRuntime, appName)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to build deployment")
 	}
@@ -53,11 +54,19 @@ func buildServiceFileData(function *v1alpha2.Function, appName string) ([]byte,
 	return data, nil
 }
 
-func buildDeploymentFileData(functionConfig *config.FunctionConfig, function *v1alpha2.Function, appName string) ([]byte, error) {
+func buildDeploymentFileData(functionConfig *config.FunctionConfig, function *v1alpha2.Function, functionRuntime *v1alpha2.FunctionRuntime, appName string) ([]byte, error) {
 	if function.HasGitSources() {
 		// TODO: support git source
 		return nil, errors.New("ejecting functions with git source is not supported")
 	}
+	if function.HasConfigMapSources() || function.HasOCISources() {
+		// sources have to be converted to the inline ones with InlineSourceFromFiles first
+		return nil, errors.New("ejecting functions with configmap or oci source requires their files")
+	}
+	if function.HasArchiveSources() {
+		// TODO: support archive source
+		return nil, errors.New("ejecting functions with archive source is not supported")
+	}
 
 	deployName := appName
 	if deployName == "" {
@@ -78,7 +87,8 @@ func buildDeploymentFileData(functionConfig *config.FunctionConfig, function *v1
 		}),
 		resources.DeploySetCmd([]string{}), // clear the command to use the default one from the image
 		resources.DeploySetImage("image:tag"),
-		resources.DeployUseGeneralEnvs(),
+		// the image is built from the runtime's files, so only envs of the FunctionRuntime apply to the ejected function
+		resources.DeployUseGeneralEnvs(functionRuntime),
 	).Deployment
 
 	data, err := convertK8SObjectToYaml(deploy)
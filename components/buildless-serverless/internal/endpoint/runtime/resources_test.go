@@ -8,6 +8,7 @@ import (
 	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,7 +28,7 @@ func TestBuildResources(t *testing.T) {
 				Name:      "test-function",
 				Namespace: "test-namespace",
 			},
-		}, "")
+		}, nil, "")
 
 		require.NoError(t, err)
 		require.Len(t, files, 2)
@@ -49,12 +50,48 @@ func TestBuildResources(t *testing.T) {
 				Name:      "test-function",
 				Namespace: "test-namespace",
 			},
-		}, "")
+		}, nil, "")
 
 		require.ErrorContains(t, err, "ejecting functions with git source is not supported")
 		require.Nil(t, files)
 	})
 
+	t.Run("error on configmap source", func(t *testing.T) {
+		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: "nodejs22",
+				Source: v1alpha2.Source{
+					ConfigMap: &v1alpha2.ConfigMapSource{Name: "function-sources"},
+				},
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+			},
+		}, nil, "")
+
+		require.ErrorContains(t, err, "ejecting functions with configmap or oci source requires their files")
+		require.Nil(t, files)
+	})
+
+	t.Run("error on archive source", func(t *testing.T) {
+		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: "nodejs22",
+				Source: v1alpha2.Source{
+					Archive: &v1alpha2.ArchiveSource{URL: "https://example.com/function.tar.gz"},
+				},
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+			},
+		}, nil, "")
+
+		require.ErrorContains(t, err, "ejecting functions with archive source is not supported")
+		require.Nil(t, files)
+	})
+
 	t.Run("build resources for function with specified app name", func(t *testing.T) {
 		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
 			Spec: v1alpha2.FunctionSpec{
@@ -70,7 +107,7 @@ func TestBuildResources(t *testing.T) {
 				Name:      "test-function",
 				Namespace: "test-namespace",
 			},
-		}, "test-app-name")
+		}, nil, "test-app-name")
 
 		require.NoError(t, err)
 		require.Len(t, files, 2)
@@ -79,6 +116,38 @@ func TestBuildResources(t *testing.T) {
 		require.Equal(t, "k8s/deployment.yaml", files[1].Name)
 		requireEqualBase64Objects(t, fixDeployment("test-app-name", "test-app-name"), files[1].Data)
 	})
+
+	t.Run("build resources for function with envs of function runtime", func(t *testing.T) {
+		functionRuntime := &v1alpha2.FunctionRuntime{
+			ObjectMeta: metav1.ObjectMeta{Name: "nodejs22"},
+			Spec: v1alpha2.FunctionRuntimeSpec{
+				Image: "custom-nodejs22",
+				Env:   []corev1.EnvVar{{Name: "NODE_OPTIONS", Value: "--max-old-space-size=256"}},
+			},
+		}
+
+		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: "nodejs22",
+				Source: v1alpha2.Source{
+					Inline: &v1alpha2.InlineSource{
+						Source: "console.log('Hello World')",
+					},
+				},
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+			},
+		}, functionRuntime, "")
+
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		deployment, err := base64.StdEncoding.DecodeString(files[1].Data)
+		require.NoError(t, err)
+		require.Contains(t, string(deployment), "--max-old-space-size=256")
+		require.Contains(t, string(deployment), "image: image:tag")
+	})
 }
 
 func fixTestService(appName string) string {
@@ -113,7 +182,11 @@ spec:
     matchLabels:
       app.kubernetes.io/instance: %s
       serverless.kyma-project.io/resource: deployment
-  strategy: {}
+  strategy:
+    rollingUpdate:
+      maxSurge: 25%%
+      maxUnavailable: 25%%
+    type: RollingUpdate
   template:
     metadata:
       annotations:
@@ -0,0 +1,34 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+)
+
+// InlineSourceFromFiles converts source files of the ConfigMap or OCI artifact to the inline source,
+// so the function is ejected the same way as the one with inline sources
+func InlineSourceFromFiles(f *v1alpha2.Function, files map[string]string) (*v1alpha2.InlineSource, error) {
+	handlerName, dependenciesName := resources.InlineFileNames(f, nil)
+	source, ok := files[handlerName]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("function sources don't contain the %s file", handlerName))
+	}
+
+	inline := &v1alpha2.InlineSource{
+		Source:       source,
+		Dependencies: files[dependenciesName],
+	}
+	for filePath, data := range files {
+		if filePath == handlerName || filePath == dependenciesName {
+			continue
+		}
+		if inline.Files == nil {
+			inline.Files = map[string]string{}
+		}
+		inline.Files[filePath] = data
+	}
+	return inline, nil
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineSourceFromFiles(t *testing.T) {
+	t.Run("convert nodejs sources", func(t *testing.T) {
+		f := &v1alpha2.Function{Spec: v1alpha2.FunctionSpec{Runtime: v1alpha2.NodeJs22}}
+
+		inline, err := InlineSourceFromFiles(f, map[string]string{
+			"handler.js":     "module.exports = {}",
+			"package.json":   `{"dependencies":{}}`,
+			"utils/helpers.js": "exports.a = 1",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, &v1alpha2.InlineSource{
+			Source:       "module.exports = {}",
+			Dependencies: `{"dependencies":{}}`,
+			Files: map[string]string{
+				"utils/helpers.js": "exports.a = 1",
+			},
+		}, inline)
+	})
+	t.Run("convert python sources without dependencies", func(t *testing.T) {
+		f := &v1alpha2.Function{Spec: v1alpha2.FunctionSpec{Runtime: v1alpha2.Python312}}
+
+		inline, err := InlineSourceFromFiles(f, map[string]string{
+			"handler.py": "def main(event, context):\n  return 'ok'",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, &v1alpha2.InlineSource{
+			Source: "def main(event, context):\n  return 'ok'",
+		}, inline)
+	})
+	t.Run("fail when handler is missing", func(t *testing.T) {
+		f := &v1alpha2.Function{Spec: v1alpha2.FunctionSpec{Runtime: v1alpha2.NodeJs22}}
+
+		inline, err := InlineSourceFromFiles(f, map[string]string{
+			"index.js": "module.exports = {}",
+		})
+
+		require.ErrorContains(t, err, "function sources don't contain the handler.js file")
+		require.Nil(t, inline)
+	})
+}
@@ -0,0 +1,38 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/sbom"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func (s *Server) handleSBOMRequest(w http.ResponseWriter, r *http.Request) {
+	ns := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	s.log.Infof("handling sbom request for function '%s/%s'", ns, name)
+
+	if err := validateFunctionParams(ns, name, ""); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	function := v1alpha2.Function{}
+	err := s.k8s.Get(s.ctx, client.ObjectKey{Namespace: ns, Name: name}, &function)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "failed to get function '%s/%s'", ns, name))
+		return
+	}
+
+	// status describes what the function actually runs
+	doc, err := sbom.Build(&function, function.Status.RuntimeImage, "")
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "failed to build sbom for function '%s/%s'", ns, name))
+		return
+	}
+
+	s.writeSBOMResponse(w, doc)
+}
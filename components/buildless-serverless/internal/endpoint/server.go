@@ -28,6 +28,8 @@ func NewInternalServer(ctx context.Context, log *zap.SugaredLogger, k8s client.C
 	}
 
 	server.mux.HandleFunc("/internal/function/eject/", server.handleFunctionRequest)
+	server.mux.HandleFunc("/internal/function/sbom/", server.handleSBOMRequest)
+	server.mux.HandleFunc("/internal/runtime/deprecations/", server.handleDeprecationsRequest)
 
 	return server
 }
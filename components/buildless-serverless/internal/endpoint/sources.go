@@ -0,0 +1,86 @@
+package endpoint
+
+import (
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/oci"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/runtime"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fetchOCIFiles is a variable to allow replacing registry calls in tests
+var fetchOCIFiles = oci.FetchFiles
+
+// inlineSources replaces ConfigMap and OCI sources of the function with the inline ones built from their files
+func (s *Server) inlineSources(f *v1alpha2.Function) error {
+	var files map[string]string
+	var err error
+	switch {
+	case f.HasConfigMapSources():
+		files, err = s.readConfigMapFiles(f)
+	case f.HasOCISources():
+		files, err = s.readOCIFiles(f)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	inline, err := runtime.InlineSourceFromFiles(f, files)
+	if err != nil {
+		return err
+	}
+	f.Spec.Source = v1alpha2.Source{Inline: inline}
+	return nil
+}
+
+func (s *Server) readConfigMapFiles(f *v1alpha2.Function) (map[string]string, error) {
+	cmName := f.Spec.Source.ConfigMap.Name
+	cm := &corev1.ConfigMap{}
+	if err := s.k8s.Get(s.ctx, client.ObjectKey{Namespace: f.GetNamespace(), Name: cmName}, cm); err != nil {
+		return nil, errors.Wrapf(err, "failed to get configmap '%s'", cmName)
+	}
+
+	files := map[string]string{}
+	for key, data := range cm.Data {
+		files[key] = data
+	}
+	for key, data := range cm.BinaryData {
+		files[key] = string(data)
+	}
+	return files, nil
+}
+
+// readOCIFiles downloads the artifact the function runs with, pinned to the digest from its status when it's known
+func (s *Server) readOCIFiles(f *v1alpha2.Function) (map[string]string, error) {
+	ociSource := f.Spec.Source.OCI
+	reference := ociSource.Reference
+	if status := f.Status.OCI; status != nil && status.Reference == ociSource.Reference && status.Digest != "" {
+		pinned, err := oci.PinnedReference(ociSource.Reference, status.Digest)
+		if err != nil {
+			return nil, err
+		}
+		reference = pinned
+	}
+
+	var creds *oci.Credentials
+	if f.HasOCIPullSecret() {
+		var err error
+		creds, err = oci.NewCredentials(s.ctx, s.k8s, f.GetNamespace(), ociSource.PullSecretName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	artifactFiles, err := fetchOCIFiles(s.ctx, reference, creds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch oci artifact '%s'", reference)
+	}
+	files := map[string]string{}
+	for _, file := range artifactFiles {
+		files[file.Path] = string(file.Data)
+	}
+	return files, nil
+}
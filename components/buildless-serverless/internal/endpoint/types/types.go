@@ -1,5 +1,7 @@
 package types
 
+import "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/deprecation"
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
@@ -13,3 +15,7 @@ type FilesListResponse struct {
 	OutputMessage string         `json:"outputMessage"`
 	Files         []FileResponse `json:"files"`
 }
+
+type DeprecationsResponse struct {
+	Functions []deprecation.FunctionReport `json:"functions"`
+}
@@ -0,0 +1,303 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/packagejson"
+	"github.com/pkg/errors"
+)
+
+const (
+	MediaType = "application/vnd.cyclonedx+json"
+
+	bomFormat   = "CycloneDX"
+	specVersion = "1.5"
+
+	propertyPrefix = "serverless.kyma-project.io:"
+)
+
+var (
+	npmExactVersionRegex    = regexp.MustCompile(`^[=v]*(\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?)$`)
+	pythonRequirementRegex  = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*([^;]*)`)
+	pythonExactVersionRegex = regexp.MustCompile(`^===?\s*([^\s,*]+)$`)
+	pythonNameRegex         = regexp.MustCompile(`[-_.]+`)
+)
+
+// Document is the CycloneDX bill of materials of the Function
+type Document struct {
+	BOMFormat    string       `json:"bomFormat"`
+	SpecVersion  string       `json:"specVersion"`
+	SerialNumber string       `json:"serialNumber"`
+	Version      int          `json:"version"`
+	Metadata     Metadata     `json:"metadata"`
+	Components   []Component  `json:"components"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+type Metadata struct {
+	Component Component `json:"component"`
+}
+
+type Component struct {
+	Type               string              `json:"type"`
+	BOMRef             string              `json:"bom-ref"`
+	Group              string              `json:"group,omitempty"`
+	Name               string              `json:"name"`
+	Version            string              `json:"version,omitempty"`
+	PURL               string              `json:"purl,omitempty"`
+	Properties         []Property          `json:"properties,omitempty"`
+	ExternalReferences []ExternalReference `json:"externalReferences,omitempty"`
+}
+
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type ExternalReference struct {
+	Type    string `json:"type"`
+	URL     string `json:"url"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// Build returns the bill of materials of the Function running the runtimeImage built from the given commit
+// the runtime base dependencies are read from the runtimes directory, as for the eject
+func Build(f *v1alpha2.Function, runtimeImage, commit string) (*Document, error) {
+	return build(f, fmt.Sprintf("runtimes/%s", f.Spec.Runtime), runtimeImage, commit)
+}
+
+func build(f *v1alpha2.Function, runtimeDir, runtimeImage, commit string) (*Document, error) {
+	var libraries []Component
+	var err error
+	// runtimes from the catalog install dependencies with their own commands, so only the image is listed for them
+	if f.HasPythonRuntime() {
+		libraries, err = pythonComponents(f, runtimeDir)
+	} else if f.HasNodejsRuntime() {
+		libraries, err = nodejsComponents(f, runtimeDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	function := functionComponent(f, commit)
+	components := []Component{}
+	if runtimeImage != "" {
+		components = append(components, imageComponent(runtimeImage))
+	}
+	components = append(components, libraries...)
+
+	dependsOn := make([]string, 0, len(components))
+	for _, c := range components {
+		dependsOn = append(dependsOn, c.BOMRef)
+	}
+
+	doc := &Document{
+		BOMFormat:   bomFormat,
+		SpecVersion: specVersion,
+		Version:     1,
+		Metadata: Metadata{
+			Component: function,
+		},
+		Components: components,
+		Dependencies: []Dependency{
+			{Ref: function.BOMRef, DependsOn: dependsOn},
+		},
+	}
+
+	// serial number depends only on the content, so the same Function results in the same document
+	content, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal document")
+	}
+	doc.SerialNumber = fmt.Sprintf("urn:uuid:%s", uuid.NewSHA1(uuid.NameSpaceURL, content))
+	return doc, nil
+}
+
+func functionComponent(f *v1alpha2.Function, commit string) Component {
+	c := Component{
+		Type:   "application",
+		BOMRef: fmt.Sprintf("function:%s/%s", f.GetNamespace(), f.GetName()),
+		Group:  f.GetNamespace(),
+		Name:   f.GetName(),
+		Properties: []Property{
+			{Name: propertyPrefix + "runtime", Value: string(f.Spec.Runtime)},
+		},
+	}
+
+	switch {
+	case f.HasGitSources():
+		if commit == "" && f.Status.GitRepository != nil {
+			commit = f.Status.GitRepository.Commit
+		}
+		c.Version = commit
+		c.ExternalReferences = append(c.ExternalReferences, ExternalReference{
+			Type:    "vcs",
+			URL:     f.Spec.Source.GitRepository.URL,
+			Comment: fmt.Sprintf("commit %s", commit),
+		})
+		c.Properties = append(c.Properties, Property{Name: propertyPrefix + "git-commit", Value: commit})
+	case f.HasOCISources():
+		c.ExternalReferences = append(c.ExternalReferences, ExternalReference{
+			Type: "distribution",
+			URL:  f.Spec.Source.OCI.Reference,
+		})
+		if f.Status.OCI != nil && f.Status.OCI.Digest != "" {
+			c.Version = f.Status.OCI.Digest
+			c.Properties = append(c.Properties, Property{Name: propertyPrefix + "oci-digest", Value: f.Status.OCI.Digest})
+		}
+	case f.HasArchiveSources():
+		c.ExternalReferences = append(c.ExternalReferences, ExternalReference{
+			Type: "distribution",
+			URL:  f.Spec.Source.Archive.URL,
+		})
+		if f.Status.Archive != nil && f.Status.Archive.Revision != "" {
+			c.Version = f.Status.Archive.Revision
+			c.Properties = append(c.Properties, Property{Name: propertyPrefix + "archive-revision", Value: f.Status.Archive.Revision})
+		}
+	}
+	return c
+}
+
+func imageComponent(image string) Component {
+	name, version := image, ""
+	if i := strings.Index(image, "@"); i >= 0 {
+		name, version = image[:i], image[i+1:]
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, version = image[:i], image[i+1:]
+	}
+	return Component{
+		Type:    "container",
+		BOMRef:  image,
+		Name:    name,
+		Version: version,
+	}
+}
+
+// nodejsComponents returns dependencies of the runtime package.json merged with the Function's dependencies
+// versions resolved in the Function's package-lock.json take precedence over the declared ranges
+func nodejsComponents(f *v1alpha2.Function, runtimeDir string) ([]Component, error) {
+	packagejsonFile, err := os.ReadFile(runtimeDir + "/package.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read package.json")
+	}
+
+	inline := f.Spec.Source.Inline
+	if inline != nil && inline.Dependencies != "" {
+		packagejsonFile, err = packagejson.Merge([]byte(inline.Dependencies), packagejsonFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to merge package.json")
+		}
+	}
+
+	packageJSON := struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}{}
+	if err := json.Unmarshal(packagejsonFile, &packageJSON); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal package.json")
+	}
+
+	lock := struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+	}{}
+	if inline != nil {
+		if content, ok := inline.Files["package-lock.json"]; ok {
+			if err := json.Unmarshal([]byte(content), &lock); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal package-lock.json")
+			}
+		}
+	}
+
+	components := make([]Component, 0, len(packageJSON.Dependencies))
+	for name, spec := range packageJSON.Dependencies {
+		version := spec
+		if locked, ok := lock.Packages["node_modules/"+name]; ok && locked.Version != "" {
+			version = locked.Version
+		}
+		c := Component{
+			Type:    "library",
+			BOMRef:  fmt.Sprintf("npm:%s", name),
+			Name:    name,
+			Version: version,
+		}
+		if exact := npmExactVersionRegex.FindStringSubmatch(version); exact != nil {
+			c.Version = exact[1]
+			c.PURL = fmt.Sprintf("pkg:npm/%s@%s", strings.Replace(name, "@", "%40", 1), exact[1])
+			c.BOMRef = c.PURL
+		}
+		components = append(components, c)
+	}
+	sortComponents(components)
+	return components, nil
+}
+
+// pythonComponents returns requirements of the runtime appended with the Function's dependencies
+func pythonComponents(f *v1alpha2.Function, runtimeDir string) ([]Component, error) {
+	requirementsFile, err := os.ReadFile(runtimeDir + "/requirements.txt")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read requirements.txt")
+	}
+
+	requirements := string(requirementsFile)
+	if inline := f.Spec.Source.Inline; inline != nil && inline.Dependencies != "" {
+		requirements = fmt.Sprintf("%s\n%s", requirements, inline.Dependencies)
+	}
+
+	// the Function's requirements are installed later, so they override the runtime ones
+	byName := map[string]Component{}
+	for _, line := range strings.Split(requirements, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), "\\"))
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		matches := pythonRequirementRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		name := strings.ToLower(pythonNameRegex.ReplaceAllString(matches[1], "-"))
+		spec, _, _ := strings.Cut(strings.TrimSpace(matches[3]), " ")
+		if strings.HasPrefix(spec, "@") {
+			// direct reference has no version
+			spec = ""
+		}
+		c := Component{
+			Type:    "library",
+			BOMRef:  fmt.Sprintf("pypi:%s", name),
+			Name:    name,
+			Version: spec,
+		}
+		if exact := pythonExactVersionRegex.FindStringSubmatch(spec); exact != nil {
+			c.Version = exact[1]
+			c.PURL = fmt.Sprintf("pkg:pypi/%s@%s", name, exact[1])
+			c.BOMRef = c.PURL
+		}
+		byName[name] = c
+	}
+
+	components := make([]Component, 0, len(byName))
+	for _, c := range byName {
+		components = append(components, c)
+	}
+	sortComponents(components)
+	return components, nil
+}
+
+func sortComponents(components []Component) {
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+}
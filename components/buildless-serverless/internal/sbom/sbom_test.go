@@ -0,0 +1,175 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const runtimesDir = "../../../runtimes/"
+
+func Test_build(t *testing.T) {
+	t.Run("build nodejs function sbom with merged dependencies", func(t *testing.T) {
+		f := &v1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-function", Namespace: "test-namespace"},
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: v1alpha2.NodeJs22,
+				Source: v1alpha2.Source{
+					Inline: &v1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: `{"dependencies": {"lodash": "4.17.21", "axios": "^1.6.0", "@kyma/sdk": "~1.0.0"}}`,
+						Files: map[string]string{
+							"package-lock.json": `{"packages": {"node_modules/@kyma/sdk": {"version": "1.0.3"}}}`,
+						},
+					},
+				},
+			},
+		}
+
+		doc, err := build(f, runtimesDir+"nodejs22", "europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs22:1.2.3", "")
+
+		require.NoError(t, err)
+		require.Equal(t, "CycloneDX", doc.BOMFormat)
+		require.Equal(t, "1.5", doc.SpecVersion)
+		require.Regexp(t, "^urn:uuid:", doc.SerialNumber)
+		require.Equal(t, "function:test-namespace/test-function", doc.Metadata.Component.BOMRef)
+		require.Equal(t, Component{
+			Type:    "container",
+			BOMRef:  "europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs22:1.2.3",
+			Name:    "europe-docker.pkg.dev/kyma-project/prod/function-runtime-nodejs22",
+			Version: "1.2.3",
+		}, doc.Components[0])
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "pkg:npm/lodash@4.17.21",
+			Name:    "lodash",
+			Version: "4.17.21",
+			PURL:    "pkg:npm/lodash@4.17.21",
+		})
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "pkg:npm/%40kyma/sdk@1.0.3",
+			Name:    "@kyma/sdk",
+			Version: "1.0.3",
+			PURL:    "pkg:npm/%40kyma/sdk@1.0.3",
+		})
+		// function's version takes precedence over the runtime one
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "npm:axios",
+			Name:    "axios",
+			Version: "^1.6.0",
+		})
+		// runtime dependency
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "npm:express",
+			Name:    "express",
+			Version: "^5.1.0",
+		})
+		require.Len(t, doc.Dependencies, 1)
+		require.Len(t, doc.Dependencies[0].DependsOn, len(doc.Components))
+	})
+	t.Run("build python function sbom with git commit", func(t *testing.T) {
+		f := &v1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-function", Namespace: "test-namespace"},
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: v1alpha2.Python312,
+				Source: v1alpha2.Source{
+					GitRepository: &v1alpha2.GitRepositorySource{
+						URL: "https://github.com/kyma-project/serverless.git",
+					},
+				},
+			},
+		}
+
+		doc, err := build(f, runtimesDir+"python312", "", "ae3b1c2")
+
+		require.NoError(t, err)
+		require.Equal(t, "ae3b1c2", doc.Metadata.Component.Version)
+		require.Equal(t, []ExternalReference{{
+			Type:    "vcs",
+			URL:     "https://github.com/kyma-project/serverless.git",
+			Comment: "commit ae3b1c2",
+		}}, doc.Metadata.Component.ExternalReferences)
+		require.Contains(t, doc.Metadata.Component.Properties, Property{Name: "serverless.kyma-project.io:git-commit", Value: "ae3b1c2"})
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "pkg:pypi/bottle@0.13.4",
+			Name:    "bottle",
+			Version: "0.13.4",
+			PURL:    "pkg:pypi/bottle@0.13.4",
+		})
+		// names are normalized
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "pkg:pypi/prometheus-client@0.24.1",
+			Name:    "prometheus-client",
+			Version: "0.24.1",
+			PURL:    "pkg:pypi/prometheus-client@0.24.1",
+		})
+	})
+	t.Run("python function dependencies override runtime ones", func(t *testing.T) {
+		f := &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: v1alpha2.Python312,
+				Source: v1alpha2.Source{
+					Inline: &v1alpha2.InlineSource{
+						Source:       "source",
+						Dependencies: "requests==2.32.3 --hash=sha256:abc\nmylib @ https://example.com/mylib.tar.gz",
+					},
+				},
+			},
+		}
+
+		doc, err := build(f, runtimesDir+"python312", "", "")
+
+		require.NoError(t, err)
+		require.Contains(t, doc.Components, Component{
+			Type:    "library",
+			BOMRef:  "pkg:pypi/requests@2.32.3",
+			Name:    "requests",
+			Version: "2.32.3",
+			PURL:    "pkg:pypi/requests@2.32.3",
+		})
+		require.Contains(t, doc.Components, Component{
+			Type:   "library",
+			BOMRef: "pypi:mylib",
+			Name:   "mylib",
+		})
+	})
+	t.Run("same function results in the same serial number", func(t *testing.T) {
+		f := &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: v1alpha2.NodeJs22,
+				Source: v1alpha2.Source{
+					Inline: &v1alpha2.InlineSource{Source: "source"},
+				},
+			},
+		}
+
+		doc1, err1 := build(f, runtimesDir+"nodejs22", "image:1", "")
+		doc2, err2 := build(f, runtimesDir+"nodejs22", "image:1", "")
+		doc3, err3 := build(f, runtimesDir+"nodejs22", "image:2", "")
+
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.NoError(t, err3)
+		require.Equal(t, doc1.SerialNumber, doc2.SerialNumber)
+		require.NotEqual(t, doc1.SerialNumber, doc3.SerialNumber)
+	})
+	t.Run("runtime dir does not exist", func(t *testing.T) {
+		f := &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: v1alpha2.NodeJs22,
+			},
+		}
+
+		doc, err := build(f, runtimesDir+"nodejs", "", "")
+
+		require.ErrorContains(t, err, "failed to read package.json")
+		require.Nil(t, doc)
+	})
+}
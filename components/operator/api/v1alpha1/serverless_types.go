@@ -147,10 +147,25 @@ type ServerlessStatus struct {
 	// +kubebuilder:validation:Enum=True;False
 	NetworkPoliciesEnabled string `json:"networkPoliciesEnabled,omitempty"`
 
+	// RuntimeImageUpgrade summarizes the staged upgrade of Functions' runtime images.
+	// It's set only while some Functions wait for or are in the upgrade.
+	RuntimeImageUpgrade *RuntimeImageUpgradeStatus `json:"runtimeImageUpgrade,omitempty"`
+
 	// Conditions associated with CustomStatus.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+type RuntimeImageUpgradeStatus struct {
+	// Number of Functions waiting for the upgrade.
+	Pending int `json:"pending"`
+	// Number of Functions which are being upgraded.
+	InProgress int `json:"inProgress"`
+	// Number of Functions which failed with the new runtime image.
+	Failed int `json:"failed"`
+	// Paused signifies that the upgrade is paused after failed upgrades.
+	Paused bool `json:"paused,omitempty"`
+}
+
 // +k8s:deepcopy-gen=true
 
 //+kubebuilder:object:root=true
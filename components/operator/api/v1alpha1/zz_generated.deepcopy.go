@@ -65,6 +65,21 @@ func (in *Endpoint) DeepCopy() *Endpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeImageUpgradeStatus) DeepCopyInto(out *RuntimeImageUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeImageUpgradeStatus.
+func (in *RuntimeImageUpgradeStatus) DeepCopy() *RuntimeImageUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeImageUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Serverless) DeepCopyInto(out *Serverless) {
 	*out = *in
@@ -157,6 +172,11 @@ func (in *ServerlessSpec) DeepCopy() *ServerlessSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerlessStatus) DeepCopyInto(out *ServerlessStatus) {
 	*out = *in
+	if in.RuntimeImageUpgrade != nil {
+		in, out := &in.RuntimeImageUpgrade, &out.RuntimeImageUpgrade
+		*out = new(RuntimeImageUpgradeStatus)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -156,6 +156,11 @@ func (b *Builder) WithImageFunctionRuntimePython312(image string) *Builder {
 	return b
 }
 
+func (b *Builder) WithImageFunctionScheduleInvoker(image string) *Builder {
+	b.With("global.images.function_schedule_invoker", image)
+	return b
+}
+
 func (b *Builder) WithImageKanikoExecutor(image string) *Builder {
 	b.With("global.images.kaniko_executor", image)
 	return b
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyma-project/serverless/components/operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	runtimeImageUpgradeRequeueDuration = time.Minute
+	runtimeImageUpgradePausedWarning   = "runtime image upgrade of Functions is paused after failed upgrades"
+)
+
+var functionListGVK = schema.GroupVersionKind{
+	Group:   "serverless.kyma-project.io",
+	Version: "v1alpha2",
+	Kind:    "FunctionList",
+}
+
+// updateRuntimeImageUpgradeStatus summarizes the staged runtime image upgrade reported in Functions' status
+func updateRuntimeImageUpgradeStatus(ctx context.Context, r *reconciler, s *systemState) {
+	functions := &unstructured.UnstructuredList{}
+	functions.SetGroupVersionKind(functionListGVK)
+	err := r.client.List(ctx, functions)
+	if meta.IsNoMatchError(err) {
+		// functions are not installed yet
+		s.instance.Status.RuntimeImageUpgrade = nil
+		return
+	}
+	if err != nil {
+		r.log.Warnf("error while listing functions: %s", err.Error())
+		return
+	}
+
+	progress := v1alpha1.RuntimeImageUpgradeStatus{}
+	for _, function := range functions.Items {
+		state, _, _ := unstructured.NestedString(function.Object, "status", "runtimeImageUpgrade", "state")
+		switch state {
+		case "Pending":
+			progress.Pending++
+		case "Paused":
+			progress.Pending++
+			progress.Paused = true
+		case "InProgress":
+			progress.InProgress++
+		case "Failed":
+			progress.Failed++
+		}
+	}
+
+	s.instance.Status.RuntimeImageUpgrade = nil
+	if progress != (v1alpha1.RuntimeImageUpgradeStatus{}) {
+		s.instance.Status.RuntimeImageUpgrade = &progress
+	}
+	if progress.Paused {
+		s.warningBuilder.With(runtimeImageUpgradePausedWarning)
+	}
+}
+
+// stopOrAwaitRuntimeImageUpgrade checks the upgrade progress again until all Functions are upgraded
+func stopOrAwaitRuntimeImageUpgrade(s *systemState) (stateFn, *ctrl.Result, error) {
+	if s.instance.Status.RuntimeImageUpgrade != nil {
+		return requeueAfter(runtimeImageUpgradeRequeueDuration)
+	}
+	return stop()
+}
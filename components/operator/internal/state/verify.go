@@ -12,7 +12,7 @@ import (
 )
 
 // verify if all workloads are in ready state
-func sFnVerifyResources(_ context.Context, r *reconciler, s *systemState) (stateFn, *ctrl.Result, error) {
+func sFnVerifyResources(ctx context.Context, r *reconciler, s *systemState) (stateFn, *ctrl.Result, error) {
 	result, err := chart.Verify(s.chartConfig)
 	if err != nil {
 		r.log.Warnf("error while verifying resource %s: %s",
@@ -46,6 +46,8 @@ func sFnVerifyResources(_ context.Context, r *reconciler, s *systemState) (state
 	// remove possible previous DeploymentFailure condition
 	s.instance.RemoveCondition(v1alpha1.ConditionTypeDeploymentFailure)
 
+	updateRuntimeImageUpgradeStatus(ctx, r, s)
+
 	warning := s.warningBuilder.Build()
 	if warning != "" {
 		s.setState(v1alpha1.StateWarning)
@@ -54,7 +56,7 @@ func sFnVerifyResources(_ context.Context, r *reconciler, s *systemState) (state
 			v1alpha1.ConditionReasonInstalled,
 			warning,
 		)
-		return stop()
+		return stopOrAwaitRuntimeImageUpgrade(s)
 	}
 
 	s.setState(v1alpha1.StateReady)
@@ -63,5 +65,5 @@ func sFnVerifyResources(_ context.Context, r *reconciler, s *systemState) (state
 		v1alpha1.ConditionReasonInstalled,
 		"Serverless installed",
 	)
-	return stop()
+	return stopOrAwaitRuntimeImageUpgrade(s)
 }
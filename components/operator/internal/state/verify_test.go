@@ -12,7 +12,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -94,6 +96,9 @@ func Test_sFnVerifyResources(t *testing.T) {
 		}
 		r := &reconciler{
 			log: zap.NewNop().Sugar(),
+			k8s: k8s{
+				client: fake.NewClientBuilder().Build(),
+			},
 		}
 
 		// verify and return update condition state
@@ -112,6 +117,52 @@ func Test_sFnVerifyResources(t *testing.T) {
 		)
 	})
 
+	t.Run("runtime image upgrade in progress", func(t *testing.T) {
+		s := &systemState{
+			warningBuilder: warning.NewBuilder(),
+			instance:       *testInstalledServerless.DeepCopy(),
+			chartConfig: &chart.Config{
+				Cache: fixEmptyManifestCache(),
+				CacheKey: types.NamespacedName{
+					Name:      testInstalledServerless.GetName(),
+					Namespace: testInstalledServerless.GetNamespace(),
+				},
+			},
+		}
+		r := &reconciler{
+			log: zap.NewNop().Sugar(),
+			k8s: k8s{
+				client: fake.NewClientBuilder().WithObjects(
+					fixFunctionWithUpgradeState("test-function-1", "InProgress"),
+					fixFunctionWithUpgradeState("test-function-2", "Failed"),
+					fixFunctionWithUpgradeState("test-function-3", "Paused"),
+					fixFunctionWithUpgradeState("test-function-4", ""),
+				).Build(),
+			},
+		}
+
+		// verify and return update condition state
+		next, result, err := sFnVerifyResources(context.Background(), r, s)
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{RequeueAfter: runtimeImageUpgradeRequeueDuration}, result)
+		require.Nil(t, next)
+
+		status := s.instance.Status
+		require.Equal(t, v1alpha1.StateWarning, status.State)
+		require.Equal(t, &v1alpha1.RuntimeImageUpgradeStatus{
+			Pending:    1,
+			InProgress: 1,
+			Failed:     1,
+			Paused:     true,
+		}, status.RuntimeImageUpgrade)
+		requireContainsCondition(t, status,
+			v1alpha1.ConditionTypeInstalled,
+			metav1.ConditionTrue,
+			v1alpha1.ConditionReasonInstalled,
+			"Warning: runtime image upgrade of Functions is paused after failed upgrades",
+		)
+	})
+
 	t.Run("verify error", func(t *testing.T) {
 		s := &systemState{
 			instance: *testInstalledServerless.DeepCopy(),
@@ -176,3 +227,15 @@ func Test_sFnVerifyResources(t *testing.T) {
 		require.Nil(t, next)
 	})
 }
+
+func fixFunctionWithUpgradeState(name, state string) *unstructured.Unstructured {
+	function := &unstructured.Unstructured{}
+	function.SetAPIVersion("serverless.kyma-project.io/v1alpha2")
+	function.SetKind("Function")
+	function.SetNamespace("default")
+	function.SetName(name)
+	if state != "" {
+		_ = unstructured.SetNestedField(function.Object, state, "status", "runtimeImageUpgrade", "state")
+	}
+	return function
+}
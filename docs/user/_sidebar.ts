@@ -21,7 +21,8 @@ export default [
     ] },
   { text: 'Resources', link: './resources/README', collapsed: true, items: [
     { text: 'Function CR', link: './resources/06-10-function-cr' },
-    { text: 'Serverless CR', link: './resources/06-20-serverless-cr' }
+    { text: 'Serverless CR', link: './resources/06-20-serverless-cr' },
+    { text: 'Function Runtime CR', link: './resources/06-30-function-runtime-cr' }
     ] },
   { text: 'Technical Reference', link: './technical-reference/README', collapsed: true, items: [
     { text: 'Serverless Architecture', link: './technical-reference/04-10-architecture' },